@@ -0,0 +1,277 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openbindings/cli/internal/delegates"
+	"github.com/openbindings/openbindings-go"
+)
+
+// Completion is a candidate value for an input field.
+type Completion = delegates.Completion
+
+// InputFields returns the sorted names of the top-level properties of an
+// operation's input schema.
+func InputFields(iface *openbindings.Interface, opKey string) []string {
+	props := inputProperties(iface, opKey)
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CompletableFields returns the sorted input fields of an operation whose
+// candidate values are listed by its default binding's delegate (fields
+// annotated with delegates.CompleteKey). A binding with an input transform
+// has none, since its delegate sees different field names.
+func CompletableFields(iface *openbindings.Interface, opKey string) []string {
+	_, b := DefaultBindingForOp(opKey, iface)
+	if b == nil || b.InputTransform != nil || fieldCompleter(iface, b) == nil {
+		return nil
+	}
+	var names []string
+	for name, prop := range inputProperties(iface, opKey) {
+		if _, ok := prop[delegates.CompleteKey]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CompleteOperationField returns the candidate values for an input field of
+// an operation: the values of its enum, or those listed by the delegate
+// when the field is completable (see CompletableFields). input holds the
+// values entered so far and may be nil.
+func CompleteOperationField(ctx context.Context, iface *openbindings.Interface, opKey string, field string, input any, obiDir string, contextName string) ([]Completion, error) {
+	return completeField(ctx, iface, opKey, "", field, input, obiDir, contextName)
+}
+
+// CompleteOBIOperationField is CompleteOperationField for an operation, or
+// the operation of a binding, in an OBI file.
+func CompleteOBIOperationField(ctx context.Context, obiPath string, opKey string, bindingKey string, field string, input any, contextName string) ([]Completion, error) {
+	iface, err := resolveInterface(obiPath)
+	if err != nil {
+		return nil, fmt.Errorf("load OBI %q: %w", obiPath, err)
+	}
+	return completeField(ctx, iface, opKey, bindingKey, field, input, filepath.Dir(obiPath), contextName)
+}
+
+func completeField(ctx context.Context, iface *openbindings.Interface, opKey string, bindingKey string, field string, input any, obiDir string, contextName string) ([]Completion, error) {
+	if bindingKey != "" {
+		b := BindingByKey(bindingKey, iface)
+		if b == nil {
+			return nil, fmt.Errorf("binding %q not found", bindingKey)
+		}
+		opKey = b.Operation
+	}
+	prop, ok := inputProperties(iface, opKey)[field]
+	if !ok {
+		return nil, fmt.Errorf("operation %q has no input field %q", opKey, field)
+	}
+	if values := enumStrings(prop); len(values) > 0 {
+		completions := make([]Completion, len(values))
+		for i, v := range values {
+			completions[i] = Completion{Value: v}
+		}
+		return completions, nil
+	}
+	if _, ok := prop[delegates.CompleteKey]; !ok {
+		return nil, nil
+	}
+
+	if bindingKey != "" {
+		opKey = ""
+	}
+	resolved, err := resolveBindingAndSource(iface, opKey, bindingKey, input, contextName, obiDir)
+	if err != nil {
+		return nil, err
+	}
+	fc := fieldCompleter(iface, resolved.binding)
+	if fc == nil || resolved.binding.InputTransform != nil {
+		return nil, nil
+	}
+	return fc.CompleteField(ctx, delegates.ExecuteInput{
+		Source:  resolveSourceLocation(resolved.source, obiDir),
+		Ref:     resolved.binding.Ref,
+		Input:   resolved.input,
+		Context: resolved.bindCtx,
+	}, field)
+}
+
+// OBIInputFields returns the sorted input fields of an operation, or the
+// operation of a binding, in an OBI file. Returns nil on any error.
+func OBIInputFields(obiPath string, opKey string, bindingKey string) []string {
+	iface, err := resolveInterface(obiPath)
+	if err != nil {
+		return nil
+	}
+	if bindingKey != "" {
+		b := BindingByKey(bindingKey, iface)
+		if b == nil {
+			return nil
+		}
+		opKey = b.Operation
+	}
+	return InputFields(iface, opKey)
+}
+
+// SetInputFields applies name=value assignments to an object input (nil
+// starts an empty one) and returns the result. Values are converted to the
+// type of the field's schema (boolean, integer or number, else string);
+// each assignment to an array field adds an item, replacing any items of
+// the input.
+func SetInputFields(obiPath string, opKey string, bindingKey string, input any, assignments []string) (any, error) {
+	iface, err := resolveInterface(obiPath)
+	if err != nil {
+		return nil, fmt.Errorf("load OBI %q: %w", obiPath, err)
+	}
+	if bindingKey != "" {
+		if b := BindingByKey(bindingKey, iface); b != nil {
+			opKey = b.Operation
+		}
+	}
+	return setInputFields(inputProperties(iface, opKey), input, assignments)
+}
+
+func setInputFields(props map[string]map[string]any, input any, assignments []string) (any, error) {
+	obj := map[string]any{}
+	if input != nil {
+		m, ok := input.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("fields can only be set on an object input")
+		}
+		for k, v := range m {
+			obj[k] = v
+		}
+	}
+
+	added := map[string]bool{}
+	for _, a := range assignments {
+		name, raw, ok := strings.Cut(a, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid field %q (expected name=value)", a)
+		}
+		prop := props[name]
+		isArray := prop["type"] == "array"
+		valueSchema := prop
+		if isArray {
+			valueSchema, _ = prop["items"].(map[string]any)
+		}
+		value, err := fieldValue(valueSchema, raw)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", name, err)
+		}
+		if !isArray {
+			obj[name] = value
+			continue
+		}
+		var items []any
+		if added[name] {
+			items, _ = obj[name].([]any)
+		}
+		obj[name] = append(items, value)
+		added[name] = true
+	}
+	return obj, nil
+}
+
+// fieldValue converts a field's text to the type of its schema.
+func fieldValue(schema map[string]any, raw string) (any, error) {
+	switch schema["type"] {
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", raw)
+		}
+		return b, nil
+	case "integer":
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", raw)
+		}
+		return n, nil
+	case "number":
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", raw)
+		}
+		return f, nil
+	default:
+		return raw, nil
+	}
+}
+
+// OperationFieldCandidates lists the candidate values of every completable
+// field of an operation (see CompletableFields). Fields whose completer
+// fails or lists nothing are left out.
+func OperationFieldCandidates(ctx context.Context, iface *openbindings.Interface, opKey string, obiDir string, contextName string) map[string][]Completion {
+	candidates := map[string][]Completion{}
+	for _, field := range CompletableFields(iface, opKey) {
+		completions, err := CompleteOperationField(ctx, iface, opKey, field, nil, obiDir, contextName)
+		if err == nil && len(completions) > 0 {
+			candidates[field] = completions
+		}
+	}
+	return candidates
+}
+
+// fieldCompleter returns the binding's delegate if it lists field values.
+func fieldCompleter(iface *openbindings.Interface, b *openbindings.BindingEntry) delegates.FieldCompleter {
+	source, ok := iface.Sources[b.Source]
+	if !ok {
+		return nil
+	}
+	handler, err := DefaultRegistry().ForFormat(source.Format)
+	if err != nil {
+		return nil
+	}
+	fc, _ := handler.(delegates.FieldCompleter)
+	return fc
+}
+
+// inputProperties returns the top-level properties of an operation's input
+// schema, with $refs resolved.
+func inputProperties(iface *openbindings.Interface, opKey string) map[string]map[string]any {
+	if iface == nil {
+		return nil
+	}
+	op, ok := iface.Operations[opKey]
+	if !ok || op.Input == nil {
+		return nil
+	}
+	schema := resolveSchemaFully(op.Input, iface)
+	raw, _ := schema["properties"].(map[string]any)
+	props := make(map[string]map[string]any, len(raw))
+	for name, p := range raw {
+		if prop, ok := p.(map[string]any); ok {
+			props[name] = resolveSchemaFully(prop, iface)
+		}
+	}
+	return props
+}
+
+// enumStrings returns the string values of a schema's enum, which is a
+// []any when read from JSON and a []string when generated in process.
+func enumStrings(schema map[string]any) []string {
+	switch enum := schema["enum"].(type) {
+	case []string:
+		return enum
+	case []any:
+		var values []string
+		for _, v := range enum {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
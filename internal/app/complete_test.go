@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	openbindings "github.com/openbindings/openbindings-go"
+)
+
+func completionTestInterface() *openbindings.Interface {
+	return &openbindings.Interface{
+		Operations: map[string]openbindings.Operation{
+			"install": {
+				Kind: openbindings.OperationKindMethod,
+				Input: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"plugin": map[string]any{"type": "string", "x-ob-complete": map[string]any{"run": "mise plugins ls"}},
+						"shell":  map[string]any{"type": "string", "enum": []any{"bash", "zsh"}},
+						"force":  map[string]any{"type": "boolean"},
+					},
+				},
+			},
+		},
+		Sources: map[string]openbindings.Source{
+			"usage": {Format: "usage@2.0.0", Location: "./mise.usage.kdl"},
+		},
+		Bindings: map[string]openbindings.BindingEntry{
+			"install.usage": {Operation: "install", Source: "usage", Ref: "install"},
+		},
+	}
+}
+
+func TestInputFields(t *testing.T) {
+	got := InputFields(completionTestInterface(), "install")
+	if want := []string{"force", "plugin", "shell"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("InputFields = %v, want %v", got, want)
+	}
+}
+
+func TestCompletableFields(t *testing.T) {
+	iface := completionTestInterface()
+	if got, want := CompletableFields(iface, "install"), []string{"plugin"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CompletableFields = %v, want %v", got, want)
+	}
+
+	// The delegate sees other field names behind an input transform.
+	b := iface.Bindings["install.usage"]
+	b.InputTransform = &openbindings.TransformOrRef{Transform: &openbindings.Transform{Type: "jsonata", Expression: "$"}}
+	iface.Bindings["install.usage"] = b
+	if got := CompletableFields(iface, "install"); len(got) != 0 {
+		t.Errorf("CompletableFields with an input transform = %v, want none", got)
+	}
+}
+
+func TestCompleteOperationField_Enum(t *testing.T) {
+	got, err := CompleteOperationField(context.Background(), completionTestInterface(), "install", "shell", nil, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Completion{{Value: "bash"}, {Value: "zsh"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("shell = %v, want %v", got, want)
+	}
+
+	if got, err := CompleteOperationField(context.Background(), completionTestInterface(), "install", "force", nil, "", ""); err != nil || got != nil {
+		t.Errorf("force = %v, %v; want no candidates", got, err)
+	}
+	if _, err := CompleteOperationField(context.Background(), completionTestInterface(), "install", "missing", nil, "", ""); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+func TestSetInputFields(t *testing.T) {
+	props := map[string]map[string]any{
+		"force": {"type": "boolean"},
+		"count": {"type": "integer"},
+		"tags":  {"type": "array", "items": map[string]any{"type": "string"}},
+	}
+	got, err := setInputFields(props, map[string]any{"tags": []any{"old"}, "name": "x"},
+		[]string{"force=true", "count=3", "tags=a", "tags=b", "plugin=node=18"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"force": true, "count": int64(3), "tags": []any{"a", "b"}, "name": "x", "plugin": "node=18"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("setInputFields = %v, want %v", got, want)
+	}
+
+	if _, err := setInputFields(props, nil, []string{"count=many"}); err == nil {
+		t.Error("expected an error for a non-integer value")
+	}
+	if _, err := setInputFields(props, nil, []string{"novalue"}); err == nil {
+		t.Error("expected an error for an assignment without =")
+	}
+	if _, err := setInputFields(props, []any{1}, []string{"force=true"}); err == nil {
+		t.Error("expected an error for a non-object input")
+	}
+}
@@ -30,6 +30,9 @@ const (
 	// ContextsDir is the subdirectory for named context config files.
 	ContextsDir = "contexts"
 
+	// DelegateCacheDir is the subdirectory for cached delegate probe results.
+	DelegateCacheDir = "delegate-cache"
+
 	// KeychainService is the service name used in the OS keychain.
 	KeychainService = "openbindings"
 )
@@ -2,6 +2,7 @@ package app
 
 import (
 	"fmt"
+	"net/url"
 	"sort"
 	"strings"
 
@@ -30,7 +31,8 @@ func RenderBindingContext(ctx delegates.BindingContext) string {
 		len(ctx.Headers) == 0 &&
 		len(ctx.Cookies) == 0 &&
 		len(ctx.Environment) == 0 &&
-		len(ctx.Metadata) == 0
+		len(ctx.Metadata) == 0 &&
+		ctx.Transport == nil
 
 	if empty {
 		sb.WriteString(s.Dim.Render("No context configured"))
@@ -122,9 +124,43 @@ func RenderBindingContext(ctx delegates.BindingContext) string {
 		}
 	}
 
+	if t := ctx.Transport; t != nil {
+		sb.WriteString("\n\n")
+		sb.WriteString(s.Dim.Render("Transport:"))
+		fields := []struct{ label, value string }{
+			{"CA file", t.CAFile},
+			{"Client cert", t.ClientCertFile},
+			{"Client key", t.ClientKeyFile},
+			{"Proxy", redactURL(t.ProxyURL)},
+			{"Server name", t.ServerName},
+		}
+		if t.InsecureSkipVerify {
+			fields = append(fields, struct{ label, value string }{"Insecure skip verify", "true"})
+		}
+		for _, f := range fields {
+			if f.value == "" {
+				continue
+			}
+			sb.WriteString("\n  ")
+			sb.WriteString(s.Bullet.Render("•"))
+			sb.WriteString(" ")
+			sb.WriteString(s.Dim.Render(f.label + ": "))
+			sb.WriteString(f.value)
+		}
+	}
+
 	return sb.String()
 }
 
+// redactURL masks the password in a URL's user info, if any.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Redacted()
+}
+
 // RenderContextList returns a human-friendly list of context summaries.
 func RenderContextList(summaries []ContextSummary) string {
 	s := Styles
@@ -1,12 +1,14 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/openbindings/cli/internal/delegates"
 	"github.com/zalando/go-keyring"
@@ -15,10 +17,12 @@ import (
 // ContextConfig holds the non-secret fields of a named context.
 // Persisted as JSON in ~/.config/openbindings/contexts/<name>.json.
 type ContextConfig struct {
-	Headers     map[string]string `json:"headers,omitempty"`
-	Cookies     map[string]string `json:"cookies,omitempty"`
-	Environment map[string]string `json:"environment,omitempty"`
-	Metadata    map[string]any    `json:"metadata,omitempty"`
+	Headers     map[string]string          `json:"headers,omitempty"`
+	Cookies     map[string]string          `json:"cookies,omitempty"`
+	Environment map[string]string          `json:"environment,omitempty"`
+	Metadata    map[string]any             `json:"metadata,omitempty"`
+	Transport   *delegates.TransportConfig `json:"transport,omitempty"` // Proxy, CA bundle and client certificate
+	Execution   *ExecPolicy                `json:"execution,omitempty"` // Overrides the workspace execution policy
 }
 
 // ContextSummary is a compact representation for listing contexts.
@@ -128,6 +132,30 @@ func SaveContextCredentials(name string, cred *delegates.Credentials) error {
 	return nil
 }
 
+// withContextCredentialSaver returns ctx carrying a delegates.CredentialSaver
+// that writes credentials obtained while an operation runs, such as OAuth
+// tokens, to the keychain entry of the named context. Without a context
+// name ctx is returned as is, and such credentials last only for the
+// process.
+func withContextCredentialSaver(ctx context.Context, contextName string) context.Context {
+	if contextName == "" {
+		return ctx
+	}
+	return delegates.WithCredentialSaver(ctx, func(update func(*delegates.Credentials) *delegates.Credentials) error {
+		credentialUpdates.Lock()
+		defer credentialUpdates.Unlock()
+		stored, err := LoadContextCredentials(contextName)
+		if err != nil {
+			return err
+		}
+		return SaveContextCredentials(contextName, update(stored))
+	})
+}
+
+// credentialUpdates serializes the credential updates of delegates, which
+// read the stored credentials before writing them back.
+var credentialUpdates sync.Mutex
+
 // DeleteContextCredentials removes credentials from the OS keychain.
 func DeleteContextCredentials(name string) error {
 	err := keyring.Delete(KeychainService, name)
@@ -154,6 +182,7 @@ func LoadContext(name string) (delegates.BindingContext, error) {
 		Cookies:     cfg.Cookies,
 		Environment: cfg.Environment,
 		Metadata:    cfg.Metadata,
+		Transport:   cfg.Transport,
 	}, nil
 }
 
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -171,7 +172,7 @@ func deriveSourceKey(src CreateInterfaceSource, index int) string {
 		baseName = baseName[:len(baseName)-len(ext)]
 	}
 	// Remove common suffixes
-	for _, suffix := range []string{".usage", ".openapi", ".asyncapi", ".spec"} {
+	for _, suffix := range []string{".usage", ".openapi", ".asyncapi", ".openrpc", ".spec"} {
 		baseName = strings.TrimSuffix(baseName, suffix)
 	}
 
@@ -237,23 +238,42 @@ func processSource(iface *openbindings.Interface, src CreateInterfaceSource, ind
 	// Determine binding source key using smart derivation.
 	sourceKey := deriveSourceKey(src, index)
 
+	generated, err := createFromSource(src)
+	if err != nil {
+		return err
+	}
+
+	// Merge generated content into the target interface.
+	return mergeGeneratedSource(iface, &generated, src, sourceKey)
+}
+
+// createFromSource converts a source to an Interface. Sources whose format
+// resolves to an HTTP delegate are converted by that delegate; everything else
+// uses the builtin handler registry.
+func createFromSource(src CreateInterfaceSource) (openbindings.Interface, error) {
+	wsCtx := GetWorkspaceDelegateContext()
+	resolved, err := delegates.Resolve(delegates.ResolveParams{
+		Format:              src.Format,
+		DelegatePreferences: wsCtx.DelegatePreferences,
+		WorkspaceDelegates:  wsCtx.Delegates,
+		Invoker:             invokeDelegateBinding,
+		Cache:               delegateProbeCache(),
+	}, BuiltinSupportsFormat)
+	if err == nil && resolved.Source == delegates.SourceWorkspace && delegates.IsHTTPURL(resolved.Location) {
+		return createInterfaceViaHTTPDelegate(context.Background(), resolved.Location, src)
+	}
+
 	// Look up the handler for this format.
 	handler, err := DefaultRegistry().ForFormat(src.Format)
 	if err != nil {
-		return err
+		return openbindings.Interface{}, err
 	}
 
 	// Let the handler convert the source to an Interface.
-	generated, err := handler.CreateInterface(delegates.Source{
+	return handler.CreateInterface(delegates.Source{
 		Format:   src.Format,
 		Location: src.Location,
 	})
-	if err != nil {
-		return err
-	}
-
-	// Merge generated content into the target interface.
-	return mergeGeneratedSource(iface, &generated, src, sourceKey)
 }
 
 // mergeGeneratedSource merges a handler-generated Interface into the target,
@@ -279,6 +299,9 @@ func mergeGeneratedSource(iface *openbindings.Interface, generated *openbindings
 		SetXOB(&op.LosslessFields)
 		iface.Operations[key] = op
 	}
+	if conflicts := addSchemas(iface, sourceKey, generated.Schemas); len(conflicts) > 0 {
+		return fmt.Errorf("duplicate schema %q", strings.TrimPrefix(conflicts[0].Object, "schema:"))
+	}
 
 	// Create source entry.
 	bsrc := openbindings.Source{
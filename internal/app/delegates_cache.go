@@ -0,0 +1,43 @@
+// Package app - delegates_cache.go wires the on-disk delegate probe cache.
+package app
+
+import (
+	"path/filepath"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// delegateCacheDirFunc is the resolver for the delegate probe cache directory.
+// Override in tests to use a temp directory.
+var delegateCacheDirFunc = defaultDelegateCacheDir
+
+func defaultDelegateCacheDir() (string, error) {
+	globalPath, err := GlobalConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(globalPath, DelegateCacheDir), nil
+}
+
+// delegateProbeCache returns the delegate probe cache, or nil when the global
+// config directory cannot be determined (delegates are then probed uncached).
+func delegateProbeCache() *delegates.ProbeCache {
+	dir, err := delegateCacheDirFunc()
+	if err != nil {
+		return nil
+	}
+	return delegates.NewProbeCache(dir)
+}
+
+// probeDelegate returns a delegate's probe result, served from the cache when
+// it is still fresh. force bypasses the cache and re-probes.
+func probeDelegate(loc string, force bool) (*delegates.ProbeCacheEntry, error) {
+	if cache := delegateProbeCache(); cache != nil {
+		return cache.Probe(loc, delegates.DefaultProbeTimeout, invokeDelegateBinding, force)
+	}
+	formats, err := delegates.ProbeFormats(loc, delegates.DefaultProbeTimeout, invokeDelegateBinding)
+	if err != nil {
+		return nil, err
+	}
+	return &delegates.ProbeCacheEntry{Location: loc, Formats: formats}, nil
+}
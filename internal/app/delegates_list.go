@@ -2,7 +2,9 @@
 package app
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/openbindings/cli/internal/delegates"
 	"github.com/openbindings/openbindings-go"
@@ -28,6 +30,7 @@ type DelegateListEntry struct {
 	Description string               `json:"description,omitempty"`
 	Source      string               `json:"source"`
 	Location    string               `json:"location,omitempty"`
+	CachedAt    string               `json:"cachedAt,omitempty"` // when the cached probe result was taken
 	Formats     []DelegateFormatInfo `json:"formats"`
 }
 
@@ -87,6 +90,9 @@ func (o DelegateListOutput) Render() string {
 			if p.Location != "" {
 				sb.WriteString(s.Dim.Render(" " + p.Location))
 			}
+			if age := cacheAge(p.CachedAt); age != "" {
+				sb.WriteString(s.Dim.Render(" (cached " + age + ")"))
+			}
 			if p.Description != "" {
 				sb.WriteString("\n      ")
 				sb.WriteString(s.Dim.Render(p.Description))
@@ -98,6 +104,25 @@ func (o DelegateListOutput) Render() string {
 	return sb.String()
 }
 
+// cacheAge renders the age of an RFC 3339 timestamp, e.g. "5m ago".
+func cacheAge(ts string) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return ""
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	}
+}
+
 func renderDelegateFormats(sb *strings.Builder, p DelegateListEntry, s styles) {
 	if len(p.Formats) == 0 {
 		sb.WriteString("\n      ")
@@ -178,12 +203,15 @@ func BuildDelegateListOutput(params DelegateListParams) DelegateListOutput {
 				Description: "OpenBindings interface format",
 			})
 		} else {
-			// Probe external delegate for formats
-			delegateFormats, err := delegates.ProbeFormats(p.Location, delegates.DefaultProbeTimeout)
+			// Probe external delegate for formats (served from the cache when fresh)
+			probed, err := probeDelegate(p.Location, false)
 			if err == nil {
-				for _, f := range delegateFormats {
+				for _, f := range probed.Formats {
 					formatInfos = append(formatInfos, delegates.FormatInfo{Token: f})
 				}
+				if !probed.ProbedAt.IsZero() {
+					entry.CachedAt = probed.ProbedAt.Format(time.RFC3339)
+				}
 			}
 		}
 
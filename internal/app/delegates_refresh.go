@@ -0,0 +1,101 @@
+// Package app - delegates_refresh.go contains the CLI command for refreshing cached delegate probes.
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// DelegateRefreshParams configures the delegate refresh command.
+type DelegateRefreshParams struct {
+	URL          string // when empty, every workspace delegate is refreshed
+	OutputFormat string
+	OutputPath   string
+}
+
+// DelegateRefreshEntry is the refreshed probe result for one delegate.
+type DelegateRefreshEntry struct {
+	Delegate string   `json:"delegate"`
+	Formats  []string `json:"formats,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// DelegateRefreshOutput is the output of the delegate refresh operation.
+type DelegateRefreshOutput struct {
+	Refreshed []DelegateRefreshEntry `json:"refreshed"`
+}
+
+// Render returns a human-friendly representation.
+func (o DelegateRefreshOutput) Render() string {
+	s := Styles
+	var sb strings.Builder
+
+	if len(o.Refreshed) == 0 {
+		return s.Dim.Render("No delegates in workspace")
+	}
+
+	sb.WriteString(s.Header.Render("Refreshed delegates:"))
+	for _, r := range o.Refreshed {
+		sb.WriteString("\n  ")
+		sb.WriteString(s.Key.Render(r.Delegate))
+		if r.Error != "" {
+			sb.WriteString("  ")
+			sb.WriteString(s.Error.Render("✗ " + r.Error))
+			continue
+		}
+		sb.WriteString(s.Dim.Render(fmt.Sprintf(" (%d formats)", len(r.Formats))))
+	}
+	return sb.String()
+}
+
+// DelegateRefresh re-probes workspace delegates and replaces their cached
+// probe results. Refreshing every delegate also clears entries for delegates
+// that are no longer in the workspace.
+func DelegateRefresh(params DelegateRefreshParams) error {
+	ws, _, _, err := RequireActiveWorkspace()
+	if err != nil {
+		return err
+	}
+
+	cache := delegateProbeCache()
+	if cache == nil {
+		return exitText(1, "delegate cache unavailable: cannot determine config directory", true)
+	}
+
+	targets := ws.Delegates
+	if url := strings.TrimSpace(params.URL); url != "" {
+		// Normalize local paths to the stored exec: form.
+		if delegates.IsLocalPath(url) {
+			url = delegates.ExecScheme + url
+		}
+		found := false
+		for _, d := range ws.Delegates {
+			if d == url {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return exitText(1, fmt.Sprintf("delegate %q not found in workspace", url), true)
+		}
+		targets = []string{url}
+	} else if err := cache.Clear(); err != nil {
+		return exitText(1, err.Error(), true)
+	}
+
+	output := DelegateRefreshOutput{Refreshed: []DelegateRefreshEntry{}}
+	for _, loc := range targets {
+		entry := DelegateRefreshEntry{Delegate: loc}
+		probed, err := cache.Probe(loc, delegates.DefaultProbeTimeout, invokeDelegateBinding, true)
+		if err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.Formats = probed.Formats
+		}
+		output.Refreshed = append(output.Refreshed, entry)
+	}
+
+	return OutputResult(output, params.OutputFormat, params.OutputPath)
+}
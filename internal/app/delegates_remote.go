@@ -0,0 +1,208 @@
+// Package app - delegates_remote.go contains support for HTTP-based delegates.
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/openbindings/cli/internal/delegates"
+	"github.com/openbindings/openbindings-go"
+)
+
+// invokeDelegateBinding implements delegates.BindingInvoker using the builtin
+// handlers. It selects the highest-priority binding for opKey in the delegate's
+// interface, applies its transforms, and executes it.
+func invokeDelegateBinding(ctx context.Context, iface *openbindings.Interface, ifaceURL string, opKey string, input any) (any, error) {
+	return invokeDelegateBindingWith(ctx, iface, ifaceURL, opKey, input, nil)
+}
+
+// invokeDelegateBindingWith is invokeDelegateBinding with a binding context
+// for the call to the delegate.
+func invokeDelegateBindingWith(ctx context.Context, iface *openbindings.Interface, ifaceURL string, opKey string, input any, bindCtx *delegates.BindingContext) (any, error) {
+	_, binding := DefaultBindingForOp(opKey, iface)
+	if binding == nil {
+		return nil, fmt.Errorf("delegate interface has no binding for %s", opKey)
+	}
+	source, ok := iface.Sources[binding.Source]
+	if !ok {
+		return nil, fmt.Errorf("binding source %q not found for %s", binding.Source, opKey)
+	}
+
+	execInput := input
+	if binding.InputTransform != nil {
+		transformed, err := ApplyTransform(iface.Transforms, binding.InputTransform, input)
+		if err != nil {
+			return nil, fmt.Errorf("input transform failed: %w", err)
+		}
+		execInput = transformed
+	}
+
+	handler, err := DefaultRegistry().ForFormat(source.Format)
+	if err != nil {
+		return nil, fmt.Errorf("no builtin handler for delegate binding format %q", source.Format)
+	}
+
+	src := delegates.Source{Format: source.Format}
+	if source.Location != "" {
+		src.Location = delegates.ResolveSourceURL(ifaceURL, source.Location)
+	} else {
+		src.Content = source.Content
+	}
+
+	result := handler.ExecuteOperation(ctx, delegates.ExecuteInput{
+		Source:  src,
+		Ref:     binding.Ref,
+		Input:   execInput,
+		Context: bindCtx,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("%s: %s", opKey, result.Error.Message)
+	}
+
+	output := result.Output
+	if binding.OutputTransform != nil {
+		transformed, err := ApplyTransform(iface.Transforms, binding.OutputTransform, output)
+		if err != nil {
+			return nil, fmt.Errorf("output transform failed: %w", err)
+		}
+		output = transformed
+	}
+	return output, nil
+}
+
+// fetchDelegateInterface fetches an HTTP delegate's interface, with the
+// transport of bindCtx, and returns it together with the URL it was fetched
+// from. A fresh probe cache entry is used instead of fetching when available.
+func fetchDelegateInterface(loc string, bindCtx *delegates.BindingContext) (*openbindings.Interface, string, error) {
+	ifaceURL, err := delegates.WellKnownURL(loc)
+	if err != nil {
+		return nil, "", err
+	}
+	if entry, err := probeDelegate(loc, false); err == nil && entry.Interface != nil {
+		return entry.Interface, ifaceURL, nil
+	}
+	iface, err := delegates.FetchOpenBindings(ifaceURL, delegates.DefaultFetchTimeout, bindCtx)
+	if err != nil {
+		return nil, "", err
+	}
+	return &iface, ifaceURL, nil
+}
+
+// executeViaHTTPDelegate executes an operation via an HTTP delegate's
+// executeOperation binding.
+func executeViaHTTPDelegate(ctx context.Context, delegateURL string, input ExecuteOperationInput) ExecuteOperationOutput {
+	transport := transportContext(input.Context)
+	iface, ifaceURL, err := fetchDelegateInterface(delegateURL, transport)
+	if err != nil {
+		return ExecuteOperationOutput{
+			Error: &Error{Code: "delegate_unreachable", Message: err.Error()},
+		}
+	}
+
+	// A remote delegate cannot read our local files, so send their content instead.
+	if input.Source.Content == nil && isLocalSourceFile(input.Source.Location) {
+		data, err := os.ReadFile(input.Source.Location)
+		if err != nil {
+			return ExecuteOperationOutput{
+				Error: &Error{Code: "source_read_failed", Message: fmt.Sprintf("read source: %v", err)},
+			}
+		}
+		input.Source.Content = string(data)
+		input.Source.Location = ""
+	}
+
+	payload, err := NormalizeJSON(input)
+	if err != nil {
+		return ExecuteOperationOutput{
+			Error: &Error{Code: "json_marshal_error", Message: fmt.Sprintf("failed to marshal input: %v", err)},
+		}
+	}
+
+	raw, err := invokeDelegateBindingWith(ctx, iface, ifaceURL, delegates.OpExecuteOperation, payload, transport)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ExecuteOperationOutput{
+				Error: &Error{Code: "cancelled", Message: "operation cancelled"},
+			}
+		}
+		return ExecuteOperationOutput{
+			Error: &Error{Code: "execution_failed", Message: err.Error()},
+		}
+	}
+
+	// Parse output as ExecuteOperationOutput
+	var output ExecuteOperationOutput
+	data, err := json.Marshal(raw)
+	if err != nil || json.Unmarshal(data, &output) != nil {
+		// If we can't parse the envelope, wrap raw output
+		output = ExecuteOperationOutput{Output: raw}
+	}
+	return output
+}
+
+// transportContext returns a binding context carrying only the transport
+// settings of bindCtx, for reaching a delegate through the same proxy and
+// TLS configuration without sending it the call's credentials as headers.
+func transportContext(bindCtx *delegates.BindingContext) *delegates.BindingContext {
+	if bindCtx == nil || bindCtx.Transport == nil {
+		return nil
+	}
+	return &delegates.BindingContext{Transport: bindCtx.Transport}
+}
+
+// isLocalSourceFile reports whether a source location names a local file,
+// whose content must be sent to a remote delegate. URLs, exec: references
+// and addresses such as "host:port" that are not existing files are passed
+// through as locations.
+func isLocalSourceFile(loc string) bool {
+	if loc == "" || strings.Contains(loc, "://") || delegates.IsExecURL(loc) {
+		return false
+	}
+	if delegates.IsLocalPath(loc) || !strings.Contains(loc, ":") {
+		return true
+	}
+	_, err := os.Stat(loc)
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+// createInterfaceViaHTTPDelegate converts a source to an interface via an
+// HTTP delegate's createInterface binding. Local files are sent as content.
+func createInterfaceViaHTTPDelegate(ctx context.Context, delegateURL string, src CreateInterfaceSource) (openbindings.Interface, error) {
+	iface, ifaceURL, err := fetchDelegateInterface(delegateURL, nil)
+	if err != nil {
+		return openbindings.Interface{}, err
+	}
+
+	source := map[string]any{"format": src.Format}
+	if isLocalSourceFile(src.Location) {
+		data, err := os.ReadFile(src.Location)
+		if err != nil {
+			return openbindings.Interface{}, fmt.Errorf("read source: %w", err)
+		}
+		source["content"] = string(data)
+	} else {
+		source["location"] = src.Location
+	}
+
+	raw, err := invokeDelegateBinding(ctx, iface, ifaceURL, delegates.OpCreateInterface, map[string]any{
+		"sources": []any{source},
+	})
+	if err != nil {
+		return openbindings.Interface{}, err
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return openbindings.Interface{}, fmt.Errorf("marshal delegate output: %w", err)
+	}
+	var generated openbindings.Interface
+	if err := json.Unmarshal(data, &generated); err != nil {
+		return openbindings.Interface{}, fmt.Errorf("invalid interface from delegate: %w", err)
+	}
+	return generated, nil
+}
@@ -0,0 +1,291 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// useTempDelegateCache points the delegate probe cache at a temp directory.
+func useTempDelegateCache(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	delegateCacheDirFunc = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { delegateCacheDirFunc = defaultDelegateCacheDir })
+	return dir
+}
+
+// newStandInDelegate starts an HTTP delegate that publishes an OBI whose
+// binding-format-handler operations are bound to a small OpenAPI document.
+func newStandInDelegate(t *testing.T) *httptest.Server {
+	t.Helper()
+	useTempDelegateCache(t)
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/.well-known/openbindings", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"openbindings": "0.1.0",
+			"name": "Acme Delegate",
+			"operations": {
+				"listFormats": {"kind": "method"},
+				"createInterface": {"kind": "method"},
+				"executeOperation": {"kind": "method"}
+			},
+			"sources": {
+				"api": {"format": "openapi@3.1", "location": "/openapi.json"}
+			},
+			"bindings": {
+				"listFormats.api": {"operation": "listFormats", "source": "api", "ref": "#/paths/~1formats/get"},
+				"createInterface.api": {"operation": "createInterface", "source": "api", "ref": "#/paths/~1create/post"},
+				"executeOperation.api": {"operation": "executeOperation", "source": "api", "ref": "#/paths/~1execute/post"}
+			}
+		}`)
+	})
+	mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{
+			"openapi": "3.1.0",
+			"info": {"title": "Acme Delegate", "version": "1.0.0"},
+			"servers": [{"url": %q}],
+			"paths": {
+				"/formats": {"get": {"responses": {"200": {"description": "ok"}}}},
+				"/create": {"post": {
+					"requestBody": {"content": {"application/json": {"schema": {"type": "object"}}}},
+					"responses": {"200": {"description": "ok"}}
+				}},
+				"/execute": {"post": {
+					"requestBody": {"content": {"application/json": {"schema": {"type": "object"}}}},
+					"responses": {"200": {"description": "ok"}}
+				}}
+			}
+		}`, srv.URL)
+	})
+	mux.HandleFunc("/formats", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"token": "acme@^1.0.0", "description": "Acme specs"}]`)
+	})
+	mux.HandleFunc("/create", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sources, _ := body["sources"].([]any)
+		if len(sources) != 1 {
+			http.Error(w, "expected one source", http.StatusBadRequest)
+			return
+		}
+		src, _ := sources[0].(map[string]any)
+		if src["content"] != "ping-spec" {
+			http.Error(w, "expected inline content", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"openbindings": "0.1.0",
+			"name": "Acme API",
+			"operations": {"ping": {"kind": "method"}},
+			"sources": {"acme": {"format": "acme@1.0.0"}},
+			"bindings": {"ping.acme": {"operation": "ping", "source": "acme", "ref": "ping"}}
+		}`)
+	})
+	mux.HandleFunc("/execute", func(w http.ResponseWriter, r *http.Request) {
+		var body ExecuteOperationInput
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ExecuteOperationOutput{
+			Output: map[string]any{"ref": body.Ref, "input": body.Input, "content": body.Source.Content},
+			Status: 0,
+		})
+	})
+
+	return srv
+}
+
+func TestProbeFormats_HTTPDelegate(t *testing.T) {
+	srv := newStandInDelegate(t)
+
+	formats, err := delegates.ProbeFormats(srv.URL, delegates.DefaultProbeTimeout, invokeDelegateBinding)
+	if err != nil {
+		t.Fatalf("ProbeFormats: %v", err)
+	}
+	if len(formats) != 1 || formats[0] != "acme@^1.0.0" {
+		t.Fatalf("formats = %v, want [acme@^1.0.0]", formats)
+	}
+}
+
+func TestProbeFormats_HTTPDelegateWithoutInvoker(t *testing.T) {
+	srv := newStandInDelegate(t)
+
+	if _, err := delegates.ProbeFormats(srv.URL, delegates.DefaultProbeTimeout, nil); err == nil {
+		t.Fatal("expected error without an invoker")
+	}
+}
+
+func TestResolve_HTTPDelegate(t *testing.T) {
+	srv := newStandInDelegate(t)
+
+	resolved, err := delegates.Resolve(delegates.ResolveParams{
+		Format:             "acme@1.2.0",
+		WorkspaceDelegates: []string{srv.URL},
+		Invoker:            invokeDelegateBinding,
+	}, BuiltinSupportsFormat)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if resolved.Source != delegates.SourceWorkspace || resolved.Location != srv.URL {
+		t.Fatalf("resolved = %+v, want workspace delegate at %s", resolved, srv.URL)
+	}
+}
+
+func TestExecuteViaExternalDelegate_HTTP(t *testing.T) {
+	srv := newStandInDelegate(t)
+
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "acme.spec")
+	if err := os.WriteFile(specPath, []byte("acme-spec"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	resolved := delegates.Resolved{Format: "acme@1.0.0", Delegate: "acme", Source: delegates.SourceWorkspace, Location: srv.URL}
+	out := executeViaExternalDelegate(context.Background(), resolved, ExecuteOperationInput{
+		Source: ExecuteSource{Format: "acme@1.0.0", Location: specPath},
+		Ref:    "ping",
+		Input:  map[string]any{"n": float64(1)},
+	})
+	if out.Error != nil {
+		t.Fatalf("unexpected error: %s", out.Error.Message)
+	}
+
+	m, ok := out.Output.(map[string]any)
+	if !ok {
+		t.Fatalf("output is %T, want map", out.Output)
+	}
+	if m["ref"] != "ping" {
+		t.Errorf("ref = %v, want ping", m["ref"])
+	}
+	if m["content"] != "acme-spec" {
+		t.Errorf("content = %v, want local file content to be inlined", m["content"])
+	}
+	if in, _ := m["input"].(map[string]any); in["n"] != float64(1) {
+		t.Errorf("input = %v, want {n:1}", m["input"])
+	}
+}
+
+func TestExecuteViaExternalDelegate_HTTPUnreadableSource(t *testing.T) {
+	srv := newStandInDelegate(t)
+
+	resolved := delegates.Resolved{Format: "acme@1.0.0", Delegate: "acme", Source: delegates.SourceWorkspace, Location: srv.URL}
+	out := executeViaExternalDelegate(context.Background(), resolved, ExecuteOperationInput{
+		Source: ExecuteSource{Format: "acme@1.0.0", Location: filepath.Join(t.TempDir(), "missing.spec")},
+		Ref:    "ping",
+	})
+	if out.Error == nil || out.Error.Code != "source_read_failed" {
+		t.Fatalf("error = %+v, want source_read_failed", out.Error)
+	}
+}
+
+func TestIsLocalSourceFile(t *testing.T) {
+	for loc, want := range map[string]bool{
+		"./openapi.yaml":             true,
+		"openapi.yaml":               true,
+		"https://example.com/a.json": false,
+		"exec:my-cli":                false,
+		"localhost:50051":            false,
+		"":                           false,
+	} {
+		if got := isLocalSourceFile(loc); got != want {
+			t.Errorf("isLocalSourceFile(%q) = %v, want %v", loc, got, want)
+		}
+	}
+}
+
+func TestCreateInterfaceViaHTTPDelegate(t *testing.T) {
+	srv := newStandInDelegate(t)
+
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "ping.spec")
+	if err := os.WriteFile(specPath, []byte("ping-spec"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	iface, err := createInterfaceViaHTTPDelegate(context.Background(), srv.URL, CreateInterfaceSource{
+		Format:   "acme@1.0.0",
+		Location: specPath,
+	})
+	if err != nil {
+		t.Fatalf("createInterfaceViaHTTPDelegate: %v", err)
+	}
+	if _, ok := iface.Operations["ping"]; !ok {
+		t.Fatalf("operations = %v, want ping", iface.Operations)
+	}
+	if b, ok := iface.Bindings["ping.acme"]; !ok || b.Ref != "ping" {
+		t.Fatalf("bindings = %v, want ping.acme with ref ping", iface.Bindings)
+	}
+}
+
+func TestProbeDelegate_CachesHTTPDelegate(t *testing.T) {
+	srv := newStandInDelegate(t)
+
+	first, err := probeDelegate(srv.URL, false)
+	if err != nil {
+		t.Fatalf("probeDelegate: %v", err)
+	}
+	if first.Interface == nil || first.ProbedAt.IsZero() {
+		t.Fatalf("entry = %+v, want cached interface and timestamp", first)
+	}
+
+	// The cached interface is reused for execution.
+	srv.Close()
+	iface, _, err := fetchDelegateInterface(srv.URL, nil)
+	if err != nil {
+		t.Fatalf("fetchDelegateInterface with server down: %v", err)
+	}
+	if iface.Name != "Acme Delegate" {
+		t.Fatalf("interface name = %q, want cached interface", iface.Name)
+	}
+
+	// A forced probe fails and drops the entry.
+	if _, err := probeDelegate(srv.URL, true); err == nil {
+		t.Fatal("expected forced probe to fail with the server down")
+	}
+	if entry := delegateProbeCache().Load(srv.URL); entry != nil {
+		t.Fatalf("cache entry = %+v, want nil after failed probe", entry)
+	}
+}
+
+func TestCacheAge(t *testing.T) {
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{10 * time.Second, "just now"},
+		{5 * time.Minute, "5m ago"},
+		{3 * time.Hour, "3h ago"},
+		{50 * time.Hour, "2d ago"},
+	}
+	for _, tt := range tests {
+		ts := time.Now().Add(-tt.ago).Format(time.RFC3339)
+		if got := cacheAge(ts); got != tt.want {
+			t.Errorf("cacheAge(-%v) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+	if got := cacheAge(""); got != "" {
+		t.Errorf("cacheAge(\"\") = %q, want empty", got)
+	}
+}
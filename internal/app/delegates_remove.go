@@ -62,6 +62,11 @@ func DelegateRemove(params DelegateRemoveParams) error {
 		return exitText(1, err.Error(), true)
 	}
 
+	// Drop any cached probe result for the removed delegate
+	if cache := delegateProbeCache(); cache != nil {
+		_ = cache.Invalidate(url)
+	}
+
 	result := struct {
 		Removed   string `json:"removed"`
 		Delegate  string `json:"delegate"`
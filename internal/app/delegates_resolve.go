@@ -28,6 +28,8 @@ func DelegateResolve(params DelegateResolveParams) error {
 		Format:              params.Format,
 		DelegatePreferences: wsCtx.DelegatePreferences,
 		WorkspaceDelegates:  wsCtx.Delegates,
+		Invoker:             invokeDelegateBinding,
+		Cache:               delegateProbeCache(),
 	}, BuiltinSupportsFormat)
 	if err != nil {
 		return exitText(1, err.Error(), true)
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/openbindings/cli/internal/delegates"
+	"github.com/openbindings/cli/internal/execref"
 	"github.com/openbindings/openbindings-go"
 )
 
@@ -20,6 +21,10 @@ type DeriveResult struct {
 	// set to sourceKey. Keyed by binding name.
 	Bindings map[string]openbindings.BindingEntry
 
+	// Schemas are the shared schemas the derived operations reference
+	// with "#/schemas/<name>". Nil when the source produces none.
+	Schemas map[string]openbindings.JSONSchema
+
 	// Metadata from the derived interface (Name, Description, Version).
 	// May be empty if the source doesn't provide metadata.
 	Name        string
@@ -45,9 +50,10 @@ func DeriveFromSource(source openbindings.Source, sourceKey string, obiDir strin
 	}
 
 	// Resolve source location relative to OBI directory (D5).
-	// URIs (contain ://), absolute paths, and host:port addresses pass through.
+	// URIs (contain ://), exec: references, absolute paths, and host:port
+	// addresses pass through.
 	locationPath := source.Location
-	if locationPath != "" && obiDir != "" && !filepath.IsAbs(locationPath) && !strings.Contains(locationPath, "://") && !isHostPort(locationPath) {
+	if locationPath != "" && obiDir != "" && !filepath.IsAbs(locationPath) && !strings.Contains(locationPath, "://") && !execref.IsExec(locationPath) && !isHostPort(locationPath) {
 		locationPath = filepath.Join(obiDir, locationPath)
 	}
 
@@ -80,6 +86,7 @@ func DeriveFromSource(source openbindings.Source, sourceKey string, obiDir strin
 	return DeriveResult{
 		Operations:  generated.Operations,
 		Bindings:    bindings,
+		Schemas:     generated.Schemas,
 		Name:        generated.Name,
 		Description: generated.Description,
 		Version:     generated.Version,
@@ -171,6 +178,9 @@ func deriveFromAllSources(iface *openbindings.Interface, obiDir string, onlySour
 		if src, ok := iface.Sources[ps.key]; ok {
 			assembled.Sources[ps.key] = src
 		}
+		for _, c := range addSchemas(assembled, ps.key, ps.result.Schemas) {
+			warnings = append(warnings, fmt.Sprintf("source %q: %s differs from another source's schema of the same name", ps.key, c.Object))
+		}
 	}
 
 	return deriveSourcesResult{
@@ -20,10 +20,11 @@ import (
 
 // ExecuteSource represents the binding source for execution.
 type ExecuteSource struct {
-	Format   string `json:"format"`
-	Location string `json:"location,omitempty"`
-	Content  any    `json:"content,omitempty"`
-	Binary   string `json:"binary,omitempty"` // Optional: binary name hint for CLI execution
+	Format     string                     `json:"format"`
+	Location   string                     `json:"location,omitempty"`
+	Content    any                        `json:"content,omitempty"`
+	Binary     string                     `json:"binary,omitempty"`     // Optional: binary name hint for CLI execution
+	Extensions map[string]json.RawMessage `json:"extensions,omitempty"` // Optional: x-* fields of the OBI source
 }
 
 // ExecuteOperationInput is the input for executeOperation.
@@ -40,6 +41,19 @@ type ExecuteOperationOutput struct {
 	Status     int    `json:"status,omitempty"`
 	DurationMs int64  `json:"durationMs,omitempty"`
 	Error      *Error `json:"error,omitempty"`
+
+	// Attempts is the number of attempts made when the execution policy
+	// retried the operation. Omitted when the first attempt was final.
+	Attempts int `json:"attempts,omitempty"`
+
+	// Links holds response link relations (rel → URL), used to follow
+	// pagination. Not rendered.
+	Links map[string]string `json:"links,omitempty"`
+
+	// Headers and Trailers hold response metadata from protocols that
+	// report it separately from the output (gRPC). Not rendered.
+	Headers  map[string][]string `json:"headers,omitempty"`
+	Trailers map[string][]string `json:"trailers,omitempty"`
 }
 
 // Render returns a human-friendly representation.
@@ -70,6 +84,12 @@ func (o ExecuteOperationOutput) Render() string {
 		sb.WriteString("\n")
 	}
 
+	if o.Attempts > 1 {
+		sb.WriteString(s.Dim.Render("Attempts: "))
+		sb.WriteString(fmt.Sprintf("%d", o.Attempts))
+		sb.WriteString("\n")
+	}
+
 	if o.Output != nil {
 		sb.WriteString(s.Dim.Render("Output: "))
 		switch v := o.Output.(type) {
@@ -165,6 +185,112 @@ func IsEventBinding(obiPath string, bindingKey string) bool {
 	return op.Kind == "event"
 }
 
+// IsInputStreamOperation returns true if the named operation consumes a
+// stream of input messages (its x-ob-streaming extension is "client" or
+// "bidi"). Returns false on any error.
+func IsInputStreamOperation(obiPath string, opKey string) bool {
+	iface, err := resolveInterface(obiPath)
+	if err != nil {
+		return false
+	}
+	op, ok := iface.Operations[opKey]
+	return ok && operationStreamsInput(op)
+}
+
+// IsInputStreamBinding returns true if the binding's operation consumes a
+// stream of input messages. Returns false on any error.
+func IsInputStreamBinding(obiPath string, bindingKey string) bool {
+	iface, err := resolveInterface(obiPath)
+	if err != nil {
+		return false
+	}
+	b := BindingByKey(bindingKey, iface)
+	if b == nil {
+		return false
+	}
+	op, ok := iface.Operations[b.Operation]
+	return ok && operationStreamsInput(op)
+}
+
+// IsOutputStreamOperation returns true if the named operation's output can
+// be streamed while it runs (see OperationStreamsOutput). Returns false on
+// any error.
+func IsOutputStreamOperation(obiPath string, opKey string) bool {
+	iface, err := resolveInterface(obiPath)
+	if err != nil {
+		return false
+	}
+	return OperationStreamsOutput(iface, opKey)
+}
+
+// IsOutputStreamBinding returns true if the output of the binding's
+// operation can be streamed while it runs. Returns false on any error.
+func IsOutputStreamBinding(obiPath string, bindingKey string) bool {
+	iface, err := resolveInterface(obiPath)
+	if err != nil {
+		return false
+	}
+	b := BindingByKey(bindingKey, iface)
+	return b != nil && bindingStreamsOutput(iface, b)
+}
+
+// OperationStreamsOutput reports whether the output of a method operation
+// can be streamed while it runs through SubscribeOBIOperation (e.g., the
+// lines a CLI command prints): its default binding's delegate supports it
+// (delegates.MethodStreamHandler) and the binding has no output transform,
+// which needs the complete output.
+func OperationStreamsOutput(iface *openbindings.Interface, opKey string) bool {
+	_, b := DefaultBindingForOp(opKey, iface)
+	return b != nil && bindingStreamsOutput(iface, b)
+}
+
+func bindingStreamsOutput(iface *openbindings.Interface, b *openbindings.BindingEntry) bool {
+	op, ok := iface.Operations[b.Operation]
+	if !ok || op.Kind == "event" || operationStreamsInput(op) || b.OutputTransform != nil {
+		return false
+	}
+	source, ok := iface.Sources[b.Source]
+	if !ok {
+		return false
+	}
+	handler, err := DefaultRegistry().ForFormat(source.Format)
+	if err != nil {
+		return false
+	}
+	msh, ok := handler.(delegates.MethodStreamHandler)
+	return ok && msh.StreamsMethodOutput()
+}
+
+// OutputLine returns the text of a streamed output line event ({"stdout":
+// line} or {"stderr": line}, as sent for CLI operations) and whether it was
+// written to stderr. ok is false for other events, such as parsed NDJSON
+// values.
+func OutputLine(data any) (line string, stderr bool, ok bool) {
+	m, isMap := data.(map[string]any)
+	if !isMap || len(m) != 1 {
+		return "", false, false
+	}
+	if line, ok := m["stdout"].(string); ok {
+		return line, false, true
+	}
+	if line, ok := m["stderr"].(string); ok {
+		return line, true, true
+	}
+	return "", false, false
+}
+
+func operationStreamsInput(op openbindings.Operation) bool {
+	raw, ok := op.Extensions[delegates.StreamingKey]
+	if !ok {
+		return false
+	}
+	var mode string
+	if err := json.Unmarshal(raw, &mode); err != nil {
+		return false
+	}
+	return mode == delegates.StreamingClient || mode == delegates.StreamingBidi
+}
+
 // resolvedBinding holds the resolved components for an OBI operation execution.
 type resolvedBinding struct {
 	binding *openbindings.BindingEntry
@@ -233,11 +359,44 @@ func resolveBindingAndSource(iface *openbindings.Interface, opKey, bindingKey st
 	}, nil
 }
 
+// callMetadataKey is the context key of per-call metadata (see WithMetadata).
+type callMetadataKey struct{}
+
+// WithMetadata attaches metadata entries to ctx that are layered over the
+// named context's metadata for the operations executed under it, such as a
+// per-call "responseFile".
+func WithMetadata(ctx context.Context, metadata map[string]any) context.Context {
+	return context.WithValue(ctx, callMetadataKey{}, metadata)
+}
+
+// withCallMetadata returns bindCtx with the per-call metadata of ctx layered
+// over its own. bindCtx itself is not modified.
+func withCallMetadata(ctx context.Context, bindCtx *delegates.BindingContext) *delegates.BindingContext {
+	metadata, _ := ctx.Value(callMetadataKey{}).(map[string]any)
+	if len(metadata) == 0 {
+		return bindCtx
+	}
+	merged := delegates.BindingContext{}
+	if bindCtx != nil {
+		merged = *bindCtx
+	}
+	merged.Metadata = make(map[string]any, len(merged.Metadata)+len(metadata))
+	if bindCtx != nil {
+		for k, v := range bindCtx.Metadata {
+			merged.Metadata[k] = v
+		}
+	}
+	for k, v := range metadata {
+		merged.Metadata[k] = v
+	}
+	return &merged
+}
+
 // resolveSourceLocation resolves a source location relative to the OBI directory.
 // exec: refs, URIs, absolute paths, and host:port addresses pass through unchanged;
 // relative file paths are joined with obiDir.
 func resolveSourceLocation(source openbindings.Source, obiDir string) delegates.Source {
-	delSource := delegates.Source{Format: source.Format}
+	delSource := delegates.Source{Format: source.Format, Extensions: source.Extensions}
 	if source.Location != "" {
 		loc := source.Location
 		if !execref.IsExec(loc) && !strings.Contains(loc, "://") && !filepath.IsAbs(loc) && !isHostPort(loc) && obiDir != "" {
@@ -262,7 +421,17 @@ func isHostPort(s string) bool {
 // Exactly one of opKey or bindingKey must be non-empty:
 //   - opKey: selects the highest-priority binding for that operation.
 //   - bindingKey: looks up the binding directly (operation is read from the entry).
+//
+// The execution policy from the workspace and context settings applies; see
+// ExecuteOBIOperationWithPolicy to override it for a single call.
 func ExecuteOBIOperation(ctx context.Context, obiPath string, opKey string, bindingKey string, input any, contextName string) ExecuteOperationOutput {
+	return ExecuteOBIOperationWithPolicy(ctx, obiPath, opKey, bindingKey, input, contextName, ExecPolicy{})
+}
+
+// ExecuteOBIOperationWithPolicy is ExecuteOBIOperation with a per-invocation
+// execution policy layered over the workspace and context policies.
+func ExecuteOBIOperationWithPolicy(ctx context.Context, obiPath string, opKey string, bindingKey string, input any, contextName string, override ExecPolicy) ExecuteOperationOutput {
+	ctx = withContextCredentialSaver(ctx, contextName)
 	iface, err := resolveInterface(obiPath)
 	if err != nil {
 		return ExecuteOperationOutput{
@@ -278,13 +447,21 @@ func ExecuteOBIOperation(ctx context.Context, obiPath string, opKey string, bind
 	delSource := resolveSourceLocation(resolved.source, filepath.Dir(obiPath))
 
 	lowLevel := ExecuteOperationInput{
-		Source:  ExecuteSource{Format: delSource.Format, Location: delSource.Location, Content: delSource.Content},
+		Source:  ExecuteSource{Format: delSource.Format, Location: delSource.Location, Content: delSource.Content, Extensions: delSource.Extensions},
 		Ref:     resolved.binding.Ref,
 		Input:   resolved.input,
-		Context: resolved.bindCtx,
+		Context: withCallMetadata(ctx, resolved.bindCtx),
 	}
 
-	result := ExecuteOperationWithContext(ctx, lowLevel)
+	policy, err := ResolveExecPolicy(contextName, override)
+	if err != nil {
+		return ExecuteOperationOutput{Error: &Error{Code: "policy_error", Message: err.Error()}}
+	}
+
+	idempotent := operationIdempotent(iface, resolved.binding.Operation)
+	result := executeWithPolicy(ctx, policy, idempotent, func(ctx context.Context) ExecuteOperationOutput {
+		return ExecuteOperationWithContext(ctx, lowLevel)
+	})
 
 	if resolved.binding.OutputTransform != nil && result.Error == nil {
 		transformed, tErr := ApplyTransform(iface.Transforms, resolved.binding.OutputTransform, result.Output)
@@ -302,9 +479,11 @@ func ExecuteOBIOperation(ctx context.Context, obiPath string, opKey string, bind
 type StreamEvent = delegates.StreamEvent
 
 // SubscribeOBIOperation opens a streaming subscription for an event-kind
-// operation from an OBI file. The channel is closed when the context is
-// cancelled or the stream ends.
+// operation, or an operation whose output streams (see
+// OperationStreamsOutput), from an OBI file. The channel is closed when the
+// context is cancelled or the stream ends.
 func SubscribeOBIOperation(ctx context.Context, obiPath string, opKey string, bindingKey string, input any, contextName string) (<-chan StreamEvent, error) {
+	ctx = withContextCredentialSaver(ctx, contextName)
 	iface, err := resolveInterface(obiPath)
 	if err != nil {
 		return nil, fmt.Errorf("load OBI %q: %w", obiPath, err)
@@ -334,11 +513,136 @@ func SubscribeOBIOperation(ctx context.Context, obiPath string, opKey string, bi
 	})
 }
 
+// SubscribeOBIOperationWithPolicy is SubscribeOBIOperation for an operation
+// whose output streams, run as a single attempt under the resolved execution
+// policy's timeout. Attempts are not retried, since their output has already
+// been delivered. A stream cut short by the timeout or by cancelling ctx
+// ends with a "timeout" or "cancelled" error event.
+func SubscribeOBIOperationWithPolicy(ctx context.Context, obiPath string, opKey string, bindingKey string, input any, contextName string, override ExecPolicy) (<-chan StreamEvent, error) {
+	policy, err := ResolveExecPolicy(contextName, override)
+	if err != nil {
+		return nil, err
+	}
+	return subscribeWithPolicy(ctx, policy, func(ctx context.Context) (<-chan StreamEvent, error) {
+		return SubscribeOBIOperation(ctx, obiPath, opKey, bindingKey, input, contextName)
+	})
+}
+
+// subscribeWithPolicy runs subscribe under the policy's timeout and
+// forwards its events, reporting why the stream stopped when the timeout
+// expired or ctx was cancelled.
+func subscribeWithPolicy(ctx context.Context, policy ExecPolicy, subscribe func(context.Context) (<-chan StreamEvent, error)) (<-chan StreamEvent, error) {
+	attemptCtx, cancel := policy.attemptContext(ctx)
+	ch, err := subscribe(attemptCtx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	out := make(chan StreamEvent)
+	go func() {
+		defer close(out)
+		defer cancel()
+		for ev := range ch {
+			out <- ev
+		}
+		switch {
+		case ctx.Err() != nil:
+			out <- StreamEvent{Error: &Error{Code: "cancelled", Message: "operation cancelled"}}
+		case attemptCtx.Err() == context.DeadlineExceeded:
+			out <- StreamEvent{Error: &Error{Code: "timeout", Message: fmt.Sprintf("operation timed out after %s", time.Duration(policy.Timeout))}}
+		}
+	}()
+	return out, nil
+}
+
+// StreamOBIOperation opens a call for an operation that consumes a stream of
+// input messages (see IsInputStreamOperation). Each message received on
+// messages is passed through the binding's input transform and sent as it
+// arrives; closing messages ends the input. The returned channel receives
+// the responses and is closed when the call completes.
+func StreamOBIOperation(ctx context.Context, obiPath string, opKey string, bindingKey string, messages <-chan any, contextName string) (<-chan StreamEvent, error) {
+	ctx = withContextCredentialSaver(ctx, contextName)
+	iface, err := resolveInterface(obiPath)
+	if err != nil {
+		return nil, fmt.Errorf("load OBI %q: %w", obiPath, err)
+	}
+
+	resolved, err := resolveBindingAndSource(iface, opKey, bindingKey, nil, contextName, filepath.Dir(obiPath))
+	if err != nil {
+		return nil, err
+	}
+
+	delSource := resolveSourceLocation(resolved.source, filepath.Dir(obiPath))
+
+	handler, err := DefaultRegistry().ForFormat(resolved.source.Format)
+	if err != nil {
+		return nil, fmt.Errorf("no handler for format %q: %w", resolved.source.Format, err)
+	}
+	ish, ok := handler.(delegates.InputStreamHandler)
+	if !ok {
+		return nil, fmt.Errorf("delegate for format %q does not support input streaming", resolved.source.Format)
+	}
+
+	input := delegates.ExecuteInput{
+		Source:  delSource,
+		Ref:     resolved.binding.Ref,
+		Context: resolved.bindCtx,
+	}
+	tor := resolved.binding.InputTransform
+	if tor == nil {
+		return ish.StreamOperation(ctx, input, messages)
+	}
+
+	// Transform each message on the way in. A message that fails to
+	// transform cancels the call and is reported as its last event.
+	ctx, cancel := context.WithCancel(ctx)
+	transformErrs := make(chan error, 1)
+	transformed := make(chan any)
+	go func() {
+		defer close(transformed)
+		for msg := range messages {
+			t, err := ApplyTransform(iface.Transforms, tor, msg)
+			if err != nil {
+				transformErrs <- err
+				cancel()
+				return
+			}
+			select {
+			case transformed <- t:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	events, err := ish.StreamOperation(ctx, input, transformed)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	out := make(chan StreamEvent)
+	go func() {
+		defer close(out)
+		defer cancel()
+		for ev := range events {
+			out <- ev
+		}
+		select {
+		case err := <-transformErrs:
+			out <- StreamEvent{Error: &Error{Code: "input_transform_error", Message: fmt.Sprintf("input transform failed: %v", err)}}
+		default:
+		}
+	}()
+	return out, nil
+}
+
 // SubscribeOBIOperationDirect opens a streaming subscription using
 // pre-resolved binding components. Used by the TUI which already has the
 // interface, binding, and source loaded.
-func SubscribeOBIOperationDirect(ctx context.Context, iface *openbindings.Interface, opKey string, binding *openbindings.BindingEntry, source openbindings.Source, obiDir string, contextName string) (<-chan StreamEvent, error) {
-	resolved, err := resolveBindingAndSource(iface, opKey, "", nil, contextName, obiDir)
+func SubscribeOBIOperationDirect(ctx context.Context, iface *openbindings.Interface, opKey string, binding *openbindings.BindingEntry, source openbindings.Source, obiDir string, input any, contextName string) (<-chan StreamEvent, error) {
+	ctx = withContextCredentialSaver(ctx, contextName)
+	resolved, err := resolveBindingAndSource(iface, opKey, "", input, contextName, obiDir)
 	if err != nil {
 		return nil, err
 	}
@@ -357,10 +661,68 @@ func SubscribeOBIOperationDirect(ctx context.Context, iface *openbindings.Interf
 	return sh.SubscribeOperation(ctx, delegates.ExecuteInput{
 		Source:  delSource,
 		Ref:     binding.Ref,
+		Input:   resolved.input,
 		Context: resolved.bindCtx,
 	})
 }
 
+// ResolveBindingServers returns the server URL each binding's requests will
+// be sent to, as determined by the source's delegate. Each source document
+// is loaded once. Bindings whose delegate has no notion of a server (e.g.,
+// CLI bindings) or whose server cannot be resolved are omitted.
+func ResolveBindingServers(iface *openbindings.Interface, obiDir string, contextName string) (map[string]string, error) {
+	var bindCtx *delegates.BindingContext
+	if contextName != "" {
+		bc, err := GetContext(contextName)
+		if err != nil {
+			return nil, err
+		}
+		bindCtx = &bc
+	}
+
+	// Group binding keys by source, then by ref.
+	keysByRef := map[string]map[string][]string{}
+	for key, binding := range iface.Bindings {
+		if _, ok := iface.Sources[binding.Source]; !ok {
+			continue
+		}
+		if keysByRef[binding.Source] == nil {
+			keysByRef[binding.Source] = map[string][]string{}
+		}
+		keysByRef[binding.Source][binding.Ref] = append(keysByRef[binding.Source][binding.Ref], key)
+	}
+
+	servers := map[string]string{}
+	for sourceName, byRef := range keysByRef {
+		source := iface.Sources[sourceName]
+		handler, err := DefaultRegistry().ForFormat(source.Format)
+		if err != nil {
+			continue
+		}
+		sr, ok := handler.(delegates.ServerResolver)
+		if !ok {
+			continue
+		}
+		refs := make([]string, 0, len(byRef))
+		for ref := range byRef {
+			refs = append(refs, ref)
+		}
+		resolved, err := sr.ResolveServers(resolveSourceLocation(source, obiDir), refs, bindCtx)
+		if err != nil {
+			continue
+		}
+		for ref, server := range resolved {
+			if server == "" {
+				continue
+			}
+			for _, key := range byRef[ref] {
+				servers[key] = server
+			}
+		}
+	}
+	return servers, nil
+}
+
 // ExecuteOperation executes an operation via a binding.
 // This implements executeOperation.
 //
@@ -404,6 +766,8 @@ func ExecuteOperationWithContext(ctx context.Context, input ExecuteOperationInpu
 		Format:              input.Source.Format,
 		DelegatePreferences: wsCtx.DelegatePreferences,
 		WorkspaceDelegates:  wsCtx.Delegates,
+		Invoker:             invokeDelegateBinding,
+		Cache:               delegateProbeCache(),
 	}, BuiltinSupportsFormat)
 	if err != nil {
 		return ExecuteOperationOutput{
@@ -443,10 +807,11 @@ func executeViaBuiltin(ctx context.Context, input ExecuteOperationInput) Execute
 
 	result := handler.ExecuteOperation(ctx, delegates.ExecuteInput{
 		Source: delegates.Source{
-			Format:   input.Source.Format,
-			Location: input.Source.Location,
-			Content:  input.Source.Content,
-			Binary:   input.Source.Binary,
+			Format:     input.Source.Format,
+			Location:   input.Source.Location,
+			Content:    input.Source.Content,
+			Binary:     input.Source.Binary,
+			Extensions: input.Source.Extensions,
 		},
 		Ref:     input.Ref,
 		Input:   input.Input,
@@ -458,6 +823,9 @@ func executeViaBuiltin(ctx context.Context, input ExecuteOperationInput) Execute
 		Status:     result.Status,
 		DurationMs: result.DurationMs,
 		Error:      result.Error,
+		Links:      result.Links,
+		Headers:    result.Headers,
+		Trailers:   result.Trailers,
 	}
 }
 
@@ -473,6 +841,9 @@ func executeViaExternalDelegate(ctx context.Context, resolved delegates.Resolved
 		}
 	}
 
+	if delegates.IsHTTPURL(loc) {
+		return executeViaHTTPDelegate(ctx, loc, input)
+	}
 	return executeViaCLIDelegate(ctx, loc, input)
 }
 
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/openbindings/cli/internal/delegates"
 	openbindings "github.com/openbindings/openbindings-go"
 )
 
@@ -277,3 +278,27 @@ func TestExecuteOBIOperation_InputTransformError(t *testing.T) {
 		t.Errorf("expected code 'resolution_error', got %q", result.Error.Code)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// WithMetadata
+// ---------------------------------------------------------------------------
+
+func TestWithCallMetadata(t *testing.T) {
+	if got := withCallMetadata(context.Background(), nil); got != nil {
+		t.Fatalf("without call metadata: got %+v, want nil", got)
+	}
+
+	named := &delegates.BindingContext{Metadata: map[string]any{"server": "1", "responseFile": "old.bin"}}
+	ctx := WithMetadata(context.Background(), map[string]any{"responseFile": "out.bin"})
+	got := withCallMetadata(ctx, named)
+	if got.Metadata["responseFile"] != "out.bin" || got.Metadata["server"] != "1" {
+		t.Errorf("metadata = %v, want call metadata layered over the context's", got.Metadata)
+	}
+	if named.Metadata["responseFile"] != "old.bin" {
+		t.Error("the named context's metadata should not be modified")
+	}
+
+	if got := withCallMetadata(ctx, nil); got == nil || got.Metadata["responseFile"] != "out.bin" {
+		t.Errorf("without a named context: got %+v", got)
+	}
+}
@@ -47,9 +47,9 @@ func ListFormats() []delegates.FormatInfo {
 		WorkspaceDelegates: wsCtx.Delegates,
 	})
 	for _, p := range discovered {
-		delegateFormats, err := delegates.ProbeFormats(p.Location, delegates.DefaultProbeTimeout)
+		entry, err := probeDelegate(p.Location, false)
 		if err == nil {
-			for _, f := range delegateFormats {
+			for _, f := range entry.Formats {
 				formats = append(formats, delegates.FormatInfo{Token: f})
 			}
 		}
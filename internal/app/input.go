@@ -72,6 +72,10 @@ type InputsMutateOutput struct {
 	Name      string   `json:"name"`
 	Ref       InputRef `json:"ref,omitempty"`
 	Action    string   `json:"action"` // added|removed|created|deleted
+
+	// Completable lists the template fields whose candidate values a
+	// completer lists (see `ob operation exec --field <name>=<TAB>`).
+	Completable []string `json:"completable,omitempty"`
 }
 
 func (o InputsMutateOutput) Render() string {
@@ -85,10 +89,14 @@ func (o InputsMutateOutput) Render() string {
 		}
 		return s.Success.Render("ok") + " deleted file and removed input " + s.Key.Render(o.Name)
 	case "added", "created":
+		line := s.Success.Render("ok") + " " + o.Action + " input " + s.Key.Render(o.Name)
 		if o.Ref != "" {
-			return s.Success.Render("ok") + " " + o.Action + " input " + s.Key.Render(o.Name) + s.Dim.Render(" → ") + string(o.Ref)
+			line += s.Dim.Render(" → ") + string(o.Ref)
 		}
-		return s.Success.Render("ok") + " " + o.Action + " input " + s.Key.Render(o.Name)
+		if len(o.Completable) > 0 {
+			line += "\n" + s.Dim.Render("fields with candidate values: "+strings.Join(o.Completable, ", "))
+		}
+		return line
 	default:
 		if o.Ref != "" {
 			return s.Success.Render("ok") + " " + o.Action + " input " + s.Key.Render(o.Name) + s.Dim.Render(" → ") + string(o.Ref)
@@ -333,11 +341,13 @@ func InputsCreate(targetID, opKey, name, path, template string, force bool, open
 	}
 
 	content := []byte("{}\n")
+	var completable []string
 	if template == "schema" {
 		// Best effort: if we can fetch schema, generate template.
-		if t, err := generateSchemaTemplateForTargetOp(ws, targetID, opKey); err == nil && t != nil {
+		if t, fields, err := generateSchemaTemplateForTargetOp(ws, targetID, opKey); err == nil && t != nil {
 			if b, err := json.MarshalIndent(t, "", "  "); err == nil {
 				content = append(b, '\n')
+				completable = fields
 			}
 		}
 	}
@@ -364,7 +374,7 @@ func InputsCreate(targetID, opKey, name, path, template string, force bool, open
 			return InputsMutateOutput{}, err
 		}
 	}
-	return InputsMutateOutput{Workspace: ws.Name, TargetID: targetID, OpKey: opKey, Name: name, Ref: InputRef(path), Action: "created"}, nil
+	return InputsMutateOutput{Workspace: ws.Name, TargetID: targetID, OpKey: opKey, Name: name, Ref: InputRef(path), Action: "created", Completable: completable}, nil
 }
 
 // InputsEdit opens the referenced file in the user's editor.
@@ -605,24 +615,27 @@ func randomHex(nBytes int) string {
 	return hex.EncodeToString(b)
 }
 
-func generateSchemaTemplateForTargetOp(ws *Workspace, targetID, opKey string) (map[string]any, error) {
+// generateSchemaTemplateForTargetOp generates an input template from an
+// operation's schema, along with the fields whose candidate values a
+// completer lists. Completers are not run; the fields are left empty.
+func generateSchemaTemplateForTargetOp(ws *Workspace, targetID, opKey string) (map[string]any, []string, error) {
 	target := FindTargetByID(ws, targetID)
 	if target == nil {
-		return nil, fmt.Errorf("target not found")
+		return nil, nil, fmt.Errorf("target not found")
 	}
 	probed := ProbeOBI(target.URL, 2*time.Second)
 	if probed.Status != "ok" || strings.TrimSpace(probed.OBI) == "" {
-		return nil, fmt.Errorf("failed to fetch OBI")
+		return nil, nil, fmt.Errorf("failed to fetch OBI")
 	}
 	var iface openbindings.Interface
 	if err := json.Unmarshal([]byte(probed.OBI), &iface); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	op, ok := iface.Operations[opKey]
 	if !ok || op.Input == nil {
-		return nil, fmt.Errorf("no schema")
+		return nil, nil, fmt.Errorf("no schema")
 	}
-	return generateInputTemplate(op.Input), nil
+	return generateInputTemplate(op.Input), CompletableFields(&iface, opKey), nil
 }
 
 func generateInputTemplate(schema openbindings.JSONSchema) map[string]any {
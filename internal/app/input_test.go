@@ -1,6 +1,7 @@
 package app
 
 import (
+	"strings"
 	"testing"
 
 	openbindings "github.com/openbindings/openbindings-go"
@@ -426,6 +427,12 @@ func TestInputsMutateOutput_Render(t *testing.T) {
 			}
 		})
 	}
+	t.Run("completable", func(t *testing.T) {
+		o := InputsMutateOutput{Name: "test", Action: "created", Completable: []string{"plugin", "version"}}
+		if r := o.Render(); !strings.Contains(r, "plugin, version") {
+			t.Errorf("render = %q, want the completable fields listed", r)
+		}
+	})
 }
 
 func TestInputsValidateOutput_Render(t *testing.T) {
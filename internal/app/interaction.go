@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// App-layer aliases for the delegate interaction types.
+type (
+	Interaction   = delegates.Interaction
+	Progress      = delegates.Progress
+	LogMessage    = delegates.LogMessage
+	ElicitRequest = delegates.ElicitRequest
+	ElicitResult  = delegates.ElicitResult
+)
+
+// Elicitation actions.
+const (
+	ElicitAccept  = delegates.ElicitAccept
+	ElicitDecline = delegates.ElicitDecline
+	ElicitCancel  = delegates.ElicitCancel
+)
+
+// SamplingSettings routes MCP sampling requests to an operation, typically
+// one bound to a language model API. The operation receives the
+// sampling/createMessage params as input and must return a createMessage
+// result: {"role", "content", "model", "stopReason"}.
+type SamplingSettings struct {
+	OBI       string `json:"obi"`               // Path or URL of the OBI defining the operation
+	Operation string `json:"operation"`         // Operation key
+	Context   string `json:"context,omitempty"` // Named context to apply
+}
+
+// WithInteraction attaches ia to ctx for the operations executed under it.
+// When ia has no Sample callback, sampling requests go to the operation in
+// the workspace's settings.sampling, and are rejected if none is configured.
+func WithInteraction(ctx context.Context, ia Interaction) context.Context {
+	if ia.Sample == nil {
+		if ws, _, _, err := RequireActiveWorkspace(); err == nil && ws.Settings.Sampling != nil {
+			ia.Sample = sampleVia(*ws.Settings.Sampling)
+		}
+	}
+	return delegates.WithInteraction(ctx, &ia)
+}
+
+// sampleVia returns a Sample callback that executes the configured
+// operation.
+func sampleVia(s SamplingSettings) func(context.Context, map[string]any) (map[string]any, error) {
+	return func(ctx context.Context, req map[string]any) (map[string]any, error) {
+		// The sampling operation gets no interaction of its own, so a
+		// sampling delegate cannot recursively ask for samples.
+		ctx = delegates.WithInteraction(ctx, nil)
+		result := ExecuteOBIOperation(ctx, s.OBI, s.Operation, "", req, s.Context)
+		if result.Error != nil {
+			return nil, fmt.Errorf("sampling operation %q: %s", s.Operation, result.Error.Message)
+		}
+		out, ok := result.Output.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("sampling operation %q returned %T, want a createMessage result object", s.Operation, result.Output)
+		}
+		return out, nil
+	}
+}
+
+// FormatProgress renders a progress notification as a single line, e.g.
+// "3/10 indexing files".
+func FormatProgress(p Progress) string {
+	s := fmt.Sprintf("%g", p.Progress)
+	if p.Total > 0 {
+		s += fmt.Sprintf("/%g", p.Total)
+	}
+	if p.Message != "" {
+		s += " " + p.Message
+	}
+	return s
+}
+
+// FormatLogMessage renders a server log message as a single line, prefixed
+// by its logger when it has one. Structured data is rendered as JSON.
+func FormatLogMessage(m LogMessage) string {
+	text, ok := m.Data.(string)
+	if !ok {
+		b, _ := json.Marshal(m.Data)
+		text = string(b)
+	}
+	if m.Logger != "" {
+		text = m.Logger + ": " + text
+	}
+	return text
+}
+
+// OpenBrowser opens url in the user's default browser, for Authorize
+// callbacks. It returns once the browser has been launched.
+func OpenBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
@@ -0,0 +1,464 @@
+package app
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/openbindings/openbindings-go"
+)
+
+// MCPServeInput configures ServeMCP.
+type MCPServeInput struct {
+	OBI         string   // Path or URL of the OBI whose operations are published
+	Context     string   // Named context applied to every call
+	Tags        []string // Publish only operations with one of these tags; all when empty
+	ExcludeTags []string // Never publish operations with one of these tags
+
+	// HTTPAddr is the address to serve Streamable HTTP on (e.g. ":8080").
+	// An address without a host is bound to the loopback interface. When
+	// empty, the server speaks MCP over stdin and stdout.
+	HTTPAddr string
+
+	// BearerToken, when set, is required of HTTP clients in the
+	// Authorization header. It must be set to serve a non-loopback
+	// HTTPAddr.
+	BearerToken string
+
+	// Listening is called with the endpoint URL once the HTTP server
+	// accepts connections. Optional.
+	Listening func(endpoint string)
+
+	// Authorize presents an authorization URL when a called operation's
+	// server requires OAuth and the MCP client cannot open URLs itself.
+	// Optional.
+	Authorize func(ctx context.Context, authURL string) error
+}
+
+// MCPTool is an operation published as an MCP tool.
+type MCPTool struct {
+	Name        string         `json:"name"`
+	Operation   string         `json:"operation"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"inputSchema"`
+	Idempotent  bool           `json:"idempotent,omitempty"`
+
+	// wrapped is set when the operation input is not an object and is
+	// passed as the "input" argument of the tool.
+	wrapped bool
+}
+
+// mcpInputArgument is the tool argument holding a non-object operation input.
+const mcpInputArgument = "input"
+
+// mcpToolNameInvalid matches the characters not allowed in MCP tool names.
+var mcpToolNameInvalid = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// mcpToolNameMax is the length tool names derived from operation keys are
+// cut to.
+const mcpToolNameMax = 120
+
+// mcpEndpointPath is the path the Streamable HTTP endpoint is served at.
+const mcpEndpointPath = "/mcp"
+
+// MCPTools returns the tools published for the operations of iface, sorted
+// by operation key. Operations are published when they have a binding and
+// are not event or streaming-input operations, which have no tool call
+// equivalent. tags and excludeTags filter operations as in MCPServeInput.
+//
+// An operation whose key is a valid tool name is published under that name;
+// the others get a sanitized name, suffixed when it is already taken.
+func MCPTools(iface *openbindings.Interface, tags, excludeTags []string) []MCPTool {
+	keys := make([]string, 0, len(iface.Operations))
+	for key := range iface.Operations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var published []string
+	names := map[string]bool{}
+	for _, key := range keys {
+		op := iface.Operations[key]
+		if op.Kind == "event" || operationStreamsInput(op) {
+			continue
+		}
+		if _, b := DefaultBindingForOp(key, iface); b == nil {
+			continue
+		}
+		if len(tags) > 0 && !hasAnyTag(op.Tags, tags) {
+			continue
+		}
+		if hasAnyTag(op.Tags, excludeTags) {
+			continue
+		}
+		published = append(published, key)
+		if isMCPToolName(key) {
+			names[key] = true
+		}
+	}
+
+	tools := make([]MCPTool, 0, len(published))
+	for _, key := range published {
+		op := iface.Operations[key]
+		name := key
+		if !isMCPToolName(key) {
+			name = mcpToolName(key, names)
+			names[name] = true
+		}
+		tool := MCPTool{
+			Name:        name,
+			Operation:   key,
+			Description: op.Description,
+			Idempotent:  op.Idempotent != nil && *op.Idempotent,
+		}
+		tool.InputSchema, tool.wrapped = mcpInputSchema(op.Input, iface)
+		tools = append(tools, tool)
+	}
+	return tools
+}
+
+// hasAnyTag reports whether tags contains any of want.
+func hasAnyTag(tags, want []string) bool {
+	for _, t := range want {
+		if containsTag(tags, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMCPToolName reports whether name is a valid MCP tool name.
+func isMCPToolName(name string) bool {
+	return name != "" && len(name) <= mcpToolNameMax && !mcpToolNameInvalid.MatchString(name)
+}
+
+// mcpToolName derives a valid, unused tool name from an operation key.
+func mcpToolName(opKey string, used map[string]bool) string {
+	base := mcpToolNameInvalid.ReplaceAllString(opKey, "_")
+	if len(base) > mcpToolNameMax {
+		base = base[:mcpToolNameMax]
+	}
+	name := base
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	return name
+}
+
+// mcpInputSchema returns a self-contained object schema for an operation
+// input. Schema references are inlined as $defs. An input that is not an
+// object is wrapped as the "input" property, and wrapped reports so.
+func mcpInputSchema(input map[string]any, iface *openbindings.Interface) (schema map[string]any, wrapped bool) {
+	if input == nil {
+		return map[string]any{"type": "object"}, false
+	}
+	doc := buildSchemaDocument(resolveSchemaFully(input, iface), iface)
+	if _, ok := doc["type"]; !ok && doc["properties"] != nil {
+		doc["type"] = "object"
+	}
+	if t, _ := doc["type"].(string); t == "object" {
+		return doc, false
+	}
+
+	wrapper := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{mcpInputArgument: doc},
+		"required":   []string{mcpInputArgument},
+	}
+	if defs, ok := doc["$defs"]; ok {
+		delete(doc, "$defs")
+		wrapper["$defs"] = defs
+	}
+	return wrapper, true
+}
+
+// NewMCPServer returns an MCP server publishing the operations of in.OBI as
+// tools (see MCPTools). Each call executes the operation through
+// ExecuteOBIOperation with in.Context. Progress, log messages, elicitation
+// and sampling requests from the operation's server are relayed to the MCP
+// client.
+func NewMCPServer(in MCPServeInput) (*gomcp.Server, error) {
+	iface, err := resolveInterface(in.OBI)
+	if err != nil {
+		return nil, fmt.Errorf("load OBI %q: %w", in.OBI, err)
+	}
+	tools := MCPTools(iface, in.Tags, in.ExcludeTags)
+	if len(tools) == 0 {
+		return nil, fmt.Errorf("no operations in %q to publish", in.OBI)
+	}
+
+	impl := &gomcp.Implementation{Name: "ob", Title: iface.Name, Version: OBVersion}
+	server := gomcp.NewServer(impl, &gomcp.ServerOptions{Instructions: iface.Description})
+	var elicitations atomic.Int64
+	for _, tool := range tools {
+		server.AddTool(&gomcp.Tool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: tool.InputSchema,
+			Annotations: &gomcp.ToolAnnotations{IdempotentHint: tool.Idempotent},
+		}, func(ctx context.Context, req *gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+			input, err := mcpToolInput(tool, req.Params.Arguments)
+			if err != nil {
+				return mcpErrorResult(err.Error()), nil
+			}
+			ctx = WithInteraction(ctx, mcpServeInteraction(ctx, req, in.Authorize, &elicitations))
+			result := ExecuteOBIOperation(ctx, in.OBI, tool.Operation, "", input, in.Context)
+			return mcpToolResult(result), nil
+		})
+	}
+	return server, nil
+}
+
+// ServeMCP serves the operations of in.OBI over stdio, or over Streamable
+// HTTP when in.HTTPAddr is set, until ctx is done or the stdio client
+// disconnects.
+//
+// Over HTTP, requests are rejected when their Origin is not the server's
+// own, or, on a loopback address, when their Host or Origin is not a
+// loopback name, which guards against DNS rebinding. Serving a non-loopback
+// address requires in.BearerToken.
+func ServeMCP(ctx context.Context, in MCPServeInput) error {
+	var addr string
+	var loopback bool
+	if in.HTTPAddr != "" {
+		var err error
+		if addr, loopback, err = mcpListenAddr(in.HTTPAddr); err != nil {
+			return err
+		}
+		if !loopback && in.BearerToken == "" {
+			return fmt.Errorf("serving MCP on %q, which is not a loopback address, requires a bearer token", in.HTTPAddr)
+		}
+	}
+
+	server, err := NewMCPServer(in)
+	if err != nil {
+		return err
+	}
+	if in.HTTPAddr == "" {
+		return server.Run(ctx, &gomcp.StdioTransport{})
+	}
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	handler := gomcp.NewStreamableHTTPHandler(func(*http.Request) *gomcp.Server { return server }, nil)
+	mux.Handle(mcpEndpointPath, mcpGuard(handler, loopback, in.BearerToken))
+	srv := &http.Server{Handler: mux}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	if in.Listening != nil {
+		in.Listening("http://" + lis.Addr().String() + mcpEndpointPath)
+	}
+	if err := srv.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// mcpListenAddr returns the address to listen on for addr, binding an
+// address without a host to the loopback interface, and reports whether it
+// is a loopback address.
+func mcpListenAddr(addr string) (string, bool, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid HTTP address %q: %w", addr, err)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port), isLoopbackHost(host), nil
+}
+
+// isLoopbackHost reports whether host is localhost or a loopback IP.
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// mcpGuard wraps the Streamable HTTP handler with the checks described on
+// ServeMCP: the Host and Origin headers, and the bearer token when token is
+// set.
+func mcpGuard(next http.Handler, loopback bool, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if loopback && !isLoopbackHost(requestHostname(r.Host)) {
+			http.Error(w, "invalid Host header", http.StatusForbidden)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" && !mcpOriginAllowed(origin, r.Host, loopback) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		if token != "" {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "invalid or missing bearer token", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// mcpOriginAllowed reports whether a request from origin may be served: on
+// a loopback address, origins with a loopback host; otherwise, only the
+// server's own origin, the request's host.
+func mcpOriginAllowed(origin, host string, loopback bool) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if loopback {
+		return isLoopbackHost(u.Hostname())
+	}
+	return strings.EqualFold(u.Host, host)
+}
+
+// requestHostname returns the host of a Host header without the port.
+func requestHostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return strings.Trim(host, "[]")
+}
+
+// mcpToolInput converts tool call arguments to the operation input.
+func mcpToolInput(tool MCPTool, raw json.RawMessage) (any, error) {
+	var args map[string]any
+	if len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %v", err)
+		}
+	}
+	if tool.wrapped {
+		return args[mcpInputArgument], nil
+	}
+	if args == nil {
+		return nil, nil
+	}
+	return args, nil
+}
+
+// mcpToolResult converts an operation result to a tool result. Object
+// outputs are also returned as structured content.
+func mcpToolResult(result ExecuteOperationOutput) *gomcp.CallToolResult {
+	if result.Error != nil {
+		return mcpErrorResult(result.Error.Message)
+	}
+	text, ok := result.Output.(string)
+	if !ok && result.Output != nil {
+		b, err := json.Marshal(result.Output)
+		if err != nil {
+			return mcpErrorResult(fmt.Sprintf("encode output: %v", err))
+		}
+		text = string(b)
+	}
+	res := &gomcp.CallToolResult{
+		Content: []gomcp.Content{&gomcp.TextContent{Text: text}},
+		IsError: result.Status != 0,
+	}
+	if m, ok := result.Output.(map[string]any); ok {
+		res.StructuredContent = m
+	}
+	return res
+}
+
+func mcpErrorResult(message string) *gomcp.CallToolResult {
+	return &gomcp.CallToolResult{
+		Content: []gomcp.Content{&gomcp.TextContent{Text: message}},
+		IsError: true,
+	}
+}
+
+// mcpServeInteraction relays the activity of an operation called through
+// req to the MCP client: progress (when the call asked for it), log
+// messages, and the elicitation and sampling requests the client supports.
+// Authorization URLs are sent as URL elicitations when the client supports
+// them and to authorize otherwise.
+func mcpServeInteraction(ctx context.Context, req *gomcp.CallToolRequest, authorize func(context.Context, string) error, elicitations *atomic.Int64) Interaction {
+	ss := req.Session
+	ia := Interaction{
+		Log: func(m LogMessage) {
+			_ = ss.Log(ctx, &gomcp.LoggingMessageParams{Level: gomcp.LoggingLevel(m.Level), Logger: m.Logger, Data: m.Data})
+		},
+		Authorize: authorize,
+	}
+	if token := req.Params.GetProgressToken(); token != nil {
+		ia.Progress = func(p Progress) {
+			_ = ss.NotifyProgress(ctx, &gomcp.ProgressNotificationParams{ProgressToken: token, Progress: p.Progress, Total: p.Total, Message: p.Message})
+		}
+	}
+
+	var caps *gomcp.ClientCapabilities
+	if init := ss.InitializeParams(); init != nil {
+		caps = init.Capabilities
+	}
+	if caps == nil {
+		return ia
+	}
+	if caps.Elicitation != nil {
+		ia.Elicit = func(ctx context.Context, r ElicitRequest) (ElicitResult, error) {
+			res, err := ss.Elicit(ctx, &gomcp.ElicitParams{Message: r.Message, RequestedSchema: r.Schema})
+			if err != nil {
+				return ElicitResult{}, err
+			}
+			return ElicitResult{Action: res.Action, Content: res.Content}, nil
+		}
+		if caps.Elicitation.URL != nil {
+			ia.Authorize = func(ctx context.Context, authURL string) error {
+				res, err := ss.Elicit(ctx, &gomcp.ElicitParams{
+					Mode:          "url",
+					Message:       "The server behind this tool requires authorization.",
+					URL:           authURL,
+					ElicitationID: fmt.Sprintf("ob-authorize-%d", elicitations.Add(1)),
+				})
+				if err != nil {
+					return err
+				}
+				if res.Action != ElicitAccept {
+					return fmt.Errorf("authorization not granted (%s)", res.Action)
+				}
+				return nil
+			}
+		}
+	}
+	if caps.Sampling != nil {
+		ia.Sample = func(ctx context.Context, r map[string]any) (map[string]any, error) {
+			b, err := json.Marshal(r)
+			if err != nil {
+				return nil, fmt.Errorf("sampling request: %w", err)
+			}
+			var params gomcp.CreateMessageParams
+			if err := json.Unmarshal(b, &params); err != nil {
+				return nil, fmt.Errorf("sampling request: %w", err)
+			}
+			res, err := ss.CreateMessage(ctx, &params)
+			if err != nil {
+				return nil, err
+			}
+			out, ok := ToStringMap(res)
+			if !ok {
+				return nil, fmt.Errorf("sampling result is not an object")
+			}
+			return out, nil
+		}
+	}
+	return ia
+}
@@ -0,0 +1,229 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	openbindings "github.com/openbindings/openbindings-go"
+)
+
+func TestMCPTools(t *testing.T) {
+	iface := &openbindings.Interface{
+		Schemas: map[string]openbindings.JSONSchema{
+			"Pet": {"type": "object", "properties": map[string]any{"name": map[string]any{"type": "string"}}},
+		},
+		Operations: map[string]openbindings.Operation{
+			"pets.create": {Kind: "method", Description: "Create a pet", Tags: []string{"write"}, Input: openbindings.JSONSchema{"$ref": "#/schemas/Pet"}},
+			"pets/list":   {Kind: "method", Tags: []string{"read"}},
+			"pets_list":   {Kind: "method", Tags: []string{"read"}},
+			"echo":        {Kind: "method", Tags: []string{"read", "admin"}, Input: openbindings.JSONSchema{"type": "string"}},
+			"changes":     {Kind: "event", Tags: []string{"read"}},
+			"unbound":     {Kind: "method", Tags: []string{"read"}},
+		},
+		Bindings: map[string]openbindings.BindingEntry{
+			"pets.create.api": {Operation: "pets.create", Source: "api"},
+			"pets/list.api":   {Operation: "pets/list", Source: "api"},
+			"pets_list.api":   {Operation: "pets_list", Source: "api"},
+			"echo.api":        {Operation: "echo", Source: "api"},
+			"changes.api":     {Operation: "changes", Source: "api"},
+		},
+	}
+
+	names := func(tools []MCPTool) map[string]string {
+		m := map[string]string{}
+		for _, tool := range tools {
+			m[tool.Name] = tool.Operation
+		}
+		return m
+	}
+
+	all := MCPTools(iface, nil, nil)
+	want := map[string]string{"echo": "echo", "pets.create": "pets.create", "pets_list": "pets_list", "pets_list_2": "pets/list"}
+	if got := names(all); !reflect.DeepEqual(got, want) {
+		t.Errorf("tools = %v, want %v", got, want)
+	}
+
+	if got := names(MCPTools(iface, []string{"read"}, []string{"admin"})); !reflect.DeepEqual(got, map[string]string{"pets_list": "pets_list", "pets_list_2": "pets/list"}) {
+		t.Errorf("filtered tools = %v", got)
+	}
+
+	for _, tool := range all {
+		switch tool.Operation {
+		case "pets.create":
+			if tool.wrapped || tool.InputSchema["type"] != "object" || tool.InputSchema["$defs"] == nil || tool.Description != "Create a pet" {
+				t.Errorf("pets.create tool = %+v", tool)
+			}
+		case "echo":
+			props, _ := tool.InputSchema["properties"].(map[string]any)
+			if !tool.wrapped || !reflect.DeepEqual(props[mcpInputArgument], map[string]any{"type": "string"}) {
+				t.Errorf("echo tool = %+v", tool)
+			}
+		case "pets/list":
+			if !reflect.DeepEqual(tool.InputSchema, map[string]any{"type": "object"}) {
+				t.Errorf("pets/list input schema = %v", tool.InputSchema)
+			}
+		}
+	}
+}
+
+func TestMCPListenAddr(t *testing.T) {
+	for addr, want := range map[string]struct {
+		addr     string
+		loopback bool
+	}{
+		":8080":          {"127.0.0.1:8080", true},
+		"localhost:8080": {"localhost:8080", true},
+		"[::1]:8080":     {"[::1]:8080", true},
+		"0.0.0.0:8080":   {"0.0.0.0:8080", false},
+		"example.com:80": {"example.com:80", false},
+	} {
+		got, loopback, err := mcpListenAddr(addr)
+		if err != nil || got != want.addr || loopback != want.loopback {
+			t.Errorf("mcpListenAddr(%q) = %q, %v, %v; want %q, %v", addr, got, loopback, err, want.addr, want.loopback)
+		}
+	}
+	if _, _, err := mcpListenAddr("8080"); err == nil {
+		t.Error("expected an error for an address without a port")
+	}
+
+	err := ServeMCP(context.Background(), MCPServeInput{OBI: "missing.json", HTTPAddr: "0.0.0.0:0"})
+	if err == nil || !strings.Contains(err.Error(), "bearer token") {
+		t.Errorf("non-loopback address without a token: err = %v", err)
+	}
+}
+
+func TestMCPGuard(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	status := func(h http.Handler, host, origin, auth string) int {
+		req := httptest.NewRequest(http.MethodPost, "http://"+host+"/mcp", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	local := mcpGuard(ok, true, "")
+	for _, tc := range []struct {
+		host, origin string
+		want         int
+	}{
+		{"127.0.0.1:8080", "", http.StatusOK},
+		{"localhost:8080", "http://localhost:3000", http.StatusOK},
+		{"[::1]:8080", "", http.StatusOK},
+		{"evil.example:8080", "", http.StatusForbidden},
+		{"localhost:8080", "https://evil.example", http.StatusForbidden},
+		{"localhost:8080", "null", http.StatusForbidden},
+	} {
+		if got := status(local, tc.host, tc.origin, ""); got != tc.want {
+			t.Errorf("loopback: host %q, origin %q: status = %d, want %d", tc.host, tc.origin, got, tc.want)
+		}
+	}
+
+	remote := mcpGuard(ok, false, "secret")
+	for _, tc := range []struct {
+		origin, auth string
+		want         int
+	}{
+		{"", "Bearer secret", http.StatusOK},
+		{"https://mcp.example", "Bearer secret", http.StatusOK},
+		{"https://evil.example", "Bearer secret", http.StatusForbidden},
+		{"", "", http.StatusUnauthorized},
+		{"", "Bearer wrong", http.StatusUnauthorized},
+	} {
+		if got := status(remote, "mcp.example", tc.origin, tc.auth); got != tc.want {
+			t.Errorf("remote: origin %q, auth %q: status = %d, want %d", tc.origin, tc.auth, got, tc.want)
+		}
+	}
+}
+
+func TestNewMCPServer_CallsOperation(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"name": r.URL.Query().Get("name"), "id": 7})
+	}))
+	defer backend.Close()
+
+	obi := writeOBIFile(t, t.TempDir(), map[string]any{
+		"openbindings": "0.1.0",
+		"name":         "pets",
+		"operations": map[string]any{
+			"getPet": map[string]any{
+				"kind":        "method",
+				"description": "Get a pet by name",
+				"input": map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"name": map[string]any{"type": "string"}},
+				},
+			},
+		},
+		"sources": map[string]any{
+			"api": map[string]any{
+				"format": "openapi@3.1",
+				"content": map[string]any{
+					"openapi": "3.1.0",
+					"info":    map[string]any{"title": "Pets", "version": "1.0.0"},
+					"servers": []any{map[string]any{"url": backend.URL}},
+					"paths": map[string]any{
+						"/pet": map[string]any{
+							"get": map[string]any{
+								"parameters": []any{map[string]any{"name": "name", "in": "query", "schema": map[string]any{"type": "string"}}},
+								"responses":  map[string]any{"200": map[string]any{"description": "ok"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		"bindings": map[string]any{
+			"getPet.api": map[string]any{"operation": "getPet", "source": "api", "ref": "#/paths/~1pet/get"},
+		},
+	})
+
+	server, err := NewMCPServer(MCPServeInput{OBI: obi})
+	if err != nil {
+		t.Fatalf("NewMCPServer: %v", err)
+	}
+	ctx := context.Background()
+	st, ct := gomcp.NewInMemoryTransports()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := gomcp.NewClient(&gomcp.Implementation{Name: "test", Version: "1"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	tools, err := cs.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools.Tools) != 1 || tools.Tools[0].Name != "getPet" || tools.Tools[0].Description != "Get a pet by name" {
+		t.Fatalf("tools = %+v", tools.Tools)
+	}
+
+	res, err := cs.CallTool(ctx, &gomcp.CallToolParams{Name: "getPet", Arguments: map[string]any{"name": "rex"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError {
+		t.Fatalf("tool error: %+v", res.Content)
+	}
+	want := map[string]any{"name": "rex", "id": float64(7)}
+	if !reflect.DeepEqual(res.StructuredContent, want) {
+		t.Errorf("structured content = %v, want %v", res.StructuredContent, want)
+	}
+}
@@ -1,8 +1,11 @@
 package app
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -417,12 +420,116 @@ func migrateSchemaRefs(target, source *openbindings.Interface, op openbindings.O
 		target.Schemas = map[string]openbindings.JSONSchema{}
 	}
 
-	// Copy referenced schemas.
-	for _, ref := range refs {
+	// Copy referenced schemas, following refs between schemas (e.g.
+	// recursive messages) so the copied pool is self-contained.
+	copied := map[string]bool{}
+	for len(refs) > 0 {
+		ref := refs[0]
+		refs = refs[1:]
+		if copied[ref] {
+			continue
+		}
+		copied[ref] = true
 		if schema, ok := source.Schemas[ref]; ok {
 			target.Schemas[ref] = schema
+			refs = append(refs, collectRefs(schema)...)
+		}
+	}
+}
+
+// addSchemas copies the schemas pool derived from a source into iface,
+// marking each schema as managed with an x-ob marker naming the source.
+// Schemas the source already owns are replaced and identical schemas are
+// left as they are. A key held by a hand-authored schema, or by another
+// source's schema with different content, is not overwritten; it is
+// returned as a conflict instead.
+func addSchemas(iface *openbindings.Interface, sourceKey string, schemas map[string]openbindings.JSONSchema) []SyncConflict {
+	if len(schemas) == 0 {
+		return nil
+	}
+	if iface.Schemas == nil {
+		iface.Schemas = map[string]openbindings.JSONSchema{}
+	}
+	var conflicts []SyncConflict
+	for _, name := range slices.Sorted(maps.Keys(schemas)) {
+		schema := schemas[name]
+		existing, exists := iface.Schemas[name]
+		switch {
+		case !exists || schemaOwner(existing) == sourceKey:
+			marked := maps.Clone(schema)
+			marked[xobKey] = map[string]any{"source": sourceKey}
+			iface.Schemas[name] = marked
+		case !sameSchema(existing, schema):
+			local, _ := json.Marshal(existing)
+			fresh, _ := json.Marshal(schema)
+			conflicts = append(conflicts, SyncConflict{
+				Object:        "schema:" + name,
+				FieldConflict: FieldConflict{Local: local, Source: fresh},
+			})
+		}
+	}
+	return conflicts
+}
+
+// pruneSchemas removes the schemas sourceKey owns but no longer produces,
+// keeping any that operations still reference.
+func pruneSchemas(iface *openbindings.Interface, sourceKey string, schemas map[string]openbindings.JSONSchema) {
+	var stale []string
+	for name, schema := range iface.Schemas {
+		if _, ok := schemas[name]; !ok && schemaOwner(schema) == sourceKey {
+			stale = append(stale, name)
+		}
+	}
+	if len(stale) == 0 {
+		return
+	}
+	referenced := referencedSchemas(iface)
+	for _, name := range stale {
+		if !referenced[name] {
+			delete(iface.Schemas, name)
+		}
+	}
+}
+
+// schemaOwner returns the source key in a managed schema's x-ob marker, or
+// "" for a hand-authored schema.
+func schemaOwner(schema openbindings.JSONSchema) string {
+	marker, _ := schema[xobKey].(map[string]any)
+	owner, _ := marker["source"].(string)
+	return owner
+}
+
+// sameSchema reports whether two schemas have the same content, ignoring
+// x-ob markers.
+func sameSchema(a, b openbindings.JSONSchema) bool {
+	a, b = maps.Clone(a), maps.Clone(b)
+	delete(a, xobKey)
+	delete(b, xobKey)
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+// referencedSchemas returns the keys of the schemas reachable by $ref from
+// the interface's operations.
+func referencedSchemas(iface *openbindings.Interface) map[string]bool {
+	var refs []string
+	for _, op := range iface.Operations {
+		refs = append(refs, collectRefs(op.Input)...)
+		refs = append(refs, collectRefs(op.Output)...)
+		refs = append(refs, collectRefs(op.Payload)...)
+	}
+	seen := map[string]bool{}
+	for len(refs) > 0 {
+		ref := refs[0]
+		refs = refs[1:]
+		if seen[ref] {
+			continue
 		}
+		seen[ref] = true
+		refs = append(refs, collectRefs(iface.Schemas[ref])...)
 	}
+	return seen
 }
 
 // collectRefs extracts schema keys referenced by $ref in a schema.
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/openbindings/openbindings-go"
@@ -436,5 +437,75 @@ func TestCollectRefs_Nil(t *testing.T) {
 	}
 }
 
+func TestMigrateSchemaRefs_FollowsNestedRefs(t *testing.T) {
+	source := &openbindings.Interface{Schemas: map[string]openbindings.JSONSchema{
+		"Tree": {"type": "object", "properties": map[string]any{
+			"children": map[string]any{"type": "array", "items": map[string]any{"$ref": "#/schemas/Node"}},
+		}},
+		"Node": {"type": "object", "properties": map[string]any{
+			"tree": map[string]any{"$ref": "#/schemas/Tree"},
+		}},
+		"Unused": {"type": "string"},
+	}}
+	target := &openbindings.Interface{}
+	op := openbindings.Operation{Input: map[string]any{"$ref": "#/schemas/Tree"}}
+
+	migrateSchemaRefs(target, source, op)
+
+	if len(target.Schemas) != 2 || target.Schemas["Tree"] == nil || target.Schemas["Node"] == nil {
+		t.Errorf("schemas = %v, want Tree and Node", target.Schemas)
+	}
+}
+
+func TestAddSchemas(t *testing.T) {
+	iface := &openbindings.Interface{
+		Operations: map[string]openbindings.Operation{
+			"kept": {Input: map[string]any{"$ref": "#/schemas/Referenced"}},
+		},
+		Schemas: map[string]openbindings.JSONSchema{
+			"Manual":     {"type": "string"},
+			"Same":       {"type": "integer"},
+			"Other":      {"type": "boolean", "x-ob": map[string]any{"source": "other"}},
+			"Stale":      {"type": "null", "x-ob": map[string]any{"source": "api"}},
+			"Referenced": {"type": "null", "x-ob": map[string]any{"source": "api"}},
+			"Owned":      {"type": "number", "x-ob": map[string]any{"source": "api"}},
+		},
+	}
+
+	conflicts := addSchemas(iface, "api", map[string]openbindings.JSONSchema{
+		"Manual": {"type": "object"},
+		"Same":   {"type": "integer"},
+		"Other":  {"type": "array"},
+		"Owned":  {"type": "object"},
+		"New":    {"type": "string"},
+	})
+	pruneSchemas(iface, "api", map[string]openbindings.JSONSchema{
+		"Manual": {}, "Same": {}, "Other": {}, "Owned": {}, "New": {},
+	})
+
+	var objects []string
+	for _, c := range conflicts {
+		objects = append(objects, c.Object)
+	}
+	if want := []string{"schema:Manual", "schema:Other"}; !reflect.DeepEqual(objects, want) {
+		t.Errorf("conflicts = %v, want %v", objects, want)
+	}
+	if iface.Schemas["Manual"]["type"] != "string" || iface.Schemas["Other"]["type"] != "boolean" {
+		t.Errorf("schemas not owned by the source were overwritten: %v", iface.Schemas)
+	}
+	if _, ok := iface.Schemas["Same"]["x-ob"]; ok {
+		t.Error("an identical hand-authored schema should be left as it is")
+	}
+	if iface.Schemas["Owned"]["type"] != "object" || schemaOwner(iface.Schemas["New"]) != "api" {
+		t.Errorf("owned and new schemas = %v, %v", iface.Schemas["Owned"], iface.Schemas["New"])
+	}
+	if _, ok := iface.Schemas["Stale"]; ok {
+		t.Error("a schema the source no longer produces should be pruned")
+	}
+	if _, ok := iface.Schemas["Referenced"]; !ok {
+		t.Error("a stale schema still referenced by an operation should be kept")
+	}
+}
+
 // Suppress unused import warning for openbindings.
 var _ openbindings.Interface
@@ -0,0 +1,286 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/openbindings/cli/internal/delegates"
+	openbindings "github.com/openbindings/openbindings-go"
+)
+
+// paginationKey is the binding extension that declares how to page through
+// an operation's results. Unlike x-ob it is user-authored, so sync treats it
+// as a local edit and keeps it.
+const paginationKey = "x-ob-pagination"
+
+// Pagination styles for PaginationHint.Style.
+const (
+	// PaginationLink follows the rel="next" URL of the HTTP Link header,
+	// requesting it as is (see delegates.RequestURLKey).
+	PaginationLink = "link"
+	// PaginationCursor copies a cursor or page token from the output into
+	// the next page's input (e.g., gRPC next_page_token).
+	PaginationCursor = "cursor"
+	// PaginationOffset advances an offset input by the number of items received.
+	PaginationOffset = "offset"
+	// PaginationPage increments a page number input.
+	PaginationPage = "page"
+)
+
+// PaginationHint declares how to page through a binding's results. It is
+// stored on the binding entry under "x-ob-pagination".
+//
+// Paths are dot-separated and address the binding's native input and output:
+// the input after the input transform and the output before any output
+// transform. Output transforms are not applied when paging.
+type PaginationHint struct {
+	Style      string `json:"style"`
+	Items      string `json:"items,omitempty"`      // output path of the item array; empty means the output itself
+	Cursor     string `json:"cursor,omitempty"`     // cursor: output path of the next cursor
+	Param      string `json:"param,omitempty"`      // cursor, offset, page: input field to advance
+	LimitParam string `json:"limitParam,omitempty"` // offset, page: input field for the page size
+	Limit      int    `json:"limit,omitempty"`      // offset, page: page size; a short page ends paging
+	MaxPages   int    `json:"maxPages,omitempty"`   // stop after this many pages; 0 means no limit
+}
+
+// GetPaginationHint reads the pagination hint from a binding entry.
+// Returns nil, nil if the binding has none.
+func GetPaginationHint(b openbindings.BindingEntry) (*PaginationHint, error) {
+	if b.Extensions == nil {
+		return nil, nil
+	}
+	raw, ok := b.Extensions[paginationKey]
+	if !ok {
+		return nil, nil
+	}
+	var hint PaginationHint
+	if err := json.Unmarshal(raw, &hint); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", paginationKey, err)
+	}
+	if err := hint.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", paginationKey, err)
+	}
+	return &hint, nil
+}
+
+func (h PaginationHint) validate() error {
+	switch h.Style {
+	case PaginationLink:
+	case PaginationCursor:
+		if h.Cursor == "" || h.Param == "" {
+			return fmt.Errorf("cursor style requires cursor and param")
+		}
+	case PaginationOffset, PaginationPage:
+		if h.Param == "" {
+			return fmt.Errorf("%s style requires param", h.Style)
+		}
+	default:
+		return fmt.Errorf("unknown style %q (want link, cursor, offset or page)", h.Style)
+	}
+	return nil
+}
+
+// ExecuteOBIOperationPages executes a paginated operation from an OBI file,
+// following the binding's pagination hint until the last page and calling
+// emit for every item in order. Operation and binding selection work as in
+// ExecuteOBIOperation, and each page request follows the execution policy
+// resolved from the workspace, context and override.
+func ExecuteOBIOperationPages(ctx context.Context, obiPath string, opKey string, bindingKey string, input any, contextName string, override ExecPolicy, emit func(item any) error) error {
+	ctx = withContextCredentialSaver(ctx, contextName)
+	iface, err := resolveInterface(obiPath)
+	if err != nil {
+		return fmt.Errorf("failed to load OBI %q: %w", obiPath, err)
+	}
+
+	resolved, err := resolveBindingAndSource(iface, opKey, bindingKey, input, contextName, filepath.Dir(obiPath))
+	if err != nil {
+		return err
+	}
+
+	hint, err := GetPaginationHint(*resolved.binding)
+	if err != nil {
+		return err
+	}
+	if hint == nil {
+		return fmt.Errorf("binding for operation %q has no %s hint", resolved.binding.Operation, paginationKey)
+	}
+
+	pageInput := map[string]any{}
+	if resolved.input != nil {
+		m, ok := ToStringMap(resolved.input)
+		if !ok {
+			return fmt.Errorf("paginated operations require object input")
+		}
+		for k, v := range m {
+			pageInput[k] = v
+		}
+	}
+	if hint.LimitParam != "" && hint.Limit > 0 {
+		if _, ok := pageInput[hint.LimitParam]; !ok {
+			pageInput[hint.LimitParam] = hint.Limit
+		}
+	}
+
+	policy, err := ResolveExecPolicy(contextName, override)
+	if err != nil {
+		return err
+	}
+	idempotent := operationIdempotent(iface, resolved.binding.Operation)
+
+	delSource := resolveSourceLocation(resolved.source, filepath.Dir(obiPath))
+	bindCtx := withCallMetadata(ctx, resolved.bindCtx)
+	seen := map[string]bool{}
+
+	for page := 1; ; page++ {
+		result := executeWithPolicy(ctx, policy, idempotent, func(ctx context.Context) ExecuteOperationOutput {
+			return ExecuteOperationWithContext(ctx, ExecuteOperationInput{
+				Source:  ExecuteSource{Format: delSource.Format, Location: delSource.Location, Content: delSource.Content, Extensions: delSource.Extensions},
+				Ref:     resolved.binding.Ref,
+				Input:   pageInput,
+				Context: bindCtx,
+			})
+		})
+		if result.Error != nil {
+			return fmt.Errorf("page %d: %s", page, result.Error.Message)
+		}
+		if result.Status != 0 {
+			return fmt.Errorf("page %d: exit status %d", page, result.Status)
+		}
+
+		items, err := pageItems(result.Output, hint.Items)
+		if err != nil {
+			return fmt.Errorf("page %d: %w", page, err)
+		}
+		for _, item := range items {
+			if err := emit(item); err != nil {
+				return err
+			}
+		}
+
+		if hint.MaxPages > 0 && page >= hint.MaxPages {
+			return nil
+		}
+		if hint.Style == PaginationLink {
+			// The next page is requested at the Link target with the same
+			// input, whose query and path parameters the target replaces.
+			link := result.Links["next"]
+			if link == "" || seen[link] {
+				return nil
+			}
+			seen[link] = true
+			bindCtx = withMetadataEntry(bindCtx, delegates.RequestURLKey, link)
+			continue
+		}
+
+		next, ok := nextPageInput(*hint, pageInput, result, len(items))
+		if !ok {
+			return nil
+		}
+
+		// Guard against servers that keep returning the same cursor.
+		key, _ := json.Marshal(next)
+		if seen[string(key)] {
+			return nil
+		}
+		seen[string(key)] = true
+		pageInput = next
+	}
+}
+
+// withMetadataEntry returns a copy of bindCtx with one metadata entry set.
+func withMetadataEntry(bindCtx *delegates.BindingContext, key string, value any) *delegates.BindingContext {
+	return withCallMetadata(WithMetadata(context.Background(), map[string]any{key: value}), bindCtx)
+}
+
+// pageItems extracts the item array from a page's output.
+func pageItems(output any, path string) ([]any, error) {
+	value := lookupPath(output, path)
+	if value == nil {
+		return nil, nil
+	}
+	items, ok := value.([]any)
+	if !ok {
+		if path == "" {
+			return nil, fmt.Errorf("output is not an array; set items in %s", paginationKey)
+		}
+		return nil, fmt.Errorf("output %q is not an array", path)
+	}
+	return items, nil
+}
+
+// nextPageInput derives the input for the page after the one that produced
+// result, for the styles that page through input (all but link). It reports
+// false when there are no more pages.
+func nextPageInput(hint PaginationHint, current map[string]any, result ExecuteOperationOutput, count int) (map[string]any, bool) {
+	next := make(map[string]any, len(current)+1)
+	for k, v := range current {
+		next[k] = v
+	}
+
+	switch hint.Style {
+	case PaginationCursor:
+		cursor := lookupPath(result.Output, hint.Cursor)
+		if cursor == nil || cursor == "" {
+			return nil, false
+		}
+		next[hint.Param] = cursor
+	case PaginationOffset:
+		if count == 0 || (hint.Limit > 0 && count < hint.Limit) {
+			return nil, false
+		}
+		next[hint.Param] = numberOr(current[hint.Param], 0) + count
+	case PaginationPage:
+		if count == 0 || (hint.Limit > 0 && count < hint.Limit) {
+			return nil, false
+		}
+		next[hint.Param] = numberOr(current[hint.Param], 1) + 1
+	default:
+		return nil, false
+	}
+	return next, true
+}
+
+// lookupPath resolves a dot-separated path through nested objects.
+// An empty path returns v itself.
+func lookupPath(v any, path string) any {
+	if path == "" {
+		return v
+	}
+	for _, part := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[part]
+	}
+	return v
+}
+
+// numberOr interprets v as an integer, returning def when it is absent or
+// not numeric.
+func numberOr(v any, def int) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		if n == math.Trunc(n) {
+			return int(n)
+		}
+	case json.Number:
+		if i, err := n.Int64(); err == nil {
+			return int(i)
+		}
+	case string:
+		if i, err := strconv.Atoi(n); err == nil {
+			return i
+		}
+	}
+	return def
+}
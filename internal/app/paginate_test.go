@@ -0,0 +1,193 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	openbindings "github.com/openbindings/openbindings-go"
+)
+
+func TestGetPaginationHint(t *testing.T) {
+	b := openbindings.BindingEntry{Operation: "listPets", Source: "api"}
+	if hint, err := GetPaginationHint(b); hint != nil || err != nil {
+		t.Fatalf("no hint: got (%v, %v), want (nil, nil)", hint, err)
+	}
+
+	b.Extensions = map[string]json.RawMessage{
+		paginationKey: json.RawMessage(`{"style":"cursor","items":"items","cursor":"next","param":"cursor"}`),
+	}
+	hint, err := GetPaginationHint(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hint.Style != PaginationCursor || hint.Cursor != "next" || hint.Param != "cursor" {
+		t.Errorf("hint = %+v", hint)
+	}
+
+	b.Extensions[paginationKey] = json.RawMessage(`{"style":"cursor","cursor":"next"}`)
+	if _, err := GetPaginationHint(b); err == nil {
+		t.Error("expected error for cursor hint without param")
+	}
+	b.Extensions[paginationKey] = json.RawMessage(`{"style":"bogus"}`)
+	if _, err := GetPaginationHint(b); err == nil {
+		t.Error("expected error for unknown style")
+	}
+}
+
+func TestNextPageInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		hint    PaginationHint
+		current map[string]any
+		result  ExecuteOperationOutput
+		count   int
+		want    map[string]any
+	}{
+		{
+			name:    "cursor",
+			hint:    PaginationHint{Style: PaginationCursor, Cursor: "meta.next", Param: "pageToken"},
+			current: map[string]any{"filter": "dogs"},
+			result:  ExecuteOperationOutput{Output: map[string]any{"meta": map[string]any{"next": "abc"}}},
+			count:   10,
+			want:    map[string]any{"filter": "dogs", "pageToken": "abc"},
+		},
+		{
+			name:   "empty cursor ends",
+			hint:   PaginationHint{Style: PaginationCursor, Cursor: "next", Param: "pageToken"},
+			result: ExecuteOperationOutput{Output: map[string]any{"next": ""}},
+			count:  10,
+		},
+		{
+			name:    "offset advances by count",
+			hint:    PaginationHint{Style: PaginationOffset, Param: "offset", Limit: 2},
+			current: map[string]any{"offset": float64(4)},
+			count:   2,
+			want:    map[string]any{"offset": 6},
+		},
+		{
+			name:  "short offset page ends",
+			hint:  PaginationHint{Style: PaginationOffset, Param: "offset", Limit: 2},
+			count: 1,
+		},
+		{
+			name:    "page increments from default",
+			hint:    PaginationHint{Style: PaginationPage, Param: "page"},
+			current: map[string]any{},
+			count:   5,
+			want:    map[string]any{"page": 2},
+		},
+		{
+			name:  "empty page ends",
+			hint:  PaginationHint{Style: PaginationPage, Param: "page"},
+			count: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := tt.current
+			if current == nil {
+				current = map[string]any{}
+			}
+			got, ok := nextPageInput(tt.hint, current, tt.result, tt.count)
+			if tt.want == nil {
+				if ok {
+					t.Fatalf("expected last page, got next input %v", got)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("expected another page")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageItems(t *testing.T) {
+	items, err := pageItems(map[string]any{"data": map[string]any{"items": []any{"a", "b"}}}, "data.items")
+	if err != nil || len(items) != 2 {
+		t.Errorf("nested: got (%v, %v)", items, err)
+	}
+	items, err = pageItems([]any{"a"}, "")
+	if err != nil || len(items) != 1 {
+		t.Errorf("root array: got (%v, %v)", items, err)
+	}
+	if _, err := pageItems(map[string]any{"items": "nope"}, "items"); err == nil {
+		t.Error("expected error for non-array items")
+	}
+}
+
+func TestExecuteOBIOperationPages_LinkHeader(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The Link targets carry an opaque token the spec does not declare,
+		// on a path other than the operation's.
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path + "?" + r.URL.RawQuery {
+		case "/pets?limit=2":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/pets/page?page_token=b>; rel="next"`, server.URL))
+			fmt.Fprint(w, `[{"id":1},{"id":2}]`)
+		case "/pets/page?page_token=b":
+			w.Header().Set("Link", `</pets/page?page_token=c>; rel="next"`)
+			fmt.Fprint(w, `[{"id":3}]`)
+		case "/pets/page?page_token=c":
+			fmt.Fprint(w, `[{"id":4}]`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	obi := writeOBIFile(t, dir, map[string]any{
+		"openbindings": "0.1.0",
+		"operations": map[string]any{
+			"listPets": map[string]any{"kind": "method"},
+		},
+		"sources": map[string]any{
+			"api": map[string]any{
+				"format": "openapi@3.1",
+				"content": map[string]any{
+					"openapi": "3.1.0",
+					"info":    map[string]any{"title": "Pets", "version": "1.0.0"},
+					"servers": []any{map[string]any{"url": server.URL}},
+					"paths": map[string]any{
+						"/pets": map[string]any{
+							"get": map[string]any{
+								"parameters": []any{map[string]any{"name": "limit", "in": "query", "schema": map[string]any{"type": "integer"}}},
+								"responses":  map[string]any{"200": map[string]any{"description": "ok"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		"bindings": map[string]any{
+			"listPets.api": map[string]any{
+				"operation":       "listPets",
+				"source":          "api",
+				"ref":             "#/paths/~1pets/get",
+				"x-ob-pagination": map[string]any{"style": "link"},
+			},
+		},
+	})
+
+	var ids []any
+	err := ExecuteOBIOperationPages(context.Background(), obi, "listPets", "", map[string]any{"limit": 2}, "", ExecPolicy{}, func(item any) error {
+		ids = append(ids, item.(map[string]any)["id"])
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExecuteOBIOperationPages: %v", err)
+	}
+	if want := []any{float64(1), float64(2), float64(3), float64(4)}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+}
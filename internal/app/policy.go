@@ -0,0 +1,303 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	openbindings "github.com/openbindings/openbindings-go"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// Duration is a time.Duration that is written as a Go duration string
+// (e.g., "30s", "250ms") in workspace and context config files.
+type Duration time.Duration
+
+// NoTimeout is the Timeout of a policy that explicitly disables per-attempt
+// timeouts, including the built-in defaults of network handlers. It is
+// written "none" in config files.
+const NoTimeout Duration = -1
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	if d == NoTimeout {
+		return json.Marshal("none")
+	}
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"30s\": %w", err)
+	}
+	if s == "none" {
+		*d = NoTimeout
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// ExecPolicy controls how long an operation attempt may run and how failed
+// attempts are retried. Policies are layered: built-in defaults, then the
+// workspace's settings.execution, then the selected context's execution,
+// then per-invocation overrides. Zero-valued fields inherit from the layer
+// below.
+//
+// The built-in defaults set no Timeout: network handlers bound each request
+// by their own default, while CLI and exec formats run until they exit. A
+// Timeout of NoTimeout disables both.
+//
+// Retries only happen automatically for operations marked idempotent in the
+// OBI; set RetryNonIdempotent to retry other operations too.
+type ExecPolicy struct {
+	Timeout            Duration `json:"timeout,omitempty"`            // Per-attempt timeout, or "none"
+	MaxAttempts        int      `json:"maxAttempts,omitempty"`        // Total attempts, including the first
+	InitialBackoff     Duration `json:"initialBackoff,omitempty"`     // Delay before the first retry; doubles per retry
+	MaxBackoff         Duration `json:"maxBackoff,omitempty"`         // Upper bound on any delay, including Retry-After
+	RetryStatus        []int    `json:"retryStatus,omitempty"`        // HTTP response status codes that are retried
+	RetryGRPCCodes     []string `json:"retryGrpcCodes,omitempty"`     // gRPC status codes that are retried (e.g., "UNAVAILABLE")
+	RetryNonIdempotent *bool    `json:"retryNonIdempotent,omitempty"` // Retry operations not marked idempotent
+}
+
+// transientErrorCodes are delegate error codes for failures where no
+// response was received, which are retried like retryable statuses.
+var transientErrorCodes = []string{"request_failed", "connect_failed", "sse_connect_failed"}
+
+// DefaultExecPolicy returns the built-in execution policy.
+func DefaultExecPolicy() ExecPolicy {
+	return ExecPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: Duration(200 * time.Millisecond),
+		MaxBackoff:     Duration(10 * time.Second),
+		RetryStatus:    []int{408, 425, 429, 500, 502, 503, 504},
+		RetryGRPCCodes: []string{"UNAVAILABLE", "RESOURCE_EXHAUSTED", "ABORTED"},
+	}
+}
+
+// Merge returns p with every non-zero field of o applied on top.
+func (p ExecPolicy) Merge(o ExecPolicy) ExecPolicy {
+	if o.Timeout != 0 {
+		p.Timeout = o.Timeout
+	}
+	if o.MaxAttempts != 0 {
+		p.MaxAttempts = o.MaxAttempts
+	}
+	if o.InitialBackoff != 0 {
+		p.InitialBackoff = o.InitialBackoff
+	}
+	if o.MaxBackoff != 0 {
+		p.MaxBackoff = o.MaxBackoff
+	}
+	if o.RetryStatus != nil {
+		p.RetryStatus = o.RetryStatus
+	}
+	if o.RetryGRPCCodes != nil {
+		p.RetryGRPCCodes = o.RetryGRPCCodes
+	}
+	if o.RetryNonIdempotent != nil {
+		p.RetryNonIdempotent = o.RetryNonIdempotent
+	}
+	return p
+}
+
+// Validate reports whether the policy's values are usable.
+func (p ExecPolicy) Validate() error {
+	if (p.Timeout < 0 && p.Timeout != NoTimeout) || p.InitialBackoff < 0 || p.MaxBackoff < 0 {
+		return fmt.Errorf("execution policy durations must not be negative")
+	}
+	if p.MaxAttempts < 1 {
+		return fmt.Errorf("execution policy maxAttempts must be at least 1, got %d", p.MaxAttempts)
+	}
+	return nil
+}
+
+// TimeoutFromFlag converts a --timeout flag value to a policy Timeout, where
+// 0 disables the timeout.
+func TimeoutFromFlag(d time.Duration) (Duration, error) {
+	switch {
+	case d < 0:
+		return 0, fmt.Errorf("--timeout must not be negative")
+	case d == 0:
+		return NoTimeout, nil
+	}
+	return Duration(d), nil
+}
+
+// ResolveExecPolicy layers the workspace and context execution settings and
+// the given per-invocation override on top of the defaults.
+func ResolveExecPolicy(contextName string, override ExecPolicy) (ExecPolicy, error) {
+	policy := DefaultExecPolicy()
+
+	if ws, _, _, err := RequireActiveWorkspace(); err == nil && ws.Settings.Execution != nil {
+		policy = policy.Merge(*ws.Settings.Execution)
+	}
+
+	if contextName != "" {
+		cfg, err := LoadContextConfig(contextName)
+		if err != nil {
+			return ExecPolicy{}, err
+		}
+		if cfg.Execution != nil {
+			policy = policy.Merge(*cfg.Execution)
+		}
+	}
+
+	policy = policy.Merge(override)
+	if err := policy.Validate(); err != nil {
+		return ExecPolicy{}, err
+	}
+	return policy, nil
+}
+
+// operationIdempotent reports whether the OBI marks an operation idempotent.
+func operationIdempotent(iface *openbindings.Interface, opKey string) bool {
+	op, ok := iface.Operations[opKey]
+	return ok && op.Idempotent != nil && *op.Idempotent
+}
+
+// executeWithPolicy runs attempt under the policy's per-attempt timeout,
+// retrying retryable failures with exponential backoff and jitter. When
+// idempotent is false and the policy does not force retries, attempt runs
+// exactly once.
+func executeWithPolicy(ctx context.Context, policy ExecPolicy, idempotent bool, attempt func(ctx context.Context) ExecuteOperationOutput) ExecuteOperationOutput {
+	maxAttempts := policy.MaxAttempts
+	if !idempotent && (policy.RetryNonIdempotent == nil || !*policy.RetryNonIdempotent) {
+		maxAttempts = 1
+	}
+
+	start := time.Now()
+	backoff := time.Duration(policy.InitialBackoff)
+	for n := 1; ; n++ {
+		attemptCtx, cancel := policy.attemptContext(ctx)
+		result := attempt(attemptCtx)
+		timedOut := attemptCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil
+		cancel()
+
+		if n > 1 {
+			result.Attempts = n
+			result.DurationMs = time.Since(start).Milliseconds()
+		}
+		if n >= maxAttempts || ctx.Err() != nil {
+			return result
+		}
+
+		retry, retryAfter := policy.retryable(result, timedOut)
+		if !retry {
+			return result
+		}
+		delay := jitter(backoff)
+		if retryAfter > 0 {
+			if retryAfter > time.Duration(policy.MaxBackoff) {
+				return result
+			}
+			delay = retryAfter
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, time.Duration(policy.MaxBackoff))
+	}
+}
+
+// attemptContext returns ctx bounded by the policy's per-attempt timeout.
+func (p ExecPolicy) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	switch {
+	case p.Timeout == NoTimeout:
+		return context.WithCancel(delegates.WithoutDefaultTimeout(ctx))
+	case p.Timeout > 0:
+		return context.WithTimeout(ctx, time.Duration(p.Timeout))
+	}
+	return context.WithCancel(ctx)
+}
+
+// retryable reports whether a failed attempt should be retried and, if the
+// server asked for one, how long to wait first.
+func (p ExecPolicy) retryable(result ExecuteOperationOutput, timedOut bool) (bool, time.Duration) {
+	if result.Error == nil && result.Status == 0 {
+		return false, 0
+	}
+	retryAfter := retryAfterHint(result.Error)
+
+	if timedOut {
+		return true, retryAfter
+	}
+	if result.Error == nil {
+		return false, 0
+	}
+	if isHTTPStatusError(result) && slices.Contains(p.RetryStatus, result.Status) {
+		return true, retryAfter
+	}
+	if slices.Contains(transientErrorCodes, result.Error.Code) {
+		return true, retryAfter
+	}
+	if code := errorDetail(result.Error, delegates.GRPCCodeDetailsKey); code != nil {
+		name := normalizeGRPCCode(fmt.Sprint(code))
+		for _, c := range p.RetryGRPCCodes {
+			if normalizeGRPCCode(c) == name {
+				return true, retryAfter
+			}
+		}
+	}
+	return false, 0
+}
+
+// isHTTPStatusError reports whether a result's status is an HTTP response
+// status, rather than an exit code or a delegate's generic failure status.
+func isHTTPStatusError(result ExecuteOperationOutput) bool {
+	return result.Error.Code == fmt.Sprintf("http_%d", result.Status)
+}
+
+// retryAfterHint returns the Retry-After delay a delegate recorded in the
+// error details, or zero.
+func retryAfterHint(e *Error) time.Duration {
+	switch v := errorDetail(e, delegates.RetryAfterDetailsKey).(type) {
+	case int64:
+		return time.Duration(v) * time.Millisecond
+	case float64: // decoded from an external delegate's JSON output
+		return time.Duration(v) * time.Millisecond
+	}
+	return 0
+}
+
+func errorDetail(e *Error, key string) any {
+	if e == nil {
+		return nil
+	}
+	details, ok := e.Details.(map[string]any)
+	if !ok {
+		return nil
+	}
+	return details[key]
+}
+
+// normalizeGRPCCode folds the spellings "UNAVAILABLE", "Unavailable" and
+// "RESOURCE_EXHAUSTED"/"ResourceExhausted" together.
+func normalizeGRPCCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, "_", ""))
+}
+
+// jitter returns a random delay in [d/2, d).
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	half := d / 2
+	return half + rand.N(half)
+}
@@ -0,0 +1,212 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/openbindings/cli/internal/delegates"
+	usagehandler "github.com/openbindings/cli/internal/delegates/usage"
+)
+
+func TestExecPolicyMerge(t *testing.T) {
+	force := true
+	base := DefaultExecPolicy()
+	got := base.Merge(ExecPolicy{Timeout: Duration(5 * time.Second), RetryStatus: []int{503}, RetryNonIdempotent: &force})
+
+	if got.Timeout != Duration(5*time.Second) {
+		t.Errorf("Timeout = %v, want 5s", time.Duration(got.Timeout))
+	}
+	if got.MaxAttempts != base.MaxAttempts {
+		t.Errorf("MaxAttempts = %d, want inherited %d", got.MaxAttempts, base.MaxAttempts)
+	}
+	if len(got.RetryStatus) != 1 || got.RetryStatus[0] != 503 {
+		t.Errorf("RetryStatus = %v, want [503]", got.RetryStatus)
+	}
+	if got.RetryNonIdempotent == nil || !*got.RetryNonIdempotent {
+		t.Error("RetryNonIdempotent should be overridden to true")
+	}
+}
+
+func TestExecPolicyJSON(t *testing.T) {
+	var p ExecPolicy
+	if err := json.Unmarshal([]byte(`{"timeout":"1m30s","maxAttempts":4,"initialBackoff":"250ms"}`), &p); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if p.Timeout != Duration(90*time.Second) || p.MaxAttempts != 4 || p.InitialBackoff != Duration(250*time.Millisecond) {
+		t.Errorf("policy = %+v", p)
+	}
+
+	data, err := json.Marshal(ExecPolicy{Timeout: Duration(10 * time.Second)})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"timeout":"10s"}` {
+		t.Errorf("marshal = %s", data)
+	}
+
+	var none ExecPolicy
+	if err := json.Unmarshal([]byte(`{"timeout":"none"}`), &none); err != nil || none.Timeout != NoTimeout {
+		t.Errorf("none = %+v, %v", none, err)
+	}
+	if data, _ := json.Marshal(none); string(data) != `{"timeout":"none"}` {
+		t.Errorf("marshal none = %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"timeout":30}`), &p); err == nil {
+		t.Error("expected error for numeric duration")
+	}
+}
+
+func TestExecPolicyRetryable(t *testing.T) {
+	p := DefaultExecPolicy()
+	tests := []struct {
+		name       string
+		result     ExecuteOperationOutput
+		timedOut   bool
+		want       bool
+		retryAfter time.Duration
+	}{
+		{"success", ExecuteOperationOutput{}, false, false, 0},
+		{"http 503", ExecuteOperationOutput{Status: 503, Error: &Error{Code: "http_503"}}, false, true, 0},
+		{"http 404", ExecuteOperationOutput{Status: 404, Error: &Error{Code: "http_404"}}, false, false, 0},
+		{"retry after", ExecuteOperationOutput{Status: 429, Error: &Error{Code: "http_429", Details: map[string]any{delegates.RetryAfterDetailsKey: int64(1500)}}}, false, true, 1500 * time.Millisecond},
+		{"retry after from JSON", ExecuteOperationOutput{Status: 503, Error: &Error{Code: "http_503", Details: map[string]any{delegates.RetryAfterDetailsKey: float64(2000)}}}, false, true, 2 * time.Second},
+		{"exit code matching a retry status", ExecuteOperationOutput{Status: 503, Error: &Error{Code: "exit_error"}}, false, false, 0},
+		{"transport error", ExecuteOperationOutput{Status: 1, Error: &Error{Code: "request_failed"}}, false, true, 0},
+		{"grpc unavailable", ExecuteOperationOutput{Status: 1, Error: &Error{Code: "rpc_failed", Details: map[string]any{delegates.GRPCCodeDetailsKey: "Unavailable"}}}, false, true, 0},
+		{"grpc invalid argument", ExecuteOperationOutput{Status: 1, Error: &Error{Code: "rpc_failed", Details: map[string]any{delegates.GRPCCodeDetailsKey: "InvalidArgument"}}}, false, false, 0},
+		{"attempt timed out", ExecuteOperationOutput{Status: 1, Error: &Error{Code: "cancelled"}}, true, true, 0},
+		{"bad input", ExecuteOperationOutput{Status: 1, Error: &Error{Code: "invalid_input"}}, false, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, retryAfter := p.retryable(tt.result, tt.timedOut)
+			if got != tt.want || retryAfter != tt.retryAfter {
+				t.Errorf("retryable = (%v, %v), want (%v, %v)", got, retryAfter, tt.want, tt.retryAfter)
+			}
+		})
+	}
+}
+
+func TestExecuteWithPolicy(t *testing.T) {
+	fast := DefaultExecPolicy().Merge(ExecPolicy{
+		InitialBackoff: Duration(time.Millisecond),
+		MaxBackoff:     Duration(5 * time.Millisecond),
+	})
+	failing := func(calls *int) func(context.Context) ExecuteOperationOutput {
+		return func(context.Context) ExecuteOperationOutput {
+			*calls++
+			return ExecuteOperationOutput{Status: 503, Error: &Error{Code: "http_503"}}
+		}
+	}
+
+	t.Run("idempotent retries up to max attempts", func(t *testing.T) {
+		calls := 0
+		result := executeWithPolicy(context.Background(), fast, true, failing(&calls))
+		if calls != 3 || result.Attempts != 3 {
+			t.Errorf("calls = %d, attempts = %d, want 3", calls, result.Attempts)
+		}
+	})
+
+	t.Run("non-idempotent runs once", func(t *testing.T) {
+		calls := 0
+		result := executeWithPolicy(context.Background(), fast, false, failing(&calls))
+		if calls != 1 || result.Attempts != 0 {
+			t.Errorf("calls = %d, attempts = %d, want 1 call", calls, result.Attempts)
+		}
+	})
+
+	t.Run("forced retry of non-idempotent", func(t *testing.T) {
+		force := true
+		calls := 0
+		executeWithPolicy(context.Background(), fast.Merge(ExecPolicy{RetryNonIdempotent: &force}), false, failing(&calls))
+		if calls != 3 {
+			t.Errorf("calls = %d, want 3", calls)
+		}
+	})
+
+	t.Run("stops on success", func(t *testing.T) {
+		calls := 0
+		result := executeWithPolicy(context.Background(), fast, true, func(context.Context) ExecuteOperationOutput {
+			calls++
+			if calls < 2 {
+				return ExecuteOperationOutput{Status: 1, Error: &Error{Code: "request_failed"}}
+			}
+			return ExecuteOperationOutput{Output: "ok"}
+		})
+		if calls != 2 || result.Output != "ok" || result.Attempts != 2 {
+			t.Errorf("calls = %d, result = %+v", calls, result)
+		}
+	})
+
+	t.Run("retry after beyond max backoff is not waited for", func(t *testing.T) {
+		calls := 0
+		executeWithPolicy(context.Background(), fast, true, func(context.Context) ExecuteOperationOutput {
+			calls++
+			return ExecuteOperationOutput{Status: 429, Error: &Error{Code: "http_429", Details: map[string]any{delegates.RetryAfterDetailsKey: int64(60000)}}}
+		})
+		if calls != 1 {
+			t.Errorf("calls = %d, want 1", calls)
+		}
+	})
+
+	t.Run("per-attempt timeout", func(t *testing.T) {
+		p := fast.Merge(ExecPolicy{Timeout: Duration(10 * time.Millisecond), MaxAttempts: 2})
+		calls := 0
+		result := executeWithPolicy(context.Background(), p, true, func(ctx context.Context) ExecuteOperationOutput {
+			calls++
+			<-ctx.Done()
+			return ExecuteOperationOutput{Status: 1, Error: &Error{Code: "request_failed", Message: ctx.Err().Error()}}
+		})
+		if calls != 2 || result.Error == nil {
+			t.Errorf("calls = %d, result = %+v", calls, result)
+		}
+	})
+	t.Run("no timeout", func(t *testing.T) {
+		p := fast.Merge(ExecPolicy{Timeout: NoTimeout})
+		executeWithPolicy(context.Background(), p, true, func(ctx context.Context) ExecuteOperationOutput {
+			ctx, cancel := delegates.WithDefaultTimeout(ctx, time.Millisecond)
+			defer cancel()
+			if _, ok := ctx.Deadline(); ok {
+				t.Error("handler default timeout should be disabled")
+			}
+			return ExecuteOperationOutput{}
+		})
+	})
+}
+
+func TestSubscribeWithPolicyTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	p := DefaultExecPolicy().Merge(ExecPolicy{Timeout: Duration(200 * time.Millisecond)})
+	ch, err := subscribeWithPolicy(context.Background(), p, func(ctx context.Context) (<-chan StreamEvent, error) {
+		return usagehandler.Subscribe(ctx, usagehandler.ExecuteInput{
+			Source: usagehandler.Source{Binary: "sh"},
+			Ref:    `-c 'echo started; exec sleep 10'`,
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var data []any
+	var last *Error
+	for ev := range ch {
+		if ev.Error != nil {
+			last = ev.Error
+			continue
+		}
+		data = append(data, ev.Data)
+	}
+	if want := []any{map[string]any{"stdout": "started"}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("events = %v, want %v", data, want)
+	}
+	if last == nil || last.Code != "timeout" {
+		t.Fatalf("final error = %+v, want a timeout", last)
+	}
+}
@@ -5,9 +5,11 @@ import (
 
 	"github.com/openbindings/cli/internal/delegates"
 	asyncapihandler "github.com/openbindings/cli/internal/delegates/asyncapi"
+	graphqlhandler "github.com/openbindings/cli/internal/delegates/graphql"
 	grpchandler "github.com/openbindings/cli/internal/delegates/grpc"
 	mcphandler "github.com/openbindings/cli/internal/delegates/mcp"
 	openapihandler "github.com/openbindings/cli/internal/delegates/openapi"
+	openrpchandler "github.com/openbindings/cli/internal/delegates/openrpc"
 	usagehandler "github.com/openbindings/cli/internal/delegates/usage"
 )
 
@@ -30,6 +32,15 @@ func DefaultRegistry() *delegates.Registry {
 		openapihandler.Register(defaultRegistry)
 		asyncapihandler.Register(defaultRegistry)
 		grpchandler.Register(defaultRegistry)
+		graphqlhandler.Register(defaultRegistry)
+		openrpchandler.Register(defaultRegistry)
 	})
 	return defaultRegistry
 }
+
+// CloseDelegates releases connections that builtin handlers keep open
+// between operations, such as MCP sessions. Call it once the process is done
+// executing operations.
+func CloseDelegates() error {
+	return DefaultRegistry().Close()
+}
@@ -65,7 +65,11 @@ func (o SyncOutput) Render() string {
 		sb.WriteString(s.Warning.Render(fmt.Sprintf("  %d conflict(s) (local values kept):", len(o.Conflicts))))
 		for _, c := range o.Conflicts {
 			sb.WriteString("\n")
-			sb.WriteString(s.Warning.Render(fmt.Sprintf("    %s → %s", c.Object, c.Field)))
+			if c.Field == "" {
+				sb.WriteString(s.Warning.Render("    " + c.Object))
+			} else {
+				sb.WriteString(s.Warning.Render(fmt.Sprintf("    %s → %s", c.Object, c.Field)))
+			}
 		}
 	}
 	if o.Pure {
@@ -227,6 +231,7 @@ func Sync(input SyncInput) (SyncOutput, error) {
 		opsUpdated, opsAdded     []string
 		bindsUpdated, bindsAdded []string
 		conflicts                []SyncConflict
+		derivedSchemas           = map[string]map[string]openbindings.JSONSchema{}
 	)
 
 	for _, key := range syncedKeys {
@@ -246,6 +251,9 @@ func Sync(input SyncInput) (SyncOutput, error) {
 			}
 		}
 
+		conflicts = append(conflicts, addSchemas(iface, key, derived.Schemas)...)
+		derivedSchemas[key] = derived.Schemas
+
 		// The source-derived fields serve as the new base after this sync.
 		for opKey, freshOp := range derived.Operations {
 			if opFilter != nil {
@@ -389,6 +397,12 @@ func Sync(input SyncInput) (SyncOutput, error) {
 		}
 	}
 
+	// Drop the schemas sources no longer produce, now that the operations
+	// referring to them have been updated.
+	for key, schemas := range derivedSchemas {
+		pruneSchemas(iface, key, schemas)
+	}
+
 	// Strip x-ob metadata if --pure.
 	if input.Pure {
 		StripAllXOB(iface)
@@ -436,6 +450,7 @@ func remapBindings(iface openbindings.Interface, sourceKey string) DeriveResult
 	return DeriveResult{
 		Operations:  iface.Operations,
 		Bindings:    bindings,
+		Schemas:     iface.Schemas,
 		Name:        iface.Name,
 		Description: iface.Description,
 		Version:     iface.Version,
@@ -46,8 +46,10 @@ type WorkspaceTarget struct {
 
 // WorkspaceSettings represents workspace settings.
 type WorkspaceSettings struct {
-	Editor       string `json:"editor,omitempty"`
-	OutputFormat string `json:"outputFormat,omitempty"`
+	Editor       string            `json:"editor,omitempty"`
+	OutputFormat string            `json:"outputFormat,omitempty"`
+	Execution    *ExecPolicy       `json:"execution,omitempty"` // Timeout and retry policy for operation execution
+	Sampling     *SamplingSettings `json:"sampling,omitempty"`  // Operation that answers MCP sampling requests
 }
 
 // WorkspaceUI holds TUI state for session restoration.
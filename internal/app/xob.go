@@ -167,6 +167,11 @@ func StripAllXOB(iface *openbindings.Interface) {
 			iface.Bindings[k] = b
 		}
 	}
+
+	// Schemas.
+	for _, schema := range iface.Schemas {
+		delete(schema, xobKey)
+	}
 }
 
 // HashContent returns "sha256:<hex>" for the given data.
@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/openbindings/cli/internal/app"
 	"github.com/openbindings/cli/internal/delegates"
@@ -82,6 +85,10 @@ func newContextSetCmd() *cobra.Command {
 		cookies     []string
 		envVars     []string
 		metaEntries []string
+		timeout     time.Duration
+		maxAttempts int
+		forceRetry  bool
+		transport   delegates.TransportConfig
 	)
 
 	cmd := &cobra.Command{
@@ -96,6 +103,24 @@ you'll be prompted to enter it securely.
 Non-secret flags (--header, --cookie, --env, --meta) are stored in
 a config file and can be specified multiple times.
 
+Transport flags (--ca-file, --client-cert, --client-key, --proxy,
+--server-name, --insecure-skip-verify) configure how network bindings
+(OpenAPI, AsyncAPI, MCP, gRPC) connect. Pass an empty value to clear one.
+
+CLI bindings (usage specs) run commands with the --env variables layered
+over ob's own environment. The metadata keys cleanEnv=true (start from
+only the --env variables), workingDir=<dir> and stdinFile=<file> (fed
+to the command's standard input unless the input has a "stdin" field)
+further configure the command's process.
+
+OpenAPI bindings write binary responses to the file named by the
+metadata key responseFile=<file> instead of returning them as base64
+(see also ob op exec --response-file).
+
+Execution flags (--timeout, --max-attempts, --force-retry) set the
+context's execution policy, which overrides the workspace's
+settings.execution when the context is used.
+
 Examples:
   ob context set github --bearer-token
   ob context set github --bearer-token=ghp_xxxx
@@ -103,7 +128,11 @@ Examples:
   ob context set myapi --basic
   ob context set github --header "Accept: application/vnd.github+json"
   ob context set myapi --env "API_URL=https://api.example.com"
-  ob context set myapi --meta "org=acme"`,
+  ob context set myapi --meta "org=acme"
+  ob context set mycli --env "TOKEN=xxx" --meta cleanEnv=true --meta workingDir=/srv/app
+  ob context set staging --timeout 10s --max-attempts 5
+  ob context set corp --proxy http://proxy.corp:3128 --ca-file corp-ca.pem
+  ob context set internal --client-cert client.pem --client-key client.key`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
@@ -222,8 +251,39 @@ Examples:
 				cfgChanged = true
 			}
 
+			transportChanged, err := applyTransportFlags(cmd, &cfg, transport)
+			if err != nil {
+				return app.ExitResult{Code: 1, Message: err.Error(), ToStderr: true}
+			}
+			if transportChanged {
+				cfgChanged = true
+			}
+
+			if cmd.Flags().Changed("timeout") || cmd.Flags().Changed("max-attempts") || cmd.Flags().Changed("force-retry") {
+				if cfg.Execution == nil {
+					cfg.Execution = &app.ExecPolicy{}
+				}
+				if cmd.Flags().Changed("timeout") {
+					t, err := app.TimeoutFromFlag(timeout)
+					if err != nil {
+						return app.ExitResult{Code: 1, Message: err.Error(), ToStderr: true}
+					}
+					cfg.Execution.Timeout = t
+				}
+				if cmd.Flags().Changed("max-attempts") {
+					if maxAttempts < 1 {
+						return app.ExitResult{Code: 1, Message: "--max-attempts must be at least 1", ToStderr: true}
+					}
+					cfg.Execution.MaxAttempts = maxAttempts
+				}
+				if cmd.Flags().Changed("force-retry") {
+					cfg.Execution.RetryNonIdempotent = &forceRetry
+				}
+				cfgChanged = true
+			}
+
 			if !credChanged && !cfgChanged {
-				return app.ExitResult{Code: 1, Message: "no fields specified; use --bearer-token, --api-key, --basic, --header, --cookie, --env, or --meta", ToStderr: true}
+				return app.ExitResult{Code: 1, Message: "no fields specified; use --bearer-token, --api-key, --basic, --header, --cookie, --env, --meta, a transport flag, or an execution flag", ToStderr: true}
 			}
 
 			if credChanged {
@@ -250,10 +310,70 @@ Examples:
 	cmd.Flags().StringArrayVar(&cookies, "cookie", nil, "add cookie as \"Key=Value\" (repeatable)")
 	cmd.Flags().StringArrayVar(&envVars, "env", nil, "add env var as \"VAR=value\" (repeatable)")
 	cmd.Flags().StringArrayVar(&metaEntries, "meta", nil, "add metadata as \"key=value\" (repeatable)")
+	cmd.Flags().StringVar(&transport.CAFile, "ca-file", "", "PEM CA bundle to trust in addition to system roots")
+	cmd.Flags().StringVar(&transport.ClientCertFile, "client-cert", "", "PEM client certificate for mTLS")
+	cmd.Flags().StringVar(&transport.ClientKeyFile, "client-key", "", "PEM private key for --client-cert")
+	cmd.Flags().StringVar(&transport.ProxyURL, "proxy", "", "HTTP(S) proxy URL (overrides HTTP_PROXY/HTTPS_PROXY)")
+	cmd.Flags().StringVar(&transport.ServerName, "server-name", "", "TLS server name (SNI) override")
+	cmd.Flags().BoolVar(&transport.InsecureSkipVerify, "insecure-skip-verify", false, "skip TLS server certificate verification")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "per-attempt execution timeout (0 disables it)")
+	cmd.Flags().IntVar(&maxAttempts, "max-attempts", 0, "total attempts for retryable failures")
+	cmd.Flags().BoolVar(&forceRetry, "force-retry", false, "retry operations that are not marked idempotent")
 
 	return cmd
 }
 
+// applyTransportFlags copies the transport flags set on cmd onto cfg,
+// resolving file paths to absolute paths so the context works from any
+// directory. It reports whether any transport flag was set.
+func applyTransportFlags(cmd *cobra.Command, cfg *app.ContextConfig, flags delegates.TransportConfig) (bool, error) {
+	var t delegates.TransportConfig
+	if cfg.Transport != nil {
+		t = *cfg.Transport
+	}
+
+	changed := false
+	set := func(name string, dst *string, value string, isPath bool) error {
+		if !cmd.Flags().Changed(name) {
+			return nil
+		}
+		if isPath && value != "" {
+			abs, err := filepath.Abs(value)
+			if err != nil {
+				return fmt.Errorf("--%s: %w", name, err)
+			}
+			value = abs
+		}
+		*dst = value
+		changed = true
+		return nil
+	}
+	err := errors.Join(
+		set("ca-file", &t.CAFile, flags.CAFile, true),
+		set("client-cert", &t.ClientCertFile, flags.ClientCertFile, true),
+		set("client-key", &t.ClientKeyFile, flags.ClientKeyFile, true),
+		set("proxy", &t.ProxyURL, flags.ProxyURL, false),
+		set("server-name", &t.ServerName, flags.ServerName, false),
+	)
+	if err != nil {
+		return false, err
+	}
+	if cmd.Flags().Changed("insecure-skip-verify") {
+		t.InsecureSkipVerify = flags.InsecureSkipVerify
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+
+	if t == (delegates.TransportConfig{}) {
+		cfg.Transport = nil
+	} else {
+		cfg.Transport = &t
+	}
+	return true, nil
+}
+
 func newContextRemoveCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:     "remove <name>",
@@ -20,6 +20,7 @@ delegates are discovered by probing the interface contracts they satisfy.`,
 		newDelegateRemoveCmd(),
 		newDelegatePreferCmd(),
 		newDelegateResolveCmd(),
+		newDelegateRefreshCmd(),
 	)
 
 	return c
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/openbindings/cli/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func newDelegateRefreshCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "refresh [url]",
+		Short: "Re-probe delegates and refresh the capability cache",
+		Long: `Re-probe delegates and replace their cached capabilities.
+
+Probe results (interface and supported formats) are cached on disk so format
+resolution does not run every delegate on each invocation. Cached entries
+expire after 24 hours, when a CLI delegate's binary changes, or when an HTTP
+delegate's interface ETag changes. Use refresh to pick up changes sooner.
+
+With no argument, the whole cache is cleared and every workspace delegate is
+re-probed.
+
+Examples:
+  ob delegate refresh
+  ob delegate refresh exec:my-cli`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var url string
+			if len(args) == 1 {
+				url = args[0]
+			}
+			format, outputPath := getOutputFlags(cmd)
+			return app.DelegateRefresh(app.DelegateRefreshParams{
+				URL:          url,
+				OutputFormat: format,
+				OutputPath:   outputPath,
+			})
+		},
+	}
+	return c
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/openbindings/cli/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func newMCPCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Serve OBI operations over MCP",
+		Long: `Serve the operations of an OpenBindings interface to MCP (Model Context
+Protocol) clients such as AI agents.`,
+	}
+
+	cmd.AddCommand(
+		newMCPServeCmd(),
+	)
+
+	return cmd
+}
+
+// mcpTokenEnv is the environment variable read for the bearer token of
+// `ob mcp serve --http` when --token is not given.
+const mcpTokenEnv = "OB_MCP_TOKEN"
+
+func newMCPServeCmd() *cobra.Command {
+	var contextName string
+	var tags []string
+	var excludeTags []string
+	var httpAddr string
+	var token string
+
+	cmd := &cobra.Command{
+		Use:   "serve <obi-path>",
+		Short: "Publish an OBI's operations as MCP tools",
+		Long: `Run an MCP server that publishes every operation of an OpenBindings
+interface as a tool. The tool's input schema is the operation's input
+schema and its description is the operation's description. Calls execute
+the operation through its highest-priority binding, so OpenAPI, gRPC,
+CLI and MCP-backed operations are all reachable through one endpoint.
+
+Event operations and operations that stream their input are not
+published, nor are operations without a binding. Use --tag to publish
+only operations with one of the given tags, and --exclude-tag to leave
+out operations with any of the given tags.
+
+By default the server speaks MCP over stdin and stdout, for clients that
+start it as a subprocess. Use --http to serve Streamable HTTP instead; the
+endpoint is /mcp on the given address. An address without a host (e.g.
+:8080) is bound to localhost, and requests whose Host or Origin header is
+not local are rejected. Use --token (or the OB_MCP_TOKEN environment
+variable) to require a bearer token of HTTP clients; it is required to
+serve on an address other than localhost.
+
+Use --context to apply a named context (credentials, headers, etc.) to
+every call. Progress, log messages, elicitation and sampling requests from
+the servers behind the operations are relayed to the MCP client.
+
+Examples:
+  ob mcp serve interface.json
+  ob mcp serve interface.json --context github --tag read
+  ob mcp serve interface.json --exclude-tag admin
+  ob mcp serve interface.json --http localhost:8080
+  OB_MCP_TOKEN=secret ob mcp serve interface.json --http 0.0.0.0:8080`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			if token == "" {
+				token = os.Getenv(mcpTokenEnv)
+			}
+
+			err := app.ServeMCP(ctx, app.MCPServeInput{
+				OBI:         args[0],
+				Context:     contextName,
+				Tags:        tags,
+				ExcludeTags: excludeTags,
+				HTTPAddr:    httpAddr,
+				BearerToken: token,
+				Listening: func(endpoint string) {
+					fmt.Fprintf(os.Stderr, "Serving MCP at %s\n", endpoint)
+				},
+				Authorize: authorizeInBrowser,
+			})
+			if err != nil && ctx.Err() == nil {
+				return app.ExitResult{Code: 1, Message: err.Error(), ToStderr: true}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&contextName, "context", "", "named context to apply to every call")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "publish only operations with this tag (repeatable)")
+	cmd.Flags().StringSliceVar(&excludeTags, "exclude-tag", nil, "leave out operations with this tag (repeatable)")
+	cmd.Flags().StringVar(&httpAddr, "http", "", "serve Streamable HTTP on this address instead of stdio")
+	cmd.Flags().StringVar(&token, "token", "", "bearer token HTTP clients must send (default $"+mcpTokenEnv+")")
+
+	return cmd
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/openbindings/cli/internal/app"
+	"github.com/openbindings/cli/internal/elicit"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 func newOperationCmd() *cobra.Command {
@@ -36,7 +41,13 @@ exposes. Use subcommands to list, rename, remove, or execute operations.`,
 func newOperationExecCmd() *cobra.Command {
 	var bindingKey string
 	var inputJSON string
+	var fields []string
 	var contextName string
+	var allPages bool
+	var timeout time.Duration
+	var maxAttempts int
+	var forceRetry bool
+	var responseFile string
 
 	cmd := &cobra.Command{
 		Use:     "exec <obi-path> [operation]",
@@ -53,12 +64,58 @@ the operation is derived from the binding entry.
 Use --context to apply a named context (credentials, headers, etc.)
 to the execution.
 
+Use --field name=value to set single input fields, converted to the
+type of the field's schema; repeat it to add items to an array field.
+Shell completion offers the field names and then their candidate
+values: enum values, and the values listed by the delegate, such as
+those a usage spec's complete directives produce.
+
+Operations that stream their input (gRPC client-streaming and
+bidirectional methods) take an array of messages with --input, a single
+message built with --field, or read NDJSON messages from stdin and send
+each one as soon as it is read.
+Responses are written as NDJSON as they arrive.
+
+Progress updates and log messages from the server (MCP) are written
+to stderr. When the server asks for input (MCP elicitation) and stdin
+is a terminal, a form is shown; otherwise the request is declined.
+
+CLI operations (usage-spec bindings) print their output while the
+command runs: each line as it is printed, and JSON lines as NDJSON. The
+exit status is the command's. Interrupting ob interrupts the command.
+Use -F or -o to get the aggregated result instead; it is also used when
+--max-attempts or --force-retry asks for retries.
+
+Use --all-pages to follow pagination and stream every item as NDJSON.
+The binding must declare how it pages with an x-ob-pagination hint, e.g.
+  "x-ob-pagination": {"style": "cursor", "items": "items",
+                      "cursor": "nextPageToken", "param": "pageToken"}
+Styles are link (HTTP Link rel="next"), cursor, offset and page.
+
+Network requests are bounded by a per-attempt timeout (CLI and exec
+formats are not, unless one is set), and failed attempts of operations
+marked idempotent are retried with exponential backoff (honoring
+Retry-After). Defaults come from the workspace's settings.execution and
+the context's execution settings; --timeout and --max-attempts override
+them for one call, and --force-retry retries non-idempotent operations.
+
+Binary responses (OpenAPI) are returned as base64 unless
+--response-file names a file to write them to; the output then holds
+the file's path, size and content type. It applies to a single response,
+so it cannot be combined with --all-pages or streamed operations.
+
 Examples:
   ob op exec interface.json listPets --input '{"limit":10}'
   ob op exec interface.json echo
   ob op exec interface.json --binding listPets.openapi --input '{"limit":10}'
   ob op exec interface.json listPets --context github
-  ob op exec interface.json listPets -F json`,
+  ob op exec mise.obi.json install --field plugin=node --field version=20
+  ob op exec interface.json listPets --all-pages
+  ob op exec interface.json listPets --timeout 5s --max-attempts 5
+  ob op exec interface.json listPets -F json
+  ob op exec interface.json downloadReport --response-file report.pdf
+  ob op exec interface.json uploadChunks --input '[{"data":"aGk="},{"data":"Ynll"}]'
+  tail -f requests.ndjson | ob op exec interface.json chat`,
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			obiFile := args[0]
@@ -83,12 +140,82 @@ Examples:
 					return app.ExitResult{Code: 2, Message: fmt.Sprintf("invalid --input JSON: %v", err), ToStderr: true}
 				}
 			}
+			if len(fields) > 0 {
+				var err error
+				input, err = app.SetInputFields(obiFile, operationKey, bindingKey, input, fields)
+				if err != nil {
+					return app.ExitResult{Code: 2, Message: fmt.Sprintf("invalid --field: %v", err), ToStderr: true}
+				}
+			}
+
+			if allPages && responseFile != "" {
+				return app.ExitResult{Code: 2, Message: "--response-file cannot be used with --all-pages", ToStderr: true}
+			}
+
+			// Operations that stream their input (gRPC client-streaming and
+			// bidi) send --input's elements, or NDJSON from stdin as it is read.
+			streamsInput := (operationKey != "" && app.IsInputStreamOperation(obiFile, operationKey)) ||
+				(bindingKey != "" && app.IsInputStreamBinding(obiFile, bindingKey))
+			if streamsInput && allPages {
+				return app.ExitResult{Code: 2, Message: "--all-pages cannot be used with streaming-input operations", ToStderr: true}
+			}
+			if streamsInput && responseFile != "" {
+				return app.ExitResult{Code: 2, Message: "--response-file cannot be used with streaming-input operations", ToStderr: true}
+			}
+			// Stdin is free for elicitation prompts unless it carries the input.
+			haveInput := inputJSON != "" || len(fields) > 0
+			stdinIsTerminal := term.IsTerminal(int(os.Stdin.Fd()))
+			promptable := stdinIsTerminal && !(streamsInput && !haveInput)
+			interaction := cliInteraction(promptable)
+
+			if streamsInput {
+				ctx, stop := signal.NotifyContext(app.WithInteraction(context.Background(), interaction), os.Interrupt)
+				defer stop()
+				ctx, cancel := context.WithCancel(ctx)
+				defer cancel()
+
+				if !haveInput && stdinIsTerminal {
+					fmt.Fprintln(os.Stderr, "Reading input messages from stdin as NDJSON; press Ctrl-D to end the input (or use --input).")
+				}
+				messages, readErrs := inputStream(ctx, cancel, input, haveInput, os.Stdin)
+				ch, err := app.StreamOBIOperation(ctx, obiFile, operationKey, bindingKey, messages, contextName)
+				if err != nil {
+					return app.ExitResult{Code: 1, Message: err.Error(), ToStderr: true}
+				}
+
+				enc := json.NewEncoder(os.Stdout)
+				var streamErr string
+				for ev := range ch {
+					if ev.Error != nil {
+						streamErr = ev.Error.Message
+						continue
+					}
+					if err := enc.Encode(ev.Data); err != nil {
+						return app.ExitResult{Code: 1, Message: fmt.Sprintf("write error: %v", err), ToStderr: true}
+					}
+				}
+				select {
+				case err := <-readErrs:
+					return app.ExitResult{Code: 2, Message: err.Error(), ToStderr: true}
+				default:
+				}
+				if streamErr != "" {
+					return app.ExitResult{Code: 1, Message: streamErr, ToStderr: true}
+				}
+				return nil
+			}
 
 			// Check if the operation is an event — if so, stream via subscribe.
 			isEvent := (operationKey != "" && app.IsEventOperation(obiFile, operationKey)) ||
 				(bindingKey != "" && app.IsEventBinding(obiFile, bindingKey))
+			if isEvent && allPages {
+				return app.ExitResult{Code: 2, Message: "--all-pages cannot be used with event operations", ToStderr: true}
+			}
+			if isEvent && responseFile != "" {
+				return app.ExitResult{Code: 2, Message: "--response-file cannot be used with event operations", ToStderr: true}
+			}
 			if isEvent {
-				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+				ctx, stop := signal.NotifyContext(app.WithInteraction(context.Background(), interaction), os.Interrupt)
 				defer stop()
 
 				ch, err := app.SubscribeOBIOperation(ctx, obiFile, operationKey, bindingKey, input, contextName)
@@ -109,19 +236,189 @@ Examples:
 				return nil
 			}
 
-			output := app.ExecuteOBIOperation(context.Background(), obiFile, operationKey, bindingKey, input, contextName)
+			policy := app.ExecPolicy{MaxAttempts: maxAttempts}
+			if cmd.Flags().Changed("timeout") {
+				t, err := app.TimeoutFromFlag(timeout)
+				if err != nil {
+					return app.ExitResult{Code: 1, Message: err.Error(), ToStderr: true}
+				}
+				policy.Timeout = t
+			}
+			if cmd.Flags().Changed("force-retry") {
+				policy.RetryNonIdempotent = &forceRetry
+			}
+
+			// CLI operations print their output as the command runs, unless a
+			// formatted or saved result, or retries, are asked for.
 			format, outputPath := getOutputFlags(cmd)
+			retries := cmd.Flags().Changed("max-attempts") || cmd.Flags().Changed("force-retry")
+			streamsOutput := format == "" && outputPath == "" && !allPages && !retries &&
+				((operationKey != "" && app.IsOutputStreamOperation(obiFile, operationKey)) ||
+					(bindingKey != "" && app.IsOutputStreamBinding(obiFile, bindingKey)))
+			if streamsOutput {
+				if responseFile != "" {
+					return app.ExitResult{Code: 2, Message: "--response-file cannot be used with operations whose output streams", ToStderr: true}
+				}
+				ctx, stop := signal.NotifyContext(app.WithInteraction(context.Background(), interaction), os.Interrupt)
+				defer stop()
+
+				ch, err := app.SubscribeOBIOperationWithPolicy(ctx, obiFile, operationKey, bindingKey, input, contextName, policy)
+				if err != nil {
+					return app.ExitResult{Code: 1, Message: err.Error(), ToStderr: true}
+				}
+
+				enc := json.NewEncoder(os.Stdout)
+				var streamErr *app.Error
+				for ev := range ch {
+					if ev.Error != nil {
+						streamErr = ev.Error
+						continue
+					}
+					if line, toStderr, ok := app.OutputLine(ev.Data); ok {
+						if toStderr {
+							fmt.Fprintln(os.Stderr, line)
+						} else {
+							fmt.Fprintln(os.Stdout, line)
+						}
+						continue
+					}
+					if err := enc.Encode(ev.Data); err != nil {
+						return app.ExitResult{Code: 1, Message: fmt.Sprintf("write error: %v", err), ToStderr: true}
+					}
+				}
+				if streamErr != nil {
+					code := 1
+					if details, ok := streamErr.Details.(map[string]any); ok {
+						if status, ok := details["status"].(int); ok && status > 0 {
+							code = status
+						}
+					}
+					return app.ExitResult{Code: code, Message: streamErr.Message, ToStderr: true}
+				}
+				return nil
+			}
+
+			if allPages {
+				ctx, stop := signal.NotifyContext(app.WithInteraction(context.Background(), interaction), os.Interrupt)
+				defer stop()
+
+				enc := json.NewEncoder(os.Stdout)
+				err := app.ExecuteOBIOperationPages(ctx, obiFile, operationKey, bindingKey, input, contextName, policy, func(item any) error {
+					return enc.Encode(item)
+				})
+				if err != nil {
+					return app.ExitResult{Code: 1, Message: err.Error(), ToStderr: true}
+				}
+				return nil
+			}
+
+			ctx := app.WithInteraction(context.Background(), interaction)
+			if responseFile != "" {
+				ctx = app.WithMetadata(ctx, map[string]any{"responseFile": responseFile})
+			}
+			output := app.ExecuteOBIOperationWithPolicy(ctx, obiFile, operationKey, bindingKey, input, contextName, policy)
 			return app.OutputResult(output, format, outputPath)
 		},
 	}
 
 	cmd.Flags().StringVar(&bindingKey, "binding", "", "binding key to execute (operation is derived from the entry)")
-	cmd.Flags().StringVar(&inputJSON, "input", "", "operation input as JSON")
+	cmd.Flags().StringVar(&inputJSON, "input", "", "operation input as JSON (an array of messages for streaming-input operations)")
+	cmd.Flags().StringArrayVar(&fields, "field", nil, "set an input field as \"name=value\" (repeatable; overrides --input)")
 	cmd.Flags().StringVar(&contextName, "context", "", "named context to apply (credentials, headers, etc.)")
+	cmd.Flags().BoolVar(&allPages, "all-pages", false, "follow pagination and stream all items as NDJSON")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "per-attempt timeout (overrides workspace and context settings; 0 disables it)")
+	cmd.Flags().IntVar(&maxAttempts, "max-attempts", 0, "total attempts for retryable failures (overrides workspace and context settings)")
+	cmd.Flags().BoolVar(&forceRetry, "force-retry", false, "retry operations that are not marked idempotent")
+	cmd.Flags().StringVar(&responseFile, "response-file", "", "write a binary response to this file instead of returning it as base64")
+
+	cmd.ValidArgsFunction = completeOperationArgs
+	_ = cmd.RegisterFlagCompletionFunc("field", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeFieldFlag(args, bindingKey, inputJSON, fields, contextName, toComplete)
+	})
 
 	return cmd
 }
 
+// completeOperationArgs completes the OBI path and then its operation keys.
+func completeOperationArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 1 {
+		if len(args) == 0 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	list, err := app.OperationList(args[0], "")
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var keys []string
+	for _, op := range list.Operations {
+		if strings.HasPrefix(op.Key, toComplete) {
+			keys = append(keys, completionEntry(op.Key, op.Description))
+		}
+	}
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFieldFlag completes --field: first the operation's input field
+// names, then, after "name=", the field's candidate values (its enum, or the
+// values its delegate lists, such as a usage spec completer's output).
+func completeFieldFlag(args []string, bindingKey, inputJSON string, fields []string, contextName, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	obiFile, operationKey := args[0], ""
+	if len(args) > 1 {
+		operationKey = args[1]
+	}
+	if operationKey == "" && bindingKey == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	name, prefix, hasValue := strings.Cut(toComplete, "=")
+	if !hasValue {
+		var names []string
+		for _, field := range app.OBIInputFields(obiFile, operationKey, bindingKey) {
+			if strings.HasPrefix(field, toComplete) {
+				names = append(names, field+"=")
+			}
+		}
+		return names, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Values entered so far are passed on to the completer.
+	var input any
+	if inputJSON != "" {
+		_ = json.Unmarshal([]byte(inputJSON), &input)
+	}
+	if withFields, err := app.SetInputFields(obiFile, operationKey, bindingKey, input, fields); err == nil {
+		input = withFields
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	completions, err := app.CompleteOBIOperationField(ctx, obiFile, operationKey, bindingKey, name, input, contextName)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var values []string
+	for _, c := range completions {
+		if strings.HasPrefix(c.Value, prefix) {
+			values = append(values, completionEntry(name+"="+c.Value, c.Description))
+		}
+	}
+	return values, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionEntry formats a shell completion with an optional description.
+func completionEntry(value, description string) string {
+	if description == "" {
+		return value
+	}
+	return value + "\t" + strings.SplitN(description, "\n", 2)[0]
+}
+
 func newOperationListCmd() *cobra.Command {
 	var tagFilter string
 
@@ -226,6 +523,93 @@ Examples:
 	return cmd
 }
 
+// inputStream returns the messages to send to an operation that streams its
+// input: the elements of the input given with --input or --field when it is
+// an array, that input itself otherwise, or JSON values read from r as they
+// arrive when neither was given. A read error is sent on the error channel and cancels the call so
+// that partial input is not taken as complete.
+func inputStream(ctx context.Context, cancel context.CancelFunc, input any, haveInput bool, r io.Reader) (<-chan any, <-chan error) {
+	messages := make(chan any)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(messages)
+		send := func(msg any) bool {
+			select {
+			case messages <- msg:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if haveInput {
+			items, ok := input.([]any)
+			if !ok {
+				items = []any{input}
+			}
+			for _, msg := range items {
+				if !send(msg) {
+					return
+				}
+			}
+			return
+		}
+
+		dec := json.NewDecoder(r)
+		for {
+			var msg any
+			if err := dec.Decode(&msg); err != nil {
+				if err != io.EOF {
+					errs <- fmt.Errorf("invalid input message on stdin: %w", err)
+					cancel()
+				}
+				return
+			}
+			if !send(msg) {
+				return
+			}
+		}
+	}()
+
+	return messages, errs
+}
+
+// cliInteraction reports progress and server log messages on stderr while
+// an operation runs. When a server requires authorization, the URL to
+// visit is printed on stderr and opened in the browser. When promptable,
+// elicitation requests are answered with a form on the terminal; otherwise
+// they are declined. Sampling requests follow the workspace's
+// settings.sampling.
+func cliInteraction(promptable bool) app.Interaction {
+	ia := app.Interaction{
+		Progress: func(p app.Progress) {
+			fmt.Fprintf(os.Stderr, "progress: %s\n", app.FormatProgress(p))
+		},
+		Log: func(m app.LogMessage) {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", m.Level, app.FormatLogMessage(m))
+		},
+		Authorize: authorizeInBrowser,
+	}
+	if promptable {
+		var mu sync.Mutex // one form on the terminal at a time
+		ia.Elicit = func(_ context.Context, req app.ElicitRequest) (app.ElicitResult, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return elicit.Run(req)
+		}
+	}
+	return ia
+}
+
+// authorizeInBrowser prints an authorization URL on stderr and opens it in
+// the browser.
+func authorizeInBrowser(_ context.Context, authURL string) error {
+	fmt.Fprintf(os.Stderr, "The server requires authorization. Open this URL to continue:\n  %s\n", authURL)
+	_ = app.OpenBrowser(authURL) // the printed URL is enough when no browser is available
+	return nil
+}
+
 // checkManagedOps loads the OBI and returns a warning string if any of the
 // given operation keys are managed (have x-ob metadata). Returns "" if none are managed.
 func checkManagedOps(obiPath string, keys []string) string {
@@ -5,6 +5,7 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"sync"
 
 	"github.com/openbindings/cli/internal/app"
 	"github.com/openbindings/openbindings-go/canonicaljson"
@@ -18,10 +19,20 @@ import (
 //go:embed usage.kdl
 var embeddedUsageSpec string
 
+// closeDelegatesOnce registers the cobra finalizer that closes the builtin
+// delegates; NewRoot may be called more than once.
+var closeDelegatesOnce sync.Once
+
 // NewRoot builds the top-level `ob` command.
 //
 // We keep errors/usage silent and let our main() decide how to print ExitResult vs generic errors.
 func NewRoot() *cobra.Command {
+	// Release the connections builtin delegates keep open (such as pooled
+	// MCP sessions) once the command has run, whether or not it failed.
+	closeDelegatesOnce.Do(func() {
+		cobra.OnFinalize(func() { _ = app.CloseDelegates() })
+	})
+
 	var usageSpec bool
 	var openbindingsFlag bool
 
@@ -89,6 +100,9 @@ func NewRoot() *cobra.Command {
 	browseCmd := newBrowseCmd()
 	browseCmd.GroupID = "explore"
 
+	mcpCmd := newMCPCmd()
+	mcpCmd.GroupID = "explore"
+
 	createCmd := newCreateCmd()
 	createCmd.GroupID = "authoring"
 
@@ -141,6 +155,7 @@ func NewRoot() *cobra.Command {
 		initCmd,
 		statusCmd,
 		browseCmd,
+		mcpCmd,
 		createCmd,
 		sourceCmd,
 		operationCmd,
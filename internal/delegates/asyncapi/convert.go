@@ -24,7 +24,7 @@ const DefaultSourceName = "asyncapi"
 
 // ConvertToInterface converts an AsyncAPI 3.0 document to an OpenBindings interface.
 func ConvertToInterface(source delegates.Source) (openbindings.Interface, error) {
-	doc, err := loadDocument(source)
+	doc, err := loadDocument(source, nil)
 	if err != nil {
 		return openbindings.Interface{}, fmt.Errorf("load AsyncAPI document: %w", err)
 	}
@@ -112,9 +112,10 @@ func ConvertToInterface(source delegates.Source) (openbindings.Interface, error)
 	return iface, nil
 }
 
-// loadDocument loads and parses an AsyncAPI document from a source.
-func loadDocument(source delegates.Source) (*Document, error) {
-	data, err := sourceToBytesAsync(source)
+// loadDocument loads and parses an AsyncAPI document from a source,
+// fetching remote documents with the context's transport.
+func loadDocument(source delegates.Source, bindCtx *delegates.BindingContext) (*Document, error) {
+	data, err := sourceToBytesAsync(source, bindCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -138,7 +139,7 @@ func loadDocument(source delegates.Source) (*Document, error) {
 	return &doc, nil
 }
 
-func sourceToBytesAsync(source delegates.Source) ([]byte, error) {
+func sourceToBytesAsync(source delegates.Source, bindCtx *delegates.BindingContext) ([]byte, error) {
 	if source.Content != nil {
 		return delegates.ContentToBytes(source.Content)
 	}
@@ -152,7 +153,11 @@ func sourceToBytesAsync(source delegates.Source) ([]byte, error) {
 		if err != nil {
 			return nil, fmt.Errorf("fetch %q: %w", source.Location, err)
 		}
-		resp, err := http.DefaultClient.Do(req)
+		client, err := delegates.HTTPClient(bindCtx)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
 		if err != nil {
 			return nil, fmt.Errorf("fetch %q: %w", source.Location, err)
 		}
@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -30,7 +32,7 @@ func parseSSEPayload(dataLines []string) any {
 func Execute(ctx context.Context, input delegates.ExecuteInput) delegates.ExecuteOutput {
 	start := time.Now()
 
-	doc, err := loadDocument(input.Source)
+	doc, err := loadDocument(input.Source, input.Context)
 	if err != nil {
 		return delegates.FailedOutput(start, "doc_load_failed", err.Error())
 	}
@@ -50,19 +52,17 @@ func Execute(ctx context.Context, input delegates.ExecuteInput) delegates.Execut
 		return delegates.FailedOutput(start, "no_server", err.Error())
 	}
 
-	channelName := extractRefName(asyncOp.Channel.Ref)
-	channel, hasChannel := doc.Channels[channelName]
-
-	address := channelName
-	if hasChannel && channel.Address != "" {
-		address = channel.Address
+	channel, address, params, err := resolveChannel(doc, asyncOp, input.Input)
+	if err != nil {
+		return delegates.FailedOutput(start, "invalid_input", err.Error())
 	}
+	ep := endpoint{serverURL: serverURL, protocol: protocol, address: address, params: params, bindings: channel.Bindings}
 
 	switch asyncOp.Action {
 	case "receive":
-		return executeReceive(ctx, serverURL, protocol, address, input, start)
+		return executeReceive(ctx, ep, input, start)
 	case "send":
-		return executeSend(ctx, serverURL, protocol, address, input, start)
+		return executeSend(ctx, ep, asyncOp.Reply != nil, input, start)
 	default:
 		return delegates.FailedOutput(start, "unsupported_action", fmt.Sprintf("unknown action %q", asyncOp.Action))
 	}
@@ -126,9 +126,66 @@ func resolveServer(doc *Document, bindCtx *delegates.BindingContext) (url string
 	return "", "", fmt.Errorf("no supported server found (need http, https, ws, or wss protocol)")
 }
 
+// endpoint is where an operation's messages are sent or received.
+type endpoint struct {
+	serverURL string
+	protocol  string
+	address   string
+	params    []string // channel parameters substituted in address
+	bindings  *ChannelBindings
+}
+
+// channelURL returns the channel URL on the server.
+func (ep endpoint) channelURL() string {
+	return ep.serverURL + "/" + strings.TrimLeft(ep.address, "/")
+}
+
+// resolveChannel returns the channel of an operation and its address, with
+// the channel parameters substituted from the input or their defaults, and
+// the names of those parameters.
+func resolveChannel(doc *Document, op Operation, input any) (Channel, string, []string, error) {
+	channelName := extractRefName(op.Channel.Ref)
+	channel := doc.Channels[channelName]
+	address := channelName
+	if channel.Address != "" {
+		address = channel.Address
+	}
+
+	values, _ := input.(map[string]any)
+	var params, missing []string
+	address = channelParamPattern.ReplaceAllStringFunc(address, func(m string) string {
+		name := m[1 : len(m)-1]
+		params = append(params, name)
+		if v, ok := values[name]; ok && v != nil {
+			return url.PathEscape(formatValue(v))
+		}
+		if p, ok := channel.Parameters[name]; ok && p.Default != "" {
+			return url.PathEscape(p.Default)
+		}
+		missing = append(missing, name)
+		return m
+	})
+	if len(missing) > 0 {
+		return channel, "", nil, fmt.Errorf("missing channel parameter(s) for %q: %s", channelName, strings.Join(missing, ", "))
+	}
+	return channel, address, params, nil
+}
+
+// channelParamPattern matches the parameter expressions in a channel address.
+var channelParamPattern = regexp.MustCompile(`\{[^{}]+\}`)
+
+// formatValue renders a scalar input value as a string.
+func formatValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
 // executeReceive handles "receive" operations (subscribing to events).
-// Supports SSE (HTTP) protocol. Reads a configurable number of events.
-func executeReceive(ctx context.Context, serverURL, protocol, address string, input delegates.ExecuteInput, start time.Time) delegates.ExecuteOutput {
+// Supports SSE (HTTP) and WebSocket protocols. Reads a configurable number
+// of events.
+func executeReceive(ctx context.Context, ep endpoint, input delegates.ExecuteInput, start time.Time) delegates.ExecuteOutput {
 	maxEvents := 1
 	if input.Input != nil {
 		if m, ok := input.Input.(map[string]any); ok {
@@ -138,32 +195,35 @@ func executeReceive(ctx context.Context, serverURL, protocol, address string, in
 		}
 	}
 
-	switch protocol {
+	switch ep.protocol {
 	case "http", "https":
-		return executeSSESubscribe(ctx, serverURL, address, maxEvents, input, start)
+		return executeSSESubscribe(ctx, ep.channelURL(), maxEvents, input, start)
+	case "ws", "wss":
+		return executeWSReceive(ctx, ep, maxEvents, input, start)
 	default:
 		return delegates.FailedOutput(start, "unsupported_protocol",
-			fmt.Sprintf("receive not supported for protocol %q (supported: http, https)", protocol))
+			fmt.Sprintf("receive not supported for protocol %q (supported: http, https, ws, wss)", ep.protocol))
 	}
 }
 
 // executeSend handles "send" operations (publishing messages).
-// Supports HTTP protocol via POST.
-func executeSend(ctx context.Context, serverURL, protocol, address string, input delegates.ExecuteInput, start time.Time) delegates.ExecuteOutput {
-	switch protocol {
+// Supports HTTP protocol via POST and WebSocket protocols. When the
+// operation has a reply, the reply message is the output.
+func executeSend(ctx context.Context, ep endpoint, expectReply bool, input delegates.ExecuteInput, start time.Time) delegates.ExecuteOutput {
+	switch ep.protocol {
 	case "http", "https":
-		return executeHTTPSend(ctx, serverURL, address, input, start)
+		return executeHTTPSend(ctx, ep.channelURL(), input, start)
+	case "ws", "wss":
+		return executeWSSend(ctx, ep, expectReply, input, start)
 	default:
 		return delegates.FailedOutput(start, "unsupported_protocol",
-			fmt.Sprintf("send not supported for protocol %q (supported: http, https)", protocol))
+			fmt.Sprintf("send not supported for protocol %q (supported: http, https, ws, wss)", ep.protocol))
 	}
 }
 
 // executeSSESubscribe connects to an SSE endpoint and collects events.
-func executeSSESubscribe(ctx context.Context, serverURL, address string, maxEvents int, input delegates.ExecuteInput, start time.Time) delegates.ExecuteOutput {
-	url := serverURL + "/" + strings.TrimLeft(address, "/")
-
-	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+func executeSSESubscribe(ctx context.Context, url string, maxEvents int, input delegates.ExecuteInput, start time.Time) delegates.ExecuteOutput {
+	ctx, cancel := delegates.WithDefaultTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -173,14 +233,18 @@ func executeSSESubscribe(ctx context.Context, serverURL, address string, maxEven
 	req.Header.Set("Accept", "text/event-stream")
 	delegates.ApplyHTTPContext(req, input.Context)
 
-	resp, err := http.DefaultClient.Do(req)
+	client, err := delegates.HTTPClient(input.Context)
+	if err != nil {
+		return delegates.FailedOutput(start, "transport_config_failed", err.Error())
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return delegates.FailedOutput(start, "sse_connect_failed", err.Error())
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return delegates.HTTPErrorOutput(start, resp.StatusCode, resp.Status)
+		return delegates.HTTPResponseErrorOutput(start, resp)
 	}
 
 	var events []any
@@ -222,7 +286,7 @@ func executeSSESubscribe(ctx context.Context, serverURL, address string, maxEven
 // Subscribe opens a streaming subscription for an AsyncAPI operation.
 // Only "receive" operations are supported for streaming.
 func Subscribe(ctx context.Context, input delegates.ExecuteInput) (<-chan delegates.StreamEvent, error) {
-	doc, err := loadDocument(input.Source)
+	doc, err := loadDocument(input.Source, input.Context)
 	if err != nil {
 		return nil, fmt.Errorf("load document: %w", err)
 	}
@@ -246,25 +310,24 @@ func Subscribe(ctx context.Context, input delegates.ExecuteInput) (<-chan delega
 		return nil, fmt.Errorf("resolve server: %w", err)
 	}
 
-	channelName := extractRefName(asyncOp.Channel.Ref)
-	channel, hasChannel := doc.Channels[channelName]
-	address := channelName
-	if hasChannel && channel.Address != "" {
-		address = channel.Address
+	channel, address, params, err := resolveChannel(doc, asyncOp, input.Input)
+	if err != nil {
+		return nil, err
 	}
+	ep := endpoint{serverURL: serverURL, protocol: protocol, address: address, params: params, bindings: channel.Bindings}
 
 	switch protocol {
 	case "http", "https":
-		return subscribeSSE(ctx, serverURL, address, input)
+		return subscribeSSE(ctx, ep.channelURL(), input)
+	case "ws", "wss":
+		return subscribeWS(ctx, ep, input)
 	default:
-		return nil, fmt.Errorf("streaming not supported for protocol %q (supported: http, https)", protocol)
+		return nil, fmt.Errorf("streaming not supported for protocol %q (supported: http, https, ws, wss)", protocol)
 	}
 }
 
 // subscribeSSE connects to an SSE endpoint and streams events on the returned channel.
-func subscribeSSE(ctx context.Context, serverURL, address string, input delegates.ExecuteInput) (<-chan delegates.StreamEvent, error) {
-	sseURL := serverURL + "/" + strings.TrimLeft(address, "/")
-
+func subscribeSSE(ctx context.Context, sseURL string, input delegates.ExecuteInput) (<-chan delegates.StreamEvent, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", sseURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("build request: %w", err)
@@ -272,7 +335,11 @@ func subscribeSSE(ctx context.Context, serverURL, address string, input delegate
 	req.Header.Set("Accept", "text/event-stream")
 	delegates.ApplyHTTPContext(req, input.Context)
 
-	resp, err := http.DefaultClient.Do(req)
+	client, err := delegates.HTTPClient(input.Context)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("SSE connect: %w", err)
 	}
@@ -332,10 +399,8 @@ func subscribeSSE(ctx context.Context, serverURL, address string, input delegate
 }
 
 // executeHTTPSend publishes a message via HTTP POST to the channel address.
-func executeHTTPSend(ctx context.Context, serverURL, address string, input delegates.ExecuteInput, start time.Time) delegates.ExecuteOutput {
-	url := serverURL + "/" + strings.TrimLeft(address, "/")
-
-	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+func executeHTTPSend(ctx context.Context, url string, input delegates.ExecuteInput, start time.Time) delegates.ExecuteOutput {
+	ctx, cancel := delegates.WithDefaultTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	var bodyData []byte
@@ -357,7 +422,11 @@ func executeHTTPSend(ctx context.Context, serverURL, address string, input deleg
 	req.Header.Set("Accept", "application/json")
 	delegates.ApplyHTTPContext(req, input.Context)
 
-	resp, err := http.DefaultClient.Do(req)
+	client, err := delegates.HTTPClient(input.Context)
+	if err != nil {
+		return delegates.FailedOutput(start, "transport_config_failed", err.Error())
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return delegates.FailedOutput(start, "request_failed", err.Error())
 	}
@@ -366,7 +435,7 @@ func executeHTTPSend(ctx context.Context, serverURL, address string, input deleg
 	duration := time.Since(start).Milliseconds()
 
 	if resp.StatusCode >= 400 {
-		return delegates.HTTPErrorOutput(start, resp.StatusCode, resp.Status)
+		return delegates.HTTPResponseErrorOutput(start, resp)
 	}
 
 	if resp.StatusCode == 202 || resp.StatusCode == 204 {
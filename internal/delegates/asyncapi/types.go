@@ -39,6 +39,7 @@ type Channel struct {
 	Description string              `json:"description,omitempty" yaml:"description,omitempty"`
 	Servers     []ServerRef         `json:"servers,omitempty" yaml:"servers,omitempty"`
 	Parameters  map[string]Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Bindings    *ChannelBindings    `json:"bindings,omitempty" yaml:"bindings,omitempty"`
 	Ref         string              `json:"$ref,omitempty" yaml:"$ref,omitempty"`
 }
 
@@ -93,6 +94,21 @@ type Parameter struct {
 	Enum        []string `json:"enum,omitempty" yaml:"enum,omitempty"`
 }
 
+// ChannelBindings holds the protocol-specific channel bindings.
+// Only the WebSocket binding is modeled.
+type ChannelBindings struct {
+	WS *WebSocketChannelBinding `json:"ws,omitempty" yaml:"ws,omitempty"`
+}
+
+// WebSocketChannelBinding describes the WebSocket handshake of a channel.
+// Query and Headers are JSON Schemas of type object whose properties name
+// the query parameters and headers sent with the handshake.
+type WebSocketChannelBinding struct {
+	Method  string         `json:"method,omitempty" yaml:"method,omitempty"`
+	Query   map[string]any `json:"query,omitempty" yaml:"query,omitempty"`
+	Headers map[string]any `json:"headers,omitempty" yaml:"headers,omitempty"`
+}
+
 // Tag is a metadata tag.
 type Tag struct {
 	Name        string `json:"name" yaml:"name"`
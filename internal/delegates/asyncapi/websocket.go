@@ -0,0 +1,229 @@
+package asyncapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// maxWSMessageSize bounds the size of a received WebSocket message.
+const maxWSMessageSize = 16 << 20
+
+// dialWS opens a WebSocket connection to the endpoint. The handshake carries
+// the query parameters and headers of the channel's ws binding, followed by
+// the context's credentials and headers.
+func dialWS(ctx context.Context, ep endpoint, input delegates.ExecuteInput) (*websocket.Conn, error) {
+	u, err := url.Parse(ep.channelURL())
+	if err != nil {
+		return nil, fmt.Errorf("invalid channel URL: %w", err)
+	}
+	header := http.Header{}
+	if ep.bindings != nil && ep.bindings.WS != nil {
+		q := u.Query()
+		for name, value := range bindingValues(ep.bindings.WS.Query, input.Input) {
+			q.Set(name, value)
+		}
+		u.RawQuery = q.Encode()
+		for name, value := range bindingValues(ep.bindings.WS.Headers, input.Input) {
+			header.Set(name, value)
+		}
+	}
+	delegates.ApplyHTTPContext(&http.Request{Header: header}, input.Context)
+
+	client, err := delegates.HTTPClient(input.Context)
+	if err != nil {
+		return nil, err
+	}
+	conn, _, err := websocket.Dial(ctx, u.String(), &websocket.DialOptions{
+		HTTPClient: client,
+		HTTPHeader: header,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("WebSocket connect: %w", err)
+	}
+	conn.SetReadLimit(maxWSMessageSize)
+	return conn, nil
+}
+
+// bindingValues returns the values of the properties of a ws binding schema
+// (query or headers): the input field of the same name when present, else
+// the property's const or default.
+func bindingValues(schema map[string]any, input any) map[string]string {
+	props, _ := schema["properties"].(map[string]any)
+	if len(props) == 0 {
+		return nil
+	}
+	values, _ := input.(map[string]any)
+	out := make(map[string]string, len(props))
+	for name, p := range props {
+		prop, _ := p.(map[string]any)
+		if v, ok := values[name]; ok && v != nil {
+			out[name] = formatValue(v)
+		} else if v, ok := prop["const"]; ok {
+			out[name] = formatValue(v)
+		} else if v, ok := prop["default"]; ok {
+			out[name] = formatValue(v)
+		}
+	}
+	return out
+}
+
+// wsPayload returns the message to send for input: an object input without
+// the fields used for the channel parameters and the ws binding's query and
+// headers, which go in the handshake.
+func wsPayload(ep endpoint, input any) any {
+	values, ok := input.(map[string]any)
+	if !ok {
+		return input
+	}
+	consumed := slices.Clone(ep.params)
+	if ep.bindings != nil && ep.bindings.WS != nil {
+		for _, schema := range []map[string]any{ep.bindings.WS.Query, ep.bindings.WS.Headers} {
+			props, _ := schema["properties"].(map[string]any)
+			for name := range props {
+				consumed = append(consumed, name)
+			}
+		}
+	}
+	payload := maps.Clone(values)
+	for _, name := range consumed {
+		delete(payload, name)
+	}
+	return payload
+}
+
+// parseWSMessage decodes a message as JSON, falling back to its text.
+func parseWSMessage(data []byte) any {
+	var parsed any
+	if json.Unmarshal(data, &parsed) == nil {
+		return parsed
+	}
+	return string(data)
+}
+
+// executeWSReceive connects to a WebSocket channel and collects messages.
+func executeWSReceive(ctx context.Context, ep endpoint, maxEvents int, input delegates.ExecuteInput, start time.Time) delegates.ExecuteOutput {
+	ctx, cancel := delegates.WithDefaultTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	conn, err := dialWS(ctx, ep, input)
+	if err != nil {
+		return delegates.FailedOutput(start, "ws_connect_failed", err.Error())
+	}
+	defer conn.CloseNow()
+
+	var events []any
+	for len(events) < maxEvents {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			if websocket.CloseStatus(err) == websocket.StatusNormalClosure && len(events) > 0 {
+				break
+			}
+			return delegates.FailedOutput(start, "ws_read_failed", err.Error())
+		}
+		events = append(events, parseWSMessage(data))
+	}
+	conn.Close(websocket.StatusNormalClosure, "")
+
+	var output any
+	if len(events) == 1 {
+		output = events[0]
+	} else {
+		output = events
+	}
+
+	return delegates.ExecuteOutput{
+		Output:     output,
+		Status:     0,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+}
+
+// executeWSSend publishes a message on a WebSocket channel. When expectReply
+// is set, the next message received is returned as the output.
+func executeWSSend(ctx context.Context, ep endpoint, expectReply bool, input delegates.ExecuteInput, start time.Time) delegates.ExecuteOutput {
+	ctx, cancel := delegates.WithDefaultTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	bodyData := []byte("{}")
+	if payload := wsPayload(ep, input.Input); payload != nil {
+		var err error
+		bodyData, err = json.Marshal(payload)
+		if err != nil {
+			return delegates.FailedOutput(start, "body_marshal_failed", err.Error())
+		}
+	}
+
+	conn, err := dialWS(ctx, ep, input)
+	if err != nil {
+		return delegates.FailedOutput(start, "ws_connect_failed", err.Error())
+	}
+	defer conn.CloseNow()
+
+	if err := conn.Write(ctx, websocket.MessageText, bodyData); err != nil {
+		return delegates.FailedOutput(start, "ws_write_failed", err.Error())
+	}
+
+	var output any
+	if expectReply {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			return delegates.FailedOutput(start, "ws_read_failed", err.Error())
+		}
+		output = parseWSMessage(data)
+	}
+	conn.Close(websocket.StatusNormalClosure, "")
+
+	return delegates.ExecuteOutput{
+		Output:     output,
+		Status:     0,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+}
+
+// subscribeWS connects to a WebSocket channel and streams its messages on
+// the returned channel until ctx is done or the server closes the
+// connection.
+func subscribeWS(ctx context.Context, ep endpoint, input delegates.ExecuteInput) (<-chan delegates.StreamEvent, error) {
+	dialCtx, cancel := delegates.WithDefaultTimeout(ctx, defaultTimeout)
+	conn, err := dialWS(dialCtx, ep, input)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan delegates.StreamEvent)
+	go func() {
+		defer conn.CloseNow()
+		defer close(ch)
+
+		for {
+			_, data, err := conn.Read(ctx)
+			if err != nil {
+				if ctx.Err() != nil || websocket.CloseStatus(err) == websocket.StatusNormalClosure {
+					return
+				}
+				select {
+				case ch <- delegates.StreamEvent{Error: &delegates.Error{Code: "stream_error", Message: err.Error()}}:
+				case <-ctx.Done():
+				}
+				return
+			}
+			select {
+			case ch <- delegates.StreamEvent{Data: parseWSMessage(data)}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
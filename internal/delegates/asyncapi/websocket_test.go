@@ -0,0 +1,186 @@
+package asyncapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/coder/websocket"
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// wsDoc is an AsyncAPI document for a chat server at host. Sending to a
+// room replies with a message; receiving from a room reads its messages.
+func wsDoc(host string) string {
+	return fmt.Sprintf(`{
+		"asyncapi": "3.0.0",
+		"info": {"title": "Chat", "version": "1.0.0"},
+		"servers": {
+			"local": {"host": %q, "protocol": "ws"}
+		},
+		"channels": {
+			"room": {
+				"address": "/rooms/{roomId}",
+				"parameters": {"roomId": {"default": "lobby"}},
+				"messages": {"Chat": {"payload": {"type": "object"}}},
+				"bindings": {
+					"ws": {
+						"query": {"type": "object", "properties": {"format": {"type": "string", "default": "json"}}},
+						"headers": {"type": "object", "properties": {"X-Client": {"type": "string", "const": "ob"}}}
+					}
+				}
+			}
+		},
+		"operations": {
+			"sendChat": {
+				"action": "send",
+				"channel": {"$ref": "#/channels/room"},
+				"reply": {"messages": [{"$ref": "#/channels/room/messages/Chat"}]}
+			},
+			"onChat": {
+				"action": "receive",
+				"channel": {"$ref": "#/channels/room"}
+			}
+		}
+	}`, host)
+}
+
+// handshake records the handshake of the last WebSocket connection.
+type handshake struct {
+	path, query string
+	header      http.Header
+}
+
+// newWSEchoServer starts a WebSocket server that sends the given greetings
+// and then echoes every message it receives.
+func newWSEchoServer(t *testing.T, greetings ...string) (*httptest.Server, chan handshake) {
+	t.Helper()
+	handshakes := make(chan handshake, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handshakes <- handshake{path: r.URL.Path, query: r.URL.RawQuery, header: r.Header}
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		ctx := r.Context()
+		for _, g := range greetings {
+			if err := conn.Write(ctx, websocket.MessageText, []byte(g)); err != nil {
+				return
+			}
+		}
+		for {
+			typ, data, err := conn.Read(ctx)
+			if err != nil {
+				return
+			}
+			if err := conn.Write(ctx, typ, data); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, handshakes
+}
+
+func TestExecuteWSSendWithReply(t *testing.T) {
+	srv, handshakes := newWSEchoServer(t)
+
+	result := Execute(context.Background(), delegates.ExecuteInput{
+		Source:  delegates.Source{Format: "asyncapi@3.0", Content: wsDoc(hostFromURL(srv.URL))},
+		Ref:     "#/operations/sendChat",
+		Input:   map[string]any{"roomId": "go team", "format": "text", "text": "hello"},
+		Context: &delegates.BindingContext{Headers: map[string]string{"X-Trace": "1"}, Credentials: &delegates.Credentials{BearerToken: "tok"}},
+	})
+	if result.Error != nil {
+		t.Fatalf("Execute failed: %s", result.Error.Message)
+	}
+	// The channel parameter and query field go in the handshake only.
+	want := map[string]any{"text": "hello"}
+	if !reflect.DeepEqual(result.Output, want) {
+		t.Errorf("Output = %v, want %v", result.Output, want)
+	}
+
+	hs := <-handshakes
+	if hs.path != "/rooms/go team" {
+		t.Errorf("path = %q, want /rooms/go team", hs.path)
+	}
+	if hs.query != "format=text" {
+		t.Errorf("query = %q, want format=text", hs.query)
+	}
+	for name, want := range map[string]string{"X-Client": "ob", "X-Trace": "1", "Authorization": "Bearer tok"} {
+		if got := hs.header.Get(name); got != want {
+			t.Errorf("header %s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestExecuteWSReceive(t *testing.T) {
+	srv, handshakes := newWSEchoServer(t, `{"seq":1}`, `plain text`, `{"seq":3}`)
+
+	result := Execute(context.Background(), delegates.ExecuteInput{
+		Source: delegates.Source{Format: "asyncapi@3.0", Content: wsDoc(hostFromURL(srv.URL))},
+		Ref:    "#/operations/onChat",
+		Input:  map[string]any{"maxEvents": float64(2), "format": "text"},
+	})
+	if result.Error != nil {
+		t.Fatalf("Execute failed: %s", result.Error.Message)
+	}
+	want := []any{map[string]any{"seq": float64(1)}, "plain text"}
+	if !reflect.DeepEqual(result.Output, want) {
+		t.Errorf("Output = %v, want %v", result.Output, want)
+	}
+
+	hs := <-handshakes
+	if hs.path != "/rooms/lobby" || hs.query != "format=text" {
+		t.Errorf("handshake = %s?%s, want /rooms/lobby?format=text", hs.path, hs.query)
+	}
+}
+
+func TestSubscribeWS(t *testing.T) {
+	srv, _ := newWSEchoServer(t, `{"seq":1}`, `{"seq":2}`, `{"seq":3}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := New().SubscribeOperation(ctx, delegates.ExecuteInput{
+		Source: delegates.Source{Format: "asyncapi@3.0", Content: wsDoc(hostFromURL(srv.URL))},
+		Ref:    "#/operations/onChat",
+	})
+	if err != nil {
+		t.Fatalf("SubscribeOperation: %v", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		ev := <-ch
+		if ev.Error != nil {
+			t.Fatalf("event %d: %s", i, ev.Error.Message)
+		}
+		if want := map[string]any{"seq": float64(i)}; !reflect.DeepEqual(ev.Data, want) {
+			t.Fatalf("event %d = %v, want %v", i, ev.Data, want)
+		}
+	}
+
+	cancel()
+	for ev := range ch {
+		if ev.Error != nil {
+			t.Fatalf("event after cancel: %s", ev.Error.Message)
+		}
+	}
+}
+
+func TestExecuteWSMissingChannelParameter(t *testing.T) {
+	doc := strings.Replace(wsDoc("localhost:1"), `"parameters": {"roomId": {"default": "lobby"}},`, "", 1)
+
+	result := Execute(context.Background(), delegates.ExecuteInput{
+		Source: delegates.Source{Format: "asyncapi@3.0", Content: doc},
+		Ref:    "#/operations/sendChat",
+		Input:  map[string]any{"text": "hello"},
+	})
+	if result.Error == nil || result.Error.Code != "invalid_input" || !strings.Contains(result.Error.Message, "roomId") {
+		t.Fatalf("Error = %+v, want invalid_input naming roomId", result.Error)
+	}
+}
@@ -0,0 +1,191 @@
+// Package delegates - cache.go contains the on-disk delegate probe cache.
+package delegates
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/openbindings/cli/internal/execref"
+	"github.com/openbindings/openbindings-go"
+)
+
+// ProbeCacheEntry is the cached probe result for a single delegate.
+type ProbeCacheEntry struct {
+	Location string `json:"location"`
+	// Validator identifies the probed version of the delegate: the binary's
+	// mtime and size for CLI delegates, or the interface ETag for HTTP delegates.
+	Validator string                  `json:"validator,omitempty"`
+	ProbedAt  time.Time               `json:"probedAt"`
+	Formats   []string                `json:"formats"`
+	Interface *openbindings.Interface `json:"interface,omitempty"`
+}
+
+// ProbeCache stores delegate probe results on disk so format resolution does
+// not run every workspace delegate on each invocation.
+//
+// An entry is reused while it is younger than TTL and its validator still
+// matches. CLI delegates are re-probed as soon as their binary changes. HTTP
+// delegates are trusted until the TTL expires, then revalidated with a
+// conditional request against the interface ETag. Entries are dropped when a
+// probe fails.
+type ProbeCache struct {
+	// Dir is the directory holding cache entries.
+	Dir string
+	// TTL is the maximum entry age. Zero means DefaultProbeCacheTTL.
+	TTL time.Duration
+}
+
+// NewProbeCache returns a cache rooted at dir with the default TTL.
+func NewProbeCache(dir string) *ProbeCache {
+	return &ProbeCache{Dir: dir, TTL: DefaultProbeCacheTTL}
+}
+
+func (c *ProbeCache) ttl() time.Duration {
+	if c.TTL <= 0 {
+		return DefaultProbeCacheTTL
+	}
+	return c.TTL
+}
+
+// entryPath returns the cache file for a delegate location.
+func (c *ProbeCache) entryPath(loc string) string {
+	sum := sha256.Sum256([]byte(loc))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// Load returns the cached entry for a delegate location, or nil if there is
+// none. Unreadable entries are treated as missing.
+func (c *ProbeCache) Load(loc string) *ProbeCacheEntry {
+	data, err := os.ReadFile(c.entryPath(loc))
+	if err != nil {
+		return nil
+	}
+	var entry ProbeCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Location != loc {
+		return nil
+	}
+	return &entry
+}
+
+// Store writes an entry to the cache.
+func (c *ProbeCache) Store(entry *ProbeCacheEntry) error {
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal cache entry: %w", err)
+	}
+	path := c.entryPath(entry.Location)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	return nil
+}
+
+// Invalidate removes the cached entry for a delegate location.
+func (c *ProbeCache) Invalidate(loc string) error {
+	if err := os.Remove(c.entryPath(loc)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove cache entry: %w", err)
+	}
+	return nil
+}
+
+// Clear removes every cached entry.
+func (c *ProbeCache) Clear() error {
+	if err := os.RemoveAll(c.Dir); err != nil {
+		return fmt.Errorf("clear delegate cache: %w", err)
+	}
+	return nil
+}
+
+// ProbeFormats is ProbeFormats served from the cache when possible.
+func (c *ProbeCache) ProbeFormats(loc string, timeout time.Duration, invoke BindingInvoker) ([]string, error) {
+	entry, err := c.Probe(loc, timeout, invoke, false)
+	if err != nil {
+		return nil, err
+	}
+	return entry.Formats, nil
+}
+
+// Probe returns the cache entry for a delegate, probing it when the entry is
+// missing or stale, or when force is set. A failed probe invalidates the entry.
+func (c *ProbeCache) Probe(loc string, timeout time.Duration, invoke BindingInvoker, force bool) (*ProbeCacheEntry, error) {
+	if !force {
+		if entry := c.Load(loc); entry != nil && c.revalidate(entry, timeout) {
+			return entry, nil
+		}
+	}
+
+	entry, err := probeDelegate(loc, timeout, invoke)
+	if err != nil {
+		_ = c.Invalidate(loc)
+		return nil, err
+	}
+	entry.ProbedAt = time.Now().UTC()
+	// A cache write failure only costs a future re-probe.
+	_ = c.Store(entry)
+	return entry, nil
+}
+
+// revalidate reports whether a cached entry can still be used, refreshing
+// its timestamp when an expired HTTP entry is confirmed unchanged.
+func (c *ProbeCache) revalidate(entry *ProbeCacheEntry, timeout time.Duration) bool {
+	expired := time.Since(entry.ProbedAt) >= c.ttl()
+
+	if IsHTTPURL(entry.Location) {
+		if !expired {
+			return true
+		}
+		if entry.Validator == "" {
+			return false
+		}
+		_, _, notModified, err := fetchOpenBindings(entry.Location, timeout, entry.Validator, nil)
+		if err != nil || !notModified {
+			return false
+		}
+		entry.ProbedAt = time.Now().UTC()
+		_ = c.Store(entry)
+		return true
+	}
+
+	if expired {
+		return false
+	}
+	cmd := entry.Location
+	if IsExecURL(cmd) {
+		root, err := execref.RootCommand(cmd)
+		if err != nil {
+			return false
+		}
+		cmd = root
+	}
+	v := binaryValidator(cmd)
+	return v != "" && v == entry.Validator
+}
+
+// binaryValidator identifies the current version of a delegate binary by its
+// modification time and size. It returns "" if the binary cannot be found.
+func binaryValidator(cmd string) string {
+	path, err := exec.LookPath(cmd)
+	if err != nil {
+		return ""
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size())
+}
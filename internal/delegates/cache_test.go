@@ -0,0 +1,194 @@
+package delegates
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openbindings/openbindings-go"
+)
+
+// writeScriptDelegate writes a shell-script CLI delegate that appends a line
+// to a log file on every run and reports the given format.
+func writeScriptDelegate(t *testing.T, dir, format string) (bin, logPath string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell-script delegates are not supported on windows")
+	}
+	bin = filepath.Join(dir, "acme-delegate")
+	logPath = filepath.Join(dir, "runs.log")
+	script := fmt.Sprintf(`#!/bin/sh
+echo "$1" >> %q
+case "$1" in
+--openbindings)
+  echo '{"openbindings":"0.1.0","operations":{"listFormats":{"kind":"method"}},"sources":{"cli":{"format":"usage@2.0.0"}},"bindings":{"listFormats.cli":{"operation":"listFormats","source":"cli","ref":"formats"}}}'
+  ;;
+formats)
+  echo %q
+  ;;
+esac
+`, logPath, format)
+	if err := os.WriteFile(bin, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return bin, logPath
+}
+
+func countRuns(t *testing.T, logPath string) int {
+	t.Helper()
+	data, err := os.ReadFile(logPath)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "--openbindings")
+}
+
+func TestProbeCache_CLIDelegate(t *testing.T) {
+	dir := t.TempDir()
+	bin, logPath := writeScriptDelegate(t, dir, "acme@1.0.0")
+	cache := NewProbeCache(filepath.Join(dir, "cache"))
+
+	for i := 0; i < 3; i++ {
+		formats, err := cache.ProbeFormats(bin, DefaultProbeTimeout, nil)
+		if err != nil {
+			t.Fatalf("ProbeFormats: %v", err)
+		}
+		if len(formats) != 1 || formats[0] != "acme@1.0.0" {
+			t.Fatalf("formats = %v, want [acme@1.0.0]", formats)
+		}
+	}
+	if n := countRuns(t, logPath); n != 1 {
+		t.Fatalf("delegate probed %d times, want 1", n)
+	}
+
+	entry := cache.Load(bin)
+	if entry == nil || entry.Interface == nil || entry.Validator == "" || entry.ProbedAt.IsZero() {
+		t.Fatalf("cache entry = %+v, want interface, validator and timestamp", entry)
+	}
+
+	// Rewriting the binary changes its mtime/size and invalidates the entry.
+	writeScriptDelegate(t, dir, "acme@2.0.0")
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(bin, future, future); err != nil {
+		t.Fatal(err)
+	}
+	formats, err := cache.ProbeFormats(bin, DefaultProbeTimeout, nil)
+	if err != nil {
+		t.Fatalf("ProbeFormats: %v", err)
+	}
+	if len(formats) != 1 || formats[0] != "acme@2.0.0" {
+		t.Fatalf("formats after rebuild = %v, want [acme@2.0.0]", formats)
+	}
+	if n := countRuns(t, logPath); n != 2 {
+		t.Fatalf("delegate probed %d times, want 2", n)
+	}
+}
+
+func TestProbeCache_TTLExpiry(t *testing.T) {
+	dir := t.TempDir()
+	bin, logPath := writeScriptDelegate(t, dir, "acme@1.0.0")
+	cache := &ProbeCache{Dir: filepath.Join(dir, "cache"), TTL: time.Hour}
+
+	if _, err := cache.ProbeFormats(bin, DefaultProbeTimeout, nil); err != nil {
+		t.Fatalf("ProbeFormats: %v", err)
+	}
+	entry := cache.Load(bin)
+	entry.ProbedAt = time.Now().Add(-2 * time.Hour)
+	if err := cache.Store(entry); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.ProbeFormats(bin, DefaultProbeTimeout, nil); err != nil {
+		t.Fatalf("ProbeFormats: %v", err)
+	}
+	if n := countRuns(t, logPath); n != 2 {
+		t.Fatalf("delegate probed %d times, want 2 after expiry", n)
+	}
+}
+
+func TestProbeCache_FailedProbeInvalidates(t *testing.T) {
+	dir := t.TempDir()
+	bin, _ := writeScriptDelegate(t, dir, "acme@1.0.0")
+	cache := NewProbeCache(filepath.Join(dir, "cache"))
+
+	if _, err := cache.Probe(bin, DefaultProbeTimeout, nil, false); err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	if err := os.Remove(bin); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Probe(bin, DefaultProbeTimeout, nil, true); err == nil {
+		t.Fatal("expected probe of a removed delegate to fail")
+	}
+	if entry := cache.Load(bin); entry != nil {
+		t.Fatalf("cache entry = %+v, want nil after failed probe", entry)
+	}
+}
+
+func TestProbeCache_HTTPDelegateETag(t *testing.T) {
+	var fetches, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"openbindings":"0.1.0","operations":{"listFormats":{"kind":"method"}}}`)
+	}))
+	defer srv.Close()
+
+	var invocations atomic.Int32
+	invoke := func(ctx context.Context, iface *openbindings.Interface, ifaceURL, opKey string, input any) (any, error) {
+		invocations.Add(1)
+		return []any{"acme@1.0.0"}, nil
+	}
+
+	cache := &ProbeCache{Dir: t.TempDir(), TTL: time.Hour}
+	if _, err := cache.ProbeFormats(srv.URL, DefaultProbeTimeout, invoke); err != nil {
+		t.Fatalf("ProbeFormats: %v", err)
+	}
+	if entry := cache.Load(srv.URL); entry == nil || entry.Validator != `"v1"` {
+		t.Fatalf("cache entry = %+v, want ETag validator", entry)
+	}
+
+	// Within the TTL the entry is used without any request.
+	if _, err := cache.ProbeFormats(srv.URL, DefaultProbeTimeout, invoke); err != nil {
+		t.Fatalf("ProbeFormats: %v", err)
+	}
+	if fetches.Load() != 1 || invocations.Load() != 1 {
+		t.Fatalf("fetches=%d invocations=%d, want 1/1 within TTL", fetches.Load(), invocations.Load())
+	}
+
+	// After expiry a 304 revalidates the entry without re-running listFormats.
+	entry := cache.Load(srv.URL)
+	entry.ProbedAt = time.Now().Add(-2 * time.Hour)
+	if err := cache.Store(entry); err != nil {
+		t.Fatal(err)
+	}
+	formats, err := cache.ProbeFormats(srv.URL, DefaultProbeTimeout, invoke)
+	if err != nil {
+		t.Fatalf("ProbeFormats: %v", err)
+	}
+	if len(formats) != 1 || formats[0] != "acme@1.0.0" {
+		t.Fatalf("formats = %v, want [acme@1.0.0]", formats)
+	}
+	if notModified.Load() != 1 || invocations.Load() != 1 {
+		t.Fatalf("notModified=%d invocations=%d, want 1/1 after revalidation", notModified.Load(), invocations.Load())
+	}
+	if age := time.Since(cache.Load(srv.URL).ProbedAt); age > time.Minute {
+		t.Fatalf("revalidated entry age = %v, want refreshed timestamp", age)
+	}
+}
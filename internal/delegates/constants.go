@@ -37,10 +37,23 @@ const (
 const (
 	// OpListFormats is the listFormats operation.
 	OpListFormats = "listFormats"
+
+	// OpCreateInterface is the createInterface operation.
+	OpCreateInterface = "createInterface"
+
+	// OpExecuteOperation is the executeOperation operation.
+	OpExecuteOperation = "executeOperation"
 )
 
 // Timeouts for network and probe operations.
 const (
 	// DefaultProbeTimeout is the default timeout for probing delegates.
 	DefaultProbeTimeout = 2 * time.Second
+
+	// DefaultFetchTimeout is the default timeout for fetching a remote delegate's interface.
+	DefaultFetchTimeout = 10 * time.Second
+
+	// DefaultProbeCacheTTL is how long cached probe results are reused before
+	// a delegate is probed again.
+	DefaultProbeCacheTTL = 24 * time.Hour
 )
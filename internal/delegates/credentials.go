@@ -0,0 +1,28 @@
+// Package delegates - credentials.go lets delegates persist credentials they
+// obtain or refresh while an operation runs.
+package delegates
+
+import "context"
+
+// CredentialSaver persists updated credentials for the binding context of
+// the running operation, for example the tokens a delegate obtained through
+// an OAuth authorization flow. update is called with the context's currently
+// stored credentials, which may be nil, and returns the credentials to store
+// in their place; it must not modify stored. Savers serialize updates, so
+// delegates that change different parts of the credentials concurrently do
+// not lose each other's changes.
+type CredentialSaver func(update func(stored *Credentials) *Credentials) error
+
+type credentialSaverKey struct{}
+
+// WithCredentialSaver returns a copy of ctx carrying save.
+func WithCredentialSaver(ctx context.Context, save CredentialSaver) context.Context {
+	return context.WithValue(ctx, credentialSaverKey{}, save)
+}
+
+// CredentialSaverFrom returns the CredentialSaver attached to ctx, or nil
+// when updated credentials cannot be persisted.
+func CredentialSaverFrom(ctx context.Context) CredentialSaver {
+	save, _ := ctx.Value(credentialSaverKey{}).(CredentialSaver)
+	return save
+}
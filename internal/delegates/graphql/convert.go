@@ -0,0 +1,296 @@
+package graphql
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/openbindings/cli/internal/delegates"
+	"github.com/openbindings/openbindings-go"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// DefaultSourceName is the default source key for GraphQL sources.
+const DefaultSourceName = "graphql"
+
+// Root operation types. A binding ref is the root operation type and the
+// name of one of its fields, e.g. "query/user".
+const (
+	opQuery        = "query"
+	opMutation     = "mutation"
+	opSubscription = "subscription"
+)
+
+// ConvertToInterface converts a GraphQL schema to an OpenBindings interface.
+// Each field of the query and mutation types becomes a method operation and
+// each field of the subscription type an event operation. The input is the
+// field's arguments; the output (or event payload) is the data of the
+// field's default selection set (see selectionFor).
+func ConvertToInterface(schema *ast.Schema, sourceLocation string) (openbindings.Interface, error) {
+	if schema == nil {
+		return openbindings.Interface{}, fmt.Errorf("nil schema")
+	}
+
+	iface := openbindings.Interface{
+		OpenBindings: openbindings.MaxTestedVersion,
+		Name:         interfaceName(sourceLocation),
+		Description:  schema.Description,
+		Operations:   map[string]openbindings.Operation{},
+		Bindings:     map[string]openbindings.BindingEntry{},
+		Sources: map[string]openbindings.Source{
+			DefaultSourceName: {
+				Format:   FormatToken,
+				Location: sourceLocation,
+			},
+		},
+	}
+
+	usedKeys := map[string]string{}
+	schemas := newSchemaBuilder(schema)
+
+	for _, opType := range []string{opQuery, opMutation, opSubscription} {
+		root := rootType(schema, opType)
+		if root == nil {
+			continue
+		}
+		for _, field := range root.Fields {
+			if strings.HasPrefix(field.Name, "__") {
+				continue
+			}
+			ref := opType + "/" + field.Name
+			opKey := resolveKeyCollision(delegates.SanitizeKey(field.Name), opType, usedKeys)
+			usedKeys[opKey] = ref
+
+			op := openbindings.Operation{
+				Kind:        openbindings.OperationKindMethod,
+				Description: field.Description,
+				Deprecated:  field.Directives.ForName("deprecated") != nil,
+			}
+			if len(field.Arguments) > 0 {
+				op.Input = schemas.arguments(field.Arguments)
+			}
+			sel := selectionFor(schema, field)
+			if opType == opSubscription {
+				op.Kind = openbindings.OperationKindEvent
+				op.Payload = sel.schema
+			} else {
+				op.Output = sel.schema
+			}
+
+			iface.Operations[opKey] = op
+			iface.Bindings[opKey+"."+DefaultSourceName] = openbindings.BindingEntry{
+				Operation: opKey,
+				Source:    DefaultSourceName,
+				Ref:       ref,
+			}
+		}
+	}
+
+	if len(schemas.schemas) > 0 {
+		iface.Schemas = schemas.schemas
+	}
+
+	return iface, nil
+}
+
+// interfaceName derives a name from the source location: the host of an
+// endpoint, or the base name of an SDL file without its extension.
+func interfaceName(location string) string {
+	if location == "" {
+		return ""
+	}
+	name := delegates.NameFromLocation(location)
+	if IsSDLSource(location) {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
+
+// rootType returns the schema's root type for an operation type, or nil.
+func rootType(schema *ast.Schema, opType string) *ast.Definition {
+	switch opType {
+	case opQuery:
+		return schema.Query
+	case opMutation:
+		return schema.Mutation
+	case opSubscription:
+		return schema.Subscription
+	}
+	return nil
+}
+
+// resolveKeyCollision returns key, or key prefixed by the root operation
+// type (e.g., "mutation_user") when a field of another root type took it.
+func resolveKeyCollision(key string, prefix string, used map[string]string) string {
+	if _, taken := used[key]; !taken {
+		return key
+	}
+	candidate := prefix + "_" + key
+	if _, taken := used[candidate]; !taken {
+		return candidate
+	}
+	for i := 2; ; i++ {
+		numbered := fmt.Sprintf("%s_%d", candidate, i)
+		if _, taken := used[numbered]; !taken {
+			return numbered
+		}
+	}
+}
+
+// schemaBuilder converts GraphQL argument and input types to JSON Schema.
+// Non-null arguments and input fields without a default are required.
+// Input objects that contain themselves are emitted once into schemas and
+// referenced with "$ref": "#/schemas/<name>".
+type schemaBuilder struct {
+	schema    *ast.Schema
+	schemas   map[string]openbindings.JSONSchema
+	recursive map[string]bool // memoized isRecursive results by type name
+}
+
+func newSchemaBuilder(schema *ast.Schema) *schemaBuilder {
+	return &schemaBuilder{
+		schema:    schema,
+		schemas:   map[string]openbindings.JSONSchema{},
+		recursive: map[string]bool{},
+	}
+}
+
+// arguments returns the object schema of a field's arguments.
+func (b *schemaBuilder) arguments(args ast.ArgumentDefinitionList) map[string]any {
+	properties := map[string]any{}
+	var required []any
+	for _, arg := range args {
+		properties[arg.Name] = b.value(arg.Type, arg.Description, arg.DefaultValue, arg.Directives)
+		if arg.Type.NonNull && arg.DefaultValue == nil {
+			required = append(required, arg.Name)
+		}
+	}
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// value returns the schema of an argument or input field.
+func (b *schemaBuilder) value(t *ast.Type, description string, defaultValue *ast.Value, directives ast.DirectiveList) map[string]any {
+	s := b.inputType(t)
+	if description != "" {
+		s["description"] = description
+	}
+	if defaultValue != nil {
+		if v, err := defaultValue.Value(nil); err == nil {
+			s["default"] = v
+		}
+	}
+	if directives.ForName("deprecated") != nil {
+		s["deprecated"] = true
+	}
+	return s
+}
+
+func (b *schemaBuilder) inputType(t *ast.Type) map[string]any {
+	if t.Elem != nil {
+		return map[string]any{
+			"type":  "array",
+			"items": b.inputType(t.Elem),
+		}
+	}
+	def := b.schema.Types[t.NamedType]
+	if def == nil {
+		return map[string]any{}
+	}
+	if def.Kind != ast.InputObject {
+		return leafSchema(def)
+	}
+	if !b.isRecursive(def) {
+		return b.inputObject(def)
+	}
+	if _, ok := b.schemas[def.Name]; !ok {
+		b.schemas[def.Name] = nil // placeholder: stops the recursion below
+		b.schemas[def.Name] = b.inputObject(def)
+	}
+	return map[string]any{"$ref": "#/schemas/" + def.Name}
+}
+
+func (b *schemaBuilder) inputObject(def *ast.Definition) map[string]any {
+	properties := map[string]any{}
+	var required []any
+	for _, f := range def.Fields {
+		properties[f.Name] = b.value(f.Type, f.Description, f.DefaultValue, f.Directives)
+		if f.Type.NonNull && f.DefaultValue == nil {
+			required = append(required, f.Name)
+		}
+	}
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if def.Description != "" {
+		schema["description"] = def.Description
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// isRecursive reports whether an input object contains itself, directly or
+// through other input objects.
+func (b *schemaBuilder) isRecursive(def *ast.Definition) bool {
+	if r, ok := b.recursive[def.Name]; ok {
+		return r
+	}
+
+	seen := map[string]bool{}
+	var reaches func(d *ast.Definition) bool
+	reaches = func(d *ast.Definition) bool {
+		for _, f := range d.Fields {
+			t := b.schema.Types[f.Type.Name()]
+			if t == nil || t.Kind != ast.InputObject {
+				continue
+			}
+			if t.Name == def.Name {
+				return true
+			}
+			if seen[t.Name] {
+				continue
+			}
+			seen[t.Name] = true
+			if reaches(t) {
+				return true
+			}
+		}
+		return false
+	}
+
+	r := reaches(def)
+	b.recursive[def.Name] = r
+	return r
+}
+
+// leafSchema returns the schema of a scalar or enum type. Custom scalars
+// accept any value.
+func leafSchema(def *ast.Definition) map[string]any {
+	switch def.Name {
+	case "Int":
+		return map[string]any{"type": "integer"}
+	case "Float":
+		return map[string]any{"type": "number"}
+	case "String", "ID":
+		return map[string]any{"type": "string"}
+	case "Boolean":
+		return map[string]any{"type": "boolean"}
+	}
+	if def.Kind == ast.Enum {
+		values := make([]any, len(def.EnumValues))
+		for i, v := range def.EnumValues {
+			values[i] = v.Name
+		}
+		return map[string]any{"type": "string", "enum": values}
+	}
+	return map[string]any{}
+}
@@ -0,0 +1,352 @@
+package graphql
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/openbindings/openbindings-go"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const testSDL = `
+"A shop."
+schema {
+  query: Query
+  mutation: Mutation
+  subscription: Subscription
+}
+
+type Query {
+  "Look up a product."
+  product(id: ID!): Product
+  products(first: Int = 10, filter: ProductFilter): [Product!]!
+  search(text: String!): [SearchResult!]!
+  legacy: String @deprecated(reason: "use product")
+}
+
+type Mutation {
+  addProduct(input: ProductInput!): Product!
+  product(id: ID!): Product
+}
+
+type Subscription {
+  priceChanged(id: ID): Product!
+}
+
+interface Node {
+  id: ID!
+}
+
+type Product implements Node {
+  id: ID!
+  name: String!
+  price: Float
+  status: Status!
+  reviews(first: Int!): [Review!]!
+  category: Category
+}
+
+type Category {
+  name: String!
+  parent: Category
+}
+
+type Review {
+  stars: Int!
+}
+
+type Author {
+  id: ID
+  name: String!
+}
+
+union SearchResult = Product | Author
+
+enum Status {
+  ACTIVE
+  RETIRED
+}
+
+input ProductInput {
+  "The product name."
+  name: String!
+  price: Float = 1.5
+  tags: [String!]
+}
+
+input ProductFilter {
+  status: Status
+  and: [ProductFilter!]
+}
+`
+
+func loadTestSchema(t *testing.T) *ast.Schema {
+	t.Helper()
+	schema, err := parseSchema([]byte(testSDL), "shop.graphql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func TestConvertToInterface(t *testing.T) {
+	iface, err := ConvertToInterface(loadTestSchema(t), "./shop.graphql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if iface.Name != "shop" || iface.Description != "A shop." {
+		t.Errorf("name, description = %q, %q", iface.Name, iface.Description)
+	}
+	if src := iface.Sources[DefaultSourceName]; src.Format != FormatToken || src.Location != "./shop.graphql" {
+		t.Errorf("source = %+v", src)
+	}
+
+	wantRefs := map[string]string{
+		"product":          "query/product",
+		"products":         "query/products",
+		"search":           "query/search",
+		"legacy":           "query/legacy",
+		"addProduct":       "mutation/addProduct",
+		"mutation_product": "mutation/product",
+		"priceChanged":     "subscription/priceChanged",
+	}
+	if len(iface.Operations) != len(wantRefs) {
+		t.Errorf("operations = %d, want %d", len(iface.Operations), len(wantRefs))
+	}
+	for opKey, ref := range wantRefs {
+		b, ok := iface.Bindings[opKey+"."+DefaultSourceName]
+		if !ok || b.Ref != ref || b.Operation != opKey {
+			t.Errorf("binding for %s = %+v, want ref %q", opKey, b, ref)
+		}
+	}
+
+	product := iface.Operations["product"]
+	if product.Kind != openbindings.OperationKindMethod || product.Description != "Look up a product." {
+		t.Errorf("product = %+v", product)
+	}
+	if got := product.Input["required"]; !reflect.DeepEqual(got, []any{"id"}) {
+		t.Errorf("product input required = %v", got)
+	}
+	if !iface.Operations["legacy"].Deprecated {
+		t.Error("legacy should be deprecated")
+	}
+	if iface.Operations["legacy"].Input != nil {
+		t.Error("an operation without arguments has no input")
+	}
+
+	event := iface.Operations["priceChanged"]
+	if event.Kind != openbindings.OperationKindEvent || event.Payload == nil || event.Output != nil {
+		t.Errorf("priceChanged = %+v, want an event with a payload", event)
+	}
+
+	products := iface.Operations["products"]
+	first := products.Input["properties"].(map[string]any)["first"].(map[string]any)
+	if first["type"] != "integer" || first["default"] != int64(10) {
+		t.Errorf("products.first = %v", first)
+	}
+	if _, ok := products.Input["required"]; ok {
+		t.Error("products has no required arguments")
+	}
+	// ProductFilter contains itself, so it is referenced.
+	filter := products.Input["properties"].(map[string]any)["filter"].(map[string]any)
+	if filter["$ref"] != "#/schemas/ProductFilter" {
+		t.Errorf("filter = %v, want a $ref", filter)
+	}
+	if _, ok := iface.Schemas["ProductFilter"]; !ok {
+		t.Error("missing ProductFilter schema")
+	}
+
+	input := iface.Operations["addProduct"].Input["properties"].(map[string]any)["input"].(map[string]any)
+	if !reflect.DeepEqual(input["required"], []any{"name"}) {
+		t.Errorf("ProductInput required = %v", input["required"])
+	}
+	name := input["properties"].(map[string]any)["name"].(map[string]any)
+	if name["description"] != "The product name." {
+		t.Errorf("ProductInput.name = %v", name)
+	}
+
+	out := products.Output
+	if out["type"] != "array" {
+		t.Fatalf("products output = %v", out)
+	}
+	item := out["items"].(map[string]any)
+	props := item["properties"].(map[string]any)
+	if _, ok := props["reviews"]; ok {
+		t.Error("reviews takes a required argument and should not be selected")
+	}
+	if status := props["status"].(map[string]any); !reflect.DeepEqual(status["enum"], []any{"ACTIVE", "RETIRED"}) {
+		t.Errorf("status = %v", status)
+	}
+	category := props["category"].(map[string]any)["properties"].(map[string]any)
+	if _, ok := category["parent"]; ok {
+		t.Error("category.parent is beyond the selection depth")
+	}
+}
+
+func TestSelectionFor(t *testing.T) {
+	schema := loadTestSchema(t)
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"product", "{ id name price status category { name } }"},
+		{"search", "{ __typename ... on Author { name } ... on Product { name price status category { name } } }"},
+		{"legacy", ""},
+	}
+	for _, tt := range tests {
+		got := selectionFor(schema, schema.Query.Fields.ForName(tt.field)).text
+		if got != tt.want {
+			t.Errorf("%s selection = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestParseSchema_Introspection(t *testing.T) {
+	schema := loadTestSchema(t)
+	want, err := ConvertToInterface(schema, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, result := range map[string]any{
+		"bare":     introspectionOf(schema),
+		"envelope": map[string]any{"data": introspectionOf(schema)},
+	} {
+		data, err := json.Marshal(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromIntrospection, err := parseSchema(data, "")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got, err := ConvertToInterface(fromIntrospection, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got.Operations, want.Operations) || !reflect.DeepEqual(got.Bindings, want.Bindings) {
+			t.Errorf("%s: interface from introspection differs from the one from SDL", name)
+		}
+	}
+}
+
+func TestParseRef(t *testing.T) {
+	opType, field, sel, err := parseRef("query/product { id name }")
+	if err != nil || opType != "query" || field != "product" || sel != "{ id name }" {
+		t.Errorf("parseRef = %q, %q, %q, %v", opType, field, sel, err)
+	}
+	for _, ref := range []string{"product", "fragment/product", "query/"} {
+		if _, _, _, err := parseRef(ref); err == nil {
+			t.Errorf("parseRef(%q): expected an error", ref)
+		}
+	}
+}
+
+func TestIsSDLSource(t *testing.T) {
+	for loc, want := range map[string]bool{
+		"./schema.graphql":                      true,
+		"schema.GQL":                            true,
+		"https://example.com/schema.graphqls?x": true,
+		"https://example.com/graphql":           false,
+		"./schema.json":                         false,
+	} {
+		if got := IsSDLSource(loc); got != want {
+			t.Errorf("IsSDLSource(%q) = %v, want %v", loc, got, want)
+		}
+	}
+}
+
+// introspectionOf renders a schema as the data of an introspection query.
+func introspectionOf(schema *ast.Schema) map[string]any {
+	var typeRef func(t *ast.Type) map[string]any
+	typeRef = func(t *ast.Type) map[string]any {
+		if t.NonNull {
+			inner := *t
+			inner.NonNull = false
+			return map[string]any{"kind": "NON_NULL", "name": nil, "ofType": typeRef(&inner)}
+		}
+		if t.Elem != nil {
+			return map[string]any{"kind": "LIST", "name": nil, "ofType": typeRef(t.Elem)}
+		}
+		return map[string]any{"kind": string(schema.Types[t.NamedType].Kind), "name": t.NamedType}
+	}
+	inputValue := func(name, description string, t *ast.Type, def *ast.Value) map[string]any {
+		v := map[string]any{"name": name, "description": description, "type": typeRef(t), "defaultValue": nil}
+		if def != nil {
+			v["defaultValue"] = def.String()
+		}
+		return v
+	}
+	deprecated := func(directives ast.DirectiveList) (bool, any) {
+		d := directives.ForName("deprecated")
+		if d == nil {
+			return false, nil
+		}
+		if arg := d.Arguments.ForName("reason"); arg != nil {
+			return true, arg.Value.Raw
+		}
+		return true, nil
+	}
+	named := func(def *ast.Definition) any {
+		if def == nil {
+			return nil
+		}
+		return map[string]any{"name": def.Name}
+	}
+
+	names := make([]string, 0, len(schema.Types))
+	for name := range schema.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var types []any
+	for _, name := range names {
+		def := schema.Types[name]
+		t := map[string]any{"kind": string(def.Kind), "name": def.Name, "description": def.Description}
+		var fields, inputFields, interfaces, enumValues, possibleTypes []any
+		for _, f := range def.Fields {
+			if def.Kind == ast.InputObject {
+				inputFields = append(inputFields, inputValue(f.Name, f.Description, f.Type, f.DefaultValue))
+				continue
+			}
+			if strings.HasPrefix(f.Name, "__") {
+				continue
+			}
+			var args []any
+			for _, a := range f.Arguments {
+				args = append(args, inputValue(a.Name, a.Description, a.Type, a.DefaultValue))
+			}
+			isDeprecated, reason := deprecated(f.Directives)
+			fields = append(fields, map[string]any{
+				"name": f.Name, "description": f.Description, "args": args, "type": typeRef(f.Type),
+				"isDeprecated": isDeprecated, "deprecationReason": reason,
+			})
+		}
+		for _, name := range def.Interfaces {
+			interfaces = append(interfaces, map[string]any{"kind": "INTERFACE", "name": name})
+		}
+		for _, v := range def.EnumValues {
+			isDeprecated, reason := deprecated(v.Directives)
+			enumValues = append(enumValues, map[string]any{
+				"name": v.Name, "description": v.Description, "isDeprecated": isDeprecated, "deprecationReason": reason,
+			})
+		}
+		for _, name := range def.Types {
+			possibleTypes = append(possibleTypes, map[string]any{"kind": "OBJECT", "name": name})
+		}
+		t["fields"], t["inputFields"], t["interfaces"], t["enumValues"], t["possibleTypes"] = fields, inputFields, interfaces, enumValues, possibleTypes
+		types = append(types, t)
+	}
+
+	return map[string]any{"__schema": map[string]any{
+		"queryType":        named(schema.Query),
+		"mutationType":     named(schema.Mutation),
+		"subscriptionType": named(schema.Subscription),
+		"types":            types,
+	}}
+}
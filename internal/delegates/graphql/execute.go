@@ -0,0 +1,261 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// request is a GraphQL request body.
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// response is a GraphQL response body, with the HTTP response it came in
+// (whose body is already consumed).
+type response struct {
+	Data   json.RawMessage  `json:"data"`
+	Errors []map[string]any `json:"errors"`
+
+	http *http.Response
+}
+
+// fieldData returns the data of a root field, or nil.
+func (r *response) fieldData(field string) any {
+	var data map[string]any
+	if err := json.Unmarshal(r.Data, &data); err != nil {
+		return nil
+	}
+	return data[field]
+}
+
+// operation is a request prepared for a binding ref.
+type operation struct {
+	endpoint string
+	opType   string
+	field    string
+	req      request
+}
+
+// Execute runs a query or mutation with an HTTP POST to the endpoint. The
+// input holds the field's arguments, which are sent as variables; the
+// output is the field's data. A subscription yields its first event.
+func Execute(ctx context.Context, input delegates.ExecuteInput) delegates.ExecuteOutput {
+	start := time.Now()
+
+	op, opErr := prepare(ctx, input)
+	if opErr != nil {
+		return delegates.FailedOutput(start, opErr.Code, opErr.Message)
+	}
+	if op.opType == opSubscription {
+		return executeSubscription(ctx, op, input.Context, start)
+	}
+
+	resp, err := post(ctx, op.endpoint, input.Context, op.req)
+	if err != nil {
+		return delegates.FailedOutput(start, "request_failed", err.Error())
+	}
+	output := resp.fieldData(op.field)
+
+	if len(resp.Errors) > 0 {
+		errOutput := delegates.FailedOutput(start, "graphql_error", errorMessage(resp.Errors))
+		if resp.http.StatusCode >= 400 {
+			errOutput.Status = resp.http.StatusCode
+		}
+		errOutput.Output = output
+		errOutput.Error.Details = map[string]any{"errors": resp.Errors}
+		return errOutput
+	}
+	if resp.http.StatusCode >= 400 {
+		return delegates.HTTPResponseErrorOutput(start, resp.http)
+	}
+
+	return delegates.ExecuteOutput{
+		Output:     output,
+		Status:     0,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+}
+
+// executeSubscription subscribes and returns the first event.
+func executeSubscription(ctx context.Context, op *operation, bindCtx *delegates.BindingContext, start time.Time) delegates.ExecuteOutput {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events, err := subscribe(ctx, op, bindCtx)
+	if err != nil {
+		return delegates.FailedOutput(start, "subscribe_failed", err.Error())
+	}
+	ev, ok := <-events
+	if !ok {
+		msg := "subscription completed without an event"
+		if ctx.Err() != nil {
+			msg = fmt.Sprintf("no event received: %v", context.Cause(ctx))
+		}
+		return delegates.FailedOutput(start, "no_event", msg)
+	}
+	if ev.Error != nil {
+		return delegates.ExecuteOutput{
+			Output:     ev.Data,
+			Status:     1,
+			DurationMs: time.Since(start).Milliseconds(),
+			Error:      ev.Error,
+		}
+	}
+	return delegates.ExecuteOutput{
+		Output:     ev.Data,
+		Status:     0,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+}
+
+// prepare resolves the endpoint and schema of an operation and builds its
+// request. Errors carry the code of the failed step.
+func prepare(ctx context.Context, input delegates.ExecuteInput) (*operation, *delegates.Error) {
+	opType, field, selectionSet, err := parseRef(input.Ref)
+	if err != nil {
+		return nil, &delegates.Error{Code: "invalid_ref", Message: err.Error()}
+	}
+	endpoint, err := endpointFor(input)
+	if err != nil {
+		return nil, &delegates.Error{Code: "no_endpoint", Message: err.Error()}
+	}
+	variables, ok := delegates.ToStringAnyMap(input.Input)
+	if !ok && input.Input != nil {
+		return nil, &delegates.Error{Code: "invalid_input", Message: "input must be an object of field arguments"}
+	}
+
+	schema, err := executionSchema(ctx, input.Source, input.Context)
+	if err != nil {
+		return nil, &delegates.Error{Code: "schema_load_failed", Message: err.Error()}
+	}
+	root := rootType(schema, opType)
+	if root == nil {
+		return nil, &delegates.Error{Code: "field_not_found", Message: fmt.Sprintf("schema has no %s type", opType)}
+	}
+	f := root.Fields.ForName(field)
+	if f == nil {
+		return nil, &delegates.Error{Code: "field_not_found", Message: fmt.Sprintf("%s type %q has no field %q", opType, root.Name, field)}
+	}
+	if selectionSet == "" {
+		selectionSet = selectionFor(schema, f).text
+	}
+
+	// Arguments are bound to variables of the same name. Those without a
+	// value are left out, so the server applies their defaults, unless
+	// they are required.
+	var defs, args []string
+	sent := map[string]any{}
+	for _, arg := range f.Arguments {
+		value, given := variables[arg.Name]
+		if !given && !(arg.Type.NonNull && arg.DefaultValue == nil) {
+			continue
+		}
+		defs = append(defs, "$"+arg.Name+": "+arg.Type.String())
+		args = append(args, arg.Name+": $"+arg.Name)
+		if given {
+			sent[arg.Name] = value
+		}
+	}
+
+	var doc strings.Builder
+	doc.WriteString(opType + " " + field)
+	if len(defs) > 0 {
+		doc.WriteString("(" + strings.Join(defs, ", ") + ")")
+	}
+	doc.WriteString(" { " + field)
+	if len(args) > 0 {
+		doc.WriteString("(" + strings.Join(args, ", ") + ")")
+	}
+	if selectionSet != "" {
+		doc.WriteString(" " + selectionSet)
+	}
+	doc.WriteString(" }")
+
+	req := request{Query: doc.String(), OperationName: field}
+	if len(sent) > 0 {
+		req.Variables = sent
+	}
+	return &operation{endpoint: endpoint, opType: opType, field: field, req: req}, nil
+}
+
+// parseRef splits a binding ref into its root operation type and field
+// name. A selection set may follow the field name to replace the default
+// one, e.g. "query/user { id name }".
+func parseRef(ref string) (opType, field, selectionSet string, err error) {
+	opType, rest, ok := strings.Cut(strings.TrimSpace(ref), "/")
+	if !ok {
+		return "", "", "", fmt.Errorf("invalid ref %q (expected query/<field>, mutation/<field> or subscription/<field>)", ref)
+	}
+	field = rest
+	if i := strings.IndexAny(rest, " \t\r\n{"); i >= 0 {
+		field, selectionSet = rest[:i], strings.TrimSpace(rest[i:])
+	}
+	if opType != opQuery && opType != opMutation && opType != opSubscription {
+		return "", "", "", fmt.Errorf("invalid ref %q: unknown operation type %q", ref, opType)
+	}
+	if field == "" {
+		return "", "", "", fmt.Errorf("invalid ref %q: missing field name", ref)
+	}
+	return opType, field, selectionSet, nil
+}
+
+// post sends a GraphQL request with the context's credentials and headers.
+// Error statuses whose body is not a GraphQL response yield a response
+// without data.
+func post(ctx context.Context, endpoint string, bindCtx *delegates.BindingContext, body request) (*response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/graphql-response+json, application/json")
+	delegates.ApplyHTTPContext(req, bindCtx)
+
+	client, err := delegates.HTTPClient(bindCtx)
+	if err != nil {
+		return nil, err
+	}
+	httpResp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &response{http: httpResp}
+	if err := json.Unmarshal(respBody, resp); err != nil && httpResp.StatusCode < 400 {
+		return nil, fmt.Errorf("invalid GraphQL response: %w", err)
+	}
+	return resp, nil
+}
+
+// errorMessage joins the messages of GraphQL errors.
+func errorMessage(errs []map[string]any) string {
+	msgs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		if msg, ok := e["message"].(string); ok {
+			msgs = append(msgs, msg)
+		}
+	}
+	if len(msgs) == 0 {
+		return "GraphQL request failed"
+	}
+	return strings.Join(msgs, "; ")
+}
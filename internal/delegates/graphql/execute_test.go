@@ -0,0 +1,288 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/openbindings/cli/internal/delegates"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// newTestServer serves the test schema: introspection, queries and
+// mutations over HTTP, and subscriptions over graphql-transport-ws.
+// Operations other than introspection require the bearer token "secret".
+// Documents are validated against the schema before they are answered.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	schema := loadTestSchema(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") == "websocket" {
+			serveSubscription(t, schema, w, r)
+			return
+		}
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if req.OperationName == "IntrospectionQuery" {
+			json.NewEncoder(w).Encode(map[string]any{"data": introspectionOf(schema)})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(answer(schema, req))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func answer(schema *ast.Schema, req request) map[string]any {
+	if _, errs := gqlparser.LoadQueryWithRules(schema, req.Query, nil); len(errs) > 0 {
+		return map[string]any{"errors": errs}
+	}
+	switch req.OperationName {
+	case "product":
+		if req.Variables["id"] == "missing" {
+			return map[string]any{
+				"data":   map[string]any{"product": nil},
+				"errors": []any{map[string]any{"message": "product not found", "path": []any{"product"}}},
+			}
+		}
+		return map[string]any{"data": map[string]any{"product": map[string]any{"id": req.Variables["id"], "name": "Lamp"}}}
+	case "addProduct":
+		input, _ := req.Variables["input"].(map[string]any)
+		return map[string]any{"data": map[string]any{"addProduct": map[string]any{"id": "p2", "name": input["name"]}}}
+	case "products":
+		return map[string]any{"data": map[string]any{"products": []any{}, "query": req.Query}}
+	case "search":
+		return map[string]any{"data": map[string]any{"search": []any{map[string]any{"__typename": "Author", "name": "Ada"}}}}
+	}
+	return map[string]any{"errors": []any{map[string]any{"message": "unexpected operation " + req.OperationName}}}
+}
+
+func serveSubscription(t *testing.T, schema *ast.Schema, w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: []string{subprotocol}})
+	if err != nil {
+		t.Errorf("accept: %v", err)
+		return
+	}
+	defer conn.CloseNow()
+	ctx := r.Context()
+
+	init, err := readMessage(ctx, conn)
+	if err != nil || init.Type != "connection_init" {
+		t.Errorf("connection_init = %+v, %v", init, err)
+		return
+	}
+	var params map[string]string
+	json.Unmarshal(init.Payload, &params)
+	if params["Authorization"] != "Bearer secret" {
+		conn.Close(4403, "forbidden")
+		return
+	}
+	writeMessage(ctx, conn, message{Type: "connection_ack"})
+
+	sub, err := readMessage(ctx, conn)
+	if err != nil || sub.Type != "subscribe" {
+		t.Errorf("subscribe = %+v, %v", sub, err)
+		return
+	}
+	var req request
+	json.Unmarshal(sub.Payload, &req)
+	if _, errs := gqlparser.LoadQueryWithRules(schema, req.Query, nil); len(errs) > 0 {
+		payload, _ := json.Marshal(errs)
+		writeMessage(ctx, conn, message{ID: sub.ID, Type: "error", Payload: payload})
+		return
+	}
+	for _, price := range []float64{9.5, 8} {
+		payload, _ := json.Marshal(map[string]any{"data": map[string]any{"priceChanged": map[string]any{"id": req.Variables["id"], "price": price}}})
+		writeMessage(ctx, conn, message{ID: sub.ID, Type: "next", Payload: payload})
+	}
+	writeMessage(ctx, conn, message{ID: sub.ID, Type: "complete"})
+	conn.Close(websocket.StatusNormalClosure, "")
+}
+
+func bearer(token string) *delegates.BindingContext {
+	return &delegates.BindingContext{Credentials: &delegates.Credentials{BearerToken: token}}
+}
+
+func TestExecute_Query(t *testing.T) {
+	server := newTestServer(t)
+	source := delegates.Source{Format: FormatToken, Location: server.URL}
+
+	out := Execute(context.Background(), delegates.ExecuteInput{
+		Source: source, Ref: "query/product", Input: map[string]any{"id": "p1"}, Context: bearer("secret"),
+	})
+	if out.Error != nil {
+		t.Fatalf("Execute: %+v", out.Error)
+	}
+	if want := map[string]any{"id": "p1", "name": "Lamp"}; !reflect.DeepEqual(out.Output, want) {
+		t.Errorf("output = %v, want %v", out.Output, want)
+	}
+
+	out = Execute(context.Background(), delegates.ExecuteInput{
+		Source: source, Ref: "query/product", Input: map[string]any{"id": "missing"}, Context: bearer("secret"),
+	})
+	if out.Status != 1 || out.Error == nil || out.Error.Code != "graphql_error" || out.Error.Message != "product not found" {
+		t.Errorf("missing product = status %d, error %+v", out.Status, out.Error)
+	}
+
+	out = Execute(context.Background(), delegates.ExecuteInput{
+		Source: source, Ref: "query/nope", Context: bearer("secret"),
+	})
+	if out.Error == nil || out.Error.Code != "field_not_found" {
+		t.Errorf("unknown field = %+v, want field_not_found", out.Error)
+	}
+}
+
+func TestExecute_UnionWithConflictingFields(t *testing.T) {
+	server := newTestServer(t)
+
+	// Product.id is ID! and Author.id is ID; selecting both unaliased
+	// fails validation.
+	out := Execute(context.Background(), delegates.ExecuteInput{
+		Source:  delegates.Source{Format: FormatToken, Location: server.URL},
+		Ref:     "query/search",
+		Input:   map[string]any{"text": "a"},
+		Context: bearer("secret"),
+	})
+	if out.Error != nil {
+		t.Fatalf("Execute: %+v", out.Error)
+	}
+	if want := []any{map[string]any{"__typename": "Author", "name": "Ada"}}; !reflect.DeepEqual(out.Output, want) {
+		t.Errorf("output = %v, want %v", out.Output, want)
+	}
+}
+
+func TestPrepare_OmitsUnsetArguments(t *testing.T) {
+	server := newTestServer(t)
+	op, opErr := prepare(context.Background(), delegates.ExecuteInput{
+		Source: delegates.Source{Location: server.URL},
+		Ref:    "query/products",
+		Input:  map[string]any{"filter": map[string]any{"status": "ACTIVE"}, "unknown": 1},
+	})
+	if opErr != nil {
+		t.Fatal(opErr.Message)
+	}
+	want := "query products($filter: ProductFilter) { products(filter: $filter) { id name price status category { name } } }"
+	if op.req.Query != want {
+		t.Errorf("query = %q, want %q", op.req.Query, want)
+	}
+	if _, ok := op.req.Variables["unknown"]; ok {
+		t.Error("variables should only hold arguments of the field")
+	}
+
+	op, opErr = prepare(context.Background(), delegates.ExecuteInput{
+		Source: delegates.Source{Location: server.URL},
+		Ref:    "query/product { name }",
+	})
+	if opErr != nil {
+		t.Fatal(opErr.Message)
+	}
+	if want := "query product($id: ID!) { product(id: $id) { name } }"; op.req.Query != want {
+		t.Errorf("query = %q, want %q", op.req.Query, want)
+	}
+}
+
+func TestExecute_SDLSource(t *testing.T) {
+	server := newTestServer(t)
+	path := filepath.Join(t.TempDir(), "shop.graphql")
+	if err := os.WriteFile(path, []byte(testSDL), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	source := delegates.Source{
+		Format:     FormatToken,
+		Location:   path,
+		Extensions: map[string]json.RawMessage{sourceExtensionKey: json.RawMessage(`{"endpoint":"` + server.URL + `"}`)},
+	}
+	input := map[string]any{"input": map[string]any{"name": "Desk"}}
+
+	out := Execute(context.Background(), delegates.ExecuteInput{Source: source, Ref: "mutation/addProduct", Input: input, Context: bearer("secret")})
+	if out.Error != nil {
+		t.Fatalf("Execute: %+v", out.Error)
+	}
+	if got := out.Output.(map[string]any)["name"]; got != "Desk" {
+		t.Errorf("name = %v, want Desk", got)
+	}
+
+	out = Execute(context.Background(), delegates.ExecuteInput{Source: source, Ref: "mutation/addProduct", Input: input})
+	if out.Status != http.StatusUnauthorized {
+		t.Errorf("without credentials: status = %d, want 401", out.Status)
+	}
+
+	source.Extensions = nil
+	out = Execute(context.Background(), delegates.ExecuteInput{Source: source, Ref: "mutation/addProduct", Input: input})
+	if out.Error == nil || out.Error.Code != "no_endpoint" {
+		t.Errorf("without an endpoint: error = %+v, want no_endpoint", out.Error)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	server := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	events, err := New().SubscribeOperation(ctx, delegates.ExecuteInput{
+		Source:  delegates.Source{Location: server.URL},
+		Ref:     "subscription/priceChanged",
+		Input:   map[string]any{"id": "p1"},
+		Context: bearer("secret"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prices []any
+	for ev := range events {
+		if ev.Error != nil {
+			t.Fatalf("event error: %+v", ev.Error)
+		}
+		prices = append(prices, ev.Data.(map[string]any)["price"])
+	}
+	if want := []any{9.5, float64(8)}; !reflect.DeepEqual(prices, want) {
+		t.Errorf("prices = %v, want %v", prices, want)
+	}
+
+	out := Execute(ctx, delegates.ExecuteInput{
+		Source: delegates.Source{Location: server.URL}, Ref: "subscription/priceChanged", Context: bearer("secret"),
+	})
+	if out.Error != nil || out.Output.(map[string]any)["price"] != 9.5 {
+		t.Errorf("Execute on a subscription = %v, %+v; want the first event", out.Output, out.Error)
+	}
+
+	if _, err := Subscribe(ctx, delegates.ExecuteInput{Source: delegates.Source{Location: server.URL}, Ref: "query/product"}); err == nil {
+		t.Error("expected an error subscribing to a query")
+	}
+}
+
+func TestDiscoverSource(t *testing.T) {
+	server := newTestServer(t)
+	content, iface, err := New().DiscoverSource(context.Background(), delegates.Source{Format: FormatToken, Location: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), `"__schema"`) {
+		t.Errorf("content should be the introspection result, got %.80s", content)
+	}
+	if _, ok := iface.Operations["priceChanged"]; !ok || len(iface.Operations) != 7 {
+		t.Errorf("operations = %d", len(iface.Operations))
+	}
+
+	again, _, err := New().DiscoverSource(context.Background(), delegates.Source{Format: FormatToken, Location: server.URL})
+	if err != nil || string(again) != string(content) {
+		t.Error("discovery content should be stable between runs")
+	}
+}
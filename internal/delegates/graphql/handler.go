@@ -0,0 +1,124 @@
+// Package graphql implements the GraphQL binding format handler delegate.
+//
+// The GraphQL handler reads a schema from an SDL file or by introspecting a
+// live endpoint, and converts the fields of its root types to OpenBindings
+// operations: queries and mutations are methods, subscriptions are events.
+// Bindings refer to a root field as "<operation type>/<field>":
+//
+//	"sources": {"shop": {"format": "graphql", "location": "https://shop.example.com/graphql"}}
+//	"bindings": {"product.shop": {"operation": "product", "source": "shop", "ref": "query/product"}}
+//
+// Queries and mutations are sent with an HTTP POST; subscriptions use the
+// graphql-transport-ws WebSocket protocol. The operation's input holds the
+// field's arguments, which are sent as variables, and the field is queried
+// with a default selection set of bounded depth. A ref may carry its own
+// selection set instead, e.g. "query/product { id name }".
+//
+// When the location is an SDL file, the endpoint comes from the context's
+// "endpoint" metadata or the OBI source's x-graphql extension:
+//
+//	"sources": {"shop": {"format": "graphql", "location": "./shop.graphql", "x-graphql": {"endpoint": "https://shop.example.com/graphql"}}}
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/openbindings/cli/internal/delegates"
+	"github.com/openbindings/openbindings-go"
+	"github.com/openbindings/openbindings-go/canonicaljson"
+)
+
+// FormatToken is the format identifier for GraphQL sources.
+const FormatToken = "graphql"
+
+// DefaultTimeout is the maximum time to wait for GraphQL requests.
+const DefaultTimeout = 30 * time.Second
+
+// Handler implements the GraphQL binding format handler delegate.
+type Handler struct{}
+
+// New creates a new GraphQL handler.
+func New() *Handler {
+	return &Handler{}
+}
+
+// GetInfo returns identity and metadata about this delegate.
+func (h *Handler) GetInfo() delegates.SoftwareInfo {
+	return delegates.SoftwareInfo{
+		Name:        "GraphQL",
+		Description: "GraphQL APIs from SDL files or endpoint introspection",
+	}
+}
+
+// ListFormats returns the binding formats this delegate supports.
+func (h *Handler) ListFormats() []delegates.FormatInfo {
+	return []delegates.FormatInfo{
+		{
+			Token:       FormatToken,
+			Description: "GraphQL schemas via SDL files or introspection (queries, mutations and subscriptions)",
+		},
+	}
+}
+
+// CreateInterface loads a GraphQL schema and converts it to an OpenBindings
+// interface.
+func (h *Handler) CreateInterface(source delegates.Source) (openbindings.Interface, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+
+	_, iface, err := h.discoverAndConvert(ctx, source)
+	return iface, err
+}
+
+// ExecuteOperation executes a GraphQL operation.
+func (h *Handler) ExecuteOperation(ctx context.Context, input delegates.ExecuteInput) delegates.ExecuteOutput {
+	ctx, cancel := delegates.WithDefaultTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	return Execute(ctx, input)
+}
+
+// SubscribeOperation implements the delegates.StreamHandler interface for
+// subscriptions.
+func (h *Handler) SubscribeOperation(ctx context.Context, input delegates.ExecuteInput) (<-chan delegates.StreamEvent, error) {
+	return Subscribe(ctx, input)
+}
+
+// DiscoverSource implements the delegates.SourceDiscoverer interface. The
+// content is the SDL document, or the introspection result as canonical
+// JSON.
+func (h *Handler) DiscoverSource(ctx context.Context, source delegates.Source) ([]byte, openbindings.Interface, error) {
+	return h.discoverAndConvert(ctx, source)
+}
+
+func (h *Handler) discoverAndConvert(ctx context.Context, source delegates.Source) ([]byte, openbindings.Interface, error) {
+	schema, content, err := loadSchema(ctx, source, nil)
+	if err != nil {
+		return nil, openbindings.Interface{}, fmt.Errorf("GraphQL discovery: %w", err)
+	}
+
+	iface, err := ConvertToInterface(schema, source.Location)
+	if err != nil {
+		return nil, openbindings.Interface{}, fmt.Errorf("GraphQL convert: %w", err)
+	}
+
+	if delegates.MaybeJSON(string(content)) {
+		var v any
+		if err := json.Unmarshal(content, &v); err != nil {
+			return nil, openbindings.Interface{}, fmt.Errorf("GraphQL serialize discovery: %w", err)
+		}
+		if content, err = canonicaljson.Marshal(v); err != nil {
+			return nil, openbindings.Interface{}, fmt.Errorf("GraphQL serialize discovery: %w", err)
+		}
+	}
+
+	return content, iface, nil
+}
+
+// Register registers the GraphQL handler with a registry.
+func Register(r *delegates.Registry) {
+	r.Register(New())
+}
@@ -0,0 +1,415 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openbindings/cli/internal/delegates"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// sdlExts are the file extensions recognized as GraphQL SDL documents.
+var sdlExts = []string{".graphql", ".graphqls", ".gql", ".sdl"}
+
+// sourceExtensionKey is the OBI source extension holding GraphQL source
+// settings, e.g. "x-graphql": {"endpoint": "https://api.example.com/graphql"}.
+const sourceExtensionKey = "x-graphql"
+
+// IsSDLSource reports whether location names an SDL document (a local file
+// or a URL) rather than a GraphQL endpoint to introspect.
+func IsSDLSource(location string) bool {
+	if delegates.IsHTTPURL(location) {
+		if i := strings.IndexAny(location, "?#"); i >= 0 {
+			location = location[:i]
+		}
+	}
+	return slices.Contains(sdlExts, strings.ToLower(filepath.Ext(location)))
+}
+
+// endpointFor returns the URL that operations are sent to: the source
+// location itself when it is an endpoint, otherwise the context's
+// "endpoint" metadata or the OBI source's x-graphql extension.
+func endpointFor(input delegates.ExecuteInput) (string, error) {
+	if input.Context != nil {
+		if ep, ok := input.Context.Metadata["endpoint"].(string); ok && ep != "" {
+			return ep, nil
+		}
+	}
+	if raw, ok := input.Source.Extensions[sourceExtensionKey]; ok {
+		var ext struct {
+			Endpoint string `json:"endpoint"`
+		}
+		if err := json.Unmarshal(raw, &ext); err != nil {
+			return "", fmt.Errorf("parse %s: %w", sourceExtensionKey, err)
+		}
+		if ext.Endpoint != "" {
+			return ext.Endpoint, nil
+		}
+	}
+	if delegates.IsHTTPURL(input.Source.Location) && !IsSDLSource(input.Source.Location) {
+		return input.Source.Location, nil
+	}
+	return "", fmt.Errorf("GraphQL source %q has no endpoint; set the \"endpoint\" context metadata or the source's %s.endpoint", input.Source.Location, sourceExtensionKey)
+}
+
+// loadSchema reads a source's schema from its inline content, an SDL
+// document, or the introspection of its endpoint. It also returns the
+// content the schema was read from (SDL, or the introspection result as
+// JSON).
+func loadSchema(ctx context.Context, source delegates.Source, bindCtx *delegates.BindingContext) (*ast.Schema, []byte, error) {
+	var data []byte
+	var err error
+	switch {
+	case source.Content != nil:
+		data, err = delegates.ContentToBytes(source.Content)
+	case source.Location == "":
+		err = fmt.Errorf("source must have location or content")
+	case IsSDLSource(source.Location):
+		data, err = readSDL(ctx, source.Location, bindCtx)
+	case delegates.IsHTTPURL(source.Location):
+		data, err = introspect(ctx, source.Location, bindCtx)
+	default:
+		err = fmt.Errorf("GraphQL source %q is neither an SDL file (%s) nor an HTTP endpoint", source.Location, strings.Join(sdlExts, ", "))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	schema, err := parseSchema(data, source.Location)
+	if err != nil {
+		return nil, nil, err
+	}
+	return schema, data, nil
+}
+
+// introspectedSchemas caches the schemas of introspected endpoints, so that
+// executing several operations against an endpoint introspects it once.
+var introspectedSchemas sync.Map // location -> *ast.Schema
+
+// executionSchema returns the schema operations of the source are built
+// against, reusing an earlier introspection of the same endpoint.
+func executionSchema(ctx context.Context, source delegates.Source, bindCtx *delegates.BindingContext) (*ast.Schema, error) {
+	cacheable := source.Content == nil && !IsSDLSource(source.Location)
+	if cacheable {
+		if s, ok := introspectedSchemas.Load(source.Location); ok {
+			return s.(*ast.Schema), nil
+		}
+	}
+	schema, _, err := loadSchema(ctx, source, bindCtx)
+	if err != nil {
+		return nil, err
+	}
+	if cacheable {
+		introspectedSchemas.Store(source.Location, schema)
+	}
+	return schema, nil
+}
+
+func readSDL(ctx context.Context, location string, bindCtx *delegates.BindingContext) ([]byte, error) {
+	if !delegates.IsHTTPURL(location) {
+		return os.ReadFile(location)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %q: %w", location, err)
+	}
+	client, err := delegates.HTTPClient(bindCtx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %q: %w", location, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("fetch %q: HTTP %d", location, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// parseSchema parses SDL or an introspection result (with or without its
+// "data" envelope) into a validated schema.
+func parseSchema(data []byte, name string) (*ast.Schema, error) {
+	sdl := string(data)
+	if delegates.MaybeJSON(sdl) {
+		var result introspectionResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("parse introspection result: %w", err)
+		}
+		s := result.Schema
+		if s == nil && result.Data != nil {
+			s = result.Data.Schema
+		}
+		if s == nil {
+			return nil, fmt.Errorf("introspection result has no __schema")
+		}
+		sdl = s.sdl()
+	}
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: name, Input: sdl})
+	if err != nil {
+		return nil, fmt.Errorf("parse GraphQL schema: %w", err)
+	}
+	return schema, nil
+}
+
+// introspect runs the introspection query against an endpoint and returns
+// the result's "data" as JSON.
+func introspect(ctx context.Context, endpoint string, bindCtx *delegates.BindingContext) ([]byte, error) {
+	resp, err := post(ctx, endpoint, bindCtx, request{Query: introspectionQuery, OperationName: "IntrospectionQuery"})
+	if err != nil {
+		return nil, fmt.Errorf("introspect %q: %w", endpoint, err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("introspect %q: %s", endpoint, errorMessage(resp.Errors))
+	}
+	if resp.http.StatusCode >= 400 {
+		return nil, fmt.Errorf("introspect %q: HTTP %d", endpoint, resp.http.StatusCode)
+	}
+	if len(resp.Data) == 0 || string(resp.Data) == "null" {
+		return nil, fmt.Errorf("introspect %q: empty response", endpoint)
+	}
+	return resp.Data, nil
+}
+
+const introspectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } }
+}`
+
+// introspectionResult is the result of the introspection query.
+type introspectionResult struct {
+	Schema *introspectionSchema `json:"__schema"`
+	Data   *struct {
+		Schema *introspectionSchema `json:"__schema"`
+	} `json:"data"`
+}
+
+type introspectionSchema struct {
+	QueryType        *typeRef            `json:"queryType"`
+	MutationType     *typeRef            `json:"mutationType"`
+	SubscriptionType *typeRef            `json:"subscriptionType"`
+	Types            []introspectionType `json:"types"`
+}
+
+type introspectionType struct {
+	Kind          string               `json:"kind"`
+	Name          string               `json:"name"`
+	Description   string               `json:"description"`
+	Fields        []introspectionField `json:"fields"`
+	InputFields   []inputValue         `json:"inputFields"`
+	Interfaces    []typeRef            `json:"interfaces"`
+	EnumValues    []enumValue          `json:"enumValues"`
+	PossibleTypes []typeRef            `json:"possibleTypes"`
+}
+
+type introspectionField struct {
+	Name              string       `json:"name"`
+	Description       string       `json:"description"`
+	Args              []inputValue `json:"args"`
+	Type              typeRef      `json:"type"`
+	IsDeprecated      bool         `json:"isDeprecated"`
+	DeprecationReason *string      `json:"deprecationReason"`
+}
+
+type inputValue struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Type         typeRef `json:"type"`
+	DefaultValue *string `json:"defaultValue"`
+}
+
+type enumValue struct {
+	Name              string  `json:"name"`
+	Description       string  `json:"description"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+type typeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *typeRef `json:"ofType"`
+}
+
+// String returns the type in SDL notation, e.g. "[String!]!".
+func (t typeRef) String() string {
+	switch t.Kind {
+	case "NON_NULL":
+		if t.OfType != nil {
+			return t.OfType.String() + "!"
+		}
+	case "LIST":
+		if t.OfType != nil {
+			return "[" + t.OfType.String() + "]"
+		}
+	}
+	return t.Name
+}
+
+// builtinScalars are declared by the schema prelude.
+var builtinScalars = []string{"String", "Int", "Float", "Boolean", "ID"}
+
+// sdl renders the introspected schema as SDL. Introspection types and
+// built-in scalars are left to the prelude; directives other than
+// @deprecated are not part of introspection and are dropped.
+func (s *introspectionSchema) sdl() string {
+	var b strings.Builder
+	b.WriteString("schema {\n")
+	for _, root := range []struct {
+		op  string
+		ref *typeRef
+	}{{"query", s.QueryType}, {"mutation", s.MutationType}, {"subscription", s.SubscriptionType}} {
+		if root.ref != nil && root.ref.Name != "" {
+			fmt.Fprintf(&b, "  %s: %s\n", root.op, root.ref.Name)
+		}
+	}
+	b.WriteString("}\n")
+
+	types := slices.Clone(s.Types)
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	for _, t := range types {
+		if strings.HasPrefix(t.Name, "__") || slices.Contains(builtinScalars, t.Name) {
+			continue
+		}
+		b.WriteString("\n")
+		writeDescription(&b, "", t.Description)
+		switch t.Kind {
+		case "SCALAR":
+			fmt.Fprintf(&b, "scalar %s\n", t.Name)
+		case "OBJECT", "INTERFACE":
+			keyword := "type"
+			if t.Kind == "INTERFACE" {
+				keyword = "interface"
+			}
+			fmt.Fprintf(&b, "%s %s", keyword, t.Name)
+			for i, iface := range t.Interfaces {
+				if i == 0 {
+					b.WriteString(" implements ")
+				} else {
+					b.WriteString(" & ")
+				}
+				b.WriteString(iface.Name)
+			}
+			b.WriteString(" {\n")
+			for _, f := range t.Fields {
+				writeDescription(&b, "  ", f.Description)
+				fmt.Fprintf(&b, "  %s", f.Name)
+				if len(f.Args) > 0 {
+					b.WriteString("(")
+					for i, arg := range f.Args {
+						if i > 0 {
+							b.WriteString(", ")
+						}
+						writeInputValue(&b, arg)
+					}
+					b.WriteString(")")
+				}
+				fmt.Fprintf(&b, ": %s%s\n", f.Type, deprecation(f.IsDeprecated, f.DeprecationReason))
+			}
+			b.WriteString("}\n")
+		case "UNION":
+			names := make([]string, len(t.PossibleTypes))
+			for i, pt := range t.PossibleTypes {
+				names[i] = pt.Name
+			}
+			fmt.Fprintf(&b, "union %s = %s\n", t.Name, strings.Join(names, " | "))
+		case "ENUM":
+			fmt.Fprintf(&b, "enum %s {\n", t.Name)
+			for _, v := range t.EnumValues {
+				writeDescription(&b, "  ", v.Description)
+				fmt.Fprintf(&b, "  %s%s\n", v.Name, deprecation(v.IsDeprecated, v.DeprecationReason))
+			}
+			b.WriteString("}\n")
+		case "INPUT_OBJECT":
+			fmt.Fprintf(&b, "input %s {\n", t.Name)
+			for _, f := range t.InputFields {
+				writeDescription(&b, "  ", f.Description)
+				b.WriteString("  ")
+				writeInputValue(&b, f)
+				b.WriteString("\n")
+			}
+			b.WriteString("}\n")
+		}
+	}
+	return b.String()
+}
+
+func writeInputValue(b *strings.Builder, v inputValue) {
+	fmt.Fprintf(b, "%s: %s", v.Name, v.Type)
+	if v.DefaultValue != nil {
+		fmt.Fprintf(b, " = %s", *v.DefaultValue)
+	}
+}
+
+func writeDescription(b *strings.Builder, indent, description string) {
+	if description != "" {
+		fmt.Fprintf(b, "%s%s\n", indent, quote(description))
+	}
+}
+
+func deprecation(deprecated bool, reason *string) string {
+	if !deprecated {
+		return ""
+	}
+	if reason == nil || *reason == "" {
+		return " @deprecated"
+	}
+	return " @deprecated(reason: " + quote(*reason) + ")"
+}
+
+// quote returns s as a GraphQL string literal. JSON string escapes are a
+// subset of GraphQL's.
+func quote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,155 @@
+package graphql
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// maxSelectionDepth bounds how many levels of object fields a default
+// selection set descends into, which keeps queries over cyclic or very
+// wide schemas small.
+const maxSelectionDepth = 2
+
+// selection is a selection set and the JSON Schema of the data it selects.
+type selection struct {
+	text   string // e.g. "{ id name }"; empty for scalar and enum fields
+	schema map[string]any
+}
+
+// selectionFor returns the default selection set of a field: every scalar
+// and enum field of its type, and of the object fields within
+// maxSelectionDepth levels. Fields that take required arguments are left
+// out. Interfaces and unions also select __typename, and unions select the
+// fields of each member type, except fields that members declare with
+// different types, which could not be merged into one response field.
+func selectionFor(schema *ast.Schema, field *ast.FieldDefinition) selection {
+	sel, _ := selector{schema}.typ(field.Type, 1)
+	return sel
+}
+
+type selector struct {
+	schema *ast.Schema
+}
+
+// typ returns the selection of a field of type t, or false when t is a
+// composite type below maxSelectionDepth.
+func (s selector) typ(t *ast.Type, depth int) (selection, bool) {
+	if t.Elem != nil {
+		items, ok := s.typ(t.Elem, depth)
+		return selection{
+			text:   items.text,
+			schema: map[string]any{"type": "array", "items": items.schema},
+		}, ok
+	}
+	def := s.schema.Types[t.NamedType]
+	if def == nil {
+		return selection{schema: map[string]any{}}, true
+	}
+	if def.IsLeafType() {
+		return selection{schema: leafSchema(def)}, true
+	}
+	if depth > maxSelectionDepth {
+		return selection{}, false
+	}
+	return s.composite(def, depth, nil), true
+}
+
+// composite returns the selection of an object, interface or union type,
+// leaving out the fields named in skip.
+func (s selector) composite(def *ast.Definition, depth int, skip map[string]bool) selection {
+	var parts []string
+	properties := map[string]any{}
+	var required []any
+
+	if def.IsAbstractType() {
+		parts = append(parts, "__typename")
+		properties["__typename"] = map[string]any{"type": "string"}
+		required = append(required, "__typename")
+	}
+
+	for _, f := range def.Fields {
+		if strings.HasPrefix(f.Name, "__") || hasRequiredArguments(f) || skip[f.Name] {
+			continue
+		}
+		sel, ok := s.typ(f.Type, depth+1)
+		if !ok {
+			continue
+		}
+		parts = append(parts, strings.TrimSpace(f.Name+" "+sel.text))
+		if f.Description != "" {
+			sel.schema["description"] = f.Description
+		}
+		properties[f.Name] = sel.schema
+		if f.Type.NonNull {
+			required = append(required, f.Name)
+		}
+	}
+
+	var variants []any
+	if def.Kind == ast.Union {
+		members := slices.Clone(s.schema.GetPossibleTypes(def))
+		sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+		conflicts := conflictingFields(members)
+		for _, member := range members {
+			sel := s.composite(member, depth, conflicts)
+			sel.schema["properties"].(map[string]any)["__typename"] = map[string]any{"const": member.Name}
+			parts = append(parts, "... on "+member.Name+" "+sel.text)
+			variants = append(variants, sel.schema)
+		}
+	}
+
+	// A selection set cannot be empty; this happens when every field of
+	// the type is an object field beyond the depth limit.
+	if len(parts) == 0 {
+		parts = append(parts, "__typename")
+		properties["__typename"] = map[string]any{"type": "string"}
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if def.Description != "" {
+		schema["description"] = def.Description
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if len(variants) > 0 {
+		schema["anyOf"] = variants
+	}
+	return selection{text: "{ " + strings.Join(parts, " ") + " }", schema: schema}
+}
+
+// conflictingFields returns the names of the fields that union members
+// declare with different types (e.g. ID! and ID). GraphQL rejects a query
+// selecting such a field in fragments on several members unless the fields
+// are aliased, so default selections leave them out.
+func conflictingFields(members []*ast.Definition) map[string]bool {
+	types := map[string]string{}
+	conflicts := map[string]bool{}
+	for _, member := range members {
+		for _, f := range member.Fields {
+			t := f.Type.String()
+			if prev, ok := types[f.Name]; ok && prev != t {
+				conflicts[f.Name] = true
+			}
+			types[f.Name] = t
+		}
+	}
+	return conflicts
+}
+
+// hasRequiredArguments reports whether a field takes a non-null argument
+// without a default, which a default selection has no value for.
+func hasRequiredArguments(f *ast.FieldDefinition) bool {
+	for _, arg := range f.Arguments {
+		if arg.Type.NonNull && arg.DefaultValue == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/coder/websocket"
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// subprotocol is the GraphQL over WebSocket protocol subscriptions use.
+const subprotocol = "graphql-transport-ws"
+
+// subscriptionID identifies the one subscription of a connection.
+const subscriptionID = "1"
+
+// maxWSMessageSize bounds the size of a received WebSocket message.
+const maxWSMessageSize = 16 << 20
+
+// message is a graphql-transport-ws protocol message.
+type message struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Subscribe starts a subscription and streams the field's data from each
+// result until ctx is done or the server completes it.
+func Subscribe(ctx context.Context, input delegates.ExecuteInput) (<-chan delegates.StreamEvent, error) {
+	op, opErr := prepare(ctx, input)
+	if opErr != nil {
+		return nil, fmt.Errorf("%s", opErr.Message)
+	}
+	if op.opType != opSubscription {
+		return nil, fmt.Errorf("ref %q is not a subscription", input.Ref)
+	}
+	return subscribe(ctx, op, input.Context)
+}
+
+// subscribe runs a subscription over a graphql-transport-ws connection to
+// the endpoint (its http(s) scheme replaced by ws(s)). The context's
+// credentials and headers are sent in the handshake and as the
+// connection_init payload, where servers commonly read them.
+func subscribe(ctx context.Context, op *operation, bindCtx *delegates.BindingContext) (<-chan delegates.StreamEvent, error) {
+	dialCtx, cancel := delegates.WithDefaultTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	header := http.Header{}
+	delegates.ApplyHTTPContext(&http.Request{Header: header}, bindCtx)
+	client, err := delegates.HTTPClient(bindCtx)
+	if err != nil {
+		return nil, err
+	}
+	conn, _, err := websocket.Dial(dialCtx, wsURL(op.endpoint), &websocket.DialOptions{
+		HTTPClient:   client,
+		HTTPHeader:   header,
+		Subprotocols: []string{subprotocol},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("WebSocket connect: %w", err)
+	}
+	conn.SetReadLimit(maxWSMessageSize)
+	if conn.Subprotocol() != subprotocol {
+		conn.CloseNow()
+		return nil, fmt.Errorf("server does not support the %s protocol", subprotocol)
+	}
+	if err := startSubscription(dialCtx, conn, header, op.req); err != nil {
+		conn.CloseNow()
+		return nil, err
+	}
+
+	ch := make(chan delegates.StreamEvent)
+	go func() {
+		defer conn.CloseNow()
+		defer close(ch)
+
+		send := func(ev delegates.StreamEvent) bool {
+			select {
+			case ch <- ev:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		for {
+			msg, err := readMessage(ctx, conn)
+			if err != nil {
+				if ctx.Err() == nil && websocket.CloseStatus(err) != websocket.StatusNormalClosure {
+					send(delegates.StreamEvent{Error: &delegates.Error{Code: "stream_error", Message: err.Error()}})
+				}
+				return
+			}
+			switch msg.Type {
+			case "ping":
+				if err := writeMessage(ctx, conn, message{Type: "pong"}); err != nil {
+					return
+				}
+			case "next":
+				var resp response
+				if err := json.Unmarshal(msg.Payload, &resp); err != nil {
+					send(delegates.StreamEvent{Error: &delegates.Error{Code: "invalid_message", Message: err.Error()}})
+					return
+				}
+				ev := delegates.StreamEvent{Data: resp.fieldData(op.field)}
+				if len(resp.Errors) > 0 {
+					ev.Error = &delegates.Error{Code: "graphql_error", Message: errorMessage(resp.Errors), Details: map[string]any{"errors": resp.Errors}}
+				}
+				if !send(ev) {
+					return
+				}
+			case "error":
+				var errs []map[string]any
+				_ = json.Unmarshal(msg.Payload, &errs)
+				send(delegates.StreamEvent{Error: &delegates.Error{Code: "graphql_error", Message: errorMessage(errs), Details: map[string]any{"errors": errs}}})
+				return
+			case "complete":
+				conn.Close(websocket.StatusNormalClosure, "")
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// startSubscription initializes the connection and subscribes.
+func startSubscription(ctx context.Context, conn *websocket.Conn, header http.Header, req request) error {
+	init := message{Type: "connection_init"}
+	if len(header) > 0 {
+		params := make(map[string]string, len(header))
+		for name := range header {
+			params[name] = header.Get(name)
+		}
+		init.Payload, _ = json.Marshal(params)
+	}
+	if err := writeMessage(ctx, conn, init); err != nil {
+		return err
+	}
+	for acked := false; !acked; {
+		msg, err := readMessage(ctx, conn)
+		if err != nil {
+			return fmt.Errorf("connection_init: %w", err)
+		}
+		switch msg.Type {
+		case "connection_ack":
+			acked = true
+		case "ping":
+			if err := writeMessage(ctx, conn, message{Type: "pong"}); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("connection_init: unexpected %q message", msg.Type)
+		}
+	}
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	return writeMessage(ctx, conn, message{ID: subscriptionID, Type: "subscribe", Payload: payload})
+}
+
+func readMessage(ctx context.Context, conn *websocket.Conn) (message, error) {
+	var msg message
+	_, data, err := conn.Read(ctx)
+	if err != nil {
+		return msg, err
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return msg, fmt.Errorf("invalid %s message: %w", subprotocol, err)
+	}
+	return msg, nil
+}
+
+func writeMessage(ctx context.Context, conn *websocket.Conn, msg message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return conn.Write(ctx, websocket.MessageText, data)
+}
+
+// wsURL returns the WebSocket URL of an HTTP endpoint.
+func wsURL(endpoint string) string {
+	switch {
+	case strings.HasPrefix(endpoint, "https://"):
+		return "wss://" + strings.TrimPrefix(endpoint, "https://")
+	case strings.HasPrefix(endpoint, "http://"):
+		return "ws://" + strings.TrimPrefix(endpoint, "http://")
+	}
+	return endpoint
+}
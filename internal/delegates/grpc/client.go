@@ -2,12 +2,12 @@ package grpc
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"strings"
 
 	"github.com/jhump/protoreflect/desc"
 	"github.com/jhump/protoreflect/grpcreflect"
+	"github.com/openbindings/cli/internal/delegates"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
@@ -47,7 +47,7 @@ func (d *Discovery) ToCanonical() map[string]any {
 // services and their method descriptors. Standard gRPC infrastructure
 // services (grpc.reflection, grpc.health) are excluded.
 func Discover(ctx context.Context, address string) (*Discovery, error) {
-	conn, err := dial(ctx, address)
+	conn, err := dial(ctx, address, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -77,18 +77,39 @@ func Discover(ctx context.Context, address string) (*Discovery, error) {
 }
 
 // dial creates a gRPC client connection with appropriate transport credentials.
-// Addresses ending in :443 or containing "://" with https use TLS; otherwise plaintext.
-func dial(ctx context.Context, address string) (*grpc.ClientConn, error) {
+// Addresses ending in :443 or using https://, and contexts with TLS settings
+// (CA file, client certificate, etc.), use TLS; otherwise plaintext. A proxy
+// configured in the context is reached with HTTP CONNECT, and resolves the
+// server's name itself.
+func dial(ctx context.Context, address string, bindCtx *delegates.BindingContext) (*grpc.ClientConn, error) {
 	var opts []grpc.DialOption
 
-	if needsTLS(address) {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
+	if needsTLS(address) || (bindCtx != nil && bindCtx.Transport.HasTLS()) {
+		tlsCfg, err := delegates.TLSConfig(bindCtx)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
 	} else {
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
 
+	proxyDial, err := delegates.ProxyDialer(bindCtx)
+	if err != nil {
+		return nil, err
+	}
+	if proxyDial != nil {
+		opts = append(opts, grpc.WithNoProxy(), grpc.WithContextDialer(proxyDial))
+	}
+
 	target := address
-	if !strings.Contains(address, "://") {
+	switch {
+	case strings.Contains(address, "://"):
+	case proxyDial != nil:
+		// The proxy may be the only way to resolve the name, so it is
+		// sent the address as is rather than an IP resolved here.
+		target = "passthrough:///" + address
+	default:
 		target = "dns:///" + address
 	}
 
@@ -1,7 +1,9 @@
 package grpc
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/jhump/protoreflect/desc"
 	"github.com/openbindings/cli/internal/delegates"
@@ -31,13 +33,10 @@ func ConvertToInterface(disc *Discovery, sourceLocation string) (openbindings.In
 	}
 
 	usedKeys := map[string]string{}
+	schemas := newSchemaBuilder()
 
 	for _, svc := range disc.Services {
 		for _, method := range svc.GetMethods() {
-			if method.IsClientStreaming() {
-				continue
-			}
-
 			fqn := svc.GetFullyQualifiedName() + "/" + method.GetName()
 			opKey := delegates.SanitizeKey(method.GetName())
 			opKey = resolveKeyCollision(opKey, svc.GetName(), usedKeys)
@@ -55,15 +54,30 @@ func ConvertToInterface(disc *Discovery, sourceLocation string) (openbindings.In
 
 			inputType := method.GetInputType()
 			if inputType != nil {
-				op.Input = messageToJSONSchema(inputType)
+				op.Input = schemas.message(inputType)
+			}
+
+			// Client-streaming and bidirectional methods take the request
+			// messages as an array, or one at a time via InputStreamHandler.
+			if method.IsClientStreaming() {
+				if op.Input != nil {
+					op.Input = map[string]any{"type": "array", "items": op.Input}
+				}
+				streaming := delegates.StreamingClient
+				if method.IsServerStreaming() {
+					streaming = delegates.StreamingBidi
+				}
+				op.Extensions = map[string]json.RawMessage{
+					delegates.StreamingKey: json.RawMessage(strconv.Quote(streaming)),
+				}
 			}
 
 			outputType := method.GetOutputType()
 			if outputType != nil {
 				if method.IsServerStreaming() {
-					op.Payload = messageToJSONSchema(outputType)
+					op.Payload = schemas.message(outputType)
 				} else {
-					op.Output = messageToJSONSchema(outputType)
+					op.Output = schemas.message(outputType)
 				}
 			}
 
@@ -78,6 +92,10 @@ func ConvertToInterface(disc *Discovery, sourceLocation string) (openbindings.In
 		}
 	}
 
+	if len(schemas.schemas) > 0 {
+		iface.Schemas = schemas.schemas
+	}
+
 	if len(disc.Services) > 0 {
 		svc := disc.Services[0]
 		iface.Name = svc.GetName()
@@ -115,8 +133,48 @@ func trimComment(s string) string {
 	return s
 }
 
-// messageToJSONSchema converts a protobuf message descriptor to JSON Schema.
-func messageToJSONSchema(msg *desc.MessageDescriptor) map[string]any {
+// schemaBuilder converts protobuf message descriptors to JSON Schema
+// following the proto3 JSON mapping as jsonpb implements it:
+//
+//   - properties are named by the original field name, which jsonpb accepts
+//     on input and emits on output (see responseToJSON);
+//   - 64-bit integers are decimal strings and enums are value names;
+//   - map keys are strings, repeated fields are arrays;
+//   - fields of a oneof are mutually exclusive;
+//   - well-known types (Timestamp, Duration, Struct, Any, wrappers, ...)
+//     use their special JSON forms.
+//
+// Messages that contain themselves are emitted once into schemas and
+// referenced with "$ref": "#/schemas/<full name>".
+type schemaBuilder struct {
+	schemas   map[string]openbindings.JSONSchema
+	recursive map[string]bool // memoized isRecursive results by full name
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{
+		schemas:   map[string]openbindings.JSONSchema{},
+		recursive: map[string]bool{},
+	}
+}
+
+// message returns the schema for a message, or a $ref for recursive ones.
+func (b *schemaBuilder) message(msg *desc.MessageDescriptor) map[string]any {
+	name := msg.GetFullyQualifiedName()
+	if s, ok := wellKnownSchema(name); ok {
+		return s
+	}
+	if !b.isRecursive(msg) {
+		return b.object(msg)
+	}
+	if _, ok := b.schemas[name]; !ok {
+		b.schemas[name] = nil // placeholder: stops the recursion below
+		b.schemas[name] = b.object(msg)
+	}
+	return map[string]any{"$ref": "#/schemas/" + name}
+}
+
+func (b *schemaBuilder) object(msg *desc.MessageDescriptor) map[string]any {
 	schema := map[string]any{
 		"type": "object",
 	}
@@ -128,53 +186,68 @@ func messageToJSONSchema(msg *desc.MessageDescriptor) map[string]any {
 
 	properties := map[string]any{}
 	for _, field := range fields {
-		properties[field.GetJSONName()] = fieldToSchema(field)
+		properties[field.GetName()] = b.field(field)
 	}
 	schema["properties"] = properties
 
+	var exclusive []any
+	for _, oneof := range msg.GetOneOfs() {
+		if !oneof.IsSynthetic() { // proto3 optional fields are not a real oneof
+			exclusive = append(exclusive, oneofSchema(oneof))
+		}
+	}
+	if len(exclusive) > 0 {
+		schema["allOf"] = exclusive
+	}
+
 	return schema
 }
 
-func fieldToSchema(field *desc.FieldDescriptor) map[string]any {
+func (b *schemaBuilder) field(field *desc.FieldDescriptor) map[string]any {
 	if field.IsMap() {
-		valField := field.GetMapValueType()
-		return map[string]any{
+		schema := map[string]any{
 			"type":                 "object",
-			"additionalProperties": scalarOrMessageSchema(valField),
+			"additionalProperties": b.single(field.GetMapValueType()),
+		}
+		if keys := mapKeySchema(field.GetMapKeyType()); keys != nil {
+			schema["propertyNames"] = keys
 		}
+		return schema
 	}
 
-	s := scalarOrMessageSchema(field)
-
-	if field.IsRepeated() && !field.IsMap() {
+	s := b.single(field)
+	if field.IsRepeated() {
 		return map[string]any{
 			"type":  "array",
 			"items": s,
 		}
 	}
-
 	return s
 }
 
-func scalarOrMessageSchema(field *desc.FieldDescriptor) map[string]any {
-	t := field.GetType()
-	switch t {
+// single returns the schema for one value of a field, ignoring repetition.
+func (b *schemaBuilder) single(field *desc.FieldDescriptor) map[string]any {
+	switch field.GetType() {
 	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
 		return map[string]any{"type": "boolean"}
 
 	case descriptorpb.FieldDescriptorProto_TYPE_INT32,
 		descriptorpb.FieldDescriptorProto_TYPE_SINT32,
-		descriptorpb.FieldDescriptorProto_TYPE_SFIXED32,
-		descriptorpb.FieldDescriptorProto_TYPE_UINT32,
-		descriptorpb.FieldDescriptorProto_TYPE_FIXED32:
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED32:
 		return map[string]any{"type": "integer"}
 
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED32:
+		return map[string]any{"type": "integer", "minimum": 0}
+
 	case descriptorpb.FieldDescriptorProto_TYPE_INT64,
 		descriptorpb.FieldDescriptorProto_TYPE_SINT64,
-		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64,
-		descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		return int64Schema(true)
+
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT64,
 		descriptorpb.FieldDescriptorProto_TYPE_FIXED64:
-		return map[string]any{"type": "string"}
+		return int64Schema(false)
 
 	case descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
 		descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:
@@ -184,24 +257,26 @@ func scalarOrMessageSchema(field *desc.FieldDescriptor) map[string]any {
 		return map[string]any{"type": "string"}
 
 	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
-		return map[string]any{"type": "string", "contentEncoding": "base64"}
+		return bytesSchema()
 
 	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
 		enumDesc := field.GetEnumType()
-		if enumDesc != nil {
-			var values []any
-			for _, v := range enumDesc.GetValues() {
-				values = append(values, v.GetName())
-			}
-			return map[string]any{"type": "string", "enum": values}
+		if enumDesc == nil {
+			return map[string]any{"type": "string"}
 		}
-		return map[string]any{"type": "string"}
+		if enumDesc.GetFullyQualifiedName() == "google.protobuf.NullValue" {
+			return map[string]any{"type": "null"}
+		}
+		var values []any
+		for _, v := range enumDesc.GetValues() {
+			values = append(values, v.GetName())
+		}
+		return map[string]any{"type": "string", "enum": values}
 
 	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
 		descriptorpb.FieldDescriptorProto_TYPE_GROUP:
-		msgDesc := field.GetMessageType()
-		if msgDesc != nil {
-			return messageToJSONSchema(msgDesc)
+		if msgDesc := field.GetMessageType(); msgDesc != nil {
+			return b.message(msgDesc)
 		}
 		return map[string]any{"type": "object"}
 
@@ -210,6 +285,128 @@ func scalarOrMessageSchema(field *desc.FieldDescriptor) map[string]any {
 	}
 }
 
+// isRecursive reports whether msg contains itself, directly or through
+// other messages.
+func (b *schemaBuilder) isRecursive(msg *desc.MessageDescriptor) bool {
+	name := msg.GetFullyQualifiedName()
+	if r, ok := b.recursive[name]; ok {
+		return r
+	}
+
+	seen := map[string]bool{}
+	var reaches func(m *desc.MessageDescriptor) bool
+	reaches = func(m *desc.MessageDescriptor) bool {
+		for _, field := range m.GetFields() {
+			t := field.GetMessageType() // map entry for map fields
+			if t == nil {
+				continue
+			}
+			n := t.GetFullyQualifiedName()
+			if n == name {
+				return true
+			}
+			if seen[n] {
+				continue
+			}
+			seen[n] = true
+			if reaches(t) {
+				return true
+			}
+		}
+		return false
+	}
+
+	r := reaches(msg)
+	b.recursive[name] = r
+	return r
+}
+
+// oneofSchema allows at most one field of a oneof to be present: exactly
+// one of "has field 1", ..., "has field n" and "has none of them" holds.
+func oneofSchema(oneof *desc.OneOfDescriptor) map[string]any {
+	var alternatives, present []any
+	for _, field := range oneof.GetChoices() {
+		alternatives = append(alternatives, map[string]any{"required": []any{field.GetName()}})
+		present = append(present, map[string]any{"required": []any{field.GetName()}})
+	}
+	alternatives = append(alternatives, map[string]any{"not": map[string]any{"anyOf": present}})
+	return map[string]any{"oneOf": alternatives}
+}
+
+// mapKeySchema constrains the (always string) JSON keys of a map field
+// whose proto key type is not string. Returns nil for string keys.
+func mapKeySchema(key *desc.FieldDescriptor) map[string]any {
+	switch key.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
+		return map[string]any{"enum": []any{"true", "false"}}
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
+		return nil
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED64:
+		return map[string]any{"pattern": "^[0-9]+$"}
+	default:
+		return map[string]any{"pattern": "^-?[0-9]+$"}
+	}
+}
+
+// int64Schema describes a 64-bit integer, which proto3 JSON writes as a
+// decimal string.
+func int64Schema(signed bool) map[string]any {
+	pattern := "^[0-9]+$"
+	if signed {
+		pattern = "^-?[0-9]+$"
+	}
+	return map[string]any{"type": "string", "pattern": pattern}
+}
+
+func bytesSchema() map[string]any {
+	return map[string]any{"type": "string", "contentEncoding": "base64"}
+}
+
+// wellKnownSchema returns the JSON form of a google.protobuf well-known
+// type, which differs from the generic message mapping.
+func wellKnownSchema(name string) (map[string]any, bool) {
+	switch name {
+	case "google.protobuf.Timestamp":
+		return map[string]any{"type": "string", "format": "date-time"}, true
+	case "google.protobuf.Duration":
+		return map[string]any{"type": "string", "pattern": `^-?[0-9]+(\.[0-9]{1,9})?s$`}, true
+	case "google.protobuf.FieldMask":
+		return map[string]any{"type": "string", "description": "Comma-separated field paths"}, true
+	case "google.protobuf.Struct":
+		return map[string]any{"type": "object"}, true
+	case "google.protobuf.ListValue":
+		return map[string]any{"type": "array"}, true
+	case "google.protobuf.Value":
+		return map[string]any{}, true
+	case "google.protobuf.Any":
+		return map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"@type": map[string]any{"type": "string"}},
+			"required":   []any{"@type"},
+		}, true
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue":
+		return map[string]any{"type": "number"}, true
+	case "google.protobuf.Int64Value":
+		return int64Schema(true), true
+	case "google.protobuf.UInt64Value":
+		return int64Schema(false), true
+	case "google.protobuf.Int32Value":
+		return map[string]any{"type": "integer"}, true
+	case "google.protobuf.UInt32Value":
+		return map[string]any{"type": "integer", "minimum": 0}, true
+	case "google.protobuf.BoolValue":
+		return map[string]any{"type": "boolean"}, true
+	case "google.protobuf.StringValue":
+		return map[string]any{"type": "string"}, true
+	case "google.protobuf.BytesValue":
+		return bytesSchema(), true
+	}
+	return nil, false
+}
+
 func resolveKeyCollision(key string, prefix string, used map[string]string) string {
 	if _, taken := used[key]; !taken {
 		return key
@@ -0,0 +1,126 @@
+package grpc
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/openbindings/openbindings-go"
+)
+
+const catalogProto = `
+syntax = "proto3";
+package catalog.v1;
+
+import "google/protobuf/duration.proto";
+import "google/protobuf/struct.proto";
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+
+service Catalog {
+  rpc Put(Item) returns (Category);
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_ACTIVE = 1;
+}
+
+message Item {
+  string item_id = 1;
+  int64 stock = 2;
+  uint64 views = 3;
+  uint32 rank = 4;
+  bytes image = 5;
+  Status status = 6;
+  repeated string tags = 7;
+  map<int32, string> labels = 8;
+  google.protobuf.Timestamp created_at = 9;
+  google.protobuf.Duration ttl = 10;
+  google.protobuf.Struct attrs = 11;
+  google.protobuf.Int64Value limit = 12;
+  google.protobuf.NullValue nothing = 13;
+  oneof price {
+    double amount = 14;
+    string free_text = 15;
+  }
+  optional string note = 16;
+}
+
+message Category {
+  string name = 1;
+  repeated Category children = 2;
+}
+`
+
+func convertCatalog(t *testing.T) openbindings.Interface {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "catalog.proto")
+	writeFile(t, path, catalogProto)
+	disc, err := DiscoverFiles(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iface, err := ConvertToInterface(disc, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return iface
+}
+
+func TestConvertProto3JSONMapping(t *testing.T) {
+	input := convertCatalog(t).Operations["Put"].Input
+	props, _ := input["properties"].(map[string]any)
+
+	tests := map[string]map[string]any{
+		"item_id":    {"type": "string"},
+		"stock":      {"type": "string", "pattern": "^-?[0-9]+$"},
+		"views":      {"type": "string", "pattern": "^[0-9]+$"},
+		"rank":       {"type": "integer", "minimum": 0},
+		"image":      {"type": "string", "contentEncoding": "base64"},
+		"status":     {"type": "string", "enum": []any{"STATUS_UNSPECIFIED", "STATUS_ACTIVE"}},
+		"tags":       {"type": "array", "items": map[string]any{"type": "string"}},
+		"labels":     {"type": "object", "additionalProperties": map[string]any{"type": "string"}, "propertyNames": map[string]any{"pattern": "^-?[0-9]+$"}},
+		"created_at": {"type": "string", "format": "date-time"},
+		"ttl":        {"type": "string", "pattern": `^-?[0-9]+(\.[0-9]{1,9})?s$`},
+		"attrs":      {"type": "object"},
+		"limit":      {"type": "string", "pattern": "^-?[0-9]+$"},
+		"nothing":    {"type": "null"},
+		"note":       {"type": "string"},
+	}
+	for name, want := range tests {
+		if got := props[name]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+
+	// Only the real oneof is exclusive; the synthetic one for "note" is not.
+	allOf, _ := input["allOf"].([]any)
+	if len(allOf) != 1 {
+		t.Fatalf("allOf = %v, want one oneof constraint", input["allOf"])
+	}
+	alternatives, _ := allOf[0].(map[string]any)["oneOf"].([]any)
+	if len(alternatives) != 3 {
+		t.Errorf("oneof alternatives = %v, want amount, free_text or neither", alternatives)
+	}
+}
+
+func TestConvertRecursiveMessageUsesRef(t *testing.T) {
+	iface := convertCatalog(t)
+
+	ref := map[string]any{"$ref": "#/schemas/catalog.v1.Category"}
+	if out := iface.Operations["Put"].Output; !reflect.DeepEqual(out, ref) {
+		t.Errorf("output = %v, want %v", out, ref)
+	}
+	category, ok := iface.Schemas["catalog.v1.Category"]
+	if !ok {
+		t.Fatalf("schemas = %v, want catalog.v1.Category", iface.Schemas)
+	}
+	children := category["properties"].(map[string]any)["children"]
+	if want := map[string]any{"type": "array", "items": ref}; !reflect.DeepEqual(children, want) {
+		t.Errorf("children = %v, want %v", children, want)
+	}
+	if _, ok := iface.Schemas["catalog.v1.Item"]; ok {
+		t.Error("non-recursive Item should be inlined, not pooled")
+	}
+}
@@ -0,0 +1,237 @@
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/openbindings/cli/internal/delegates"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// descriptorSetExts are the file extensions recognized as compiled
+// FileDescriptorSets (e.g., from protoc --descriptor_set_out or buf build).
+var descriptorSetExts = []string{".pb", ".binpb", ".protoset", ".desc"}
+
+// sourceExtensionKey is the OBI source extension holding gRPC source
+// settings, e.g. "x-grpc": {"address": "localhost:50051"}.
+const sourceExtensionKey = "x-grpc"
+
+// sourceExtension is the value of the x-grpc source extension.
+type sourceExtension struct {
+	Address     string   `json:"address"`     // Server address for descriptor sources
+	ImportPaths []string `json:"importPaths"` // .proto import paths, relative to the .proto file's directory
+}
+
+// importPathsKey is the context metadata key of .proto import paths: a list,
+// or a string of paths separated by the OS path list separator.
+const importPathsKey = "importPaths"
+
+// IsDescriptorSource reports whether location names a local .proto file or
+// compiled descriptor set rather than a server address. Such sources are
+// resolved without server reflection.
+func IsDescriptorSource(location string) bool {
+	ext := strings.ToLower(filepath.Ext(location))
+	return ext == ".proto" || slices.Contains(descriptorSetExts, ext)
+}
+
+// DiscoverFiles loads the services declared in a .proto file or descriptor
+// set. For a .proto file, only the services of that file are returned; its
+// imports supply message types and are resolved against importPaths before
+// the file's own directories (see parseProtoFile). For a descriptor set, the
+// services of every file in the set are returned.
+func DiscoverFiles(location string, importPaths []string) (*Discovery, error) {
+	services, err := loadServices(location, importPaths)
+	if err != nil {
+		return nil, err
+	}
+	var disc Discovery
+	for _, svc := range services {
+		if !isInfraService(svc.GetFullyQualifiedName()) {
+			disc.Services = append(disc.Services, svc)
+		}
+	}
+	return &disc, nil
+}
+
+func loadServices(location string, importPaths []string) ([]*desc.ServiceDescriptor, error) {
+	var files []*desc.FileDescriptor
+	if strings.EqualFold(filepath.Ext(location), ".proto") {
+		fds, err := parseProtoFile(location, importPaths)
+		if err != nil {
+			return nil, err
+		}
+		files = fds
+	} else {
+		fds, err := readDescriptorSet(location)
+		if err != nil {
+			return nil, err
+		}
+		files = fds
+	}
+
+	var services []*desc.ServiceDescriptor
+	for _, fd := range files {
+		services = append(services, fd.GetServices()...)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].GetFullyQualifiedName() < services[j].GetFullyQualifiedName()
+	})
+	return services, nil
+}
+
+// parseProtoFile compiles a .proto file. Imports are resolved against the
+// given import paths, then the file's directory and each of its parent
+// directories in turn, so imports written relative to an enclosing directory
+// (typically the repository root) are found without configuring import
+// paths. A file inside one of the import paths is named relative to it, as
+// other files import it. Well-known types (google/protobuf/*.proto) are
+// built in.
+func parseProtoFile(path string, importPaths []string) ([]*desc.FileDescriptor, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(abs)
+	name := ""
+	for _, p := range importPaths {
+		if rel, err := filepath.Rel(p, abs); err == nil && filepath.IsLocal(rel) {
+			name = filepath.ToSlash(rel)
+			break
+		}
+	}
+	paths := slices.Clone(importPaths)
+	if name == "" {
+		name = filepath.Base(abs)
+		paths = append([]string{dir}, paths...)
+	}
+	parser := protoparse.Parser{
+		ImportPaths:           append(paths, ancestorDirs(dir)...),
+		IncludeSourceCodeInfo: true,
+	}
+	fds, err := parser.ParseFiles(name)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return fds, nil
+}
+
+// ancestorDirs returns dir followed by each of its parents up to the root.
+func ancestorDirs(dir string) []string {
+	var dirs []string
+	for {
+		dirs = append(dirs, dir)
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dirs
+		}
+		dir = parent
+	}
+}
+
+// readDescriptorSet reads a binary FileDescriptorSet. The set must be
+// self-contained (protoc --include_imports).
+func readDescriptorSet(path string) ([]*desc.FileDescriptor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read descriptor set: %w", err)
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("decode descriptor set %s: %w", path, err)
+	}
+	byName, err := desc.CreateFileDescriptorsFromSet(&set)
+	if err != nil {
+		return nil, fmt.Errorf("load descriptor set %s (was it built with --include_imports?): %w", path, err)
+	}
+	files := make([]*desc.FileDescriptor, 0, len(byName))
+	for _, fd := range byName {
+		files = append(files, fd)
+	}
+	return files, nil
+}
+
+// parseSourceExtension returns the source's x-grpc extension, which may be
+// absent.
+func parseSourceExtension(source delegates.Source) (sourceExtension, error) {
+	var ext sourceExtension
+	if raw, ok := source.Extensions[sourceExtensionKey]; ok {
+		if err := json.Unmarshal(raw, &ext); err != nil {
+			return ext, fmt.Errorf("parse %s: %w", sourceExtensionKey, err)
+		}
+	}
+	return ext, nil
+}
+
+// protoImportPaths returns the absolute import paths for a .proto source:
+// the context's "importPaths" metadata, relative to the working directory,
+// followed by the OBI source's x-grpc importPaths, relative to the .proto
+// file's directory. bindCtx may be nil.
+func protoImportPaths(source delegates.Source, bindCtx *delegates.BindingContext) ([]string, error) {
+	var paths []string
+	if bindCtx != nil {
+		switch v := bindCtx.Metadata[importPathsKey].(type) {
+		case nil:
+		case string:
+			paths = append(paths, filepath.SplitList(v)...)
+		case []any:
+			for _, p := range v {
+				s, ok := p.(string)
+				if !ok {
+					return nil, fmt.Errorf("%s metadata must be a list of strings", importPathsKey)
+				}
+				paths = append(paths, s)
+			}
+		default:
+			return nil, fmt.Errorf("%s metadata must be a string or a list of strings", importPathsKey)
+		}
+	}
+	for i, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return nil, err
+		}
+		paths[i] = abs
+	}
+
+	ext, err := parseSourceExtension(source)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := filepath.Abs(filepath.Dir(source.Location))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range ext.ImportPaths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		paths = append(paths, filepath.Clean(p))
+	}
+	return paths, nil
+}
+
+// serverAddress returns the address to dial for a descriptor source: the
+// context's "address" metadata, or else the OBI source's x-grpc address.
+func serverAddress(input delegates.ExecuteInput) (string, error) {
+	if input.Context != nil {
+		if addr, ok := input.Context.Metadata["address"].(string); ok && addr != "" {
+			return addr, nil
+		}
+	}
+	ext, err := parseSourceExtension(input.Source)
+	if err != nil {
+		return "", err
+	}
+	if ext.Address != "" {
+		return ext.Address, nil
+	}
+	return "", fmt.Errorf("gRPC source %q has no server address; set the \"address\" context metadata or the source's %s.address", input.Source.Location, sourceExtensionKey)
+}
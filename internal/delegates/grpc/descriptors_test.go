@@ -0,0 +1,173 @@
+package grpc
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+func TestIsDescriptorSource(t *testing.T) {
+	tests := map[string]bool{
+		"localhost:50051":         false,
+		"api.example.com:443":     false,
+		"https://api.example.com": false,
+		"./protos/shop.proto":     true,
+		"shop.PROTO":              true,
+		"build/shop.pb":           true,
+		"shop.protoset":           true,
+		"shop.binpb":              true,
+	}
+	for location, want := range tests {
+		if got := IsDescriptorSource(location); got != want {
+			t.Errorf("IsDescriptorSource(%q) = %v, want %v", location, got, want)
+		}
+	}
+}
+
+func TestDiscoverFilesResolvesImportsFromParentDirs(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "acme", "common", "types.proto"), `
+syntax = "proto3";
+package acme.common;
+message Money { string currency = 1; int64 units = 2; }
+`)
+	shop := filepath.Join(root, "acme", "shop", "v1", "shop.proto")
+	writeFile(t, shop, `
+syntax = "proto3";
+package acme.shop.v1;
+import "acme/common/types.proto";
+import "google/protobuf/empty.proto";
+
+service Shop {
+  // Places an order.
+  rpc PlaceOrder(Order) returns (acme.common.Money);
+  rpc Ping(google.protobuf.Empty) returns (google.protobuf.Empty);
+}
+message Order { string item = 1; }
+`)
+
+	disc, err := DiscoverFiles(shop, nil)
+	if err != nil {
+		t.Fatalf("DiscoverFiles: %v", err)
+	}
+	if len(disc.Services) != 1 || disc.Services[0].GetFullyQualifiedName() != "acme.shop.v1.Shop" {
+		t.Fatalf("services = %v", disc.Services)
+	}
+
+	iface, err := ConvertToInterface(disc, shop)
+	if err != nil {
+		t.Fatalf("ConvertToInterface: %v", err)
+	}
+	op, ok := iface.Operations["PlaceOrder"]
+	if !ok {
+		t.Fatalf("missing PlaceOrder operation: %v", iface.Operations)
+	}
+	if op.Description != "Places an order." {
+		t.Errorf("description = %q", op.Description)
+	}
+	if b := iface.Bindings["PlaceOrder."+DefaultSourceName]; b.Ref != "acme.shop.v1.Shop/PlaceOrder" {
+		t.Errorf("binding ref = %q", b.Ref)
+	}
+}
+
+func TestDiscoverFilesImportPaths(t *testing.T) {
+	root := t.TempDir()
+	vendor := filepath.Join(root, "third_party")
+	writeFile(t, filepath.Join(vendor, "money", "money.proto"), `
+syntax = "proto3";
+package money;
+message Money { int64 units = 1; }
+`)
+	shop := filepath.Join(root, "proto", "shop.proto")
+	writeFile(t, shop, `
+syntax = "proto3";
+package shop;
+import "money/money.proto";
+service Shop { rpc Price(money.Money) returns (money.Money); }
+`)
+
+	if _, err := DiscoverFiles(shop, nil); err == nil {
+		t.Fatal("expected an unresolved import without import paths")
+	}
+
+	tests := map[string]struct {
+		source  delegates.Source
+		bindCtx *delegates.BindingContext
+	}{
+		"source extension": {source: delegates.Source{
+			Location:   shop,
+			Extensions: map[string]json.RawMessage{sourceExtensionKey: json.RawMessage(`{"importPaths":["../third_party"]}`)},
+		}},
+		"context metadata": {
+			source:  delegates.Source{Location: shop},
+			bindCtx: &delegates.BindingContext{Metadata: map[string]any{importPathsKey: []any{vendor}}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			paths, err := protoImportPaths(tt.source, tt.bindCtx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			disc, err := DiscoverFiles(shop, paths)
+			if err != nil {
+				t.Fatalf("DiscoverFiles: %v", err)
+			}
+			if len(disc.Services) != 1 || disc.Services[0].GetFullyQualifiedName() != "shop.Shop" {
+				t.Errorf("services = %v", disc.Services)
+			}
+		})
+	}
+}
+
+func TestDiscoverFilesParseError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.proto")
+	writeFile(t, path, `syntax = "proto3"; import "missing/dep.proto";`)
+
+	if _, err := DiscoverFiles(path, nil); err == nil || !strings.Contains(err.Error(), "bad.proto") {
+		t.Errorf("err = %v, want parse error naming the file", err)
+	}
+}
+
+func TestServerAddress(t *testing.T) {
+	ext := map[string]json.RawMessage{sourceExtensionKey: json.RawMessage(`{"address":"source:50051"}`)}
+	tests := []struct {
+		name    string
+		input   delegates.ExecuteInput
+		want    string
+		wantErr bool
+	}{
+		{"context metadata", delegates.ExecuteInput{
+			Source:  delegates.Source{Location: "shop.proto", Extensions: ext},
+			Context: &delegates.BindingContext{Metadata: map[string]any{"address": "ctx:50051"}},
+		}, "ctx:50051", false},
+		{"source extension", delegates.ExecuteInput{
+			Source: delegates.Source{Location: "shop.proto", Extensions: ext},
+		}, "source:50051", false},
+		{"missing", delegates.ExecuteInput{
+			Source: delegates.Source{Location: "shop.proto"},
+		}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := serverAddress(tt.input)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("serverAddress = (%q, %v), want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
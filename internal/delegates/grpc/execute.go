@@ -14,28 +14,40 @@ import (
 	"github.com/jhump/protoreflect/dynamic/grpcdynamic"
 	"github.com/jhump/protoreflect/grpcreflect"
 	"github.com/openbindings/cli/internal/delegates"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 // ExecuteInput is the input for gRPC operation execution.
 type ExecuteInput struct {
-	Address string // host:port of the gRPC server
-	Ref     string // fully qualified method ref (e.g., "blend.CoffeeShop/PlaceOrder")
-	Input   any    // Operation input data (JSON-compatible map)
+	Address     string                    // host:port of the gRPC server
+	Descriptors string                    // Optional .proto file or descriptor set; when set, reflection is not used
+	ImportPaths []string                  // Import paths for a .proto Descriptors file
+	Ref         string                    // fully qualified method ref (e.g., "blend.CoffeeShop/PlaceOrder")
+	Input       any                       // Operation input data (JSON-compatible map)
+	Context     *delegates.BindingContext // Runtime context (transport settings)
 }
 
 // ExecuteOutput is the output from gRPC operation execution.
 type ExecuteOutput struct {
-	Output     any    // Execution result
-	Status     int    // 0 for success, 1 for error
-	DurationMs int64  // Execution duration
+	Output     any   // Execution result
+	Status     int   // 0 for success, 1 for error
+	DurationMs int64 // Execution duration
 	Error      *delegates.Error
+	Headers    map[string][]string // Response header metadata
+	Trailers   map[string][]string // Response trailer metadata
 }
 
 // Execute invokes a gRPC method dynamically. It resolves the method descriptor
-// via reflection, marshals JSON input to a protobuf message, invokes the RPC,
-// and marshals the response back to JSON.
+// from input.Descriptors or via server reflection, marshals JSON input to a protobuf message, invokes the RPC,
+// and marshals the response back to JSON. Client-streaming and bidirectional
+// methods take an array of request messages (see executeStream).
+//
+// Context credentials and headers are sent as request metadata (also on the
+// reflection calls), and the RPC deadline is ctx's deadline.
 func Execute(ctx context.Context, input ExecuteInput) ExecuteOutput {
 	start := time.Now()
+	ctx = withOutgoingMetadata(ctx, input.Context)
 
 	svcName, methodName, err := parseRef(input.Ref)
 	if err != nil {
@@ -46,7 +58,7 @@ func Execute(ctx context.Context, input ExecuteInput) ExecuteOutput {
 		}
 	}
 
-	conn, err := dial(ctx, input.Address)
+	conn, err := dial(ctx, input.Address, input.Context)
 	if err != nil {
 		return ExecuteOutput{
 			Status:     1,
@@ -56,10 +68,7 @@ func Execute(ctx context.Context, input ExecuteInput) ExecuteOutput {
 	}
 	defer conn.Close()
 
-	refClient := grpcreflect.NewClientAuto(ctx, conn)
-	defer refClient.Reset()
-
-	svcDesc, err := refClient.ResolveService(svcName)
+	svcDesc, release, err := resolveService(ctx, conn, input.Descriptors, input.ImportPaths, svcName)
 	if err != nil {
 		return ExecuteOutput{
 			Status:     1,
@@ -67,6 +76,7 @@ func Execute(ctx context.Context, input ExecuteInput) ExecuteOutput {
 			Error:      &delegates.Error{Code: "resolve_failed", Message: fmt.Sprintf("resolve service %q: %v", svcName, err)},
 		}
 	}
+	defer release()
 
 	methodDesc := svcDesc.FindMethodByName(methodName)
 	if methodDesc == nil {
@@ -77,6 +87,10 @@ func Execute(ctx context.Context, input ExecuteInput) ExecuteOutput {
 		}
 	}
 
+	if methodDesc.IsClientStreaming() {
+		return executeStream(ctx, start, conn, methodDesc, input.Input)
+	}
+
 	reqMsg, err := buildRequest(methodDesc, input.Input)
 	if err != nil {
 		return ExecuteOutput{
@@ -87,41 +101,23 @@ func Execute(ctx context.Context, input ExecuteInput) ExecuteOutput {
 	}
 
 	stub := grpcdynamic.NewStub(conn)
+	var header, trailer metadata.MD
+	callOpts := []grpc.CallOption{grpc.Header(&header), grpc.Trailer(&trailer)}
 
+	var resp proto.Message
 	if methodDesc.IsServerStreaming() {
-		stream, err := stub.InvokeRpcServerStream(ctx, methodDesc, reqMsg)
+		stream, err := stub.InvokeRpcServerStream(ctx, methodDesc, reqMsg, callOpts...)
 		if err != nil {
-			return ExecuteOutput{
-				Status:     1,
-				DurationMs: time.Since(start).Milliseconds(),
-				Error:      &delegates.Error{Code: "rpc_failed", Message: err.Error()},
-			}
+			return rpcErrorOutput(start, "rpc_failed", err, header, trailer)
 		}
-		resp, err := stream.RecvMsg()
+		resp, err = stream.RecvMsg()
 		if err != nil {
-			return ExecuteOutput{
-				Status:     1,
-				DurationMs: time.Since(start).Milliseconds(),
-				Error:      &delegates.Error{Code: "stream_recv_failed", Message: err.Error()},
-			}
+			return rpcErrorOutput(start, "stream_recv_failed", err, header, trailer)
 		}
-		output, err := responseToJSON(resp)
+	} else {
+		resp, err = stub.InvokeRpc(ctx, methodDesc, reqMsg, callOpts...)
 		if err != nil {
-			return ExecuteOutput{
-				Status:     1,
-				DurationMs: time.Since(start).Milliseconds(),
-				Error:      &delegates.Error{Code: "marshal_failed", Message: err.Error()},
-			}
-		}
-		return ExecuteOutput{Output: output, DurationMs: time.Since(start).Milliseconds()}
-	}
-
-	resp, err := stub.InvokeRpc(ctx, methodDesc, reqMsg)
-	if err != nil {
-		return ExecuteOutput{
-			Status:     1,
-			DurationMs: time.Since(start).Milliseconds(),
-			Error:      &delegates.Error{Code: "rpc_failed", Message: err.Error()},
+			return rpcErrorOutput(start, "rpc_failed", err, header, trailer)
 		}
 	}
 
@@ -134,26 +130,38 @@ func Execute(ctx context.Context, input ExecuteInput) ExecuteOutput {
 		}
 	}
 
-	return ExecuteOutput{Output: output, DurationMs: time.Since(start).Milliseconds()}
+	return ExecuteOutput{
+		Output:     output,
+		DurationMs: time.Since(start).Milliseconds(),
+		Headers:    metadataMap(header),
+		Trailers:   metadataMap(trailer),
+	}
 }
 
-// Subscribe opens a server-streaming RPC and returns events on a channel.
+// Subscribe opens a server-streaming or bidirectional RPC and returns events
+// on a channel.
 func Subscribe(ctx context.Context, input ExecuteInput) (<-chan delegates.StreamEvent, error) {
+	ctx = withOutgoingMetadata(ctx, input.Context)
+
 	svcName, methodName, err := parseRef(input.Ref)
 	if err != nil {
 		return nil, err
 	}
 
-	conn, err := dial(ctx, input.Address)
+	conn, err := dial(ctx, input.Address, input.Context)
 	if err != nil {
 		return nil, err
 	}
 
-	refClient := grpcreflect.NewClientAuto(ctx, conn)
+	svcDesc, release, err := resolveService(ctx, conn, input.Descriptors, input.ImportPaths, svcName)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("resolve service %q: %w", svcName, err)
+	}
 
 	// cleanup closes resources on error paths; nilled once the goroutine takes ownership.
 	cleanup := func() {
-		refClient.Reset()
+		release()
 		conn.Close()
 	}
 	defer func() {
@@ -162,11 +170,6 @@ func Subscribe(ctx context.Context, input ExecuteInput) (<-chan delegates.Stream
 		}
 	}()
 
-	svcDesc, err := refClient.ResolveService(svcName)
-	if err != nil {
-		return nil, fmt.Errorf("resolve service %q: %w", svcName, err)
-	}
-
 	methodDesc := svcDesc.FindMethodByName(methodName)
 	if methodDesc == nil {
 		return nil, fmt.Errorf("method %q not found in service %q", methodName, svcName)
@@ -176,6 +179,16 @@ func Subscribe(ctx context.Context, input ExecuteInput) (<-chan delegates.Stream
 		return nil, fmt.Errorf("method %q is not server-streaming", input.Ref)
 	}
 
+	// Bidirectional methods stream the request messages in the input array.
+	if methodDesc.IsClientStreaming() {
+		ch, err := startStream(ctx, conn, methodDesc, inputMessages(input.Input), cleanup)
+		if err != nil {
+			return nil, err
+		}
+		cleanup = nil
+		return ch, nil
+	}
+
 	reqMsg, err := buildRequest(methodDesc, input.Input)
 	if err != nil {
 		return nil, err
@@ -190,7 +203,7 @@ func Subscribe(ctx context.Context, input ExecuteInput) (<-chan delegates.Stream
 	ch := make(chan delegates.StreamEvent, 16)
 	go func() {
 		defer close(ch)
-		defer refClient.Reset()
+		defer release()
 		defer conn.Close()
 
 		for {
@@ -222,6 +235,32 @@ func Subscribe(ctx context.Context, input ExecuteInput) (<-chan delegates.Stream
 	return ch, nil
 }
 
+// resolveService finds a service descriptor in the descriptor file when one
+// is given, and otherwise asks the server via reflection. The returned
+// function releases the reflection client.
+func resolveService(ctx context.Context, conn *grpc.ClientConn, descriptors string, importPaths []string, name string) (*desc.ServiceDescriptor, func(), error) {
+	if descriptors != "" {
+		services, err := loadServices(descriptors, importPaths)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, svc := range services {
+			if svc.GetFullyQualifiedName() == name {
+				return svc, func() {}, nil
+			}
+		}
+		return nil, nil, fmt.Errorf("service not found in %s", descriptors)
+	}
+
+	refClient := grpcreflect.NewClientAuto(ctx, conn)
+	svc, err := refClient.ResolveService(name)
+	if err != nil {
+		refClient.Reset()
+		return nil, nil, err
+	}
+	return svc, refClient.Reset, nil
+}
+
 // parseRef splits "package.Service/Method" into service and method names.
 func parseRef(ref string) (string, string, error) {
 	ref = strings.TrimSpace(ref)
@@ -259,6 +298,22 @@ func buildRequest(method *desc.MethodDescriptor, input any) (*dynamic.Message, e
 	return msg, nil
 }
 
+// rpcErrorOutput builds the output for a failed RPC. The error details carry
+// the gRPC status (see statusDetailsMap) so retry policies can match on it.
+func rpcErrorOutput(start time.Time, code string, err error, header, trailer metadata.MD) ExecuteOutput {
+	return ExecuteOutput{
+		Status:     1,
+		DurationMs: time.Since(start).Milliseconds(),
+		Error: &delegates.Error{
+			Code:    code,
+			Message: err.Error(),
+			Details: statusDetailsMap(err),
+		},
+		Headers:  metadataMap(header),
+		Trailers: metadataMap(trailer),
+	}
+}
+
 func responseToJSON(resp proto.Message) (any, error) {
 	dm, ok := resp.(*dynamic.Message)
 	if !ok {
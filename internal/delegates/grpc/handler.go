@@ -3,6 +3,16 @@
 // The gRPC handler uses server reflection to discover services and methods,
 // converts protobuf descriptors to OpenBindings operations, and dynamically
 // invokes RPCs using JSON input/output.
+//
+// A source location may instead name a .proto file or a compiled descriptor
+// set, for servers that disable reflection. Descriptors are then read
+// locally, and the server address comes from the context's "address"
+// metadata or the OBI source's x-grpc extension. Imports of a .proto file are
+// resolved against the context's "importPaths" metadata and the extension's
+// importPaths (relative to the .proto file), then the file's enclosing
+// directories:
+//
+//	"sources": {"shop": {"format": "grpc", "location": "./shop.proto", "x-grpc": {"address": "localhost:50051", "importPaths": ["../third_party"]}}}
 package grpc
 
 import (
@@ -33,7 +43,7 @@ func New() *Handler {
 func (h *Handler) GetInfo() delegates.SoftwareInfo {
 	return delegates.SoftwareInfo{
 		Name:        "gRPC",
-		Description: "gRPC servers discovered via server reflection",
+		Description: "gRPC services from server reflection, .proto files or descriptor sets",
 	}
 }
 
@@ -42,13 +52,13 @@ func (h *Handler) ListFormats() []delegates.FormatInfo {
 	return []delegates.FormatInfo{
 		{
 			Token:       FormatToken,
-			Description: "gRPC services via server reflection (unary and server-streaming)",
+			Description: "gRPC services via server reflection, .proto files or descriptor sets (unary and streaming)",
 		},
 	}
 }
 
-// CreateInterface discovers a gRPC server's services and converts them
-// to an OpenBindings interface.
+// CreateInterface discovers a gRPC server's services (or loads them from a
+// descriptor source) and converts them to an OpenBindings interface.
 func (h *Handler) CreateInterface(source delegates.Source) (openbindings.Interface, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
 	defer cancel()
@@ -59,31 +69,71 @@ func (h *Handler) CreateInterface(source delegates.Source) (openbindings.Interfa
 
 // ExecuteOperation executes a gRPC operation via dynamic invocation.
 func (h *Handler) ExecuteOperation(ctx context.Context, input delegates.ExecuteInput) delegates.ExecuteOutput {
-	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	ctx, cancel := delegates.WithDefaultTimeout(ctx, DefaultTimeout)
 	defer cancel()
 
-	result := Execute(ctx, ExecuteInput{
-		Address: input.Source.Location,
-		Ref:     input.Ref,
-		Input:   input.Input,
-	})
+	execInput, err := executeInput(input)
+	if err != nil {
+		return delegates.ExecuteOutput{
+			Status: 1,
+			Error:  &delegates.Error{Code: "no_address", Message: err.Error()},
+		}
+	}
+	result := Execute(ctx, execInput)
 
 	return delegates.ExecuteOutput{
 		Output:     result.Output,
 		Status:     result.Status,
 		DurationMs: result.DurationMs,
 		Error:      result.Error,
+		Headers:    result.Headers,
+		Trailers:   result.Trailers,
 	}
 }
 
 // SubscribeOperation implements the delegates.StreamHandler interface for
-// server-streaming RPCs.
+// server-streaming and bidirectional RPCs.
 func (h *Handler) SubscribeOperation(ctx context.Context, input delegates.ExecuteInput) (<-chan delegates.StreamEvent, error) {
-	return Subscribe(ctx, ExecuteInput{
+	execInput, err := executeInput(input)
+	if err != nil {
+		return nil, err
+	}
+	return Subscribe(ctx, execInput)
+}
+
+// StreamOperation implements the delegates.InputStreamHandler interface for
+// client-streaming and bidirectional RPCs.
+func (h *Handler) StreamOperation(ctx context.Context, input delegates.ExecuteInput, messages <-chan any) (<-chan delegates.StreamEvent, error) {
+	execInput, err := executeInput(input)
+	if err != nil {
+		return nil, err
+	}
+	return Stream(ctx, execInput, messages)
+}
+
+// executeInput maps a delegate input to a gRPC input. A descriptor source's
+// location becomes Descriptors and the address is resolved separately.
+func executeInput(input delegates.ExecuteInput) (ExecuteInput, error) {
+	in := ExecuteInput{
 		Address: input.Source.Location,
 		Ref:     input.Ref,
 		Input:   input.Input,
-	})
+		Context: input.Context,
+	}
+	if IsDescriptorSource(input.Source.Location) {
+		addr, err := serverAddress(input)
+		if err != nil {
+			return ExecuteInput{}, err
+		}
+		importPaths, err := protoImportPaths(input.Source, input.Context)
+		if err != nil {
+			return ExecuteInput{}, err
+		}
+		in.Address = addr
+		in.Descriptors = input.Source.Location
+		in.ImportPaths = importPaths
+	}
+	return in, nil
 }
 
 // DiscoverSource implements the delegates.SourceDiscoverer interface.
@@ -94,10 +144,20 @@ func (h *Handler) DiscoverSource(ctx context.Context, source delegates.Source) (
 func (h *Handler) discoverAndConvert(ctx context.Context, source delegates.Source) ([]byte, openbindings.Interface, error) {
 	addr := source.Location
 	if addr == "" {
-		return nil, openbindings.Interface{}, fmt.Errorf("gRPC source requires a location (host:port address)")
+		return nil, openbindings.Interface{}, fmt.Errorf("gRPC source requires a location (host:port address, .proto file or descriptor set)")
 	}
 
-	disc, err := Discover(ctx, addr)
+	var disc *Discovery
+	var err error
+	if IsDescriptorSource(addr) {
+		importPaths, pathsErr := protoImportPaths(source, nil)
+		if pathsErr != nil {
+			return nil, openbindings.Interface{}, pathsErr
+		}
+		disc, err = DiscoverFiles(addr, importPaths)
+	} else {
+		disc, err = Discover(ctx, addr)
+	}
 	if err != nil {
 		return nil, openbindings.Interface{}, fmt.Errorf("gRPC discovery: %w", err)
 	}
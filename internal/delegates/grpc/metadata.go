@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/openbindings/cli/internal/delegates"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// withOutgoingMetadata attaches BindingContext credentials and headers to ctx
+// as gRPC request metadata. Credentials map to the authorization key the same
+// way delegates.ApplyHTTPContext maps them to the Authorization header;
+// headers are sent as-is (lower-cased) and override credentials.
+func withOutgoingMetadata(ctx context.Context, bindCtx *delegates.BindingContext) context.Context {
+	if bindCtx == nil {
+		return ctx
+	}
+
+	md := metadata.MD{}
+	if creds := bindCtx.Credentials; creds != nil {
+		if creds.BearerToken != "" {
+			md.Set("authorization", "Bearer "+creds.BearerToken)
+		} else if creds.APIKey != "" {
+			md.Set("authorization", "ApiKey "+creds.APIKey)
+		} else if creds.Basic != nil {
+			userPass := creds.Basic.Username + ":" + creds.Basic.Password
+			md.Set("authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(userPass)))
+		}
+	}
+	for k, v := range bindCtx.Headers {
+		md.Set(k, v)
+	}
+
+	if len(md) == 0 {
+		return ctx
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// statusDetailsMap describes a failed RPC's status for ExecuteOutput error
+// details: the code name, message, any rich error details (google.rpc types
+// such as ErrorInfo or BadRequest) as JSON, and a RetryInfo delay as a
+// Retry-After hint.
+func statusDetailsMap(err error) map[string]any {
+	st := status.Convert(err)
+	details := map[string]any{
+		delegates.GRPCCodeDetailsKey: st.Code().String(),
+		"grpcMessage":                st.Message(),
+	}
+
+	var rich []any
+	for _, d := range st.Proto().GetDetails() {
+		data, mErr := protojson.Marshal(d)
+		if mErr != nil {
+			rich = append(rich, map[string]any{"@type": d.GetTypeUrl()})
+			continue
+		}
+		var v any
+		if json.Unmarshal(data, &v) == nil {
+			rich = append(rich, v)
+		}
+	}
+	if len(rich) > 0 {
+		details["grpcDetails"] = rich
+	}
+
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			details[delegates.RetryAfterDetailsKey] = info.GetRetryDelay().AsDuration().Milliseconds()
+		}
+	}
+	return details
+}
+
+// metadataMap converts gRPC metadata to a plain map, or nil when empty.
+func metadataMap(md metadata.MD) map[string][]string {
+	if len(md) == 0 {
+		return nil
+	}
+	return map[string][]string(md)
+}
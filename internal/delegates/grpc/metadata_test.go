@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openbindings/cli/internal/delegates"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestWithOutgoingMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		bindCtx *delegates.BindingContext
+		want    metadata.MD
+	}{
+		{"nil context", nil, nil},
+		{"bearer token", &delegates.BindingContext{Credentials: &delegates.Credentials{BearerToken: "tok"}}, metadata.Pairs("authorization", "Bearer tok")},
+		{"api key", &delegates.BindingContext{Credentials: &delegates.Credentials{APIKey: "key"}}, metadata.Pairs("authorization", "ApiKey key")},
+		{"basic", &delegates.BindingContext{Credentials: &delegates.Credentials{Basic: &delegates.BasicCredentials{Username: "user", Password: "secret"}}}, metadata.Pairs("authorization", "Basic dXNlcjpzZWNyZXQ=")},
+		{"headers override credentials", &delegates.BindingContext{
+			Credentials: &delegates.Credentials{BearerToken: "tok"},
+			Headers:     map[string]string{"Authorization": "Custom x", "X-Tenant": "acme"},
+		}, metadata.Pairs("authorization", "Custom x", "x-tenant", "acme")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := withOutgoingMetadata(context.Background(), tt.bindCtx)
+			got, _ := metadata.FromOutgoingContext(ctx)
+			if len(got) != len(tt.want) {
+				t.Fatalf("metadata = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if g := got.Get(k); len(g) != 1 || g[0] != v[0] {
+					t.Errorf("%s = %v, want %v", k, g, v)
+				}
+			}
+		})
+	}
+}
+
+func TestStatusDetailsMap(t *testing.T) {
+	st, err := status.New(codes.Unavailable, "try later").WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)},
+		&errdetails.ErrorInfo{Reason: "OVERLOADED", Domain: "example.com"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	details := statusDetailsMap(st.Err())
+	if details[delegates.GRPCCodeDetailsKey] != "Unavailable" || details["grpcMessage"] != "try later" {
+		t.Errorf("details = %v", details)
+	}
+	if details[delegates.RetryAfterDetailsKey] != int64(1500) {
+		t.Errorf("%s = %v, want 1500", delegates.RetryAfterDetailsKey, details[delegates.RetryAfterDetailsKey])
+	}
+	rich, _ := details["grpcDetails"].([]any)
+	if len(rich) != 2 {
+		t.Fatalf("grpcDetails = %v, want 2 entries", details["grpcDetails"])
+	}
+	info, _ := rich[1].(map[string]any)
+	if info["@type"] != "type.googleapis.com/google.rpc.ErrorInfo" || info["reason"] != "OVERLOADED" {
+		t.Errorf("ErrorInfo = %v", rich[1])
+	}
+
+	plain := statusDetailsMap(status.Error(codes.NotFound, "missing"))
+	if _, ok := plain["grpcDetails"]; ok {
+		t.Errorf("unexpected grpcDetails for a status without details: %v", plain)
+	}
+}
@@ -0,0 +1,250 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/golang/protobuf/proto" //nolint:staticcheck // matches jhump/protoreflect return types
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic/grpcdynamic"
+	"github.com/openbindings/cli/internal/delegates"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Stream invokes a client-streaming or bidirectional-streaming method. Each
+// message received on messages is sent as it arrives, and the send side is
+// closed when messages is closed. The returned channel receives the single
+// response of a client-streaming call, or each response of a bidirectional
+// call as it is received, and is closed when the call completes.
+func Stream(ctx context.Context, input ExecuteInput, messages <-chan any) (<-chan delegates.StreamEvent, error) {
+	ctx = withOutgoingMetadata(ctx, input.Context)
+
+	svcName, methodName, err := parseRef(input.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dial(ctx, input.Address, input.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	svcDesc, release, err := resolveService(ctx, conn, input.Descriptors, input.ImportPaths, svcName)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("resolve service %q: %w", svcName, err)
+	}
+	closeAll := func() {
+		release()
+		conn.Close()
+	}
+
+	methodDesc := svcDesc.FindMethodByName(methodName)
+	if methodDesc == nil {
+		closeAll()
+		return nil, fmt.Errorf("method %q not found in service %q", methodName, svcName)
+	}
+	if !methodDesc.IsClientStreaming() {
+		closeAll()
+		return nil, fmt.Errorf("method %q is not client-streaming or bidirectional", input.Ref)
+	}
+
+	ch, err := startStream(ctx, conn, methodDesc, messages, closeAll)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+	return ch, nil
+}
+
+// startStream opens a client-streaming or bidirectional call on conn and
+// pumps messages into it. done is called once the call has finished.
+func startStream(ctx context.Context, conn *grpc.ClientConn, method *desc.MethodDescriptor, messages <-chan any, done func(), opts ...grpc.CallOption) (<-chan delegates.StreamEvent, error) {
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	stub := grpcdynamic.NewStub(conn)
+	ch := make(chan delegates.StreamEvent, 16)
+
+	if !method.IsServerStreaming() {
+		stream, err := stub.InvokeRpcClientStream(ctx, method, opts...)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("invoke stream: %w", err)
+		}
+		go func() {
+			defer close(ch)
+			defer done()
+			defer cancel()
+
+			if sendErr := sendAll(ctx, method, messages, stream.SendMsg); sendErr != nil {
+				ch <- delegates.StreamEvent{Error: sendErr}
+				return
+			}
+			resp, err := stream.CloseAndReceive()
+			if err != nil {
+				if !cancelled(parent) {
+					ch <- streamErrorEvent("rpc_failed", err)
+				}
+				return
+			}
+			ch <- responseEvent(resp)
+		}()
+		return ch, nil
+	}
+
+	stream, err := stub.InvokeRpcBidiStream(ctx, method, opts...)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invoke stream: %w", err)
+	}
+
+	// sendErrs carries an input error from the send side, which cancels the
+	// call; the receive side reports it in place of the cancellation.
+	sendErrs := make(chan *delegates.Error, 1)
+	go func() {
+		if sendErr := sendAll(ctx, method, messages, stream.SendMsg); sendErr != nil {
+			sendErrs <- sendErr
+			cancel()
+			return
+		}
+		_ = stream.CloseSend()
+	}()
+
+	go func() {
+		defer close(ch)
+		defer done()
+		defer cancel()
+
+		for {
+			resp, err := stream.RecvMsg()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				select {
+				case sendErr := <-sendErrs:
+					ch <- delegates.StreamEvent{Error: sendErr}
+				default:
+					if !cancelled(parent) {
+						ch <- streamErrorEvent("stream_error", err)
+					}
+				}
+				return
+			}
+			ev := responseEvent(resp)
+			ch <- ev
+			if ev.Error != nil {
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// executeStream runs a client-streaming or bidirectional call to completion,
+// sending the messages of input (see inputMessages). The output is the
+// response of a client-streaming call, or the array of responses of a
+// bidirectional call.
+func executeStream(ctx context.Context, start time.Time, conn *grpc.ClientConn, method *desc.MethodDescriptor, input any) ExecuteOutput {
+	var header, trailer metadata.MD
+	ch, err := startStream(ctx, conn, method, inputMessages(input), func() {}, grpc.Header(&header), grpc.Trailer(&trailer))
+	if err != nil {
+		return rpcErrorOutput(start, "rpc_failed", err, header, trailer)
+	}
+
+	responses := []any{}
+	var callErr *delegates.Error
+	for ev := range ch {
+		if ev.Error != nil {
+			callErr = ev.Error
+			continue
+		}
+		responses = append(responses, ev.Data)
+	}
+
+	out := ExecuteOutput{
+		DurationMs: time.Since(start).Milliseconds(),
+		Headers:    metadataMap(header),
+		Trailers:   metadataMap(trailer),
+	}
+	switch {
+	case callErr != nil:
+		out.Status = 1
+		out.Error = callErr
+	case method.IsServerStreaming():
+		out.Output = responses
+	case len(responses) > 0:
+		out.Output = responses[0]
+	}
+	return out
+}
+
+// cancelled reports whether ctx was cancelled by the caller, as opposed to
+// reaching its deadline. Calls cancelled by the caller end without an error.
+func cancelled(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.Canceled)
+}
+
+// sendAll converts and sends each message until messages is closed or ctx
+// is done. A send failure means the server has ended the call; its status
+// is reported by the receive side, so sendAll just stops. Only input that
+// cannot be converted to the request type is returned as an error.
+func sendAll(ctx context.Context, method *desc.MethodDescriptor, messages <-chan any, send func(proto.Message) error) *delegates.Error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			req, err := buildRequest(method, msg)
+			if err != nil {
+				return &delegates.Error{Code: "invalid_input", Message: err.Error()}
+			}
+			if err := send(req); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// inputMessages returns a closed channel holding the request messages of a
+// non-interactive call: each element when input is an array, otherwise
+// input itself. A nil input sends no messages.
+func inputMessages(input any) <-chan any {
+	var msgs []any
+	switch v := input.(type) {
+	case nil:
+	case []any:
+		msgs = v
+	default:
+		msgs = []any{v}
+	}
+	ch := make(chan any, len(msgs))
+	for _, m := range msgs {
+		ch <- m
+	}
+	close(ch)
+	return ch
+}
+
+func responseEvent(resp proto.Message) delegates.StreamEvent {
+	output, err := responseToJSON(resp)
+	if err != nil {
+		return delegates.StreamEvent{Error: &delegates.Error{Code: "marshal_failed", Message: err.Error()}}
+	}
+	return delegates.StreamEvent{Data: output}
+}
+
+func streamErrorEvent(code string, err error) delegates.StreamEvent {
+	return delegates.StreamEvent{Error: &delegates.Error{
+		Code:    code,
+		Message: err.Error(),
+		Details: statusDetailsMap(err),
+	}}
+}
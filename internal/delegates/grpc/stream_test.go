@@ -0,0 +1,300 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/openbindings/cli/internal/delegates"
+	"github.com/openbindings/openbindings-go"
+	"google.golang.org/grpc"
+)
+
+const calcProto = `
+syntax = "proto3";
+package calc.v1;
+
+service Calc {
+  // Adds up a stream of numbers.
+  rpc Sum(stream Number) returns (Number);
+  // Doubles each number as it arrives.
+  rpc Double(stream Number) returns (stream Number);
+}
+
+message Number { int32 value = 1; }
+`
+
+// startCalcServer serves calc.v1.Calc in-process with dynamic messages and
+// returns its address and the path of its .proto file.
+func startCalcServer(t *testing.T) (string, string) {
+	t.Helper()
+	protoPath := filepath.Join(t.TempDir(), "calc.proto")
+	writeFile(t, protoPath, calcProto)
+	services, err := loadServices(protoPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	number := services[0].FindMethodByName("Sum").GetInputType()
+
+	recv := func(stream grpc.ServerStream) (int32, error) {
+		msg := dynamic.NewMessage(number)
+		if err := stream.RecvMsg(msg); err != nil {
+			return 0, err
+		}
+		return msg.GetFieldByName("value").(int32), nil
+	}
+	reply := func(stream grpc.ServerStream, v int32) error {
+		msg := dynamic.NewMessage(number)
+		msg.SetFieldByName("value", v)
+		return stream.SendMsg(msg)
+	}
+
+	srv := grpc.NewServer()
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "calc.v1.Calc",
+		HandlerType: (*any)(nil),
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    "Sum",
+				ClientStreams: true,
+				Handler: func(_ any, stream grpc.ServerStream) error {
+					var sum int32
+					for {
+						v, err := recv(stream)
+						if err == io.EOF {
+							return reply(stream, sum)
+						}
+						if err != nil {
+							return err
+						}
+						sum += v
+					}
+				},
+			},
+			{
+				StreamName:    "Double",
+				ClientStreams: true,
+				ServerStreams: true,
+				Handler: func(_ any, stream grpc.ServerStream) error {
+					for {
+						v, err := recv(stream)
+						if err == io.EOF {
+							return nil
+						}
+						if err != nil {
+							return err
+						}
+						if err := reply(stream, 2*v); err != nil {
+							return err
+						}
+					}
+				},
+			},
+		},
+	}, nil)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String(), protoPath
+}
+
+func number(v float64) map[string]any {
+	return map[string]any{"value": v}
+}
+
+// nextEvent waits for the next event, failing the test after a timeout.
+func nextEvent(t *testing.T, ch <-chan delegates.StreamEvent) (delegates.StreamEvent, bool) {
+	t.Helper()
+	select {
+	case ev, ok := <-ch:
+		return ev, ok
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a stream event")
+		return delegates.StreamEvent{}, false
+	}
+}
+
+func TestStreamClientStreaming(t *testing.T) {
+	addr, protoPath := startCalcServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	messages := make(chan any, 3)
+	messages <- number(1)
+	messages <- number(2)
+	messages <- number(3)
+	close(messages)
+
+	ch, err := Stream(ctx, ExecuteInput{Address: addr, Descriptors: protoPath, Ref: "calc.v1.Calc/Sum"}, messages)
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	ev, ok := nextEvent(t, ch)
+	if !ok || ev.Error != nil || !reflect.DeepEqual(ev.Data, number(6)) {
+		t.Fatalf("event = %+v (ok=%v), want sum 6", ev, ok)
+	}
+	if _, ok := nextEvent(t, ch); ok {
+		t.Error("expected the channel to close after the response")
+	}
+}
+
+func TestStreamBidiInterleaved(t *testing.T) {
+	addr, protoPath := startCalcServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	messages := make(chan any)
+	ch, err := Stream(ctx, ExecuteInput{Address: addr, Descriptors: protoPath, Ref: "calc.v1.Calc/Double"}, messages)
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+
+	// Each response arrives before the next request is sent.
+	for _, v := range []float64{1, 5} {
+		messages <- number(v)
+		ev, ok := nextEvent(t, ch)
+		if !ok || ev.Error != nil || !reflect.DeepEqual(ev.Data, number(2*v)) {
+			t.Fatalf("event = %+v (ok=%v), want %v", ev, ok, 2*v)
+		}
+	}
+	close(messages)
+	if ev, ok := nextEvent(t, ch); ok {
+		t.Errorf("unexpected event after closing input: %+v", ev)
+	}
+}
+
+func TestStreamInvalidInput(t *testing.T) {
+	addr, protoPath := startCalcServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	messages := make(chan any, 1)
+	messages <- "not an object"
+	ch, err := Stream(ctx, ExecuteInput{Address: addr, Descriptors: protoPath, Ref: "calc.v1.Calc/Double"}, messages)
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	ev, ok := nextEvent(t, ch)
+	if !ok || ev.Error == nil || ev.Error.Code != "invalid_input" {
+		t.Errorf("event = %+v (ok=%v), want invalid_input error", ev, ok)
+	}
+}
+
+func TestExecuteStreamingMethodsWithArrayInput(t *testing.T) {
+	addr, protoPath := startCalcServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	input := []any{number(4), number(5)}
+	sum := Execute(ctx, ExecuteInput{Address: addr, Descriptors: protoPath, Ref: "calc.v1.Calc/Sum", Input: input})
+	if sum.Error != nil || !reflect.DeepEqual(sum.Output, number(9)) {
+		t.Errorf("Sum = %+v, want 9", sum)
+	}
+
+	doubled := Execute(ctx, ExecuteInput{Address: addr, Descriptors: protoPath, Ref: "calc.v1.Calc/Double", Input: input})
+	if doubled.Error != nil || !reflect.DeepEqual(doubled.Output, []any{number(8), number(10)}) {
+		t.Errorf("Double = %+v, want [8 10]", doubled)
+	}
+}
+
+func TestConvertStreamingMethods(t *testing.T) {
+	protoPath := filepath.Join(t.TempDir(), "calc.proto")
+	writeFile(t, protoPath, calcProto)
+	disc, err := DiscoverFiles(protoPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iface, err := ConvertToInterface(disc, protoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		op        string
+		kind      string
+		streaming string
+	}{
+		{"Sum", openbindings.OperationKindMethod, delegates.StreamingClient},
+		{"Double", openbindings.OperationKindEvent, delegates.StreamingBidi},
+	}
+	for _, tt := range tests {
+		op, ok := iface.Operations[tt.op]
+		if !ok {
+			t.Fatalf("missing operation %q", tt.op)
+		}
+		if op.Kind != tt.kind {
+			t.Errorf("%s kind = %q, want %q", tt.op, op.Kind, tt.kind)
+		}
+		if op.Input["type"] != "array" {
+			t.Errorf("%s input = %v, want an array of messages", tt.op, op.Input)
+		}
+		var streaming string
+		if err := json.Unmarshal(op.Extensions[delegates.StreamingKey], &streaming); err != nil || streaming != tt.streaming {
+			t.Errorf("%s %s = %q, want %q", tt.op, delegates.StreamingKey, streaming, tt.streaming)
+		}
+	}
+}
+
+func TestExecuteThroughProxy(t *testing.T) {
+	addr, protoPath := startCalcServer(t)
+	_, port, _ := net.SplitHostPort(addr)
+
+	// The proxy is the only one that can resolve calc.internal.
+	var connectHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "CONNECT only", http.StatusMethodNotAllowed)
+			return
+		}
+		connectHost = r.Host
+		host, port, _ := net.SplitHostPort(r.Host)
+		if host != "calc.internal" {
+			http.Error(w, "unknown host", http.StatusBadGateway)
+			return
+		}
+		upstream, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", port))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			upstream.Close()
+			return
+		}
+		fmt.Fprint(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+		go func() {
+			defer upstream.Close()
+			defer conn.Close()
+			go io.Copy(upstream, conn)
+			io.Copy(conn, upstream)
+		}()
+	}))
+	defer proxy.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	target := net.JoinHostPort("calc.internal", port)
+	bindCtx := &delegates.BindingContext{Transport: &delegates.TransportConfig{ProxyURL: proxy.URL}}
+	sum := Execute(ctx, ExecuteInput{Address: target, Descriptors: protoPath, Ref: "calc.v1.Calc/Sum", Input: []any{number(4), number(5)}, Context: bindCtx})
+	if sum.Error != nil || !reflect.DeepEqual(sum.Output, number(9)) {
+		t.Fatalf("Sum = %+v, want 9", sum)
+	}
+	if connectHost != target {
+		t.Errorf("CONNECT %q, want %q", connectHost, target)
+	}
+}
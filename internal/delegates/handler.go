@@ -13,7 +13,10 @@ package delegates
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 
@@ -59,6 +62,7 @@ type BindingContext struct {
 	Cookies     map[string]string `json:"cookies,omitempty"`
 	Environment map[string]string `json:"environment,omitempty"`
 	Metadata    map[string]any    `json:"metadata,omitempty"`
+	Transport   *TransportConfig  `json:"transport,omitempty"`
 }
 
 // Handler defines the interface for a binding format handler delegate.
@@ -112,12 +116,86 @@ type StreamHandler interface {
 	SubscribeOperation(ctx context.Context, input ExecuteInput) (<-chan StreamEvent, error)
 }
 
+// MethodStreamHandler is an optional interface for StreamHandlers whose
+// SubscribeOperation also accepts method operations, streaming their output
+// while they run (e.g., the lines a CLI command prints). Callers that show
+// output as it arrives may subscribe to such operations instead of calling
+// ExecuteOperation, which still returns the aggregated result.
+type MethodStreamHandler interface {
+	StreamHandler
+	StreamsMethodOutput() bool
+}
+
+// InputStreamHandler is an optional interface that delegates may implement
+// for operations that consume a stream of input messages (e.g., gRPC
+// client-streaming and bidirectional methods). Such operations carry a
+// StreamingKey extension.
+//
+// Each message received on messages is sent as it arrives, and closing the
+// channel ends the input. The returned channel receives the responses (one
+// for client streaming, any number for bidirectional streaming) and is
+// closed when the call completes. input.Input is ignored.
+type InputStreamHandler interface {
+	StreamOperation(ctx context.Context, input ExecuteInput, messages <-chan any) (<-chan StreamEvent, error)
+}
+
+// StreamingKey is the operation extension delegates set on operations that
+// stream their input: StreamingClient when a single response follows the
+// input stream, StreamingBidi when responses are streamed as well.
+const StreamingKey = "x-ob-streaming"
+
+// Values of the StreamingKey extension.
+const (
+	StreamingClient = "client"
+	StreamingBidi   = "bidi"
+)
+
+// ServerResolver is an optional interface that delegates may implement when
+// their operations are sent to a server chosen from the source document
+// (e.g., OpenAPI servers with variables). Callers such as the browse TUI use
+// it to show where an operation will be sent before executing it.
+type ServerResolver interface {
+	// ResolveServers returns the base URL each operation ref of a source
+	// would be sent to, given the source and context that ExecuteOperation
+	// would receive. The source document is loaded once for all refs; refs
+	// whose server cannot be resolved are omitted.
+	ResolveServers(source Source, refs []string, bindCtx *BindingContext) (map[string]string, error)
+}
+
+// CompleteKey is the input schema annotation delegates set on properties
+// whose candidate values are listed on demand (e.g., a usage spec completer
+// that runs a shell command). Its value describes the completer; callers
+// only test for its presence and use FieldCompleter to get the values.
+const CompleteKey = "x-ob-complete"
+
+// EnvKey is the input schema annotation naming the environment variable a
+// property is also read from when the input does not set it.
+const EnvKey = "x-ob-env"
+
+// Completion is a candidate value for an input field.
+type Completion struct {
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// FieldCompleter is an optional interface that delegates may implement to
+// list candidate values for input properties annotated with CompleteKey.
+// Callers such as the browse TUI and shell completion use it to offer live
+// values for a field.
+type FieldCompleter interface {
+	// CompleteField returns the candidate values for field, given the same
+	// input that ExecuteOperation would receive. input.Input holds the
+	// values entered so far and may be nil.
+	CompleteField(ctx context.Context, input ExecuteInput, field string) ([]Completion, error)
+}
+
 // Source represents a binding source for conversion.
 type Source struct {
-	Format   string // Format token (e.g., "usage@2.0.0")
-	Location string // File path or URL
-	Content  any    // Inline content (alternative to Location)
-	Binary   string // Binary name hint for CLI execution
+	Format     string                     // Format token (e.g., "usage@2.0.0")
+	Location   string                     // File path or URL
+	Content    any                        // Inline content (alternative to Location)
+	Binary     string                     // Binary name hint for CLI execution
+	Extensions map[string]json.RawMessage // x-* fields of the OBI source, for format-specific settings
 }
 
 // ExecuteInput is the input for operation execution.
@@ -128,12 +206,21 @@ type ExecuteInput struct {
 	Context *BindingContext // Runtime context (credentials, headers, etc.)
 }
 
+// RequestURLKey is the BindingContext.Metadata key of a URL to send the
+// request to instead of the one built from the ref and input, such as the
+// target of a pagination Link. Handlers that honor it only send to the
+// origin of the server they resolve, so credentials stay with that server.
+const RequestURLKey = "requestURL"
+
 // ExecuteOutput is the output from operation execution.
 type ExecuteOutput struct {
-	Output     any    // Execution result
-	Status     int    // 0 for success, 1 for pre-request error, HTTP status code for HTTP errors
-	DurationMs int64  // Execution duration in milliseconds
-	Error      *Error // Non-nil when Status != 0
+	Output     any                 // Execution result
+	Status     int                 // 0 for success, 1 for pre-request error, HTTP status code for HTTP errors
+	DurationMs int64               // Execution duration in milliseconds
+	Error      *Error              // Non-nil when Status != 0
+	Links      map[string]string   // Response link relations (rel → absolute URL), used to follow pagination
+	Headers    map[string][]string // Response header metadata (gRPC)
+	Trailers   map[string][]string // Response trailer metadata (gRPC)
 }
 
 // Error represents an execution error.
@@ -210,6 +297,18 @@ func (r *Registry) All() []Handler {
 	return result
 }
 
+// Close releases resources held by registered handlers that implement
+// io.Closer, such as pooled MCP sessions.
+func (r *Registry) Close() error {
+	var errs []error
+	for _, h := range r.All() {
+		if c, ok := h.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // AllFormats returns all format info from all registered delegates in deterministic order.
 func (r *Registry) AllFormats() []FormatInfo {
 	var result []FormatInfo
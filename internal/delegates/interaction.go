@@ -0,0 +1,83 @@
+// Package delegates - interaction.go lets delegates report progress and ask
+// the caller for input while an operation runs.
+package delegates
+
+import "context"
+
+// Interaction holds callbacks through which a delegate reports activity and
+// asks the caller for input while an operation runs, such as MCP progress
+// notifications, log messages, elicitation and sampling requests, and OAuth
+// authorization. Callers attach it to the context passed to
+// ExecuteOperation with WithInteraction.
+//
+// Every field is optional. Delegates drop notifications that have no
+// callback and decline requests they cannot route. Callbacks may be called
+// from other goroutines while the operation is running.
+type Interaction struct {
+	// Progress receives progress updates for the running operation.
+	Progress func(Progress)
+
+	// Log receives log messages the server sends while the operation runs.
+	Log func(LogMessage)
+
+	// Elicit asks the user for the input described by req.Schema.
+	Elicit func(ctx context.Context, req ElicitRequest) (ElicitResult, error)
+
+	// Sample asks a language model to generate a message. The request and
+	// result use the shapes of the MCP sampling/createMessage params and
+	// result.
+	Sample func(ctx context.Context, req map[string]any) (map[string]any, error)
+
+	// Authorize asks the user to open authURL in a browser to grant the
+	// delegate access to a server (an OAuth authorization code flow). It
+	// returns once the URL has been presented; the delegate waits for the
+	// authorization server's redirect itself.
+	Authorize func(ctx context.Context, authURL string) error
+}
+
+// Progress is a progress update for a running operation.
+type Progress struct {
+	Progress float64 // Progress so far; increases with each update
+	Total    float64 // Total expected, or 0 when unknown
+	Message  string  // Optional human-readable status
+}
+
+// LogMessage is a log message sent by a server while an operation runs.
+type LogMessage struct {
+	Level  string // Syslog severity name (e.g., "info", "warning", "error")
+	Logger string // Optional logger name
+	Data   any    // Message payload, usually a string
+}
+
+// ElicitRequest asks the user for structured input during an operation.
+type ElicitRequest struct {
+	Message string         // What the server is asking for
+	Schema  map[string]any // JSON Schema of a flat object with primitive properties
+}
+
+// ElicitResult is the user's answer to an ElicitRequest.
+type ElicitResult struct {
+	Action  string         // ElicitAccept, ElicitDecline or ElicitCancel
+	Content map[string]any // The submitted values when Action is ElicitAccept
+}
+
+// Elicitation actions.
+const (
+	ElicitAccept  = "accept"
+	ElicitDecline = "decline"
+	ElicitCancel  = "cancel"
+)
+
+type interactionKey struct{}
+
+// WithInteraction returns a copy of ctx carrying ia. A nil ia removes any
+// Interaction inherited from ctx.
+func WithInteraction(ctx context.Context, ia *Interaction) context.Context {
+	return context.WithValue(ctx, interactionKey{}, ia)
+}
+
+// InteractionFrom returns the Interaction attached to ctx, or nil.
+func InteractionFrom(ctx context.Context) *Interaction {
+	ia, _ := ctx.Value(interactionKey{}).(*Interaction)
+	return ia
+}
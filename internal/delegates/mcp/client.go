@@ -5,17 +5,30 @@
 //   - Converting MCP entities to OpenBindings interfaces
 //   - Executing operations via the MCP JSON-RPC protocol
 //
-// Only the Streamable HTTP transport is supported. Source locations must be
-// HTTP or HTTPS URLs pointing to an MCP-capable endpoint.
+// Two transports are supported, selected by the source location:
+//   - Streamable HTTP, for HTTP or HTTPS URLs pointing to an MCP-capable endpoint
+//   - stdio, for exec: references (e.g., "exec:npx -y @acme/mcp-server"); the
+//     command is started as a subprocess for each connection
+//
+// HTTP servers that require OAuth authorization are authorized on first
+// connect through the Interaction in the context (see package mcpauth), and
+// the tokens obtained are kept with the binding context's credentials.
 package mcp
 
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/exec"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/openbindings/cli/internal/delegates"
+	"github.com/openbindings/cli/internal/execref"
+	"github.com/openbindings/cli/internal/mcpauth"
 )
 
 // DefaultTimeout is the maximum time to wait for MCP server operations.
@@ -28,6 +41,12 @@ type Discovery struct {
 	ResourceTemplates []*mcp.ResourceTemplate
 	Prompts           []*mcp.Prompt
 	ServerInfo        *mcp.Implementation
+	Capabilities      *mcp.ServerCapabilities
+}
+
+// canSubscribe reports whether the server supports resource subscriptions.
+func (d *Discovery) canSubscribe() bool {
+	return d.Capabilities != nil && d.Capabilities.Resources != nil && d.Capabilities.Resources.Subscribe
 }
 
 // ClientVersion can be set by the app layer to inject the CLI version
@@ -43,11 +62,13 @@ func clientInfo() *mcp.Implementation {
 	}
 }
 
-// Discover connects to an MCP server over Streamable HTTP, performs the
-// initialization handshake, and paginates through tools/list, resources/list,
-// and prompts/list. The url must be an HTTP or HTTPS URL.
-func Discover(ctx context.Context, url string) (*Discovery, error) {
-	session, err := connect(ctx, url)
+// Discover connects to an MCP server, performs the initialization handshake,
+// and paginates through tools/list, resources/list, resources/templates/list
+// and prompts/list. The
+// location must be an HTTP or HTTPS URL or an exec: reference; bindCtx may
+// be nil.
+func Discover(ctx context.Context, location string, bindCtx *delegates.BindingContext) (*Discovery, error) {
+	session, err := connect(ctx, location, bindCtx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("connect to MCP server: %w", err)
 	}
@@ -59,6 +80,7 @@ func Discover(ctx context.Context, url string) (*Discovery, error) {
 	initResult := session.InitializeResult()
 	if initResult != nil {
 		result.ServerInfo = initResult.ServerInfo
+		result.Capabilities = initResult.Capabilities
 	}
 
 	// List tools (paginated).
@@ -102,71 +124,88 @@ func Discover(ctx context.Context, url string) (*Discovery, error) {
 	return result, nil
 }
 
-// CallTool connects to an MCP server and calls a tool by name.
-func CallTool(ctx context.Context, url string, toolName string, args map[string]any) (*mcp.CallToolResult, error) {
-	session, err := connect(ctx, url)
+// connect starts a transport for location (see newTransport) and performs
+// the initialization handshake, bounded by DefaultTimeout unless ctx has a
+// deadline. opts may be nil.
+//
+// When an HTTP server rejects the handshake for lack of authorization, the
+// user is asked to authorize through the Interaction in ctx (see
+// mcpauth.Client.Authorize) and the handshake is retried once. The tokens
+// obtained are passed to the CredentialSaver in ctx, if any.
+func connect(ctx context.Context, location string, bindCtx *delegates.BindingContext, opts *mcp.ClientOptions) (*mcp.ClientSession, error) {
+	transport, auth, err := newTransport(ctx, location, bindCtx)
 	if err != nil {
-		return nil, fmt.Errorf("connect to MCP server: %w", err)
+		return nil, err
+	}
+	client := mcp.NewClient(clientInfo(), opts)
+	session, err := handshake(ctx, client, transport)
+	if err == nil || auth == nil || !auth.NeedsAuthorization() {
+		return session, err
 	}
-	defer session.Close()
 
-	result, err := session.CallTool(ctx, &mcp.CallToolParams{
-		Name:      toolName,
-		Arguments: args,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("call tool %q: %w", toolName, err)
+	var open func(ctx context.Context, authURL string) error
+	if ia := delegates.InteractionFrom(ctx); ia != nil {
+		open = ia.Authorize
 	}
-	return result, nil
+	authCtx, cancel := delegates.WithDefaultTimeout(ctx, mcpauth.AuthorizationTimeout)
+	defer cancel()
+	if err := auth.Authorize(authCtx, open); err != nil {
+		return nil, err
+	}
+	return handshake(ctx, client, &mcp.StreamableClientTransport{Endpoint: location, HTTPClient: auth.HTTPClient()})
 }
 
-// ReadResource connects to an MCP server and reads a resource by URI.
-func ReadResource(ctx context.Context, url string, uri string) (*mcp.ReadResourceResult, error) {
-	session, err := connect(ctx, url)
-	if err != nil {
-		return nil, fmt.Errorf("connect to MCP server: %w", err)
-	}
-	defer session.Close()
+// handshake connects client over transport.
+func handshake(ctx context.Context, client *mcp.Client, transport mcp.Transport) (*mcp.ClientSession, error) {
+	ctx, cancel := delegates.WithDefaultTimeout(ctx, DefaultTimeout)
+	defer cancel()
+	return client.Connect(ctx, transport, nil)
+}
 
-	result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{
-		URI: uri,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("read resource %q: %w", uri, err)
+// newTransport returns a stdio transport running the command of an exec:
+// reference, or a Streamable HTTP transport for an HTTP or HTTPS URL. The
+// command inherits the process environment plus bindCtx.Environment; HTTP
+// requests use the transport settings, headers and credentials of bindCtx,
+// through the returned mcpauth.Client, which is nil for exec: references.
+func newTransport(ctx context.Context, location string, bindCtx *delegates.BindingContext) (mcp.Transport, *mcpauth.Client, error) {
+	if execref.IsExec(location) {
+		argv, err := execref.Parse(location)
+		if err != nil {
+			return nil, nil, err
+		}
+		cmd := exec.Command(argv[0], argv[1:]...)
+		cmd.Env = commandEnv(bindCtx)
+		return &mcp.CommandTransport{Command: cmd}, nil, nil
 	}
-	return result, nil
-}
 
-// GetPrompt connects to an MCP server and gets a prompt by name.
-func GetPrompt(ctx context.Context, url string, promptName string, args map[string]string) (*mcp.GetPromptResult, error) {
-	session, err := connect(ctx, url)
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		return nil, nil, fmt.Errorf("MCP source location must be an HTTP or HTTPS URL or an exec: command, got %q", location)
+	}
+	httpClient, err := delegates.HTTPClient(bindCtx)
 	if err != nil {
-		return nil, fmt.Errorf("connect to MCP server: %w", err)
+		return nil, nil, fmt.Errorf("transport config: %w", err)
 	}
-	defer session.Close()
-
-	result, err := session.GetPrompt(ctx, &mcp.GetPromptParams{
-		Name:      promptName,
-		Arguments: args,
-	})
+	auth, err := mcpauth.NewClient(location, httpClient, bindCtx, delegates.CredentialSaverFrom(ctx))
 	if err != nil {
-		return nil, fmt.Errorf("get prompt %q: %w", promptName, err)
+		return nil, nil, err
 	}
-	return result, nil
+	return &mcp.StreamableClientTransport{Endpoint: location, HTTPClient: auth.HTTPClient()}, auth, nil
 }
 
-// connect establishes a Streamable HTTP connection to an MCP server and
-// performs the initialization handshake. Only HTTP and HTTPS URLs are accepted.
-func connect(ctx context.Context, url string) (*mcp.ClientSession, error) {
-	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
-		return nil, fmt.Errorf("MCP source location must be an HTTP or HTTPS URL, got %q", url)
+// commandEnv returns the environment for a stdio server: the current
+// process environment with bindCtx.Environment layered on top.
+func commandEnv(bindCtx *delegates.BindingContext) []string {
+	env := os.Environ()
+	if bindCtx == nil || len(bindCtx.Environment) == 0 {
+		return env
 	}
-
-	transport := &mcp.StreamableClientTransport{Endpoint: url}
-	client := mcp.NewClient(clientInfo(), nil)
-	session, err := client.Connect(ctx, transport, nil)
-	if err != nil {
-		return nil, err
+	keys := make([]string, 0, len(bindCtx.Environment))
+	for k := range bindCtx.Environment {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		env = append(env, k+"="+bindCtx.Environment[k])
 	}
-	return session, nil
+	return env
 }
@@ -8,11 +8,14 @@ import (
 	"github.com/openbindings/openbindings-go"
 )
 
-// Ref prefixes mirror the MCP JSON-RPC method namespaces.
+// Ref prefixes mirror the MCP JSON-RPC method namespaces. Resource refs
+// name a resource URI or a URI template; subscription refs name the
+// resource (or template) whose updates are streamed.
 const (
-	RefPrefixTools     = "tools/"
-	RefPrefixResources = "resources/"
-	RefPrefixPrompts   = "prompts/"
+	RefPrefixTools         = "tools/"
+	RefPrefixResources     = "resources/"
+	RefPrefixSubscriptions = "resources/subscribe/"
+	RefPrefixPrompts       = "prompts/"
 )
 
 // DefaultSourceName is the default source key for MCP sources.
@@ -146,17 +149,8 @@ func ConvertToInterface(discovery *Discovery, sourceLocation string) (openbindin
 			Description: desc,
 		}
 
-		// Build input schema with the URI template.
-		op.Input = map[string]any{
-			"type": "object",
-			"properties": map[string]any{
-				"uriTemplate": map[string]any{
-					"type":        "string",
-					"const":       tmpl.URITemplate,
-					"description": "URI template (RFC 6570)",
-				},
-			},
-		}
+		// The input fills in the variables of the URI template.
+		op.Input = uriTemplateSchema(tmpl.URITemplate)
 
 		iface.Operations[opKey] = op
 
@@ -168,6 +162,17 @@ func ConvertToInterface(discovery *Discovery, sourceLocation string) (openbindin
 		}
 	}
 
+	// Convert resource subscriptions, when the server supports them, to
+	// event operations streaming the resource after each update.
+	if discovery.canSubscribe() {
+		for _, resource := range discovery.Resources {
+			addSubscription(&iface, usedKeys, resource.Name, resource.URI, nil)
+		}
+		for _, tmpl := range discovery.ResourceTemplates {
+			addSubscription(&iface, usedKeys, tmpl.Name, tmpl.URITemplate, uriTemplateSchema(tmpl.URITemplate))
+		}
+	}
+
 	// Convert prompts.
 	for _, prompt := range discovery.Prompts {
 		opKey := delegates.SanitizeKey(prompt.Name)
@@ -203,6 +208,27 @@ func ConvertToInterface(discovery *Discovery, sourceLocation string) (openbindin
 	return iface, nil
 }
 
+// addSubscription adds the event operation subscribing to updates of the
+// resource (or resource template) named name at uri.
+func addSubscription(iface *openbindings.Interface, usedKeys map[string]string, name, uri string, input map[string]any) {
+	opKey := delegates.SanitizeKey(name + "_updates")
+	opKey = resolveKeyCollision(opKey, "subscription", usedKeys)
+	usedKeys[opKey] = "subscription"
+
+	iface.Operations[opKey] = openbindings.Operation{
+		Kind:        openbindings.OperationKindEvent,
+		Description: fmt.Sprintf("Updates of %s, streamed as they are reported", uri),
+		Input:       input,
+	}
+
+	bindingKey := opKey + "." + DefaultSourceName
+	iface.Bindings[bindingKey] = openbindings.BindingEntry{
+		Operation: opKey,
+		Source:    DefaultSourceName,
+		Ref:       RefPrefixSubscriptions + uri,
+	}
+}
+
 // promptArgsToSchema converts MCP prompt arguments to a JSON Schema.
 func promptArgsToSchema(args []*gomcp.PromptArgument) map[string]any {
 	properties := map[string]any{}
@@ -236,8 +262,8 @@ func promptArgsToSchema(args []*gomcp.PromptArgument) map[string]any {
 // share the same name. The first entity to claim a key wins the unprefixed name;
 // subsequent colliders are prefixed with their entity type (e.g., "prompt_echo").
 //
-// Processing order is tools → resources → resource templates → prompts, so tools
-// win unprefixed names by convention.
+// Processing order is tools → resources → resource templates → subscriptions
+// → prompts, so tools win unprefixed names by convention.
 func resolveKeyCollision(key string, entityType string, used map[string]string) string {
 	if _, taken := used[key]; !taken {
 		return key
@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
@@ -127,6 +128,73 @@ func TestConvertToInterface_ResourcesOnly(t *testing.T) {
 	}
 }
 
+func TestConvertToInterface_ResourceTemplatesAndSubscriptions(t *testing.T) {
+	discovery := &Discovery{
+		Resources: []*gomcp.Resource{
+			{Name: "readme", URI: "file:///README.md"},
+		},
+		ResourceTemplates: []*gomcp.ResourceTemplate{
+			{Name: "issue", URITemplate: "tracker://{project}/issues/{id}{?fields}"},
+		},
+		Capabilities: &gomcp.ServerCapabilities{
+			Resources: &gomcp.ResourceCapabilities{Subscribe: true},
+		},
+	}
+
+	iface, err := ConvertToInterface(discovery, "https://test-server.example.com/mcp")
+	if err != nil {
+		t.Fatalf("ConvertToInterface: %v", err)
+	}
+
+	issue := iface.Operations["issue"]
+	wantInput := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"project": map[string]any{"type": "string"},
+			"id":      map[string]any{"type": "string"},
+			"fields":  map[string]any{"type": "string"},
+		},
+		"required": []string{"project", "id"},
+	}
+	if !reflect.DeepEqual(issue.Input, wantInput) {
+		t.Errorf("template input = %#v, want %#v", issue.Input, wantInput)
+	}
+
+	tests := []struct {
+		op    string
+		ref   string
+		input bool
+	}{
+		{"readme_updates", "resources/subscribe/file:///README.md", false},
+		{"issue_updates", "resources/subscribe/tracker://{project}/issues/{id}{?fields}", true},
+	}
+	for _, tt := range tests {
+		op, ok := iface.Operations[tt.op]
+		if !ok {
+			t.Fatalf("missing operation %q", tt.op)
+		}
+		if op.Kind != "event" {
+			t.Errorf("%s kind = %q, want event", tt.op, op.Kind)
+		}
+		if (op.Input != nil) != tt.input {
+			t.Errorf("%s input = %v", tt.op, op.Input)
+		}
+		if ref := iface.Bindings[tt.op+"."+DefaultSourceName].Ref; ref != tt.ref {
+			t.Errorf("%s ref = %q, want %q", tt.op, ref, tt.ref)
+		}
+	}
+
+	// Without the subscribe capability, no subscription operations are added.
+	discovery.Capabilities = nil
+	iface, err = ConvertToInterface(discovery, "https://test-server.example.com/mcp")
+	if err != nil {
+		t.Fatalf("ConvertToInterface: %v", err)
+	}
+	if len(iface.Operations) != 2 {
+		t.Errorf("operations = %d, want 2", len(iface.Operations))
+	}
+}
+
 func TestConvertToInterface_PromptsOnly(t *testing.T) {
 	discovery := &Discovery{
 		Prompts: []*gomcp.Prompt{
@@ -10,13 +10,16 @@ import (
 	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/openbindings/cli/internal/delegates"
+	"github.com/openbindings/cli/internal/execref"
 )
 
 // ExecuteInput is the input for MCP operation execution.
 type ExecuteInput struct {
-	URL   string // HTTP/HTTPS URL of the MCP server
-	Ref   string // MCP ref (e.g., "tools/get_weather", "resources/file:///...", "prompts/code_review")
-	Input any    // Operation input data
+	Location string                    // HTTP/HTTPS URL or exec: command of the MCP server
+	Ref      string                    // MCP ref (e.g., "tools/get_weather", "resources/file:///...", "prompts/code_review")
+	Input    any                       // Operation input data
+	Context  *delegates.BindingContext // Runtime context (transport settings, environment for exec: servers)
+	Sessions *SessionPool              // Sessions to reuse; nil connects for this call only
 }
 
 // ExecuteOutput is the output from MCP operation execution.
@@ -43,15 +46,43 @@ func Execute(ctx context.Context, input ExecuteInput) ExecuteOutput {
 		}
 	}
 
-	// ParseRef guarantees entityType is one of "tools", "resources", or "prompts".
+	if !execref.IsExec(input.Location) {
+		if _, err := delegates.HTTPClient(input.Context); err != nil {
+			return ExecuteOutput{
+				Status:     1,
+				DurationMs: time.Since(start).Milliseconds(),
+				Error: &delegates.Error{
+					Code:    "transport_config_failed",
+					Message: err.Error(),
+				},
+			}
+		}
+	}
+
+	sessions := input.Sessions
+	if sessions == nil {
+		sessions = NewSessionPool()
+		defer sessions.Close()
+	}
+
+	// ParseRef guarantees entityType is one of "tools", "resources",
+	// "resources/subscribe", or "prompts".
 	var output ExecuteOutput
 	switch entityType {
 	case "tools":
-		output = executeTool(ctx, input.URL, name, input.Input)
+		output = executeTool(ctx, sessions, input.Location, input.Context, name, input.Input)
 	case "resources":
-		output = executeResource(ctx, input.URL, name)
+		output = executeResource(ctx, sessions, input.Location, input.Context, name, input.Input)
+	case "resources/subscribe":
+		output = ExecuteOutput{
+			Status: 1,
+			Error: &delegates.Error{
+				Code:    "invalid_ref",
+				Message: fmt.Sprintf("%q is a subscription; subscribe to it to receive updates", input.Ref),
+			},
+		}
 	case "prompts":
-		output = executePrompt(ctx, input.URL, name, input.Input)
+		output = executePrompt(ctx, sessions, input.Location, input.Context, name, input.Input)
 	}
 
 	output.DurationMs = time.Since(start).Milliseconds()
@@ -64,6 +95,7 @@ func Execute(ctx context.Context, input ExecuteInput) ExecuteOutput {
 //
 //	"tools/get_weather"              → ("tools", "get_weather", nil)
 //	"resources/file:///src/main.rs"  → ("resources", "file:///src/main.rs", nil)
+//	"resources/subscribe/file:///x"  → ("resources/subscribe", "file:///x", nil)
 //	"prompts/code_review"            → ("prompts", "code_review", nil)
 func ParseRef(ref string) (entityType string, name string, err error) {
 	ref = strings.TrimSpace(ref)
@@ -71,7 +103,8 @@ func ParseRef(ref string) (entityType string, name string, err error) {
 		return "", "", fmt.Errorf("empty MCP ref")
 	}
 
-	for _, prefix := range []string{RefPrefixTools, RefPrefixResources, RefPrefixPrompts} {
+	// Subscriptions share the resources/ namespace, so they are matched first.
+	for _, prefix := range []string{RefPrefixTools, RefPrefixSubscriptions, RefPrefixResources, RefPrefixPrompts} {
 		if strings.HasPrefix(ref, prefix) {
 			name := strings.TrimPrefix(ref, prefix)
 			if name == "" {
@@ -88,7 +121,7 @@ func ParseRef(ref string) (entityType string, name string, err error) {
 }
 
 // executeTool calls a tool on the MCP server.
-func executeTool(ctx context.Context, url string, toolName string, input any) ExecuteOutput {
+func executeTool(ctx context.Context, sessions *SessionPool, location string, bindCtx *delegates.BindingContext, toolName string, input any) ExecuteOutput {
 	args, ok := delegates.ToStringAnyMap(input)
 	if input != nil && !ok {
 		return ExecuteOutput{
@@ -105,7 +138,7 @@ func executeTool(ctx context.Context, url string, toolName string, input any) Ex
 		args = map[string]any{}
 	}
 
-	result, err := CallTool(ctx, url, toolName, args)
+	result, err := sessions.CallTool(ctx, location, bindCtx, toolName, args)
 	if err != nil {
 		return ExecuteOutput{
 			Status: 1,
@@ -119,9 +152,21 @@ func executeTool(ctx context.Context, url string, toolName string, input any) Ex
 	return callToolResultToOutput(result)
 }
 
-// executeResource reads a resource from the MCP server.
-func executeResource(ctx context.Context, url string, uri string) ExecuteOutput {
-	result, err := ReadResource(ctx, url, uri)
+// executeResource reads a resource from the MCP server. When uri is a
+// resource template, it is first expanded with the input.
+func executeResource(ctx context.Context, sessions *SessionPool, location string, bindCtx *delegates.BindingContext, uri string, input any) ExecuteOutput {
+	uri, err := resourceURI(uri, input)
+	if err != nil {
+		return ExecuteOutput{
+			Status: 1,
+			Error: &delegates.Error{
+				Code:    "invalid_input",
+				Message: err.Error(),
+			},
+		}
+	}
+
+	result, err := sessions.ReadResource(ctx, location, bindCtx, uri)
 	if err != nil {
 		return ExecuteOutput{
 			Status: 1,
@@ -135,8 +180,65 @@ func executeResource(ctx context.Context, url string, uri string) ExecuteOutput
 	return readResourceResultToOutput(result)
 }
 
+// resourceURI returns the URI of the resource a ref names: uri itself, or
+// its expansion with input when it is a resource template.
+func resourceURI(uri string, input any) (string, error) {
+	if !isURITemplate(uri) {
+		return uri, nil
+	}
+	return expandURITemplate(uri, input)
+}
+
+// Subscribe subscribes to updates of the resource named by a
+// resources/subscribe ref, expanding a resource template with the input.
+// The returned channel receives the resource's contents each time the
+// server reports an update, as {"uri", "contents"}, and is closed when ctx
+// is done or the session ends.
+func Subscribe(ctx context.Context, input ExecuteInput) (<-chan delegates.StreamEvent, error) {
+	entityType, name, err := ParseRef(input.Ref)
+	if err != nil {
+		return nil, err
+	}
+	if entityType != "resources/subscribe" {
+		return nil, fmt.Errorf("MCP ref %q is not a subscription (want %q)", input.Ref, RefPrefixSubscriptions+"<uri>")
+	}
+	uri, err := resourceURI(name, input.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := input.Sessions
+	if sessions == nil {
+		sessions = NewSessionPool()
+	}
+	ch, err := sessions.Subscribe(ctx, input.Location, input.Context, uri)
+	if err != nil {
+		if input.Sessions == nil {
+			sessions.Close()
+		}
+		return nil, err
+	}
+	if input.Sessions != nil {
+		return ch, nil
+	}
+
+	// Close the temporary session once the stream ends.
+	out := make(chan delegates.StreamEvent)
+	go func() {
+		defer close(out)
+		defer sessions.Close()
+		for ev := range ch {
+			select {
+			case out <- ev:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return out, nil
+}
+
 // executePrompt gets a prompt from the MCP server.
-func executePrompt(ctx context.Context, url string, promptName string, input any) ExecuteOutput {
+func executePrompt(ctx context.Context, sessions *SessionPool, location string, bindCtx *delegates.BindingContext, promptName string, input any) ExecuteOutput {
 	args, err := toStringStringMap(input)
 	if err != nil {
 		return ExecuteOutput{
@@ -148,7 +250,7 @@ func executePrompt(ctx context.Context, url string, promptName string, input any
 		}
 	}
 
-	result, err := GetPrompt(ctx, url, promptName, args)
+	result, err := sessions.GetPrompt(ctx, location, bindCtx, promptName, args)
 	if err != nil {
 		return ExecuteOutput{
 			Status: 1,
@@ -21,6 +21,7 @@ func TestParseRef(t *testing.T) {
 		{"prompts/code_review", "prompts", "code_review", false},
 		{"tools/my-tool", "tools", "my-tool", false},
 		{"resources/https://example.com/data", "resources", "https://example.com/data", false},
+		{"resources/subscribe/file:///src/main.rs", "resources/subscribe", "file:///src/main.rs", false},
 
 		// Invalid refs.
 		{"", "", "", true},
@@ -30,6 +31,7 @@ func TestParseRef(t *testing.T) {
 		{"tools/", "", "", true},
 		{"resources/", "", "", true},
 		{"prompts/", "", "", true},
+		{"resources/subscribe/", "", "", true},
 	}
 
 	for _, tt := range tests {
@@ -13,18 +13,30 @@ import (
 // Targets the 2025-11-25 MCP spec revision. Supported features:
 //   - tools/list, tools/call (incl. structuredContent and outputSchema)
 //   - resources/list, resources/read
-//   - resources/templates/list
+//   - resources/templates/list (templates are expanded from the input)
+//   - resources/subscribe, resources/unsubscribe (as event operations)
 //   - prompts/list, prompts/get
+//   - progress and log notifications, elicitation and sampling requests
+//     (see delegates.Interaction)
+//   - OAuth authorization of HTTP servers (see package mcpauth)
 //
-// Not yet supported: resource subscriptions, sampling, icons, elicitation.
+// Not yet supported: icons.
 const FormatToken = "mcp@2025-11-25"
 
-// Handler implements the MCP binding format handler delegate.
-type Handler struct{}
+// Handler implements the MCP binding format handler delegate. Sessions
+// opened by ExecuteOperation are kept for reuse until Close.
+type Handler struct {
+	sessions *SessionPool
+}
 
 // New creates a new MCP handler.
 func New() *Handler {
-	return &Handler{}
+	return &Handler{sessions: NewSessionPool()}
+}
+
+// Close ends the MCP sessions kept open by the handler.
+func (h *Handler) Close() error {
+	return h.sessions.Close()
 }
 
 // GetInfo returns identity and metadata about this delegate.
@@ -40,7 +52,7 @@ func (h *Handler) ListFormats() []delegates.FormatInfo {
 	return []delegates.FormatInfo{
 		{
 			Token:       FormatToken,
-			Description: "MCP servers (tools, resources, prompts) via Streamable HTTP transport",
+			Description: "MCP servers (tools, resources, prompts) via Streamable HTTP or stdio (exec:) transport",
 		},
 	}
 }
@@ -56,14 +68,15 @@ func (h *Handler) CreateInterface(source delegates.Source) (openbindings.Interfa
 }
 
 // ExecuteOperation executes an MCP operation via the appropriate JSON-RPC method.
+// Requests are bounded by DefaultTimeout unless ctx has a deadline; the
+// time spent waiting for the user to authorize the server is not counted.
 func (h *Handler) ExecuteOperation(ctx context.Context, input delegates.ExecuteInput) delegates.ExecuteOutput {
-	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
-	defer cancel()
-
 	result := Execute(ctx, ExecuteInput{
-		URL:   input.Source.Location,
-		Ref:   input.Ref,
-		Input: input.Input,
+		Location: input.Source.Location,
+		Ref:      input.Ref,
+		Input:    input.Input,
+		Context:  input.Context,
+		Sessions: h.sessions,
 	})
 
 	return delegates.ExecuteOutput{
@@ -74,6 +87,19 @@ func (h *Handler) ExecuteOperation(ctx context.Context, input delegates.ExecuteI
 	}
 }
 
+// SubscribeOperation implements the delegates.StreamHandler interface for
+// resource subscriptions (resources/subscribe/ refs). The subscription uses
+// the handler's pooled session for the server.
+func (h *Handler) SubscribeOperation(ctx context.Context, input delegates.ExecuteInput) (<-chan delegates.StreamEvent, error) {
+	return Subscribe(ctx, ExecuteInput{
+		Location: input.Source.Location,
+		Ref:      input.Ref,
+		Input:    input.Input,
+		Context:  input.Context,
+		Sessions: h.sessions,
+	})
+}
+
 // DiscoverSource implements the delegates.SourceDiscoverer interface.
 // It connects to the MCP server once and returns both the raw content
 // (for hashing/drift detection) and the derived interface (for merge).
@@ -85,12 +111,11 @@ func (h *Handler) DiscoverSource(ctx context.Context, source delegates.Source) (
 // converts them to an OpenBindings interface, and serializes the discovery
 // data for content hashing. Shared by CreateInterface and DiscoverSource.
 func (h *Handler) discoverAndConvert(ctx context.Context, source delegates.Source) ([]byte, openbindings.Interface, error) {
-	url := source.Location
-	if url == "" {
-		return nil, openbindings.Interface{}, fmt.Errorf("MCP source requires an HTTP or HTTPS URL")
+	if source.Location == "" {
+		return nil, openbindings.Interface{}, fmt.Errorf("MCP source requires an HTTP or HTTPS URL or an exec: command")
 	}
 
-	discovery, err := Discover(ctx, url)
+	discovery, err := Discover(ctx, source.Location, nil)
 	if err != nil {
 		return nil, openbindings.Interface{}, fmt.Errorf("MCP discovery: %w", err)
 	}
@@ -195,6 +220,10 @@ func canonicalDiscoveryJSON(discovery *Discovery) ([]byte, error) {
 		data["resourceTemplates"] = templates
 	}
 
+	if discovery.canSubscribe() {
+		data["resourceSubscriptions"] = true
+	}
+
 	if len(discovery.Prompts) > 0 {
 		prompts := make([]any, len(discovery.Prompts))
 		for i, p := range discovery.Prompts {
@@ -0,0 +1,461 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// SessionPool keeps one MCP client session open per server so that
+// consecutive calls reuse it instead of reconnecting (and, for exec:
+// servers, restarting the process). Stateful servers keep their session
+// state across calls, and long calls can report progress.
+//
+// Sessions are keyed by location and by the binding context settings that
+// shape the connection: the environment of exec: servers and the transport
+// settings, headers and credentials of HTTP servers. A session that ends, for example because the
+// server exited, is dropped and reconnected on next use.
+//
+// While a call runs, the server's progress notifications and log messages
+// and its elicitation and sampling requests are routed to the
+// delegates.Interaction in the call's context.
+type SessionPool struct {
+	mu       sync.Mutex
+	sessions map[string]*pooledSession
+	closed   bool
+
+	nextToken atomic.Int64
+}
+
+// pooledSession is a session shared by the calls made to one server.
+type pooledSession struct {
+	ready   chan struct{} // closed once the connection attempt finishes
+	done    chan struct{} // closed once the session has ended
+	session *mcp.ClientSession
+	err     error
+
+	mu       sync.Mutex
+	calls    []activeCall               // in-flight calls, oldest first
+	watchers map[string][]chan struct{} // update signals of the subscribers to each resource URI
+}
+
+// activeCall is an in-flight call and the Interaction of its context.
+type activeCall struct {
+	token string // progress token sent with the request
+	ia    *delegates.Interaction
+}
+
+// NewSessionPool returns an empty session pool.
+func NewSessionPool() *SessionPool {
+	return &SessionPool{sessions: map[string]*pooledSession{}}
+}
+
+// Close ends every pooled session. Calls made afterwards fail.
+func (p *SessionPool) Close() error {
+	p.mu.Lock()
+	sessions := p.sessions
+	p.sessions = map[string]*pooledSession{}
+	p.closed = true
+	p.mu.Unlock()
+
+	for _, ps := range sessions {
+		<-ps.ready
+		if ps.session != nil {
+			_ = ps.session.Close()
+		}
+	}
+	return nil
+}
+
+// CallTool calls a tool by name.
+func (p *SessionPool) CallTool(ctx context.Context, location string, bindCtx *delegates.BindingContext, toolName string, args map[string]any) (*mcp.CallToolResult, error) {
+	var result *mcp.CallToolResult
+	err := p.call(ctx, location, bindCtx, func(ctx context.Context, session *mcp.ClientSession, token string) error {
+		params := &mcp.CallToolParams{
+			Name:      toolName,
+			Arguments: args,
+		}
+		params.SetProgressToken(token)
+		var err error
+		result, err = session.CallTool(ctx, params)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("call tool %q: %w", toolName, err)
+	}
+	return result, nil
+}
+
+// ReadResource reads a resource by URI.
+func (p *SessionPool) ReadResource(ctx context.Context, location string, bindCtx *delegates.BindingContext, uri string) (*mcp.ReadResourceResult, error) {
+	var result *mcp.ReadResourceResult
+	err := p.call(ctx, location, bindCtx, func(ctx context.Context, session *mcp.ClientSession, token string) error {
+		params := &mcp.ReadResourceParams{
+			URI: uri,
+		}
+		params.SetProgressToken(token)
+		var err error
+		result, err = session.ReadResource(ctx, params)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read resource %q: %w", uri, err)
+	}
+	return result, nil
+}
+
+// GetPrompt gets a prompt by name.
+func (p *SessionPool) GetPrompt(ctx context.Context, location string, bindCtx *delegates.BindingContext, promptName string, args map[string]string) (*mcp.GetPromptResult, error) {
+	var result *mcp.GetPromptResult
+	err := p.call(ctx, location, bindCtx, func(ctx context.Context, session *mcp.ClientSession, token string) error {
+		params := &mcp.GetPromptParams{
+			Name:      promptName,
+			Arguments: args,
+		}
+		params.SetProgressToken(token)
+		var err error
+		result, err = session.GetPrompt(ctx, params)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get prompt %q: %w", promptName, err)
+	}
+	return result, nil
+}
+
+// Subscribe subscribes to updates of the resource at uri. After each update
+// notification the resource is read again and its contents are sent on the
+// returned channel as {"uri", "contents"}. Subscribers to the same resource
+// share one server-side subscription, which is cancelled when the last of
+// them ends. The channel is closed when ctx is done or the session ends.
+func (p *SessionPool) Subscribe(ctx context.Context, location string, bindCtx *delegates.BindingContext, uri string) (<-chan delegates.StreamEvent, error) {
+	ps, err := p.session(ctx, location, bindCtx)
+	if err != nil {
+		return nil, fmt.Errorf("connect to MCP server: %w", err)
+	}
+
+	updated := make(chan struct{}, 1) // holds at most one pending update
+	if first := ps.watch(uri, updated); first {
+		if err := ps.session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}); err != nil {
+			ps.unwatch(uri, updated)
+			return nil, fmt.Errorf("subscribe to resource %q: %w", uri, err)
+		}
+	}
+
+	ch := make(chan delegates.StreamEvent, 16)
+	go func() {
+		defer close(ch)
+		defer func() {
+			if last := ps.unwatch(uri, updated); last {
+				unsubCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				_ = ps.session.Unsubscribe(unsubCtx, &mcp.UnsubscribeParams{URI: uri})
+			}
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ps.done:
+				if ctx.Err() == nil {
+					ch <- delegates.StreamEvent{Error: &delegates.Error{Code: "session_closed", Message: "MCP session ended"}}
+				}
+				return
+			case <-updated:
+			}
+
+			ev := delegates.StreamEvent{}
+			result, err := ps.session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				ev.Error = &delegates.Error{Code: "resource_read_failed", Message: err.Error()}
+			} else {
+				ev.Data = map[string]any{"uri": uri, "contents": readResourceResultToOutput(result).Output}
+			}
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// call runs fn on the pooled session for location. The context passed to
+// fn is bounded by DefaultTimeout unless ctx has a deadline; connecting,
+// which may wait for the user to authorize, is not. The progress token
+// passed to fn identifies the call in progress notifications.
+func (p *SessionPool) call(ctx context.Context, location string, bindCtx *delegates.BindingContext, fn func(ctx context.Context, session *mcp.ClientSession, token string) error) error {
+	ps, err := p.session(ctx, location, bindCtx)
+	if err != nil {
+		return fmt.Errorf("connect to MCP server: %w", err)
+	}
+
+	ctx, cancel := delegates.WithDefaultTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	token := fmt.Sprintf("ob-%d", p.nextToken.Add(1))
+	ps.begin(activeCall{token: token, ia: delegates.InteractionFrom(ctx)})
+	defer ps.end(token)
+
+	return fn(ctx, ps.session, token)
+}
+
+// session returns the open session for location, connecting if needed.
+// Concurrent callers for the same server share one connection attempt.
+func (p *SessionPool) session(ctx context.Context, location string, bindCtx *delegates.BindingContext) (*pooledSession, error) {
+	key, err := sessionKey(location, bindCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("session pool is closed")
+	}
+	ps, ok := p.sessions[key]
+	if !ok {
+		ps = &pooledSession{ready: make(chan struct{}), done: make(chan struct{})}
+		p.sessions[key] = ps
+	}
+	p.mu.Unlock()
+
+	if ok {
+		select {
+		case <-ps.ready:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if ps.err != nil {
+			return nil, ps.err
+		}
+		return ps, nil
+	}
+
+	// The session outlives this call, so only the handshake is bound to ctx.
+	ps.session, ps.err = connect(ctx, location, bindCtx, ps.clientOptions())
+	close(ps.ready)
+	if ps.err != nil {
+		p.drop(key, ps)
+		return nil, ps.err
+	}
+
+	if init := ps.session.InitializeResult(); init != nil && init.Capabilities.Logging != nil {
+		logCtx, cancel := delegates.WithDefaultTimeout(ctx, DefaultTimeout)
+		_ = ps.session.SetLoggingLevel(logCtx, &mcp.SetLoggingLevelParams{Level: "info"})
+		cancel()
+	}
+	go func() {
+		_ = ps.session.Wait()
+		close(ps.done)
+		p.drop(key, ps)
+	}()
+	return ps, nil
+}
+
+// drop removes ps from the pool if it is still the session for key.
+func (p *SessionPool) drop(key string, ps *pooledSession) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.sessions[key] == ps {
+		delete(p.sessions, key)
+	}
+}
+
+// sessionKey identifies the connection for location under bindCtx.
+func sessionKey(location string, bindCtx *delegates.BindingContext) (string, error) {
+	var conn struct {
+		Environment map[string]string          `json:"environment,omitempty"`
+		Transport   *delegates.TransportConfig `json:"transport,omitempty"`
+		Headers     map[string]string          `json:"headers,omitempty"`
+		Credentials *delegates.Credentials     `json:"credentials,omitempty"`
+	}
+	if bindCtx != nil {
+		conn.Environment = bindCtx.Environment
+		conn.Transport = bindCtx.Transport
+		conn.Headers = bindCtx.Headers
+		conn.Credentials = bindCtx.Credentials
+	}
+	b, err := json.Marshal(conn) // map keys are sorted
+	if err != nil {
+		return "", err
+	}
+	return location + "\x00" + string(b), nil
+}
+
+func (ps *pooledSession) begin(c activeCall) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.calls = append(ps.calls, c)
+}
+
+func (ps *pooledSession) end(token string) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.calls = slices.DeleteFunc(ps.calls, func(c activeCall) bool { return c.token == token })
+}
+
+// watch registers a subscriber's update signal for uri and reports whether
+// it is the first subscriber to that resource.
+func (ps *pooledSession) watch(uri string, updated chan struct{}) bool {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.watchers == nil {
+		ps.watchers = map[string][]chan struct{}{}
+	}
+	ps.watchers[uri] = append(ps.watchers[uri], updated)
+	return len(ps.watchers[uri]) == 1
+}
+
+// unwatch removes a subscriber's update signal and reports whether it was
+// the last subscriber to uri.
+func (ps *pooledSession) unwatch(uri string, updated chan struct{}) bool {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.watchers[uri] = slices.DeleteFunc(ps.watchers[uri], func(c chan struct{}) bool { return c == updated })
+	if len(ps.watchers[uri]) > 0 {
+		return false
+	}
+	delete(ps.watchers, uri)
+	return true
+}
+
+// notifyUpdated signals the subscribers to uri, coalescing updates that
+// arrive before a subscriber has read the resource again.
+func (ps *pooledSession) notifyUpdated(uri string) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	for _, updated := range ps.watchers[uri] {
+		select {
+		case updated <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// interactions returns the Interactions of the in-flight calls, newest
+// first. Server requests that are not tied to a call go to the newest call
+// able to handle them.
+func (ps *pooledSession) interactions() []*delegates.Interaction {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	var out []*delegates.Interaction
+	for i := len(ps.calls) - 1; i >= 0; i-- {
+		if ia := ps.calls[i].ia; ia != nil {
+			out = append(out, ia)
+		}
+	}
+	return out
+}
+
+// interactionFor returns the Interaction of the call with the given progress
+// token, or nil.
+func (ps *pooledSession) interactionFor(token any) *delegates.Interaction {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	for _, c := range ps.calls {
+		if c.token == fmt.Sprint(token) {
+			return c.ia
+		}
+	}
+	return nil
+}
+
+// clientOptions routes server-initiated messages to the in-flight calls
+// and resource update notifications to the subscribers.
+func (ps *pooledSession) clientOptions() *mcp.ClientOptions {
+	return &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			ps.notifyUpdated(req.Params.URI)
+		},
+		ProgressNotificationHandler: func(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+			if ia := ps.interactionFor(req.Params.ProgressToken); ia != nil && ia.Progress != nil {
+				ia.Progress(delegates.Progress{
+					Progress: req.Params.Progress,
+					Total:    req.Params.Total,
+					Message:  req.Params.Message,
+				})
+			}
+		},
+		LoggingMessageHandler: func(_ context.Context, req *mcp.LoggingMessageRequest) {
+			msg := delegates.LogMessage{
+				Level:  string(req.Params.Level),
+				Logger: req.Params.Logger,
+				Data:   req.Params.Data,
+			}
+			for _, ia := range ps.interactions() {
+				if ia.Log != nil {
+					ia.Log(msg)
+				}
+			}
+		},
+		ElicitationHandler: func(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			for _, ia := range ps.interactions() {
+				if ia.Elicit != nil {
+					return elicit(ctx, ia, req.Params)
+				}
+			}
+			return &mcp.ElicitResult{Action: delegates.ElicitDecline}, nil
+		},
+		CreateMessageHandler: func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			for _, ia := range ps.interactions() {
+				if ia.Sample != nil {
+					return sample(ctx, ia, req.Params)
+				}
+			}
+			return nil, fmt.Errorf("sampling is not available: no sampling delegate is configured")
+		},
+	}
+}
+
+// elicit asks the user through ia for the input described by params.
+func elicit(ctx context.Context, ia *delegates.Interaction, params *mcp.ElicitParams) (*mcp.ElicitResult, error) {
+	var schema map[string]any
+	if err := remarshal(params.RequestedSchema, &schema); err != nil {
+		return nil, fmt.Errorf("elicitation schema: %w", err)
+	}
+	res, err := ia.Elicit(ctx, delegates.ElicitRequest{Message: params.Message, Schema: schema})
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ElicitResult{Action: res.Action, Content: res.Content}, nil
+}
+
+// sample forwards a sampling request to ia and converts its answer.
+func sample(ctx context.Context, ia *delegates.Interaction, params *mcp.CreateMessageParams) (*mcp.CreateMessageResult, error) {
+	var req map[string]any
+	if err := remarshal(params, &req); err != nil {
+		return nil, fmt.Errorf("sampling request: %w", err)
+	}
+	res, err := ia.Sample(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var result mcp.CreateMessageResult
+	if err := remarshal(res, &result); err != nil {
+		return nil, fmt.Errorf("sampling result: %w", err)
+	}
+	return &result, nil
+}
+
+// remarshal converts v to out through JSON.
+func remarshal(v any, out any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
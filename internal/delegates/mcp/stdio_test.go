@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// buildStdioServer compiles testdata/stdioserver and returns an exec:
+// location that runs it.
+func buildStdioServer(t *testing.T) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("builds a fixture binary")
+	}
+	bin := filepath.Join(t.TempDir(), "stdioserver")
+	out, err := exec.Command("go", "build", "-o", bin, "./testdata/stdioserver").CombinedOutput()
+	if err != nil {
+		t.Fatalf("build fixture: %v\n%s", err, out)
+	}
+	return "exec:" + bin
+}
+
+func TestStdioServer(t *testing.T) {
+	location := buildStdioServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	t.Run("discover", func(t *testing.T) {
+		disc, err := Discover(ctx, location, nil)
+		if err != nil {
+			t.Fatalf("Discover: %v", err)
+		}
+		if disc.ServerInfo == nil || disc.ServerInfo.Name != "fixture" {
+			t.Errorf("server info = %+v", disc.ServerInfo)
+		}
+		if len(disc.Tools) != 2 || len(disc.Resources) != 1 || len(disc.ResourceTemplates) != 1 || len(disc.Prompts) != 1 {
+			t.Errorf("discovered %d tools, %d resources, %d templates, %d prompts; want 2, 1, 1, 1",
+				len(disc.Tools), len(disc.Resources), len(disc.ResourceTemplates), len(disc.Prompts))
+		}
+		if !disc.canSubscribe() {
+			t.Error("expected the resource subscription capability")
+		}
+	})
+
+	bindCtx := &delegates.BindingContext{Environment: map[string]string{"FIXTURE_GREETING": "hi"}}
+	tests := []struct {
+		ref   string
+		input any
+		want  any
+	}{
+		{"tools/greet", map[string]any{"name": "Ada"}, map[string]any{"message": "hi, Ada"}},
+		{"resources/fixture://greeting", nil, "hi"},
+		{"resources/fixture://greeting/{name}", map[string]any{"name": "Ada"}, "hi, Ada"},
+		{"prompts/welcome", map[string]any{"name": "Ada"}, map[string]any{
+			"messages": []any{map[string]any{"role": "user", "content": map[string]any{"type": "text", "text": "hi, Ada"}}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			out := Execute(ctx, ExecuteInput{Location: location, Ref: tt.ref, Input: tt.input, Context: bindCtx})
+			if out.Error != nil {
+				t.Fatalf("Execute: %+v", out.Error)
+			}
+			if !reflect.DeepEqual(out.Output, tt.want) {
+				t.Errorf("output = %#v, want %#v", out.Output, tt.want)
+			}
+		})
+	}
+}
+
+func TestStdioServerSubscription(t *testing.T) {
+	location := buildStdioServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	sessions := NewSessionPool()
+	defer sessions.Close()
+
+	subCtx, stop := context.WithCancel(ctx)
+	ch, err := Subscribe(subCtx, ExecuteInput{Location: location, Ref: "resources/subscribe/fixture://greeting", Sessions: sessions})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	if out := Execute(ctx, ExecuteInput{Location: location, Ref: "tools/touch", Sessions: sessions}); out.Error != nil {
+		t.Fatalf("touch: %+v", out.Error)
+	}
+	select {
+	case ev := <-ch:
+		want := map[string]any{"uri": "fixture://greeting", "contents": "hello"}
+		if ev.Error != nil || !reflect.DeepEqual(ev.Data, want) {
+			t.Errorf("event = %+v, want %v", ev, want)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for an update")
+	}
+
+	stop()
+	for range ch {
+	}
+}
+
+func TestNewTransportRejectsUnknownLocation(t *testing.T) {
+	if _, _, err := newTransport(context.Background(), "localhost:8080", nil); err == nil {
+		t.Error("expected an error for a location that is neither a URL nor exec:")
+	}
+}
@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yosida95/uritemplate/v3"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// templateExpr matches an RFC 6570 expression such as "{id}" or "{?q,limit}".
+var templateExpr = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// templateVar is a variable of a URI template.
+type templateVar struct {
+	name     string
+	explode  bool // "{list*}": the value is a list
+	optional bool // in a query expression ("{?x}" or "{&x}")
+}
+
+// isURITemplate reports whether s contains template expressions.
+func isURITemplate(s string) bool {
+	return templateExpr.MatchString(s)
+}
+
+// templateVars returns the variables of a URI template in order of first
+// appearance.
+func templateVars(tmpl string) []templateVar {
+	var vars []templateVar
+	seen := map[string]bool{}
+	for _, m := range templateExpr.FindAllStringSubmatch(tmpl, -1) {
+		expr := m[1]
+		optional := false
+		if expr != "" && strings.ContainsRune("+#./;?&", rune(expr[0])) {
+			optional = expr[0] == '?' || expr[0] == '&'
+			expr = expr[1:]
+		}
+		for _, spec := range strings.Split(expr, ",") {
+			v := templateVar{optional: optional}
+			if name, ok := strings.CutSuffix(spec, "*"); ok {
+				spec, v.explode = name, true
+			}
+			v.name, _, _ = strings.Cut(spec, ":") // drop prefix modifiers ("{id:3}")
+			if v.name == "" || seen[v.name] {
+				continue
+			}
+			seen[v.name] = true
+			vars = append(vars, v)
+		}
+	}
+	return vars
+}
+
+// uriTemplateSchema returns the input schema of a resource template
+// operation: one property per template variable. Query variables are
+// optional; all others are required.
+func uriTemplateSchema(tmpl string) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	for _, v := range templateVars(tmpl) {
+		if v.explode {
+			properties[v.name] = map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+		} else {
+			properties[v.name] = map[string]any{"type": "string"}
+		}
+		if !v.optional {
+			required = append(required, v.name)
+		}
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// expandURITemplate fills in tmpl from the fields of input. Scalars are
+// formatted as strings and arrays as lists.
+func expandURITemplate(tmpl string, input any) (string, error) {
+	args, ok := delegates.ToStringAnyMap(input)
+	if input != nil && !ok {
+		return "", fmt.Errorf("resource template input must be an object, got %T", input)
+	}
+
+	var missing []string
+	for _, v := range templateVars(tmpl) {
+		if _, ok := args[v.name]; !ok && !v.optional {
+			missing = append(missing, v.name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", fmt.Errorf("missing template variables: %s", strings.Join(missing, ", "))
+	}
+
+	t, err := uritemplate.New(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("parse URI template %q: %w", tmpl, err)
+	}
+	values := uritemplate.Values{}
+	for name, val := range args {
+		switch val := val.(type) {
+		case nil:
+		case []any:
+			items := make([]string, len(val))
+			for i, item := range val {
+				items[i] = fmt.Sprint(item)
+			}
+			values.Set(name, uritemplate.List(items...))
+		default:
+			values.Set(name, uritemplate.String(fmt.Sprint(val)))
+		}
+	}
+	return t.Expand(values)
+}
@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"testing"
+)
+
+func TestExpandURITemplate(t *testing.T) {
+	tests := []struct {
+		tmpl    string
+		input   any
+		want    string
+		wantErr bool
+	}{
+		{"tracker://{project}/issues/{id}", map[string]any{"project": "ob", "id": float64(42)}, "tracker://ob/issues/42", false},
+		{"search://items{?q,limit}", map[string]any{"q": "a b"}, "search://items?q=a%20b", false},
+		{"file:///{+path}", map[string]any{"path": "src/main.go"}, "file:///src/main.go", false},
+		{"tags://{list*}", map[string]any{"list": []any{"a", "b"}}, "tags://a,b", false},
+		{"tracker://{project}/issues/{id}", map[string]any{"project": "ob"}, "", true},
+		{"tracker://{project}", "ob", "", true},
+	}
+	for _, tt := range tests {
+		got, err := expandURITemplate(tt.tmpl, tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("expandURITemplate(%q, %v) = %q, want error", tt.tmpl, tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("expandURITemplate(%q, %v): %v", tt.tmpl, tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("expandURITemplate(%q, %v) = %q, want %q", tt.tmpl, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestResourceURIKeepsStaticURIs(t *testing.T) {
+	got, err := resourceURI("file:///README.md", map[string]any{"ignored": true})
+	if err != nil || got != "file:///README.md" {
+		t.Errorf("resourceURI = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,73 @@
+// Command stdioserver is a minimal MCP server over stdio used by the MCP
+// delegate tests. Its tool, resources and prompt report the FIXTURE_GREETING
+// environment variable so tests can check what the client passed in. The
+// "touch" tool reports an update of fixture://greeting to subscribers.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type greetInput struct {
+	Name string `json:"name"`
+}
+
+type greetOutput struct {
+	Message string `json:"message"`
+}
+
+func main() {
+	server := mcp.NewServer(&mcp.Implementation{Name: "fixture", Version: "1.0.0"}, &mcp.ServerOptions{
+		SubscribeHandler:   func(context.Context, *mcp.SubscribeRequest) error { return nil },
+		UnsubscribeHandler: func(context.Context, *mcp.UnsubscribeRequest) error { return nil },
+	})
+
+	mcp.AddTool(server, &mcp.Tool{Name: "greet", Description: "Greets someone."},
+		func(_ context.Context, _ *mcp.CallToolRequest, in greetInput) (*mcp.CallToolResult, greetOutput, error) {
+			return nil, greetOutput{Message: greeting() + ", " + in.Name}, nil
+		})
+
+	server.AddResource(&mcp.Resource{URI: "fixture://greeting", Name: "greeting", MIMEType: "text/plain"},
+		func(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+				{URI: req.Params.URI, MIMEType: "text/plain", Text: greeting()},
+			}}, nil
+		})
+
+	mcp.AddTool(server, &mcp.Tool{Name: "touch", Description: "Reports an update of fixture://greeting."},
+		func(ctx context.Context, _ *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+			err := server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: "fixture://greeting"})
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, nil, err
+		})
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{URITemplate: "fixture://greeting/{name}", Name: "personal_greeting"},
+		func(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			name := strings.TrimPrefix(req.Params.URI, "fixture://greeting/")
+			return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+				{URI: req.Params.URI, MIMEType: "text/plain", Text: greeting() + ", " + name},
+			}}, nil
+		})
+
+	server.AddPrompt(&mcp.Prompt{Name: "welcome", Arguments: []*mcp.PromptArgument{{Name: "name", Required: true}}},
+		func(_ context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return &mcp.GetPromptResult{Messages: []*mcp.PromptMessage{
+				{Role: "user", Content: &mcp.TextContent{Text: greeting() + ", " + req.Params.Arguments["name"]}},
+			}}, nil
+		})
+
+	if err := server.Run(context.Background(), &mcp.StdioTransport{}); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func greeting() string {
+	if g := os.Getenv("FIXTURE_GREETING"); g != "" {
+		return g
+	}
+	return "hello"
+}
@@ -0,0 +1,382 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// Media types with dedicated request body encodings.
+const (
+	mediaTypeJSON      = "application/json"
+	mediaTypeForm      = "application/x-www-form-urlencoded"
+	mediaTypeMultipart = "multipart/form-data"
+	mediaTypeOctet     = "application/octet-stream"
+)
+
+// metadataResponseFile is the BindingContext.Metadata key naming a file that
+// binary responses are written to instead of being returned as base64.
+const metadataResponseFile = "responseFile"
+
+// bodyInputKey is the input field holding a request body whose schema is not
+// an object (see buildInputSchema).
+const bodyInputKey = "body"
+
+// isJSONMediaType reports whether a media type carries JSON.
+func isJSONMediaType(mediaType string) bool {
+	return strings.Contains(mediaType, "json")
+}
+
+// isTextMediaType reports whether a media type carries text that can be
+// returned as a string. Anything else is treated as binary.
+func isTextMediaType(mediaType string) bool {
+	if strings.HasPrefix(mediaType, "text/") || isJSONMediaType(mediaType) {
+		return true
+	}
+	for _, marker := range []string{"xml", "yaml", "javascript", "graphql", mediaTypeForm} {
+		if strings.Contains(mediaType, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBinarySchema reports whether a schema describes raw bytes, which are
+// supplied as file paths in operation input.
+func isBinarySchema(schema *openapi3.SchemaRef) bool {
+	if schema == nil || schema.Value == nil {
+		return false
+	}
+	s := schema.Value
+	if s.Format == "binary" {
+		return true
+	}
+	if s.Extensions != nil {
+		if _, ok := s.Extensions["contentMediaType"]; ok {
+			return true
+		}
+	}
+	if s.Items != nil {
+		return isBinarySchema(s.Items)
+	}
+	return false
+}
+
+// isFieldMediaType reports whether a media type encodes a set of named fields.
+func isFieldMediaType(mediaType string) bool {
+	return isJSONMediaType(mediaType) || mediaType == mediaTypeForm || mediaType == mediaTypeMultipart
+}
+
+// bodyIsWrapped reports whether the request body is passed as a single "body"
+// input field rather than spread into top-level fields. It mirrors the choice
+// made by buildInputSchema.
+func bodyIsWrapped(mediaType string, mt *openapi3.MediaType) bool {
+	if mt == nil || mt.Schema == nil {
+		return !isFieldMediaType(mediaType)
+	}
+	schema := schemaRefToMap(mt.Schema)
+	_, spread := schema["properties"].(map[string]any)
+	return !spread
+}
+
+// requestBodyMediaType returns the media type used to send an operation's
+// request body, chosen from its declared content. Operations without declared
+// content send JSON.
+func requestBodyMediaType(op *openapi3.Operation) (string, *openapi3.MediaType) {
+	if !hasRequestBody(op) || len(op.RequestBody.Value.Content) == 0 {
+		return mediaTypeJSON, nil
+	}
+	return preferredMediaType(op.RequestBody.Value.Content)
+}
+
+// encodeRequestBody encodes body fields for the given media type and returns
+// the body and its Content-Type header value.
+//
+// Form and multipart bodies are built from the input fields. In multipart
+// bodies, fields with a binary schema are file paths whose contents are
+// uploaded. text/* bodies are sent as-is; other binary media types read the
+// body from the file path given as input.
+func encodeRequestBody(mediaType string, mt *openapi3.MediaType, fields map[string]any) (io.Reader, string, error) {
+	// A wrapped body arrives as the lone "body" field. Other inputs (e.g. free-form
+	// object bodies supplied as top-level fields) are sent as given.
+	var value any = fields
+	if v, ok := fields[bodyInputKey]; ok && len(fields) == 1 && bodyIsWrapped(mediaType, mt) {
+		value = v
+	}
+
+	switch {
+	case isJSONMediaType(mediaType):
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, "", err
+		}
+		return bytes.NewReader(data), mediaType, nil
+
+	case mediaType == mediaTypeForm:
+		form := url.Values{}
+		for _, k := range sortedKeys(fields) {
+			for _, s := range formValues(fields[k]) {
+				form.Add(k, s)
+			}
+		}
+		return strings.NewReader(form.Encode()), mediaType, nil
+
+	case mediaType == mediaTypeMultipart:
+		return encodeMultipart(mt, fields)
+
+	case isTextMediaType(mediaType):
+		return strings.NewReader(textValue(value)), mediaType, nil
+
+	default:
+		path, ok := value.(string)
+		if !ok || path == "" {
+			return nil, "", fmt.Errorf("%s body requires a file path, got %T", mediaType, value)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("read body file: %w", err)
+		}
+		return bytes.NewReader(data), mediaType, nil
+	}
+}
+
+// encodeMultipart builds a multipart/form-data body from input fields.
+func encodeMultipart(mt *openapi3.MediaType, fields map[string]any) (io.Reader, string, error) {
+	var props openapi3.Schemas
+	if mt != nil && mt.Schema != nil && mt.Schema.Value != nil {
+		props = mt.Schema.Value.Properties
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, k := range sortedKeys(fields) {
+		v := fields[k]
+		if isBinarySchema(props[k]) {
+			paths, err := filePaths(k, v)
+			if err != nil {
+				return nil, "", err
+			}
+			for _, p := range paths {
+				if err := writeFilePart(w, k, p, partContentType(mt, k)); err != nil {
+					return nil, "", err
+				}
+			}
+			continue
+		}
+
+		switch v.(type) {
+		case map[string]any, []any:
+			h := textproto.MIMEHeader{}
+			h.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q`, k))
+			h.Set("Content-Type", mediaTypeJSON)
+			part, err := w.CreatePart(h)
+			if err != nil {
+				return nil, "", err
+			}
+			if err := json.NewEncoder(part).Encode(v); err != nil {
+				return nil, "", err
+			}
+		default:
+			if err := w.WriteField(k, textValue(v)); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return &buf, w.FormDataContentType(), nil
+}
+
+// partContentType returns the declared content type of a multipart field.
+func partContentType(mt *openapi3.MediaType, field string) string {
+	if mt != nil && mt.Encoding != nil {
+		if enc := mt.Encoding[field]; enc != nil && enc.ContentType != "" {
+			return strings.TrimSpace(strings.Split(enc.ContentType, ",")[0])
+		}
+	}
+	return mediaTypeOctet
+}
+
+func writeFilePart(w *multipart.Writer, field, path, contentType string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open file for field %q: %w", field, err)
+	}
+	defer f.Close()
+
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, field, filepath.Base(path)))
+	h.Set("Content-Type", contentType)
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, f)
+	return err
+}
+
+// filePaths extracts one or more file paths from a binary field value.
+func filePaths(field string, v any) ([]string, error) {
+	switch t := v.(type) {
+	case string:
+		return []string{t}, nil
+	case []any:
+		paths := make([]string, 0, len(t))
+		for _, item := range t {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("field %q: expected file paths, got %T", field, item)
+			}
+			paths = append(paths, s)
+		}
+		return paths, nil
+	default:
+		return nil, fmt.Errorf("field %q: expected a file path, got %T", field, v)
+	}
+}
+
+// formValues flattens a field value for form encoding. Arrays become repeated
+// values and objects are sent as JSON.
+func formValues(v any) []string {
+	if items, ok := v.([]any); ok {
+		out := make([]string, 0, len(items))
+		for _, item := range items {
+			out = append(out, textValue(item))
+		}
+		return out
+	}
+	return []string{textValue(v)}
+}
+
+// textValue renders a value as text: strings as-is, objects and arrays as JSON.
+func textValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case map[string]any, []any:
+		data, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Sprintf("%v", t)
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// acceptHeader lists the media types declared by the operation's success
+// responses, falling back to JSON.
+func acceptHeader(op *openapi3.Operation) string {
+	if op.Responses == nil {
+		return mediaTypeJSON
+	}
+	seen := map[string]bool{}
+	var types []string
+	for _, code := range []string{"200", "201", "202", "206"} {
+		resp := op.Responses.Value(code)
+		if resp == nil || resp.Value == nil {
+			continue
+		}
+		keys := make([]string, 0, len(resp.Value.Content))
+		for k := range resp.Value.Content {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if !seen[k] {
+				seen[k] = true
+				types = append(types, k)
+			}
+		}
+	}
+	if len(types) == 0 {
+		return mediaTypeJSON
+	}
+	return strings.Join(types, ", ")
+}
+
+// decodeResponseBody converts a response body into operation output. JSON is
+// parsed, text is returned as a string, and binary content is returned as a
+// base64 object or, when responseFile is set, written to that file.
+func decodeResponseBody(contentType string, body []byte, responseFile string) (any, error) {
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	mediaType := ""
+	if contentType != "" {
+		if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+			mediaType = strings.ToLower(mt)
+		}
+	}
+
+	binary := mediaType != "" && !isTextMediaType(mediaType)
+	if mediaType == "" {
+		binary = !utf8.Valid(body)
+	}
+
+	if !binary {
+		trimmed := strings.TrimSpace(string(body))
+		if delegates.MaybeJSON(trimmed) {
+			var parsed any
+			if json.Unmarshal(body, &parsed) == nil {
+				return parsed, nil
+			}
+		}
+		return string(body), nil
+	}
+
+	if mediaType == "" {
+		mediaType = mediaTypeOctet
+	}
+	if responseFile != "" {
+		if err := os.WriteFile(responseFile, body, 0o644); err != nil {
+			return nil, fmt.Errorf("write response file: %w", err)
+		}
+		return map[string]any{
+			"contentType": mediaType,
+			"file":        responseFile,
+			"size":        len(body),
+		}, nil
+	}
+	return map[string]any{
+		"contentType": mediaType,
+		"encoding":    "base64",
+		"data":        base64.StdEncoding.EncodeToString(body),
+	}, nil
+}
+
+// responseFilePath returns the binary response destination from context metadata.
+func responseFilePath(bindCtx *delegates.BindingContext) string {
+	if bindCtx == nil || bindCtx.Metadata == nil {
+		return ""
+	}
+	path, _ := bindCtx.Metadata[metadataResponseFile].(string)
+	return path
+}
@@ -0,0 +1,192 @@
+package openapi
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// bodyDoc builds a document with a single POST /upload operation whose
+// request body uses the given content map.
+func bodyDoc(serverURL, content string) string {
+	return fmt.Sprintf(`{
+		"openapi": "3.0.3",
+		"info": {"title": "Test", "version": "1.0.0"},
+		"servers": [{"url": %q}],
+		"paths": {
+			"/upload": {"post": {
+				"requestBody": {"content": %s},
+				"responses": {"200": {"description": "ok"}}
+			}}
+		}
+	}`, serverURL, content)
+}
+
+func executeUpload(t *testing.T, doc string, input map[string]any) delegates.ExecuteOutput {
+	t.Helper()
+	return Execute(context.Background(), delegates.ExecuteInput{
+		Source: delegates.Source{Format: "openapi@3.0", Content: doc},
+		Ref:    "#/paths/~1upload/post",
+		Input:  input,
+	})
+}
+
+func TestExecuteRequestBodyMediaTypes(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "report.bin")
+	if err := os.WriteFile(filePath, []byte{0x00, 0x01, 0xfe}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	type received struct {
+		mediaType string
+		body      string
+		form      map[string][]string
+		files     map[string]string
+	}
+	var got received
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = received{form: map[string][]string{}, files: map[string]string{}}
+		got.mediaType, _, _ = mime.ParseMediaType(r.Header.Get("Content-Type"))
+		switch got.mediaType {
+		case mediaTypeMultipart:
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("ParseMultipartForm: %v", err)
+			}
+			got.form = r.MultipartForm.Value
+			for name, headers := range r.MultipartForm.File {
+				f, _ := headers[0].Open()
+				data, _ := io.ReadAll(f)
+				f.Close()
+				got.files[name] = headers[0].Filename + ":" + string(data)
+			}
+		case mediaTypeForm:
+			r.ParseForm()
+			got.form = r.PostForm
+		default:
+			data, _ := io.ReadAll(r.Body)
+			got.body = string(data)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	t.Run("form-urlencoded", func(t *testing.T) {
+		doc := bodyDoc(srv.URL, `{"application/x-www-form-urlencoded": {"schema": {"type": "object", "properties": {"name": {"type": "string"}, "tags": {"type": "array", "items": {"type": "string"}}}}}}`)
+		out := executeUpload(t, doc, map[string]any{"name": "ada", "tags": []any{"a", "b"}})
+		if out.Error != nil {
+			t.Fatalf("Execute failed: %s", out.Error.Message)
+		}
+		if got.mediaType != mediaTypeForm || got.form["name"][0] != "ada" || len(got.form["tags"]) != 2 {
+			t.Fatalf("received %+v, want form with name and two tags", got)
+		}
+	})
+
+	t.Run("multipart with file", func(t *testing.T) {
+		doc := bodyDoc(srv.URL, `{"multipart/form-data": {"schema": {"type": "object", "properties": {"title": {"type": "string"}, "file": {"type": "string", "format": "binary"}}}}}`)
+		out := executeUpload(t, doc, map[string]any{"title": "Q3", "file": filePath})
+		if out.Error != nil {
+			t.Fatalf("Execute failed: %s", out.Error.Message)
+		}
+		if got.mediaType != mediaTypeMultipart || got.form["title"][0] != "Q3" {
+			t.Fatalf("received %+v, want multipart with title", got)
+		}
+		if got.files["file"] != "report.bin:\x00\x01\xfe" {
+			t.Fatalf("file part = %q, want report.bin contents", got.files["file"])
+		}
+	})
+
+	t.Run("text/plain", func(t *testing.T) {
+		doc := bodyDoc(srv.URL, `{"text/plain": {"schema": {"type": "string"}}}`)
+		out := executeUpload(t, doc, map[string]any{"body": "hello"})
+		if out.Error != nil {
+			t.Fatalf("Execute failed: %s", out.Error.Message)
+		}
+		if got.mediaType != "text/plain" || got.body != "hello" {
+			t.Fatalf("received %+v, want text/plain hello", got)
+		}
+	})
+
+	t.Run("octet-stream from file", func(t *testing.T) {
+		doc := bodyDoc(srv.URL, `{"application/octet-stream": {}}`)
+		out := executeUpload(t, doc, map[string]any{"body": filePath})
+		if out.Error != nil {
+			t.Fatalf("Execute failed: %s", out.Error.Message)
+		}
+		if got.mediaType != mediaTypeOctet || got.body != "\x00\x01\xfe" {
+			t.Fatalf("received %+v, want file bytes", got)
+		}
+	})
+
+	t.Run("missing multipart file", func(t *testing.T) {
+		doc := bodyDoc(srv.URL, `{"multipart/form-data": {"schema": {"type": "object", "properties": {"file": {"type": "string", "format": "binary"}}}}}`)
+		out := executeUpload(t, doc, map[string]any{"file": filepath.Join(dir, "missing.bin")})
+		if out.Error == nil || out.Error.Code != "body_marshal_failed" {
+			t.Fatalf("error = %+v, want body_marshal_failed", out.Error)
+		}
+	})
+}
+
+func TestExecuteBinaryResponse(t *testing.T) {
+	payload := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "image/png" {
+			t.Errorf("Accept = %q, want image/png", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(payload)
+	}))
+	defer srv.Close()
+
+	doc := fmt.Sprintf(`{
+		"openapi": "3.0.3",
+		"info": {"title": "Test", "version": "1.0.0"},
+		"servers": [{"url": %q}],
+		"paths": {
+			"/export": {"get": {"responses": {"200": {"description": "ok", "content": {"image/png": {}}}}}}
+		}
+	}`, srv.URL)
+
+	input := delegates.ExecuteInput{
+		Source: delegates.Source{Format: "openapi@3.0", Content: doc},
+		Ref:    "#/paths/~1export/get",
+	}
+
+	result := Execute(context.Background(), input)
+	if result.Error != nil {
+		t.Fatalf("Execute failed: %s", result.Error.Message)
+	}
+	out, ok := result.Output.(map[string]any)
+	if !ok {
+		t.Fatalf("Output is %T, want map[string]any", result.Output)
+	}
+	if out["contentType"] != "image/png" || out["encoding"] != "base64" {
+		t.Fatalf("output = %v, want base64 image/png", out)
+	}
+	if data, _ := base64.StdEncoding.DecodeString(out["data"].(string)); string(data) != string(payload) {
+		t.Fatalf("decoded data = %v, want %v", data, payload)
+	}
+
+	dest := filepath.Join(t.TempDir(), "export.png")
+	input.Context = &delegates.BindingContext{Metadata: map[string]any{"responseFile": dest}}
+	result = Execute(context.Background(), input)
+	if result.Error != nil {
+		t.Fatalf("Execute failed: %s", result.Error.Message)
+	}
+	out, _ = result.Output.(map[string]any)
+	if out["file"] != dest || out["size"] != len(payload) {
+		t.Fatalf("output = %v, want file %s with size %d", out, dest, len(payload))
+	}
+	if data, err := os.ReadFile(dest); err != nil || string(data) != string(payload) {
+		t.Fatalf("response file = %v (%v), want payload", data, err)
+	}
+}
@@ -3,9 +3,12 @@ package openapi
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
+	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/openbindings/cli/internal/delegates"
@@ -15,17 +18,22 @@ import (
 // FormatToken is the format identifier for OpenAPI sources.
 const FormatToken = "openapi@^3.0.0"
 
+// SwaggerFormatToken is the format identifier for Swagger 2.0 sources,
+// which are converted to OpenAPI 3 when loaded.
+const SwaggerFormatToken = "openapi@^2.0.0"
+
 // DefaultSourceName is the default source key for OpenAPI sources.
 const DefaultSourceName = "openapi"
 
 // ConvertToInterface converts an OpenAPI document to an OpenBindings interface.
 func ConvertToInterface(source delegates.Source) (openbindings.Interface, error) {
-	doc, err := loadDocument(source)
+	loaded, err := loadSourceDocument(source, nil)
 	if err != nil {
 		return openbindings.Interface{}, fmt.Errorf("load OpenAPI document: %w", err)
 	}
+	doc := loaded.doc
 
-	formatVersion := detectFormatVersion(doc.OpenAPI)
+	formatVersion := detectFormatVersion(loaded.version)
 
 	iface := openbindings.Interface{
 		OpenBindings: openbindings.MaxTestedVersion,
@@ -45,13 +53,13 @@ func ConvertToInterface(source delegates.Source) (openbindings.Interface, error)
 		iface.Description = doc.Info.Description
 	}
 
-	if doc.Paths == nil {
-		return iface, nil
-	}
-
 	usedKeys := map[string]bool{}
 
-	for _, path := range doc.Paths.InMatchingOrder() {
+	var paths []string
+	if doc.Paths != nil {
+		paths = doc.Paths.InMatchingOrder()
+	}
+	for _, path := range paths {
 		pathItem := doc.Paths.Find(path)
 		if pathItem == nil {
 			continue
@@ -95,48 +103,130 @@ func ConvertToInterface(source delegates.Source) (openbindings.Interface, error)
 				Source:    DefaultSourceName,
 				Ref:       ref,
 			}
+
+			addCallbackOperations(&iface, opKey, op, ref, usedKeys)
 		}
 	}
 
+	webhooks, err := loadWebhooks(loaded)
+	if err != nil {
+		return openbindings.Interface{}, fmt.Errorf("load webhooks: %w", err)
+	}
+	addWebhookOperations(&iface, webhooks, usedKeys)
+
 	return iface, nil
 }
 
-// loadDocument loads and parses an OpenAPI document from a source.
-func loadDocument(source delegates.Source) (*openapi3.T, error) {
+// loadedDocument is a parsed OpenAPI document along with what was needed
+// to load it, for resolving parts kin-openapi does not model (webhooks).
+type loadedDocument struct {
+	doc      *openapi3.T
+	version  string // declared spec version, e.g. "3.1.0" or "2.0" for Swagger
+	loader   *openapi3.Loader
+	location *url.URL // nil for inline content without a location
+}
+
+// loadDocument loads and parses an OpenAPI document from a source, fetching
+// remote documents and external refs with the context's transport.
+// Swagger 2.0 documents are converted to OpenAPI 3.
+func loadDocument(source delegates.Source, bindCtx *delegates.BindingContext) (*openapi3.T, error) {
+	loaded, err := loadSourceDocument(source, bindCtx)
+	if err != nil {
+		return nil, err
+	}
+	return loaded.doc, nil
+}
+
+func loadSourceDocument(source delegates.Source, bindCtx *delegates.BindingContext) (*loadedDocument, error) {
+	client, err := delegates.HTTPClient(bindCtx)
+	if err != nil {
+		return nil, err
+	}
 	loader := openapi3.NewLoader()
 	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = readFromURI(client)
 
+	data, location, err := readDocument(loader, source)
+	if err != nil {
+		return nil, err
+	}
+
+	if version, ok := swaggerVersion(data); ok {
+		doc, err := convertSwagger(loader, data, location)
+		if err != nil {
+			return nil, err
+		}
+		return &loadedDocument{doc: doc, version: version, loader: loader, location: location}, nil
+	}
+
+	var doc *openapi3.T
+	if location != nil {
+		doc, err = loader.LoadFromDataWithPath(data, location)
+	} else {
+		doc, err = loader.LoadFromData(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &loadedDocument{doc: doc, version: doc.OpenAPI, loader: loader, location: location}, nil
+}
+
+// readDocument returns the raw document bytes and the location that relative
+// external refs resolve against.
+func readDocument(loader *openapi3.Loader, source delegates.Source) ([]byte, *url.URL, error) {
 	if source.Content != nil {
 		data, err := delegates.ContentToBytes(source.Content)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if source.Location != "" {
-			loc, err := url.Parse(source.Location)
-			if err == nil {
-				return loader.LoadFromDataWithPath(data, loc)
+			if loc, err := url.Parse(source.Location); err == nil {
+				return data, loc, nil
 			}
 		}
-		return loader.LoadFromData(data)
+		return data, nil, nil
 	}
 
 	if source.Location == "" {
-		return nil, fmt.Errorf("source must have location or content")
+		return nil, nil, fmt.Errorf("source must have location or content")
 	}
 
+	var loc *url.URL
 	if strings.HasPrefix(source.Location, "http://") || strings.HasPrefix(source.Location, "https://") {
-		loc, err := url.Parse(source.Location)
+		parsed, err := url.Parse(source.Location)
 		if err != nil {
-			return nil, fmt.Errorf("invalid URL %q: %w", source.Location, err)
+			return nil, nil, fmt.Errorf("invalid URL %q: %w", source.Location, err)
 		}
-		return loader.LoadFromURI(loc)
+		loc = parsed
+	} else {
+		loc = &url.URL{Path: filepath.ToSlash(source.Location)}
+	}
+
+	data, err := loader.ReadFromURIFunc(loader, loc)
+	if err != nil {
+		return nil, nil, err
 	}
+	return data, loc, nil
+}
 
-	return loader.LoadFromFile(source.Location)
+// uriReaders holds a caching document reader per HTTP client, so documents
+// are fetched once per process for each transport, as kin-openapi's
+// DefaultReadFromURI does for the default client.
+var uriReaders sync.Map // *http.Client -> openapi3.ReadFromURIFunc
+
+// readFromURI returns a caching reader of local files and of remote
+// documents fetched with client.
+func readFromURI(client *http.Client) openapi3.ReadFromURIFunc {
+	if r, ok := uriReaders.Load(client); ok {
+		return r.(openapi3.ReadFromURIFunc)
+	}
+	r := openapi3.URIMapCache(openapi3.ReadFromURIs(openapi3.ReadFromHTTP(client), openapi3.ReadFromFile))
+	actual, _ := uriReaders.LoadOrStore(client, r)
+	return actual.(openapi3.ReadFromURIFunc)
 }
 
 // detectFormatVersion extracts a normalized version from the OpenAPI version string.
-// "3.1.0" -> "3.1", "3.0.3" -> "3.0"
+// "3.1.0" -> "3.1", "3.0.3" -> "3.0", "2.0" -> "2.0"
 func detectFormatVersion(openapi string) string {
 	parts := strings.Split(openapi, ".")
 	if len(parts) >= 2 {
@@ -167,7 +257,11 @@ func deriveOperationKey(op *openapi3.Operation, path, method string, used map[st
 	}
 
 	key := strings.Join(parts, ".") + "." + strings.ToLower(method)
-	key = delegates.SanitizeKey(key)
+	return uniqueKey(delegates.SanitizeKey(key), used)
+}
+
+// uniqueKey returns key, or key with the lowest numeric suffix not yet used.
+func uniqueKey(key string, used map[string]bool) string {
 	if !used[key] {
 		return key
 	}
@@ -191,9 +285,13 @@ func operationDescription(op *openapi3.Operation) string {
 // Per the OpenBindings spec, refs for OpenAPI use JSON Pointer (RFC 6901).
 // e.g., "#/paths/~1tasks~1{id}/get"
 func buildJSONPointerRef(path, method string) string {
-	escaped := strings.ReplaceAll(path, "~", "~0")
-	escaped = strings.ReplaceAll(escaped, "/", "~1")
-	return "#/paths/" + escaped + "/" + strings.ToLower(method)
+	return "#/paths/" + escapePointerToken(path) + "/" + strings.ToLower(method)
+}
+
+// escapePointerToken escapes a JSON Pointer reference token (RFC 6901).
+func escapePointerToken(s string) string {
+	s = strings.ReplaceAll(s, "~", "~0")
+	return strings.ReplaceAll(s, "/", "~1")
 }
 
 // buildInputSchema constructs a JSON Schema for the operation's input from
@@ -210,10 +308,6 @@ func buildInputSchema(op *openapi3.Operation, pathParams openapi3.Parameters) ma
 		}
 		param := paramRef.Value
 
-		if param.In == "cookie" {
-			continue
-		}
-
 		prop := paramToSchema(param)
 		if prop != nil {
 			properties[param.Name] = prop
@@ -310,17 +404,47 @@ func paramToSchema(param *openapi3.Parameter) map[string]any {
 
 // requestBodyToSchema extracts a JSON Schema from a request body.
 // Prefers application/json, then other JSON-like types, then first alphabetically.
+// Binary bodies and multipart file fields are described as file paths.
 func requestBodyToSchema(rb *openapi3.RequestBody) map[string]any {
 	if rb.Content == nil {
 		return nil
 	}
 
-	mt := preferJSONMediaType(rb.Content)
-	if mt == nil || mt.Schema == nil {
+	mediaType, mt := preferredMediaType(rb.Content)
+	if mt == nil {
 		return nil
 	}
+	if !isTextMediaType(mediaType) && mediaType != mediaTypeMultipart {
+		schema := schemaRefToMap(mt.Schema)
+		if schema == nil {
+			schema = map[string]any{"type": "string", "format": "binary"}
+		}
+		if _, ok := schema["description"]; !ok {
+			schema["description"] = "Path of the file to send as the request body"
+		}
+		return schema
+	}
+	if mt.Schema == nil {
+		if isFieldMediaType(mediaType) {
+			return nil
+		}
+		return map[string]any{"type": "string"}
+	}
 
-	return schemaRefToMap(mt.Schema)
+	schema := schemaRefToMap(mt.Schema)
+	if mediaType == mediaTypeMultipart && mt.Schema.Value != nil {
+		props, _ := schema["properties"].(map[string]any)
+		for name, ref := range mt.Schema.Value.Properties {
+			prop, ok := props[name].(map[string]any)
+			if !ok || !isBinarySchema(ref) {
+				continue
+			}
+			if _, ok := prop["description"]; !ok {
+				prop["description"] = "Path of the file to upload"
+			}
+		}
+	}
+	return schema
 }
 
 // buildOutputSchema extracts the output schema from the operation's success response.
@@ -346,20 +470,43 @@ func responseToSchema(resp *openapi3.Response) map[string]any {
 		return nil
 	}
 
-	mt := preferJSONMediaType(resp.Content)
-	if mt == nil || mt.Schema == nil {
+	mediaType, mt := preferredMediaType(resp.Content)
+	if mt == nil {
+		return nil
+	}
+	if !isTextMediaType(mediaType) {
+		return binaryOutputSchema()
+	}
+	if mt.Schema == nil {
 		return nil
 	}
 
 	return schemaRefToMap(mt.Schema)
 }
 
-// preferJSONMediaType selects the best media type from a content map.
-// Prefers application/json, then other JSON-compatible types, then the
-// first type alphabetically for deterministic behavior.
-func preferJSONMediaType(content openapi3.Content) *openapi3.MediaType {
-	if mt := content.Get("application/json"); mt != nil {
-		return mt
+// binaryOutputSchema describes the output produced for binary responses:
+// base64 data, or the file it was written to when a response file is set.
+func binaryOutputSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"contentType": map[string]any{"type": "string"},
+			"encoding":    map[string]any{"type": "string", "const": "base64"},
+			"data":        map[string]any{"type": "string", "contentEncoding": "base64"},
+			"file":        map[string]any{"type": "string"},
+			"size":        map[string]any{"type": "integer"},
+		},
+		"required": []string{"contentType"},
+	}
+}
+
+// preferredMediaType selects the best media type from a content map and
+// returns it with its name. Prefers application/json, then other
+// JSON-compatible types, then the first type alphabetically for
+// deterministic behavior.
+func preferredMediaType(content openapi3.Content) (string, *openapi3.MediaType) {
+	if mt := content.Get(mediaTypeJSON); mt != nil {
+		return mediaTypeJSON, mt
 	}
 
 	// Check for JSON-compatible types (e.g., application/vnd.api+json).
@@ -370,15 +517,15 @@ func preferJSONMediaType(content openapi3.Content) *openapi3.MediaType {
 	sort.Strings(keys)
 
 	for _, k := range keys {
-		if strings.Contains(k, "json") {
-			return content[k]
+		if isJSONMediaType(k) {
+			return k, content[k]
 		}
 	}
 
 	if len(keys) > 0 {
-		return content[keys[0]]
+		return keys[0], content[keys[0]]
 	}
-	return nil
+	return "", nil
 }
 
 // schemaRefToMap converts a kin-openapi SchemaRef to a plain map[string]any.
@@ -1,6 +1,8 @@
 package openapi
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -250,3 +252,47 @@ func TestDeriveOperationKey(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestBodyToSchema_NonJSON(t *testing.T) {
+	multipart := &openapi3.RequestBody{Content: openapi3.Content{
+		"multipart/form-data": &openapi3.MediaType{Schema: openapi3.NewObjectSchema().
+			WithProperty("title", openapi3.NewStringSchema()).
+			WithProperty("file", openapi3.NewStringSchema().WithFormat("binary")).NewRef()},
+	}}
+	schema := requestBodyToSchema(multipart)
+	props, _ := schema["properties"].(map[string]any)
+	file, _ := props["file"].(map[string]any)
+	if file["description"] != "Path of the file to upload" {
+		t.Errorf("file property = %v, want file path description", file)
+	}
+	if title, _ := props["title"].(map[string]any); title["description"] != nil {
+		t.Errorf("title property = %v, want no description", title)
+	}
+
+	octet := &openapi3.RequestBody{Content: openapi3.Content{"application/octet-stream": &openapi3.MediaType{}}}
+	schema = requestBodyToSchema(octet)
+	if schema["type"] != "string" || schema["format"] != "binary" {
+		t.Errorf("octet-stream schema = %v, want binary string", schema)
+	}
+
+	text := &openapi3.RequestBody{Content: openapi3.Content{"text/plain": &openapi3.MediaType{}}}
+	if schema = requestBodyToSchema(text); schema["type"] != "string" {
+		t.Errorf("text/plain schema = %v, want string", schema)
+	}
+}
+
+func TestLoadDocumentTransport(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"openapi": "3.0.3", "info": {"title": "T", "version": "1"}, "paths": {}}`))
+	}))
+	defer srv.Close()
+	source := delegates.Source{Format: FormatToken, Location: srv.URL + "/openapi.json"}
+
+	if _, err := loadDocument(source, nil); err == nil {
+		t.Fatal("expected the default client to reject the test server's certificate")
+	}
+	bindCtx := &delegates.BindingContext{Transport: &delegates.TransportConfig{InsecureSkipVerify: true}}
+	if _, err := loadDocument(source, bindCtx); err != nil {
+		t.Fatalf("with the context's transport: %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package openapi
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/openbindings/cli/internal/delegates"
+	"github.com/openbindings/openbindings-go"
+)
+
+// isEventRef reports whether ref points at a webhook or callback operation.
+// Those describe requests the API sends to the client, so they are mapped to
+// event operations and cannot be executed.
+func isEventRef(ref string) bool {
+	ref = strings.TrimPrefix(ref, "#")
+	return strings.HasPrefix(ref, "/webhooks/") || strings.Contains(ref, "/callbacks/")
+}
+
+// loadWebhooks extracts the OpenAPI 3.1 "webhooks" object. kin-openapi keeps
+// it as an unparsed extension, so it is decoded here and its refs resolved
+// against the document's components.
+func loadWebhooks(loaded *loadedDocument) (map[string]*openapi3.PathItem, error) {
+	raw, ok := loaded.doc.Extensions["webhooks"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var webhooks map[string]*openapi3.PathItem
+	if err := json.Unmarshal(data, &webhooks); err != nil {
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, nil
+	}
+
+	// Resolve refs by presenting the webhooks as paths of a document that
+	// shares the loaded document's components.
+	paths := openapi3.NewPathsWithCapacity(len(webhooks))
+	for name, item := range webhooks {
+		if item != nil {
+			paths.Set("/"+name, item)
+		}
+	}
+	shadow := &openapi3.T{
+		OpenAPI:    loaded.doc.OpenAPI,
+		Info:       loaded.doc.Info,
+		Components: loaded.doc.Components,
+		Paths:      paths,
+	}
+	if err := loaded.loader.ResolveRefsIn(shadow, loaded.location); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// addWebhookOperations maps each webhook operation to an event operation
+// whose payload is the webhook request body.
+func addWebhookOperations(iface *openbindings.Interface, webhooks map[string]*openapi3.PathItem, usedKeys map[string]bool) {
+	names := make([]string, 0, len(webhooks))
+	for name := range webhooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		item := webhooks[name]
+		if item == nil {
+			continue
+		}
+		for _, method := range sortedMethods(item) {
+			op := item.GetOperation(method)
+			ref := "#/webhooks/" + escapePointerToken(name) + "/" + strings.ToLower(method)
+			addEventOperation(iface, op, "webhooks."+name, method, ref, usedKeys)
+		}
+	}
+}
+
+// addCallbackOperations maps the callbacks declared on an operation to event
+// operations. Keys default to "<parentKey>.<callbackName>.<method>".
+func addCallbackOperations(iface *openbindings.Interface, parentKey string, op *openapi3.Operation, parentRef string, usedKeys map[string]bool) {
+	if len(op.Callbacks) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(op.Callbacks))
+	for name := range op.Callbacks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cbRef := op.Callbacks[name]
+		if cbRef == nil || cbRef.Value == nil {
+			continue
+		}
+		callback := cbRef.Value.Map()
+		expressions := make([]string, 0, len(callback))
+		for expr := range callback {
+			expressions = append(expressions, expr)
+		}
+		sort.Strings(expressions)
+
+		for _, expr := range expressions {
+			item := callback[expr]
+			if item == nil {
+				continue
+			}
+			for _, method := range sortedMethods(item) {
+				cbOp := item.GetOperation(method)
+				ref := parentRef + "/callbacks/" + escapePointerToken(name) + "/" + escapePointerToken(expr) + "/" + strings.ToLower(method)
+				addEventOperation(iface, cbOp, parentKey+"."+name, method, ref, usedKeys)
+			}
+		}
+	}
+}
+
+// addEventOperation adds a kind: event operation and its binding.
+func addEventOperation(iface *openbindings.Interface, op *openapi3.Operation, keyPrefix, method, ref string, usedKeys map[string]bool) {
+	opKey := deriveEventKey(op, keyPrefix, method, usedKeys)
+	usedKeys[opKey] = true
+
+	obiOp := openbindings.Operation{
+		Kind:        openbindings.OperationKindEvent,
+		Description: operationDescription(op),
+		Deprecated:  op.Deprecated,
+	}
+	if len(op.Tags) > 0 {
+		obiOp.Tags = op.Tags
+	}
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		if payload := requestBodyToSchema(op.RequestBody.Value); payload != nil {
+			obiOp.Payload = payload
+		}
+	}
+	iface.Operations[opKey] = obiOp
+
+	iface.Bindings[opKey+"."+DefaultSourceName] = openbindings.BindingEntry{
+		Operation: opKey,
+		Source:    DefaultSourceName,
+		Ref:       ref,
+	}
+}
+
+// deriveEventKey prefers the operationId, falling back to "<prefix>.<method>".
+func deriveEventKey(op *openapi3.Operation, prefix, method string, used map[string]bool) string {
+	if op.OperationID != "" {
+		key := delegates.SanitizeKey(op.OperationID)
+		if !used[key] {
+			return key
+		}
+	}
+	return uniqueKey(delegates.SanitizeKey(prefix+"."+strings.ToLower(method)), used)
+}
+
+// sortedMethods returns the methods defined on a path item in sorted order.
+func sortedMethods(item *openapi3.PathItem) []string {
+	ops := item.Operations()
+	methods := make([]string, 0, len(ops))
+	for method, op := range ops {
+		if op != nil {
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
@@ -0,0 +1,96 @@
+package openapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+const sampleWebhooksOpenAPI = `{
+  "openapi": "3.1.0",
+  "info": {"title": "Events", "version": "1.0.0"},
+  "paths": {
+    "/subscriptions": {
+      "post": {
+        "operationId": "subscribe",
+        "responses": {"201": {"description": "created"}},
+        "callbacks": {
+          "onEvent": {
+            "{$request.body#/callbackUrl}": {
+              "post": {
+                "requestBody": {
+                  "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Event"}}}
+                },
+                "responses": {"200": {"description": "ok"}}
+              }
+            }
+          }
+        }
+      }
+    }
+  },
+  "webhooks": {
+    "newPet": {
+      "post": {
+        "operationId": "petCreated",
+        "description": "A pet was added",
+        "requestBody": {
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Event"}}}
+        },
+        "responses": {"200": {"description": "ok"}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Event": {"type": "object", "properties": {"id": {"type": "string"}}}
+    }
+  }
+}`
+
+func TestConvertWebhooksAndCallbacks(t *testing.T) {
+	iface, err := ConvertToInterface(delegates.Source{Format: "openapi@3.1", Content: sampleWebhooksOpenAPI})
+	if err != nil {
+		t.Fatalf("ConvertToInterface failed: %v", err)
+	}
+
+	tests := []struct {
+		opKey string
+		ref   string
+	}{
+		{"petCreated", "#/webhooks/newPet/post"},
+		{"subscribe.onEvent.post", "#/paths/~1subscriptions/post/callbacks/onEvent/{$request.body#~1callbackUrl}/post"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.opKey, func(t *testing.T) {
+			op, ok := iface.Operations[tt.opKey]
+			if !ok {
+				t.Fatalf("missing operation %q; have %v", tt.opKey, iface.Operations)
+			}
+			if op.Kind != "event" {
+				t.Errorf("Kind = %q, want event", op.Kind)
+			}
+			if op.Payload == nil {
+				t.Error("Payload is nil, want request body schema")
+			}
+			if got := iface.Bindings[tt.opKey+".openapi"].Ref; got != tt.ref {
+				t.Errorf("ref = %q, want %q", got, tt.ref)
+			}
+		})
+	}
+
+	if iface.Operations["subscribe"].Kind != "method" {
+		t.Error("subscribe should remain a method")
+	}
+}
+
+func TestExecuteEventRefNotExecutable(t *testing.T) {
+	result := Execute(context.Background(), delegates.ExecuteInput{
+		Source: delegates.Source{Format: "openapi@3.1", Content: sampleWebhooksOpenAPI},
+		Ref:    "#/webhooks/newPet/post",
+	})
+	if result.Error == nil || result.Error.Code != "not_executable" {
+		t.Fatalf("Error = %+v, want not_executable", result.Error)
+	}
+}
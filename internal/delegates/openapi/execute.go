@@ -1,9 +1,7 @@
 package openapi
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -19,24 +17,23 @@ const defaultTimeout = 30 * time.Second
 
 // Execute executes an OpenAPI operation via HTTP.
 func Execute(ctx context.Context, input delegates.ExecuteInput) delegates.ExecuteOutput {
-	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	ctx, cancel := delegates.WithDefaultTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	start := time.Now()
 
-	doc, err := loadDocument(input.Source)
+	doc, err := loadDocument(input.Source, input.Context)
 	if err != nil {
 		return delegates.FailedOutput(start, "doc_load_failed", err.Error())
 	}
 
-	pathTemplate, method, err := parseRef(input.Ref)
-	if err != nil {
-		return delegates.FailedOutput(start, "invalid_ref", err.Error())
+	if isEventRef(input.Ref) {
+		return delegates.FailedOutput(start, "not_executable", fmt.Sprintf("ref %q is a webhook or callback sent by the API; it cannot be executed", input.Ref))
 	}
 
-	baseURL, err := resolveBaseURL(doc, input.Context)
+	pathTemplate, method, err := parseRef(input.Ref)
 	if err != nil {
-		return delegates.FailedOutput(start, "no_base_url", err.Error())
+		return delegates.FailedOutput(start, "invalid_ref", err.Error())
 	}
 
 	pathItem := doc.Paths.Find(pathTemplate)
@@ -48,30 +45,40 @@ func Execute(ctx context.Context, input delegates.ExecuteInput) delegates.Execut
 		return delegates.FailedOutput(start, "method_not_found", fmt.Sprintf("method %q not in path %q", method, pathTemplate))
 	}
 
+	baseURL, err := resolveBaseURL(doc, pathItem, op, input.Context)
+	if err != nil {
+		return delegates.FailedOutput(start, "no_base_url", err.Error())
+	}
+
 	allParams := mergeParameters(pathItem.Parameters, op.Parameters)
 	inputMap, _ := delegates.ToStringAnyMap(input.Input)
 	if inputMap == nil {
 		inputMap = map[string]any{}
 	}
 
-	resolvedPath, queryParams, headerParams, bodyFields := classifyInput(allParams, inputMap, pathTemplate)
+	classified := classifyInput(allParams, inputMap, pathTemplate)
 
-	reqURL := baseURL + resolvedPath
-	if len(queryParams) > 0 {
-		q := url.Values{}
-		for k, v := range queryParams {
-			q.Set(k, fmt.Sprintf("%v", v))
+	reqURL := baseURL + classified.path
+	if classified.query != "" {
+		reqURL += "?" + classified.query
+	}
+	if target, ok := requestURLOverride(input.Context); ok {
+		if !sameOrigin(target, baseURL) {
+			return delegates.FailedOutput(start, "invalid_request_url", fmt.Sprintf("%s %q is not on the server %q", delegates.RequestURLKey, target, baseURL))
 		}
-		reqURL += "?" + q.Encode()
+		reqURL = target
 	}
 
-	var bodyReader io.Reader
+	var (
+		bodyReader  io.Reader
+		contentType string
+	)
 	if hasRequestBody(op) {
-		bodyBytes, err := json.Marshal(bodyFields)
+		mediaType, mt := requestBodyMediaType(op)
+		bodyReader, contentType, err = encodeRequestBody(mediaType, mt, classified.body)
 		if err != nil {
 			return delegates.FailedOutput(start, "body_marshal_failed", err.Error())
 		}
-		bodyReader = bytes.NewReader(bodyBytes)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), reqURL, bodyReader)
@@ -80,17 +87,41 @@ func Execute(ctx context.Context, input delegates.ExecuteInput) delegates.Execut
 	}
 
 	if bodyReader != nil {
-		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Type", contentType)
 	}
-	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept", acceptHeader(op))
 
-	for k, v := range headerParams {
-		req.Header.Set(k, fmt.Sprintf("%v", v))
+	for k, v := range classified.headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range classified.cookies {
+		req.AddCookie(&http.Cookie{Name: k, Value: v})
 	}
 
-	delegates.ApplyHTTPContext(req, input.Context)
+	// Authenticate per the document's security requirements. Generic context
+	// credentials are only applied when no declared requirement was satisfied.
+	var creds *delegates.Credentials
+	if input.Context != nil {
+		creds = input.Context.Credentials
+	}
+	client, err := delegates.HTTPClient(input.Context)
+	if err != nil {
+		return delegates.FailedOutput(start, "transport_config_failed", err.Error())
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	authenticated, err := applySecurity(ctx, client, req, doc, securityRequirements(doc, op), creds)
+	if err != nil {
+		return delegates.FailedOutput(start, "auth_failed", err.Error())
+	}
+	if authenticated && input.Context != nil {
+		bindCtx := *input.Context
+		bindCtx.Credentials = nil
+		delegates.ApplyHTTPContext(req, &bindCtx)
+	} else {
+		delegates.ApplyHTTPContext(req, input.Context)
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return delegates.FailedOutput(start, "request_failed", err.Error())
 	}
@@ -103,23 +134,13 @@ func Execute(ctx context.Context, input delegates.ExecuteInput) delegates.Execut
 
 	duration := time.Since(start).Milliseconds()
 
-	var output any
-	if len(respBody) > 0 {
-		trimmed := strings.TrimSpace(string(respBody))
-		if delegates.MaybeJSON(trimmed) {
-			var parsed any
-			if json.Unmarshal(respBody, &parsed) == nil {
-				output = parsed
-			} else {
-				output = string(respBody)
-			}
-		} else {
-			output = string(respBody)
-		}
+	output, err := decodeResponseBody(resp.Header.Get("Content-Type"), respBody, responseFilePath(input.Context))
+	if err != nil {
+		return delegates.FailedOutput(start, "response_write_failed", err.Error())
 	}
 
 	if resp.StatusCode >= 400 {
-		errOutput := delegates.HTTPErrorOutput(start, resp.StatusCode, resp.Status)
+		errOutput := delegates.HTTPResponseErrorOutput(start, resp)
 		errOutput.Output = output
 		return errOutput
 	}
@@ -128,7 +149,65 @@ func Execute(ctx context.Context, input delegates.ExecuteInput) delegates.Execut
 		Output:     output,
 		Status:     0,
 		DurationMs: duration,
+		Links:      parseLinkHeader(resp.Header.Values("Link"), resp.Request.URL),
+	}
+}
+
+// requestURLOverride returns the URL the context asks the request to be sent
+// to instead of the one built from the ref and input (see
+// delegates.RequestURLKey).
+func requestURLOverride(bindCtx *delegates.BindingContext) (string, bool) {
+	if bindCtx == nil {
+		return "", false
 	}
+	target, ok := bindCtx.Metadata[delegates.RequestURLKey].(string)
+	return target, ok && target != ""
+}
+
+// sameOrigin reports whether target has the scheme and host of base.
+func sameOrigin(target, base string) bool {
+	t, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(t.Scheme, b.Scheme) && strings.EqualFold(t.Host, b.Host)
+}
+
+// parseLinkHeader parses RFC 8288 Link header values into a map of
+// relation type to target URL, resolved against the request URL. Returns
+// nil when there are no links.
+func parseLinkHeader(values []string, base *url.URL) map[string]string {
+	var links map[string]string
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			segments := strings.Split(link, ";")
+			target := strings.TrimSpace(segments[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			target = target[1 : len(target)-1]
+			if ref, err := url.Parse(target); err == nil && base != nil {
+				target = base.ResolveReference(ref).String()
+			}
+			for _, param := range segments[1:] {
+				name, val, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(val), `"`)) {
+					if links == nil {
+						links = map[string]string{}
+					}
+					links[strings.ToLower(rel)] = target
+				}
+			}
+		}
+	}
+	return links
 }
 
 // parseRef extracts the path template and HTTP method from an OpenAPI JSON Pointer ref.
@@ -159,61 +238,63 @@ func parseRef(ref string) (path string, method string, err error) {
 	return path, strings.ToLower(method), nil
 }
 
-// resolveBaseURL determines the base URL for requests.
-// Checks BindingContext.Metadata["baseURL"] first, then falls back to the
-// first server URL in the OpenAPI document.
-func resolveBaseURL(doc *openapi3.T, bindCtx *delegates.BindingContext) (string, error) {
-	if bindCtx != nil && bindCtx.Metadata != nil {
-		if base, ok := bindCtx.Metadata["baseURL"].(string); ok && base != "" {
-			return strings.TrimRight(base, "/"), nil
-		}
-	}
-
-	if doc.Servers != nil && len(doc.Servers) > 0 {
-		serverURL := doc.Servers[0].URL
-		if serverURL != "" {
-			return strings.TrimRight(serverURL, "/"), nil
-		}
-	}
-
-	return "", fmt.Errorf("no server URL: set servers in the OpenAPI doc or provide baseURL in context metadata")
+// classifiedInput is operation input split by destination, with parameters
+// serialized according to their OpenAPI style and explode settings.
+type classifiedInput struct {
+	path    string // path template with parameters substituted and percent-encoded
+	query   string // encoded query string, without the leading "?"
+	headers map[string]string
+	cookies map[string]string
+	body    map[string]any
 }
 
-// classifyInput separates input fields into path params, query params, header params,
-// and body fields based on the OpenAPI parameter definitions.
-func classifyInput(params openapi3.Parameters, input map[string]any, pathTemplate string) (resolvedPath string, query, headers, body map[string]any) {
-	query = map[string]any{}
-	headers = map[string]any{}
-	body = map[string]any{}
+// classifyInput separates input fields into path, query, header and cookie
+// parameters and body fields based on the OpenAPI parameter definitions.
+// Query parameters keep their declaration order.
+func classifyInput(params openapi3.Parameters, input map[string]any, pathTemplate string) classifiedInput {
+	out := classifiedInput{
+		path:    pathTemplate,
+		headers: map[string]string{},
+		cookies: map[string]string{},
+		body:    map[string]any{},
+	}
 
-	paramClassification := map[string]string{}
+	isParam := map[string]bool{}
+	var query []string
 	for _, paramRef := range params {
 		if paramRef == nil || paramRef.Value == nil {
 			continue
 		}
-		paramClassification[paramRef.Value.Name] = paramRef.Value.In
-	}
-
-	resolvedPath = pathTemplate
-	for name, value := range input {
-		classification, isParam := paramClassification[name]
-		if !isParam {
-			body[name] = value
+		p := paramRef.Value
+		value, ok := input[p.Name]
+		if !ok {
+			continue
+		}
+		isParam[p.Name] = true
+		if value == nil {
 			continue
 		}
-		switch classification {
-		case "path":
-			resolvedPath = strings.ReplaceAll(resolvedPath, "{"+name+"}", fmt.Sprintf("%v", value))
-		case "query":
-			query[name] = value
-		case "header":
-			headers[name] = value
+		switch p.In {
+		case openapi3.ParameterInPath:
+			out.path = strings.ReplaceAll(out.path, "{"+p.Name+"}", serializePathParam(p, value))
+		case openapi3.ParameterInQuery:
+			query = append(query, serializeQueryParam(p, value)...)
+		case openapi3.ParameterInHeader:
+			out.headers[p.Name] = serializeHeaderParam(p, value)
+		case openapi3.ParameterInCookie:
+			out.cookies[p.Name] = serializeCookieParam(p, value)
 		default:
-			body[name] = value
+			isParam[p.Name] = false
 		}
 	}
+	out.query = strings.Join(query, "&")
 
-	return resolvedPath, query, headers, body
+	for name, value := range input {
+		if !isParam[name] {
+			out.body[name] = value
+		}
+	}
+	return out
 }
 
 func hasRequestBody(op *openapi3.Operation) bool {
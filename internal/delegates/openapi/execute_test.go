@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/openbindings/cli/internal/delegates"
@@ -264,3 +265,104 @@ func TestExecuteWithAuth(t *testing.T) {
 		t.Errorf("auth = %v, want 'Bearer test-token-123'", outputMap["auth"])
 	}
 }
+
+func TestExecuteRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	doc := fmt.Sprintf(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Test", "version": "1.0.0"},
+		"servers": [{"url": %q}],
+		"paths": {
+			"/busy": {
+				"get": {"responses": {"200": {"description": "ok"}}}
+			}
+		}
+	}`, server.URL)
+
+	result := Execute(context.Background(), delegates.ExecuteInput{
+		Source: delegates.Source{Format: "openapi@3.1", Content: doc},
+		Ref:    "#/paths/~1busy/get",
+	})
+	if result.Status != http.StatusServiceUnavailable || result.Error == nil {
+		t.Fatalf("Status = %d, Error = %v, want 503 error", result.Status, result.Error)
+	}
+	details, ok := result.Error.Details.(map[string]any)
+	if !ok || details[delegates.RetryAfterDetailsKey] != int64(2000) {
+		t.Errorf("Details = %v, want %s 2000", result.Error.Details, delegates.RetryAfterDetailsKey)
+	}
+}
+
+func TestParseLinkHeader(t *testing.T) {
+	base, _ := url.Parse("https://api.example.com/v1/pets?page=1")
+	got := parseLinkHeader([]string{
+		`<https://api.example.com/pets?page=2>; rel="next", <https://api.example.com/pets?page=9>; rel="last"`,
+		`</pets?page=1>; rel="prev first"`,
+	}, base)
+	want := map[string]string{
+		"next":  "https://api.example.com/pets?page=2",
+		"last":  "https://api.example.com/pets?page=9",
+		"prev":  "https://api.example.com/pets?page=1",
+		"first": "https://api.example.com/pets?page=1",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for rel, url := range want {
+		if got[rel] != url {
+			t.Errorf("%s = %q, want %q", rel, got[rel], url)
+		}
+	}
+	if parseLinkHeader(nil, base) != nil {
+		t.Error("expected nil for no Link header")
+	}
+}
+
+func TestExecuteRequestURLOverride(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"path":%q,"query":%q,"auth":%q}`, r.URL.Path, r.URL.RawQuery, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	doc := fmt.Sprintf(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Test", "version": "1.0.0"},
+		"servers": [{"url": %q}],
+		"paths": {"/pets": {"get": {
+			"parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer"}}],
+			"responses": {"200": {"description": "ok"}}
+		}}}
+	}`, server.URL)
+	execute := func(target string) delegates.ExecuteOutput {
+		return Execute(context.Background(), delegates.ExecuteInput{
+			Source: delegates.Source{Format: "openapi@3.1", Content: doc},
+			Ref:    "#/paths/~1pets/get",
+			Input:  map[string]any{"limit": 2},
+			Context: &delegates.BindingContext{
+				Credentials: &delegates.Credentials{BearerToken: "t"},
+				Metadata:    map[string]any{delegates.RequestURLKey: target},
+			},
+		})
+	}
+
+	result := execute(server.URL + "/v2/pets?page_token=abc")
+	if result.Error != nil {
+		t.Fatalf("Execute: %s", result.Error.Message)
+	}
+	want := map[string]any{"path": "/v2/pets", "query": "page_token=abc", "auth": "Bearer t"}
+	for k, v := range want {
+		if result.Output.(map[string]any)[k] != v {
+			t.Errorf("%s = %v, want %v", k, result.Output.(map[string]any)[k], v)
+		}
+	}
+
+	result = execute("https://elsewhere.example.com/pets?page_token=abc")
+	if result.Error == nil || result.Error.Code != "invalid_request_url" {
+		t.Errorf("request URL on another origin: error = %+v, want invalid_request_url", result.Error)
+	}
+}
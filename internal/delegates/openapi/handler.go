@@ -1,7 +1,7 @@
 // Package openapi implements the OpenAPI binding format handler delegate.
 //
 // The openapi handler handles:
-//   - Converting OpenAPI 3.x documents to OpenBindings interfaces
+//   - Converting OpenAPI 3.x and Swagger 2.0 documents to OpenBindings interfaces
 //   - Executing operations via HTTP requests
 package openapi
 
@@ -24,7 +24,7 @@ func New() *Handler {
 func (h *Handler) GetInfo() delegates.SoftwareInfo {
 	return delegates.SoftwareInfo{
 		Name:        "OpenAPI",
-		Description: "REST APIs described by OpenAPI 3.x specifications",
+		Description: "REST APIs described by OpenAPI 3.x or Swagger 2.0 specifications",
 	}
 }
 
@@ -35,6 +35,10 @@ func (h *Handler) ListFormats() []delegates.FormatInfo {
 			Token:       FormatToken,
 			Description: "OpenAPI 3.x specifications (JSON or YAML)",
 		},
+		{
+			Token:       SwaggerFormatToken,
+			Description: "Swagger 2.0 specifications (JSON or YAML), converted to OpenAPI 3",
+		},
 	}
 }
 
@@ -48,6 +52,11 @@ func (h *Handler) ExecuteOperation(ctx context.Context, input delegates.ExecuteI
 	return Execute(ctx, input)
 }
 
+// ResolveServers returns the base URLs OpenAPI operations would be sent to.
+func (h *Handler) ResolveServers(source delegates.Source, refs []string, bindCtx *delegates.BindingContext) (map[string]string, error) {
+	return ResolveServers(source, refs, bindCtx)
+}
+
 // Register registers the OpenAPI handler with a registry.
 func Register(r *delegates.Registry) {
 	r.Register(New())
@@ -0,0 +1,284 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Parameter styles defined by OpenAPI 3.
+const (
+	styleSimple         = "simple"
+	styleLabel          = "label"
+	styleMatrix         = "matrix"
+	styleForm           = "form"
+	styleSpaceDelimited = "spaceDelimited"
+	stylePipeDelimited  = "pipeDelimited"
+	styleDeepObject     = "deepObject"
+)
+
+// paramStyle returns a parameter's style and explode setting, applying the
+// OpenAPI defaults for its location.
+func paramStyle(p *openapi3.Parameter) (style string, explode bool) {
+	style = p.Style
+	if style == "" {
+		switch p.In {
+		case openapi3.ParameterInQuery, openapi3.ParameterInCookie:
+			style = styleForm
+		default:
+			style = styleSimple
+		}
+	}
+	if p.Explode != nil {
+		return style, *p.Explode
+	}
+	return style, style == styleForm
+}
+
+// serializePathParam renders a path parameter value, percent-encoded, ready
+// to replace {name} in the path template.
+func serializePathParam(p *openapi3.Parameter, value any) string {
+	style, explode := paramStyle(p)
+	enc := func(s string) string { return escapeComponent(s, false) }
+	if p.Content != nil {
+		return prefixed(style, p.Name, enc(contentValue(value)))
+	}
+
+	switch v := value.(type) {
+	case []any:
+		items := mapStrings(v, enc)
+		switch style {
+		case styleLabel:
+			if explode {
+				return "." + strings.Join(items, ".")
+			}
+			return "." + strings.Join(items, ",")
+		case styleMatrix:
+			if explode {
+				parts := make([]string, len(items))
+				for i, item := range items {
+					parts[i] = ";" + p.Name + "=" + item
+				}
+				return strings.Join(parts, "")
+			}
+			return ";" + p.Name + "=" + strings.Join(items, ",")
+		default:
+			return strings.Join(items, ",")
+		}
+	case map[string]any:
+		keys := sortedKeys(v)
+		switch style {
+		case styleLabel:
+			if explode {
+				return "." + strings.Join(objectPairs(v, keys, "=", enc), ".")
+			}
+			return "." + strings.Join(objectFlat(v, keys, enc), ",")
+		case styleMatrix:
+			if explode {
+				return ";" + strings.Join(objectPairs(v, keys, "=", enc), ";")
+			}
+			return ";" + p.Name + "=" + strings.Join(objectFlat(v, keys, enc), ",")
+		default:
+			if explode {
+				return strings.Join(objectPairs(v, keys, "=", enc), ",")
+			}
+			return strings.Join(objectFlat(v, keys, enc), ",")
+		}
+	default:
+		return prefixed(style, p.Name, enc(primitiveString(v)))
+	}
+}
+
+// prefixed applies the label or matrix prefix to a single encoded value.
+func prefixed(style, name, encoded string) string {
+	switch style {
+	case styleLabel:
+		return "." + encoded
+	case styleMatrix:
+		return ";" + name + "=" + encoded
+	default:
+		return encoded
+	}
+}
+
+// serializeQueryParam renders a query parameter as encoded "name=value" pairs.
+func serializeQueryParam(p *openapi3.Parameter, value any) []string {
+	style, explode := paramStyle(p)
+	name := escapeComponent(p.Name, false)
+	enc := func(s string) string { return escapeComponent(s, p.AllowReserved) }
+	if p.Content != nil {
+		return []string{name + "=" + enc(contentValue(value))}
+	}
+
+	switch v := value.(type) {
+	case []any:
+		items := mapStrings(v, enc)
+		if explode && style != styleDeepObject {
+			pairs := make([]string, len(items))
+			for i, item := range items {
+				pairs[i] = name + "=" + item
+			}
+			return pairs
+		}
+		return []string{name + "=" + strings.Join(items, delimiter(style))}
+	case map[string]any:
+		keys := sortedKeys(v)
+		switch {
+		case style == styleDeepObject:
+			pairs := make([]string, len(keys))
+			for i, k := range keys {
+				pairs[i] = name + "[" + escapeComponent(k, false) + "]=" + enc(primitiveString(v[k]))
+			}
+			return pairs
+		case explode:
+			return objectPairs(v, keys, "=", enc)
+		default:
+			return []string{name + "=" + strings.Join(objectFlat(v, keys, enc), delimiter(style))}
+		}
+	default:
+		return []string{name + "=" + enc(primitiveString(v))}
+	}
+}
+
+// delimiter returns the separator for non-exploded query values.
+func delimiter(style string) string {
+	switch style {
+	case styleSpaceDelimited:
+		return "%20"
+	case stylePipeDelimited:
+		return "|"
+	default:
+		return ","
+	}
+}
+
+// serializeHeaderParam renders a header parameter value (simple style,
+// not percent-encoded).
+func serializeHeaderParam(p *openapi3.Parameter, value any) string {
+	if p.Content != nil {
+		return contentValue(value)
+	}
+	_, explode := paramStyle(p)
+	return serializeUnencoded(value, explode)
+}
+
+// serializeCookieParam renders a cookie parameter value. Arrays and objects
+// use the non-exploded form representation since a cookie holds one value.
+func serializeCookieParam(p *openapi3.Parameter, value any) string {
+	if p.Content != nil {
+		return contentValue(value)
+	}
+	return serializeUnencoded(value, false)
+}
+
+// serializeUnencoded renders a value in simple style without percent-encoding.
+func serializeUnencoded(value any, explode bool) string {
+	identity := func(s string) string { return s }
+	switch v := value.(type) {
+	case []any:
+		return strings.Join(mapStrings(v, identity), ",")
+	case map[string]any:
+		keys := sortedKeys(v)
+		if explode {
+			return strings.Join(objectPairs(v, keys, "=", identity), ",")
+		}
+		return strings.Join(objectFlat(v, keys, identity), ",")
+	default:
+		return primitiveString(v)
+	}
+}
+
+// objectPairs renders object entries as "key<sep>value" strings.
+func objectPairs(m map[string]any, keys []string, sep string, enc func(string) string) []string {
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		out[i] = enc(k) + sep + enc(primitiveString(m[k]))
+	}
+	return out
+}
+
+// objectFlat renders object entries as alternating key, value strings.
+func objectFlat(m map[string]any, keys []string, enc func(string) string) []string {
+	out := make([]string, 0, len(keys)*2)
+	for _, k := range keys {
+		out = append(out, enc(k), enc(primitiveString(m[k])))
+	}
+	return out
+}
+
+func mapStrings(items []any, enc func(string) string) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = enc(primitiveString(item))
+	}
+	return out
+}
+
+// primitiveString renders a scalar JSON value. Nested structures are
+// rendered as JSON.
+func primitiveString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case bool:
+		return strconv.FormatBool(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(t)
+	case int64:
+		return strconv.FormatInt(t, 10)
+	case json.Number:
+		return t.String()
+	case map[string]any, []any:
+		data, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Sprintf("%v", t)
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+// contentValue renders a parameter described by "content" (rather than a
+// schema) as JSON. Strings are passed through.
+func contentValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// escapeComponent percent-encodes s per RFC 3986, leaving unreserved
+// characters intact. When allowReserved is set, reserved characters are also
+// left intact.
+func escapeComponent(s string, allowReserved bool) string {
+	const hex = "0123456789ABCDEF"
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isUnreserved(c) || (allowReserved && strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0) {
+			sb.WriteByte(c)
+			continue
+		}
+		sb.WriteByte('%')
+		sb.WriteByte(hex[c>>4])
+		sb.WriteByte(hex[c&0x0f])
+	}
+	return sb.String()
+}
+
+func isUnreserved(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
@@ -0,0 +1,199 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// Values from the OpenAPI "Style Examples" table. Object keys are emitted in
+// sorted order, so B, G, R rather than the table's R, G, B.
+var (
+	styleString = "blue"
+	styleArray  = []any{"blue", "black", "brown"}
+	styleObject = map[string]any{"R": float64(100), "G": float64(200), "B": float64(150)}
+)
+
+func styledParam(in, style string, explode bool) *openapi3.Parameter {
+	return &openapi3.Parameter{Name: "color", In: in, Style: style, Explode: &explode}
+}
+
+func TestSerializePathParam(t *testing.T) {
+	tests := []struct {
+		style   string
+		explode bool
+		value   any
+		want    string
+	}{
+		{styleSimple, false, styleString, "blue"},
+		{styleSimple, false, styleArray, "blue,black,brown"},
+		{styleSimple, false, styleObject, "B,150,G,200,R,100"},
+		{styleSimple, true, styleArray, "blue,black,brown"},
+		{styleSimple, true, styleObject, "B=150,G=200,R=100"},
+		{styleLabel, false, styleString, ".blue"},
+		{styleLabel, false, styleArray, ".blue,black,brown"},
+		{styleLabel, false, styleObject, ".B,150,G,200,R,100"},
+		{styleLabel, true, styleArray, ".blue.black.brown"},
+		{styleLabel, true, styleObject, ".B=150.G=200.R=100"},
+		{styleMatrix, false, styleString, ";color=blue"},
+		{styleMatrix, false, styleArray, ";color=blue,black,brown"},
+		{styleMatrix, false, styleObject, ";color=B,150,G,200,R,100"},
+		{styleMatrix, true, styleArray, ";color=blue;color=black;color=brown"},
+		{styleMatrix, true, styleObject, ";B=150;G=200;R=100"},
+		// Percent-encoding of reserved characters and spaces.
+		{styleSimple, false, "a/b c?", "a%2Fb%20c%3F"},
+		{styleSimple, false, []any{"a,b", "c"}, "a%2Cb,c"},
+		{styleSimple, false, float64(1e6), "1000000"},
+	}
+	for _, tt := range tests {
+		name := fmt.Sprintf("%s/explode=%v/%v", tt.style, tt.explode, tt.value)
+		t.Run(name, func(t *testing.T) {
+			got := serializePathParam(styledParam(openapi3.ParameterInPath, tt.style, tt.explode), tt.value)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSerializeQueryParam(t *testing.T) {
+	tests := []struct {
+		style   string
+		explode bool
+		value   any
+		want    string
+	}{
+		{styleForm, false, styleString, "color=blue"},
+		{styleForm, false, styleArray, "color=blue,black,brown"},
+		{styleForm, false, styleObject, "color=B,150,G,200,R,100"},
+		{styleForm, true, styleString, "color=blue"},
+		{styleForm, true, styleArray, "color=blue&color=black&color=brown"},
+		{styleForm, true, styleObject, "B=150&G=200&R=100"},
+		{styleSpaceDelimited, false, styleArray, "color=blue%20black%20brown"},
+		{styleSpaceDelimited, false, styleObject, "color=B%20150%20G%20200%20R%20100"},
+		{stylePipeDelimited, false, styleArray, "color=blue|black|brown"},
+		{stylePipeDelimited, false, styleObject, "color=B|150|G|200|R|100"},
+		{styleDeepObject, true, styleObject, "color[B]=150&color[G]=200&color[R]=100"},
+		{styleForm, true, "a&b=c d", "color=a%26b%3Dc%20d"},
+	}
+	for _, tt := range tests {
+		name := fmt.Sprintf("%s/explode=%v/%v", tt.style, tt.explode, tt.value)
+		t.Run(name, func(t *testing.T) {
+			got := strings.Join(serializeQueryParam(styledParam(openapi3.ParameterInQuery, tt.style, tt.explode), tt.value), "&")
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSerializeQueryParam_Defaults(t *testing.T) {
+	p := &openapi3.Parameter{Name: "id", In: openapi3.ParameterInQuery}
+	if got := strings.Join(serializeQueryParam(p, []any{float64(3), float64(4)}), "&"); got != "id=3&id=4" {
+		t.Errorf("default query style = %q, want form exploded", got)
+	}
+
+	p.AllowReserved = true
+	if got := strings.Join(serializeQueryParam(p, "a/b?c"), "&"); got != "id=a/b?c" {
+		t.Errorf("allowReserved = %q, want reserved characters kept", got)
+	}
+
+	p = &openapi3.Parameter{Name: "filter", In: openapi3.ParameterInQuery, Content: openapi3.NewContentWithJSONSchema(openapi3.NewObjectSchema())}
+	if got := strings.Join(serializeQueryParam(p, map[string]any{"a": float64(1)}), "&"); got != "filter=%7B%22a%22%3A1%7D" {
+		t.Errorf("content param = %q, want encoded JSON", got)
+	}
+}
+
+func TestSerializeHeaderAndCookieParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		param *openapi3.Parameter
+		value any
+		want  string
+	}{
+		{"header string", styledParam(openapi3.ParameterInHeader, styleSimple, false), styleString, "blue"},
+		{"header array", styledParam(openapi3.ParameterInHeader, styleSimple, false), styleArray, "blue,black,brown"},
+		{"header object", styledParam(openapi3.ParameterInHeader, styleSimple, false), styleObject, "B,150,G,200,R,100"},
+		{"header object exploded", styledParam(openapi3.ParameterInHeader, styleSimple, true), styleObject, "B=150,G=200,R=100"},
+		{"cookie string", styledParam(openapi3.ParameterInCookie, styleForm, true), styleString, "blue"},
+		{"cookie array", styledParam(openapi3.ParameterInCookie, styleForm, false), styleArray, "blue,black,brown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			if tt.param.In == openapi3.ParameterInHeader {
+				got = serializeHeaderParam(tt.param, tt.value)
+			} else {
+				got = serializeCookieParam(tt.param, tt.value)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteSerializesParameters(t *testing.T) {
+	var gotPath, gotQuery, gotHeader, gotCookie string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotQuery = r.URL.RawQuery
+		gotHeader = r.Header.Get("X-Trace")
+		if c, err := r.Cookie("session"); err == nil {
+			gotCookie = c.Value
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	doc := fmt.Sprintf(`{
+		"openapi": "3.0.3",
+		"info": {"title": "Test", "version": "1.0.0"},
+		"servers": [{"url": %q}],
+		"paths": {
+			"/files/{name}": {"get": {
+				"parameters": [
+					{"name": "name", "in": "path", "required": true, "schema": {"type": "string"}},
+					{"name": "tag", "in": "query", "schema": {"type": "array", "items": {"type": "string"}}},
+					{"name": "filter", "in": "query", "style": "deepObject", "explode": true, "schema": {"type": "object"}},
+					{"name": "X-Trace", "in": "header", "schema": {"type": "array", "items": {"type": "string"}}},
+					{"name": "session", "in": "cookie", "schema": {"type": "string"}}
+				],
+				"responses": {"204": {"description": "ok"}}
+			}}
+		}
+	}`, srv.URL)
+
+	result := Execute(context.Background(), delegates.ExecuteInput{
+		Source: delegates.Source{Format: "openapi@3.0", Content: doc},
+		Ref:    "#/paths/~1files~1{name}/get",
+		Input: map[string]any{
+			"name":    "q3 report/final",
+			"tag":     []any{"a", "b"},
+			"filter":  map[string]any{"owner": "me"},
+			"X-Trace": []any{"x", "y"},
+			"session": "abc",
+		},
+	})
+	if result.Error != nil {
+		t.Fatalf("Execute failed: %s", result.Error.Message)
+	}
+	if gotPath != "/files/q3%20report%2Ffinal" {
+		t.Errorf("path = %q, want percent-encoded segment", gotPath)
+	}
+	if gotQuery != "tag=a&tag=b&filter[owner]=me" {
+		t.Errorf("query = %q, want repeated tag and deepObject filter", gotQuery)
+	}
+	if gotHeader != "x,y" {
+		t.Errorf("header = %q, want x,y", gotHeader)
+	}
+	if gotCookie != "abc" {
+		t.Errorf("cookie = %q, want abc", gotCookie)
+	}
+}
@@ -0,0 +1,305 @@
+package openapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// Keys in Credentials.Custom used for OAuth2 client credentials. When absent,
+// Credentials.Basic is used as the client ID and secret.
+const (
+	customClientID     = "clientId"
+	customClientSecret = "clientSecret"
+)
+
+// tokenExpiryLeeway is subtracted from token lifetimes so a cached token is
+// not sent just as it expires.
+const tokenExpiryLeeway = 30 * time.Second
+
+// securityRequirements returns the security requirements that apply to an
+// operation: the operation's own list when declared (an empty list disables
+// security), otherwise the document-level list. Nil means the document does
+// not describe security for this operation.
+func securityRequirements(doc *openapi3.T, op *openapi3.Operation) openapi3.SecurityRequirements {
+	if op.Security != nil {
+		return *op.Security
+	}
+	return doc.Security
+}
+
+// applySecurity authenticates req according to the first non-empty security
+// requirement that the context credentials can satisfy. It reports whether a
+// requirement was satisfied, counting an empty (optional) requirement when no
+// other one is; when none is, the request is left untouched.
+//
+// Credentials map onto schemes as follows:
+//   - apiKey (query, header or cookie): Custom[<scheme name>] or APIKey
+//   - http basic: Basic
+//   - http bearer, oauth2, openIdConnect: Custom[<scheme name>] or BearerToken
+//   - oauth2 clientCredentials: a token fetched from the flow's tokenUrl using
+//     Custom["clientId"]/Custom["clientSecret"] (or Basic), when no bearer
+//     token is configured
+func applySecurity(ctx context.Context, client *http.Client, req *http.Request, doc *openapi3.T, requirements openapi3.SecurityRequirements, creds *delegates.Credentials) (bool, error) {
+	// An explicitly empty list disables security for the operation.
+	if requirements != nil && len(requirements) == 0 {
+		return true, nil
+	}
+
+	var schemes openapi3.SecuritySchemes
+	if doc.Components != nil {
+		schemes = doc.Components.SecuritySchemes
+	}
+
+	// An empty requirement makes authentication optional; it is only used
+	// when no other requirement can be satisfied.
+	optional := false
+	for _, requirement := range requirements {
+		if len(requirement) == 0 {
+			optional = true
+			continue
+		}
+		if creds == nil || !canSatisfy(requirement, schemes, creds) {
+			continue
+		}
+		for _, name := range sortedSchemeNames(requirement) {
+			scheme := schemes[name].Value
+			if err := applyScheme(ctx, client, req, name, scheme, requirement[name], creds); err != nil {
+				return false, fmt.Errorf("security scheme %q: %w", name, err)
+			}
+		}
+		return true, nil
+	}
+	return optional, nil
+}
+
+// canSatisfy reports whether creds hold what every scheme in a requirement needs.
+func canSatisfy(requirement openapi3.SecurityRequirement, schemes openapi3.SecuritySchemes, creds *delegates.Credentials) bool {
+	for name := range requirement {
+		ref := schemes[name]
+		if ref == nil || ref.Value == nil {
+			return false
+		}
+		scheme := ref.Value
+		switch strings.ToLower(scheme.Type) {
+		case "apikey":
+			if apiKeyValue(name, creds) == "" {
+				return false
+			}
+		case "http":
+			switch strings.ToLower(scheme.Scheme) {
+			case "basic":
+				if creds.Basic == nil {
+					return false
+				}
+			case "bearer":
+				if bearerValue(name, creds) == "" {
+					return false
+				}
+			default:
+				return false
+			}
+		case "oauth2":
+			if bearerValue(name, creds) != "" {
+				continue
+			}
+			if clientCredentialsFlow(scheme) == nil {
+				return false
+			}
+			if id, _ := clientCredentials(creds); id == "" {
+				return false
+			}
+		case "openidconnect":
+			if bearerValue(name, creds) == "" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// applyScheme applies a single security scheme to req.
+func applyScheme(ctx context.Context, client *http.Client, req *http.Request, name string, scheme *openapi3.SecurityScheme, scopes []string, creds *delegates.Credentials) error {
+	switch strings.ToLower(scheme.Type) {
+	case "apikey":
+		value := apiKeyValue(name, creds)
+		switch strings.ToLower(scheme.In) {
+		case "query":
+			// Append rather than re-encode so serialized parameters stay intact.
+			pair := escapeComponent(scheme.Name, false) + "=" + escapeComponent(value, false)
+			if req.URL.RawQuery != "" {
+				req.URL.RawQuery += "&"
+			}
+			req.URL.RawQuery += pair
+		case "header":
+			req.Header.Set(scheme.Name, value)
+		case "cookie":
+			req.AddCookie(&http.Cookie{Name: scheme.Name, Value: value})
+		default:
+			return fmt.Errorf("unsupported apiKey location %q", scheme.In)
+		}
+	case "http":
+		if strings.EqualFold(scheme.Scheme, "basic") {
+			req.SetBasicAuth(creds.Basic.Username, creds.Basic.Password)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+bearerValue(name, creds))
+		}
+	case "oauth2", "openidconnect":
+		token := bearerValue(name, creds)
+		if token == "" {
+			flow := clientCredentialsFlow(scheme)
+			id, secret := clientCredentials(creds)
+			// Relative token URLs are resolved against the request URL.
+			tokenURL := delegates.ResolveSourceURL(req.URL.String(), flow.TokenURL)
+			fetched, err := clientCredentialsToken(ctx, client, tokenURL, id, secret, scopes)
+			if err != nil {
+				return err
+			}
+			token = fetched
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return nil
+}
+
+func sortedSchemeNames(requirement openapi3.SecurityRequirement) []string {
+	names := make([]string, 0, len(requirement))
+	for name := range requirement {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// customString returns Credentials.Custom[key] if it is a non-empty string.
+func customString(creds *delegates.Credentials, key string) string {
+	if creds.Custom == nil {
+		return ""
+	}
+	s, _ := creds.Custom[key].(string)
+	return s
+}
+
+func apiKeyValue(name string, creds *delegates.Credentials) string {
+	if v := customString(creds, name); v != "" {
+		return v
+	}
+	return creds.APIKey
+}
+
+func bearerValue(name string, creds *delegates.Credentials) string {
+	if v := customString(creds, name); v != "" {
+		return v
+	}
+	return creds.BearerToken
+}
+
+func clientCredentials(creds *delegates.Credentials) (id, secret string) {
+	if id := customString(creds, customClientID); id != "" {
+		return id, customString(creds, customClientSecret)
+	}
+	if creds.Basic != nil {
+		return creds.Basic.Username, creds.Basic.Password
+	}
+	return "", ""
+}
+
+func clientCredentialsFlow(scheme *openapi3.SecurityScheme) *openapi3.OAuthFlow {
+	if !strings.EqualFold(scheme.Type, "oauth2") || scheme.Flows == nil {
+		return nil
+	}
+	flow := scheme.Flows.ClientCredentials
+	if flow == nil || flow.TokenURL == "" {
+		return nil
+	}
+	return flow
+}
+
+// cachedToken is an OAuth2 access token with its expiry (zero if unknown).
+type cachedToken struct {
+	accessToken string
+	expiresAt   time.Time
+}
+
+// tokenCache holds client-credentials tokens for the life of the process,
+// keyed by token URL, client and scopes so each context gets its own token.
+var tokenCache = struct {
+	sync.Mutex
+	tokens map[string]cachedToken
+}{tokens: map[string]cachedToken{}}
+
+// clientCredentialsToken returns an access token for the client-credentials
+// grant, fetching one from tokenURL with client when no unexpired token is
+// cached.
+func clientCredentialsToken(ctx context.Context, client *http.Client, tokenURL, clientID, clientSecret string, scopes []string) (string, error) {
+	secretSum := sha256.Sum256([]byte(clientSecret))
+	key := strings.Join([]string{tokenURL, clientID, hex.EncodeToString(secretSum[:]), strings.Join(scopes, " ")}, "\x00")
+
+	tokenCache.Lock()
+	cached, ok := tokenCache.tokens[key]
+	tokenCache.Unlock()
+	if ok && (cached.expiresAt.IsZero() || time.Now().Before(cached.expiresAt)) {
+		return cached.accessToken, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(scopes) > 0 {
+		form.Set("scope", strings.Join(scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read token response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("token request to %q failed: %s", tokenURL, resp.Status)
+	}
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tok); err != nil {
+		return "", fmt.Errorf("invalid token response: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return "", fmt.Errorf("token response has no access_token")
+	}
+
+	entry := cachedToken{accessToken: tok.AccessToken}
+	if tok.ExpiresIn > 0 {
+		entry.expiresAt = time.Now().Add(time.Duration(tok.ExpiresIn)*time.Second - tokenExpiryLeeway)
+	}
+	tokenCache.Lock()
+	tokenCache.tokens[key] = entry
+	tokenCache.Unlock()
+
+	return tok.AccessToken, nil
+}
@@ -0,0 +1,219 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// echoAuthServer returns a server that echoes the authentication-related
+// parts of each request.
+func echoAuthServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie := ""
+		if c, err := r.Cookie("session"); err == nil {
+			cookie = c.Value
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"authorization":%q,"xApiKey":%q,"queryKey":%q,"cookie":%q}`,
+			r.Header.Get("Authorization"), r.Header.Get("X-API-Key"), r.URL.Query().Get("api_key"), cookie)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// securedDoc builds a document with a single GET /secure operation and the
+// given security schemes and requirements.
+func securedDoc(serverURL, schemes, security string) string {
+	return fmt.Sprintf(`{
+		"openapi": "3.0.3",
+		"info": {"title": "Test", "version": "1.0.0"},
+		"servers": [{"url": %q}],
+		"components": {"securitySchemes": %s},
+		"security": %s,
+		"paths": {
+			"/secure": {"get": {"responses": {"200": {"description": "ok"}}}}
+		}
+	}`, serverURL, schemes, security)
+}
+
+func executeSecured(t *testing.T, doc string, creds *delegates.Credentials) map[string]any {
+	t.Helper()
+	result := Execute(context.Background(), delegates.ExecuteInput{
+		Source:  delegates.Source{Format: "openapi@3.0", Content: doc},
+		Ref:     "#/paths/~1secure/get",
+		Context: &delegates.BindingContext{Credentials: creds},
+	})
+	if result.Error != nil {
+		t.Fatalf("Execute failed: %s", result.Error.Message)
+	}
+	out, ok := result.Output.(map[string]any)
+	if !ok {
+		t.Fatalf("Output is %T, want map[string]any", result.Output)
+	}
+	return out
+}
+
+func TestExecuteSecuritySchemes(t *testing.T) {
+	srv := echoAuthServer(t)
+
+	tests := []struct {
+		name     string
+		schemes  string
+		security string
+		creds    *delegates.Credentials
+		want     map[string]string
+	}{
+		{
+			name:     "apiKey in header",
+			schemes:  `{"key": {"type": "apiKey", "in": "header", "name": "X-API-Key"}}`,
+			security: `[{"key": []}]`,
+			creds:    &delegates.Credentials{APIKey: "k1"},
+			want:     map[string]string{"xApiKey": "k1", "authorization": ""},
+		},
+		{
+			name:     "apiKey in query",
+			schemes:  `{"key": {"type": "apiKey", "in": "query", "name": "api_key"}}`,
+			security: `[{"key": []}]`,
+			creds:    &delegates.Credentials{APIKey: "k2"},
+			want:     map[string]string{"queryKey": "k2", "authorization": ""},
+		},
+		{
+			name:     "apiKey in cookie",
+			schemes:  `{"key": {"type": "apiKey", "in": "cookie", "name": "session"}}`,
+			security: `[{"key": []}]`,
+			creds:    &delegates.Credentials{APIKey: "k3"},
+			want:     map[string]string{"cookie": "k3", "authorization": ""},
+		},
+		{
+			name:     "apiKey per scheme from custom",
+			schemes:  `{"key": {"type": "apiKey", "in": "header", "name": "X-API-Key"}}`,
+			security: `[{"key": []}]`,
+			creds:    &delegates.Credentials{APIKey: "generic", Custom: map[string]any{"key": "specific"}},
+			want:     map[string]string{"xApiKey": "specific"},
+		},
+		{
+			name:     "http basic",
+			schemes:  `{"basic": {"type": "http", "scheme": "basic"}}`,
+			security: `[{"basic": []}]`,
+			creds:    &delegates.Credentials{Basic: &delegates.BasicCredentials{Username: "u", Password: "p"}},
+			want:     map[string]string{"authorization": "Basic dTpw"},
+		},
+		{
+			name:     "http bearer",
+			schemes:  `{"jwt": {"type": "http", "scheme": "bearer"}}`,
+			security: `[{"jwt": []}]`,
+			creds:    &delegates.Credentials{BearerToken: "t1"},
+			want:     map[string]string{"authorization": "Bearer t1"},
+		},
+		{
+			name:     "first satisfiable alternative wins",
+			schemes:  `{"jwt": {"type": "http", "scheme": "bearer"}, "key": {"type": "apiKey", "in": "header", "name": "X-API-Key"}}`,
+			security: `[{"jwt": []}, {"key": []}]`,
+			creds:    &delegates.Credentials{APIKey: "k4"},
+			want:     map[string]string{"xApiKey": "k4", "authorization": ""},
+		},
+		{
+			name:     "all schemes in a requirement are applied",
+			schemes:  `{"jwt": {"type": "http", "scheme": "bearer"}, "key": {"type": "apiKey", "in": "query", "name": "api_key"}}`,
+			security: `[{"jwt": [], "key": []}]`,
+			creds:    &delegates.Credentials{BearerToken: "t2", APIKey: "k5"},
+			want:     map[string]string{"authorization": "Bearer t2", "queryKey": "k5"},
+		},
+		{
+			name:     "unsatisfied falls back to generic credentials",
+			schemes:  `{"basic": {"type": "http", "scheme": "basic"}}`,
+			security: `[{"basic": []}]`,
+			creds:    &delegates.Credentials{BearerToken: "t3"},
+			want:     map[string]string{"authorization": "Bearer t3"},
+		},
+		{
+			name:     "optional requirement yields to a satisfiable one",
+			schemes:  `{"jwt": {"type": "http", "scheme": "bearer"}}`,
+			security: `[{}, {"jwt": []}]`,
+			creds:    &delegates.Credentials{BearerToken: "t5"},
+			want:     map[string]string{"authorization": "Bearer t5"},
+		},
+		{
+			name:     "optional requirement when none is satisfiable",
+			schemes:  `{"basic": {"type": "http", "scheme": "basic"}}`,
+			security: `[{}, {"basic": []}]`,
+			creds:    &delegates.Credentials{BearerToken: "t6"},
+			want:     map[string]string{"authorization": ""},
+		},
+		{
+			name:     "empty security disables auth",
+			schemes:  `{}`,
+			security: `[]`,
+			creds:    &delegates.Credentials{BearerToken: "t4"},
+			want:     map[string]string{"authorization": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := executeSecured(t, securedDoc(srv.URL, tt.schemes, tt.security), tt.creds)
+			for k, want := range tt.want {
+				if out[k] != want {
+					t.Errorf("%s = %q, want %q", k, out[k], want)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteOAuth2ClientCredentials(t *testing.T) {
+	var tokenRequests atomic.Int32
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests.Add(1)
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "client" || secret != "s3cret" {
+			http.Error(w, "bad client", http.StatusUnauthorized)
+			return
+		}
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" {
+			http.Error(w, "bad grant", http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("scope") != "read" {
+			http.Error(w, "bad scope", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token": "cc-token", "token_type": "bearer", "expires_in": 3600}`)
+	}))
+	defer tokenSrv.Close()
+
+	srv := echoAuthServer(t)
+	doc := securedDoc(srv.URL,
+		fmt.Sprintf(`{"oauth": {"type": "oauth2", "flows": {"clientCredentials": {"tokenUrl": %q, "scopes": {"read": "Read"}}}}}`, tokenSrv.URL+"/token"),
+		`[{"oauth": ["read"]}]`)
+	creds := &delegates.Credentials{Custom: map[string]any{"clientId": "client", "clientSecret": "s3cret"}}
+
+	for i := 0; i < 2; i++ {
+		out := executeSecured(t, doc, creds)
+		if out["authorization"] != "Bearer cc-token" {
+			t.Fatalf("authorization = %v, want Bearer cc-token", out["authorization"])
+		}
+	}
+	if n := tokenRequests.Load(); n != 1 {
+		t.Fatalf("token requests = %d, want 1 (token should be cached)", n)
+	}
+
+	// A different client gets its own token.
+	other := &delegates.Credentials{Basic: &delegates.BasicCredentials{Username: "client", Password: "wrong"}}
+	result := Execute(context.Background(), delegates.ExecuteInput{
+		Source:  delegates.Source{Format: "openapi@3.0", Content: doc},
+		Ref:     "#/paths/~1secure/get",
+		Context: &delegates.BindingContext{Credentials: other},
+	})
+	if result.Error == nil || result.Error.Code != "auth_failed" {
+		t.Fatalf("error = %+v, want auth_failed for rejected client", result.Error)
+	}
+}
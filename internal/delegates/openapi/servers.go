@@ -0,0 +1,229 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// BindingContext.Metadata keys that control server selection.
+const (
+	// metadataBaseURL overrides the server URL entirely.
+	metadataBaseURL = "baseURL"
+	// metadataServer selects a server by index or description.
+	metadataServer = "server"
+	// metadataServerVarPrefix prefixes server variable overrides,
+	// e.g. "server.region" = "eu".
+	metadataServerVarPrefix = "server."
+	// metadataServerVariables holds server variable overrides as an object.
+	metadataServerVariables = "serverVariables"
+)
+
+// serverVarPattern matches {name} placeholders in server URLs.
+var serverVarPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// ResolveServer loads the document and returns the base URL the operation
+// identified by input.Ref would be sent to, after server selection and
+// variable substitution.
+func ResolveServer(input delegates.ExecuteInput) (string, error) {
+	doc, err := loadDocument(input.Source, input.Context)
+	if err != nil {
+		return "", err
+	}
+	return resolveRefServer(doc, input.Ref, input.Context)
+}
+
+// ResolveServers loads the document once and returns the base URL of each
+// of refs, as ResolveServer does. Refs whose server cannot be resolved are
+// omitted.
+func ResolveServers(source delegates.Source, refs []string, bindCtx *delegates.BindingContext) (map[string]string, error) {
+	doc, err := loadDocument(source, bindCtx)
+	if err != nil {
+		return nil, err
+	}
+	servers := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		if server, err := resolveRefServer(doc, ref, bindCtx); err == nil {
+			servers[ref] = server
+		}
+	}
+	return servers, nil
+}
+
+// resolveRefServer returns the base URL of the operation ref in doc.
+func resolveRefServer(doc *openapi3.T, ref string, bindCtx *delegates.BindingContext) (string, error) {
+	pathTemplate, method, err := parseRef(ref)
+	if err != nil {
+		return "", err
+	}
+	pathItem := doc.Paths.Find(pathTemplate)
+	if pathItem == nil {
+		return "", fmt.Errorf("path %q not in OpenAPI doc", pathTemplate)
+	}
+	op := pathItem.GetOperation(strings.ToUpper(method))
+	if op == nil {
+		return "", fmt.Errorf("method %q not in path %q", method, pathTemplate)
+	}
+	return resolveBaseURL(doc, pathItem, op, bindCtx)
+}
+
+// resolveBaseURL determines the base URL for an operation's requests.
+//
+// BindingContext.Metadata["baseURL"] wins when set. Otherwise the server list
+// is taken from the operation, then the path item, then the document. A
+// server is chosen by Metadata["server"] (an index, or a description or URL
+// to match), defaulting to the first, and its variables are substituted from
+// Metadata overrides ("server.<name>" keys or a "serverVariables" object) or
+// their defaults. Values are checked against each variable's enum.
+func resolveBaseURL(doc *openapi3.T, pathItem *openapi3.PathItem, op *openapi3.Operation, bindCtx *delegates.BindingContext) (string, error) {
+	var meta map[string]any
+	if bindCtx != nil {
+		meta = bindCtx.Metadata
+	}
+	if base, ok := meta[metadataBaseURL].(string); ok && base != "" {
+		return strings.TrimRight(base, "/"), nil
+	}
+
+	servers := applicableServers(doc, pathItem, op)
+	if len(servers) == 0 {
+		return "", fmt.Errorf("no server URL: set servers in the OpenAPI doc or provide baseURL in context metadata")
+	}
+
+	server, err := selectServer(servers, meta[metadataServer])
+	if err != nil {
+		return "", err
+	}
+
+	serverURL, err := substituteServerVariables(server, serverVariableOverrides(meta))
+	if err != nil {
+		return "", err
+	}
+	if serverURL == "" {
+		return "", fmt.Errorf("selected server has an empty URL")
+	}
+	return strings.TrimRight(serverURL, "/"), nil
+}
+
+// applicableServers returns the most specific non-empty server list.
+func applicableServers(doc *openapi3.T, pathItem *openapi3.PathItem, op *openapi3.Operation) openapi3.Servers {
+	if op != nil && op.Servers != nil && len(*op.Servers) > 0 {
+		return *op.Servers
+	}
+	if pathItem != nil && len(pathItem.Servers) > 0 {
+		return pathItem.Servers
+	}
+	return doc.Servers
+}
+
+// selectServer picks a server by index (number or numeric string) or by
+// case-insensitive description or exact URL. A nil selector picks the first.
+func selectServer(servers openapi3.Servers, selector any) (*openapi3.Server, error) {
+	if selector == nil {
+		return servers[0], nil
+	}
+
+	index := -1
+	switch s := selector.(type) {
+	case float64:
+		index = int(s)
+	case int:
+		index = s
+	case string:
+		if s == "" {
+			return servers[0], nil
+		}
+		if n, err := strconv.Atoi(s); err == nil {
+			index = n
+			break
+		}
+		for _, server := range servers {
+			if server != nil && (strings.EqualFold(server.Description, s) || server.URL == s) {
+				return server, nil
+			}
+		}
+		return nil, fmt.Errorf("no server matches %q; available: %s", s, describeServers(servers))
+	default:
+		return nil, fmt.Errorf("invalid server selector %v", selector)
+	}
+
+	if index < 0 || index >= len(servers) || servers[index] == nil {
+		return nil, fmt.Errorf("server index %d out of range; available: %s", index, describeServers(servers))
+	}
+	return servers[index], nil
+}
+
+// describeServers lists servers as "0 (description) url" for error messages.
+func describeServers(servers openapi3.Servers) string {
+	parts := make([]string, 0, len(servers))
+	for i, server := range servers {
+		if server == nil {
+			continue
+		}
+		if server.Description != "" {
+			parts = append(parts, fmt.Sprintf("%d (%s) %s", i, server.Description, server.URL))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d %s", i, server.URL))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// serverVariableOverrides collects server variable values from metadata.
+// "server.<name>" keys take precedence over the "serverVariables" object.
+func serverVariableOverrides(meta map[string]any) map[string]string {
+	overrides := map[string]string{}
+	if vars, ok := meta[metadataServerVariables].(map[string]any); ok {
+		for k, v := range vars {
+			overrides[k] = primitiveString(v)
+		}
+	}
+	for k, v := range meta {
+		if name, ok := strings.CutPrefix(k, metadataServerVarPrefix); ok && name != "" {
+			overrides[name] = primitiveString(v)
+		}
+	}
+	return overrides
+}
+
+// substituteServerVariables replaces {name} placeholders in a server URL.
+func substituteServerVariables(server *openapi3.Server, overrides map[string]string) (string, error) {
+	var firstErr error
+	result := serverVarPattern.ReplaceAllStringFunc(server.URL, func(match string) string {
+		name := match[1 : len(match)-1]
+		variable := server.Variables[name]
+		value, overridden := overrides[name]
+		if !overridden {
+			if variable == nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("server variable %q is not defined and has no override", name)
+				}
+				return match
+			}
+			value = variable.Default
+		}
+		if variable != nil && len(variable.Enum) > 0 && !containsString(variable.Enum, value) {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("server variable %q: %q is not one of %s", name, value, strings.Join(variable.Enum, ", "))
+			}
+			return match
+		}
+		return value
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return result, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+func templatedServers() openapi3.Servers {
+	return openapi3.Servers{
+		{
+			URL:         "https://{region}.api.example.com/{basePath}",
+			Description: "Production",
+			Variables: map[string]*openapi3.ServerVariable{
+				"region":   {Default: "us", Enum: []string{"us", "eu"}},
+				"basePath": {Default: "v1"},
+			},
+		},
+		{URL: "https://staging.example.com/", Description: "Staging"},
+	}
+}
+
+func TestResolveBaseURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    map[string]any
+		want    string
+		wantErr string
+	}{
+		{"defaults", nil, "https://us.api.example.com/v1", ""},
+		{"prefixed override", map[string]any{"server.region": "eu"}, "https://eu.api.example.com/v1", ""},
+		{"variables object", map[string]any{"serverVariables": map[string]any{"basePath": "v2"}}, "https://us.api.example.com/v2", ""},
+		{"prefixed wins", map[string]any{"server.region": "eu", "serverVariables": map[string]any{"region": "us"}}, "https://eu.api.example.com/v1", ""},
+		{"enum violation", map[string]any{"server.region": "ap"}, "", `"ap" is not one of us, eu`},
+		{"select by index", map[string]any{"server": float64(1)}, "https://staging.example.com", ""},
+		{"select by numeric string", map[string]any{"server": "1"}, "https://staging.example.com", ""},
+		{"select by description", map[string]any{"server": "staging"}, "https://staging.example.com", ""},
+		{"index out of range", map[string]any{"server": 5}, "", "out of range"},
+		{"unknown description", map[string]any{"server": "qa"}, "", `no server matches "qa"`},
+		{"baseURL wins", map[string]any{"baseURL": "http://localhost:8080/", "server": 1}, "http://localhost:8080", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &openapi3.T{Servers: templatedServers()}
+			got, err := resolveBaseURL(doc, nil, nil, &delegates.BindingContext{Metadata: tt.meta})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveBaseURLUndefinedVariable(t *testing.T) {
+	doc := &openapi3.T{Servers: openapi3.Servers{{URL: "https://{tenant}.example.com"}}}
+	if _, err := resolveBaseURL(doc, nil, nil, nil); err == nil {
+		t.Fatal("expected error for undefined variable without override")
+	}
+	got, err := resolveBaseURL(doc, nil, nil, &delegates.BindingContext{Metadata: map[string]any{"server.tenant": "acme"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "https://acme.example.com" {
+		t.Errorf("got %q, want https://acme.example.com", got)
+	}
+}
+
+func TestApplicableServers(t *testing.T) {
+	doc := &openapi3.T{Servers: openapi3.Servers{{URL: "https://doc.example.com"}}}
+	pathItem := &openapi3.PathItem{Servers: openapi3.Servers{{URL: "https://path.example.com"}}}
+	opServers := openapi3.Servers{{URL: "https://op.example.com"}}
+	op := &openapi3.Operation{Servers: &opServers}
+
+	if got := applicableServers(doc, pathItem, op)[0].URL; got != "https://op.example.com" {
+		t.Errorf("operation servers: got %q", got)
+	}
+	if got := applicableServers(doc, pathItem, &openapi3.Operation{})[0].URL; got != "https://path.example.com" {
+		t.Errorf("path servers: got %q", got)
+	}
+	if got := applicableServers(doc, &openapi3.PathItem{}, &openapi3.Operation{})[0].URL; got != "https://doc.example.com" {
+		t.Errorf("document servers: got %q", got)
+	}
+}
+
+func TestExecuteOperationServerOverride(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/items" {
+			t.Errorf("expected /v2/items, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	doc := fmt.Sprintf(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Test", "version": "1.0.0"},
+		"servers": [{"url": "https://unreachable.invalid"}],
+		"paths": {
+			"/items": {
+				"get": {
+					"servers": [{"url": "%s/{version}", "variables": {"version": {"default": "v1"}}}],
+					"responses": {"200": {"description": "ok"}}
+				}
+			}
+		}
+	}`, server.URL)
+
+	input := delegates.ExecuteInput{
+		Source:  delegates.Source{Format: "openapi@3.1", Content: doc},
+		Ref:     "#/paths/~1items/get",
+		Context: &delegates.BindingContext{Metadata: map[string]any{"server.version": "v2"}},
+	}
+
+	result := Execute(context.Background(), input)
+	if result.Error != nil {
+		t.Fatalf("Execute failed: %s", result.Error.Message)
+	}
+
+	resolved, err := ResolveServer(input)
+	if err != nil {
+		t.Fatalf("ResolveServer: %v", err)
+	}
+	if resolved != server.URL+"/v2" {
+		t.Errorf("ResolveServer = %q, want %q", resolved, server.URL+"/v2")
+	}
+}
+
+func TestResolveServers(t *testing.T) {
+	var fetches atomic.Int32
+	specServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"openapi": "3.1.0",
+			"info": {"title": "Test", "version": "1.0.0"},
+			"servers": [{"url": "https://api.example.com"}],
+			"paths": {
+				"/items": {"get": {"responses": {"200": {"description": "ok"}}}},
+				"/admin": {"get": {
+					"servers": [{"url": "https://admin.example.com/"}],
+					"responses": {"200": {"description": "ok"}}
+				}}
+			}
+		}`)
+	}))
+	defer specServer.Close()
+
+	servers, err := ResolveServers(delegates.Source{Format: "openapi@3.1", Location: specServer.URL + "/openapi.json"},
+		[]string{"#/paths/~1items/get", "#/paths/~1admin/get", "#/paths/~1missing/get"}, nil)
+	if err != nil {
+		t.Fatalf("ResolveServers: %v", err)
+	}
+	want := map[string]string{
+		"#/paths/~1items/get": "https://api.example.com",
+		"#/paths/~1admin/get": "https://admin.example.com",
+	}
+	if !reflect.DeepEqual(servers, want) {
+		t.Errorf("servers = %v, want %v", servers, want)
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("document fetched %d times, want once", n)
+	}
+}
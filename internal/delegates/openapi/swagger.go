@@ -0,0 +1,41 @@
+package openapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oasdiff/yaml"
+)
+
+// swaggerVersion reports the declared version of a Swagger 2.0 document.
+// JSON and YAML documents are both accepted.
+func swaggerVersion(data []byte) (string, bool) {
+	var probe struct {
+		Swagger string `json:"swagger"`
+	}
+	if err := yaml.Unmarshal(data, &probe); err != nil {
+		return "", false
+	}
+	if !strings.HasPrefix(probe.Swagger, "2.") {
+		return "", false
+	}
+	return probe.Swagger, true
+}
+
+// convertSwagger parses a Swagger 2.0 document and converts it to OpenAPI 3,
+// resolving refs against location.
+func convertSwagger(loader *openapi3.Loader, data []byte, location *url.URL) (*openapi3.T, error) {
+	var doc2 openapi2.T
+	if err := yaml.Unmarshal(data, &doc2); err != nil {
+		return nil, fmt.Errorf("parse Swagger 2.0 document: %w", err)
+	}
+	doc, err := openapi2conv.ToV3WithLoader(&doc2, loader, location)
+	if err != nil {
+		return nil, fmt.Errorf("convert Swagger 2.0 to OpenAPI 3: %w", err)
+	}
+	return doc, nil
+}
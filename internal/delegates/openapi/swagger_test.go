@@ -0,0 +1,92 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+const sampleSwaggerYAML = `
+swagger: "2.0"
+info:
+  title: Pet Store
+  version: "1.0"
+host: petstore.example.com
+basePath: /v1
+schemes: [https]
+paths:
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      produces: [application/json]
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          type: string
+      responses:
+        200:
+          description: A pet
+          schema:
+            $ref: "#/definitions/Pet"
+definitions:
+  Pet:
+    type: object
+    properties:
+      name:
+        type: string
+`
+
+func TestSwaggerVersion(t *testing.T) {
+	if v, ok := swaggerVersion([]byte(sampleSwaggerYAML)); !ok || v != "2.0" {
+		t.Errorf("YAML: got (%q, %v), want (2.0, true)", v, ok)
+	}
+	if v, ok := swaggerVersion([]byte(`{"swagger": "2.0", "paths": {}}`)); !ok || v != "2.0" {
+		t.Errorf("JSON: got (%q, %v), want (2.0, true)", v, ok)
+	}
+	if _, ok := swaggerVersion([]byte(sampleOpenAPI)); ok {
+		t.Error("OpenAPI 3 document detected as Swagger")
+	}
+}
+
+func TestConvertSwaggerToInterface(t *testing.T) {
+	iface, err := ConvertToInterface(delegates.Source{Format: "openapi@2.0", Content: sampleSwaggerYAML})
+	if err != nil {
+		t.Fatalf("ConvertToInterface failed: %v", err)
+	}
+
+	if iface.Name != "Pet Store" {
+		t.Errorf("Name = %q, want %q", iface.Name, "Pet Store")
+	}
+	if got := iface.Sources[DefaultSourceName].Format; got != "openapi@2.0" {
+		t.Errorf("source format = %q, want openapi@2.0", got)
+	}
+
+	op, ok := iface.Operations["getPet"]
+	if !ok {
+		t.Fatal("missing operation 'getPet'")
+	}
+	props, _ := op.Input["properties"].(map[string]any)
+	if _, ok := props["petId"]; !ok {
+		t.Error("getPet.Input missing 'petId'")
+	}
+	if op.Output == nil {
+		t.Error("getPet.Output is nil, want response schema")
+	}
+	if got := iface.Bindings["getPet.openapi"].Ref; got != "#/paths/~1pets~1{petId}/get" {
+		t.Errorf("ref = %q", got)
+	}
+}
+
+func TestResolveServerSwagger(t *testing.T) {
+	got, err := ResolveServer(delegates.ExecuteInput{
+		Source: delegates.Source{Format: "openapi@2.0", Content: sampleSwaggerYAML},
+		Ref:    "#/paths/~1pets~1{petId}/get",
+	})
+	if err != nil {
+		t.Fatalf("ResolveServer: %v", err)
+	}
+	if got != "https://petstore.example.com/v1" {
+		t.Errorf("got %q, want https://petstore.example.com/v1", got)
+	}
+}
@@ -0,0 +1,361 @@
+package openrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/openbindings/cli/internal/delegates"
+	"github.com/openbindings/openbindings-go"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSourceName is the default source key for OpenRPC sources.
+const DefaultSourceName = "openrpc"
+
+// docExts are the extensions of URL paths recognized as OpenRPC documents.
+// Other HTTP URLs are JSON-RPC endpoints.
+var docExts = []string{".json", ".yaml", ".yml"}
+
+// schemaRefPrefix is the prefix of references to component schemas, which
+// are carried over to the interface's schemas.
+const schemaRefPrefix = "#/components/schemas/"
+
+// ConvertToInterface converts an OpenRPC document to an OpenBindings
+// interface. Each method becomes a method operation whose input is an
+// object of its params by name and whose output is its result.
+func ConvertToInterface(doc *Document, sourceLocation string) (openbindings.Interface, error) {
+	if doc == nil {
+		return openbindings.Interface{}, fmt.Errorf("nil document")
+	}
+
+	iface := openbindings.Interface{
+		OpenBindings: openbindings.MaxTestedVersion,
+		Name:         doc.Info.Title,
+		Version:      doc.Info.Version,
+		Description:  doc.Info.Description,
+		Operations:   map[string]openbindings.Operation{},
+		Bindings:     map[string]openbindings.BindingEntry{},
+		Sources: map[string]openbindings.Source{
+			DefaultSourceName: {
+				Format:   "openrpc@" + doc.OpenRPC,
+				Location: sourceLocation,
+			},
+		},
+	}
+
+	usedKeys := map[string]bool{}
+	for i := range doc.Methods {
+		method, err := resolveMethod(doc, &doc.Methods[i])
+		if err != nil {
+			return openbindings.Interface{}, err
+		}
+		opKey := deriveOperationKey(method.Name, usedKeys)
+		usedKeys[opKey] = true
+
+		op := openbindings.Operation{
+			Kind:        openbindings.OperationKindMethod,
+			Description: method.Description,
+			Deprecated:  method.Deprecated,
+		}
+		if op.Description == "" {
+			op.Description = method.Summary
+		}
+		for _, tag := range method.Tags {
+			op.Tags = append(op.Tags, tag.Name)
+		}
+		if len(method.Params) > 0 {
+			op.Input = paramsSchema(method.Params)
+		}
+		if method.Result != nil {
+			op.Output = descriptorSchema(*method.Result)
+		}
+
+		iface.Operations[opKey] = op
+		iface.Bindings[opKey+"."+DefaultSourceName] = openbindings.BindingEntry{
+			Operation: opKey,
+			Source:    DefaultSourceName,
+			Ref:       method.Name,
+		}
+	}
+
+	if doc.Components != nil && len(doc.Components.Schemas) > 0 {
+		iface.Schemas = map[string]openbindings.JSONSchema{}
+		for name, s := range doc.Components.Schemas {
+			iface.Schemas[name] = rewriteRefs(s).(map[string]any)
+		}
+	}
+
+	return iface, nil
+}
+
+func deriveOperationKey(name string, used map[string]bool) string {
+	key := delegates.SanitizeKey(name)
+	if !used[key] {
+		return key
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s_%d", key, i)
+		if !used[candidate] {
+			return candidate
+		}
+	}
+}
+
+// paramsSchema returns the object schema of a method's params by name. It
+// describes one call; an array of such objects is sent as a batch.
+func paramsSchema(params []ContentDescriptor) map[string]any {
+	properties := map[string]any{}
+	var required []any
+	for _, p := range params {
+		properties[p.Name] = descriptorSchema(p)
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+	schema := map[string]any{
+		"type":        "object",
+		"description": "The method's params by name. An array of these objects is sent as a batch.",
+		"properties":  properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// descriptorSchema returns a content descriptor's schema, annotated with
+// its description.
+func descriptorSchema(cd ContentDescriptor) map[string]any {
+	schema, _ := rewriteRefs(cd.Schema).(map[string]any)
+	if schema == nil {
+		schema = map[string]any{}
+	}
+	description := cd.Description
+	if description == "" {
+		description = cd.Summary
+	}
+	if _, ok := schema["description"]; !ok && description != "" {
+		schema["description"] = description
+	}
+	if cd.Deprecated {
+		schema["deprecated"] = true
+	}
+	return schema
+}
+
+// rewriteRefs returns a copy of a schema whose references to component
+// schemas point at the interface's schemas instead.
+func rewriteRefs(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, child := range v {
+			if s, ok := child.(string); ok && k == "$ref" && strings.HasPrefix(s, schemaRefPrefix) {
+				out[k] = "#/schemas/" + strings.TrimPrefix(s, schemaRefPrefix)
+				continue
+			}
+			out[k] = rewriteRefs(child)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, child := range v {
+			out[i] = rewriteRefs(child)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// resolveMethod returns a copy of a method whose params, result and tags
+// are resolved from the document's components.
+func resolveMethod(doc *Document, m *Method) (*Method, error) {
+	resolved := *m
+	resolved.Params = make([]ContentDescriptor, len(m.Params))
+	for i, p := range m.Params {
+		cd, err := resolveDescriptor(doc, p)
+		if err != nil {
+			return nil, fmt.Errorf("method %q: %w", m.Name, err)
+		}
+		resolved.Params[i] = cd
+	}
+	if m.Result != nil {
+		cd, err := resolveDescriptor(doc, *m.Result)
+		if err != nil {
+			return nil, fmt.Errorf("method %q result: %w", m.Name, err)
+		}
+		resolved.Result = &cd
+	}
+	resolved.Tags = make([]Tag, 0, len(m.Tags))
+	for _, tag := range m.Tags {
+		if tag.Ref != "" && doc.Components != nil {
+			tag = doc.Components.Tags[refName(tag.Ref, "#/components/tags/")]
+		}
+		if tag.Name != "" {
+			resolved.Tags = append(resolved.Tags, tag)
+		}
+	}
+	return &resolved, nil
+}
+
+func resolveDescriptor(doc *Document, cd ContentDescriptor) (ContentDescriptor, error) {
+	if cd.Ref == "" {
+		return cd, nil
+	}
+	name := refName(cd.Ref, "#/components/contentDescriptors/")
+	if doc.Components != nil {
+		if resolved, ok := doc.Components.ContentDescriptors[name]; ok && resolved.Ref == "" {
+			return resolved, nil
+		}
+	}
+	return ContentDescriptor{}, fmt.Errorf("unresolved content descriptor %q", cd.Ref)
+}
+
+func refName(ref, prefix string) string {
+	if !strings.HasPrefix(ref, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(ref, prefix)
+}
+
+// findMethod returns the resolved method named name.
+func findMethod(doc *Document, name string) (*Method, error) {
+	for i := range doc.Methods {
+		if doc.Methods[i].Name == name {
+			return resolveMethod(doc, &doc.Methods[i])
+		}
+	}
+	return nil, fmt.Errorf("method %q not in OpenRPC document", name)
+}
+
+// IsEndpoint reports whether location is a JSON-RPC endpoint (a WebSocket
+// URL, or an HTTP URL that does not name a document) whose OpenRPC document
+// is read by calling rpc.discover.
+func IsEndpoint(location string) bool {
+	if isWSURL(location) {
+		return true
+	}
+	if !delegates.IsHTTPURL(location) {
+		return false
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return false
+	}
+	return !slices.Contains(docExts, strings.ToLower(path.Ext(u.Path)))
+}
+
+func isWSURL(s string) bool {
+	return strings.HasPrefix(s, "ws://") || strings.HasPrefix(s, "wss://")
+}
+
+// loadDocument reads a source's OpenRPC document from its inline content, a
+// document file or URL, or rpc.discover on an endpoint. It also returns the
+// content read.
+func loadDocument(ctx context.Context, source delegates.Source, bindCtx *delegates.BindingContext) (*Document, []byte, error) {
+	var data []byte
+	var err error
+	switch {
+	case source.Content != nil:
+		data, err = delegates.ContentToBytes(source.Content)
+	case source.Location == "":
+		err = fmt.Errorf("source must have location or content")
+	case IsEndpoint(source.Location):
+		data, err = discover(ctx, source.Location, bindCtx)
+	case delegates.IsHTTPURL(source.Location):
+		data, err = fetch(ctx, source.Location, bindCtx)
+	default:
+		data, err = os.ReadFile(source.Location)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, data, nil
+}
+
+// discoveredDocuments caches the documents of discovered endpoints, so that
+// executing several methods of an endpoint calls rpc.discover once.
+var discoveredDocuments sync.Map // location -> *Document
+
+// executionDocument returns the document methods of the source are called
+// with, reusing an earlier discovery of the same endpoint.
+func executionDocument(ctx context.Context, source delegates.Source, bindCtx *delegates.BindingContext) (*Document, error) {
+	cacheable := source.Content == nil && IsEndpoint(source.Location)
+	if cacheable {
+		if doc, ok := discoveredDocuments.Load(source.Location); ok {
+			return doc.(*Document), nil
+		}
+	}
+	doc, _, err := loadDocument(ctx, source, bindCtx)
+	if err != nil {
+		return nil, err
+	}
+	if cacheable {
+		discoveredDocuments.Store(source.Location, doc)
+	}
+	return doc, nil
+}
+
+// discover calls rpc.discover on an endpoint and returns its result.
+func discover(ctx context.Context, endpoint string, bindCtx *delegates.BindingContext) ([]byte, error) {
+	responses, err := roundTrip(ctx, endpoint, bindCtx, []call{{method: "rpc.discover"}})
+	if err != nil {
+		return nil, fmt.Errorf("rpc.discover on %q: %w", endpoint, err)
+	}
+	if r := responses[0]; r.Error != nil {
+		return nil, fmt.Errorf("rpc.discover on %q: %s", endpoint, r.Error.Message)
+	}
+	return responses[0].Result, nil
+}
+
+func fetch(ctx context.Context, location string, bindCtx *delegates.BindingContext) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %q: %w", location, err)
+	}
+	client, err := delegates.HTTPClient(bindCtx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %q: %w", location, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("fetch %q: HTTP %d", location, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// parseDocument parses an OpenRPC 1.x document in JSON or YAML.
+func parseDocument(data []byte) (*Document, error) {
+	var doc Document
+	if delegates.MaybeJSON(string(data)) {
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parse OpenRPC JSON: %w", err)
+		}
+	} else {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parse OpenRPC YAML: %w", err)
+		}
+	}
+	if !strings.HasPrefix(doc.OpenRPC, "1.") {
+		return nil, fmt.Errorf("unsupported OpenRPC version %q (expected 1.x)", doc.OpenRPC)
+	}
+	return &doc, nil
+}
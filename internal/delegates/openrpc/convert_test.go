@@ -0,0 +1,176 @@
+package openrpc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openbindings/openbindings-go"
+)
+
+const testDocument = `{
+  "openrpc": "1.2.6",
+  "info": {"title": "Ledger", "version": "1.0.0", "description": "A ledger."},
+  "servers": [{"url": "${scheme}://localhost/rpc", "variables": {"scheme": {"default": "http"}}}],
+  "methods": [
+    {
+      "name": "getBalance",
+      "summary": "Get an account balance.",
+      "tags": [{"name": "accounts"}, {"$ref": "#/components/tags/read"}],
+      "params": [
+        {"name": "account", "required": true, "schema": {"type": "string"}},
+        {"$ref": "#/components/contentDescriptors/block"}
+      ],
+      "result": {"name": "balance", "schema": {"$ref": "#/components/schemas/Amount"}}
+    },
+    {
+      "name": "transfer",
+      "paramStructure": "by-name",
+      "params": [
+        {"name": "from", "required": true, "schema": {"type": "string"}},
+        {"name": "to", "required": true, "schema": {"type": "string"}},
+        {"name": "amount", "required": true, "schema": {"$ref": "#/components/schemas/Amount"}}
+      ],
+      "result": {"name": "receipt", "schema": {"type": "object", "properties": {"from": {"type": "string"}, "amount": {"$ref": "#/components/schemas/Amount"}}}}
+    },
+    {"name": "log", "deprecated": true, "params": [{"name": "message", "schema": {"type": "string"}}]},
+    {"name": "ping", "params": [], "result": {"name": "pong", "schema": {"type": "string"}}}
+  ],
+  "components": {
+    "contentDescriptors": {
+      "block": {"name": "block", "description": "Block height.", "schema": {"type": "integer"}}
+    },
+    "schemas": {
+      "Amount": {"type": "object", "properties": {"value": {"type": "string"}, "unit": {"$ref": "#/components/schemas/Unit"}}},
+      "Unit": {"type": "string", "enum": ["wei", "gwei"]}
+    },
+    "tags": {"read": {"name": "read"}}
+  }
+}`
+
+func loadTestDocument(t *testing.T) *Document {
+	t.Helper()
+	doc, err := parseDocument([]byte(testDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestConvertToInterface(t *testing.T) {
+	iface, err := ConvertToInterface(loadTestDocument(t), "./ledger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if iface.Name != "Ledger" || iface.Version != "1.0.0" || iface.Description != "A ledger." {
+		t.Errorf("name, version, description = %q, %q, %q", iface.Name, iface.Version, iface.Description)
+	}
+	if src := iface.Sources[DefaultSourceName]; src.Format != "openrpc@1.2.6" || src.Location != "./ledger.json" {
+		t.Errorf("source = %+v", src)
+	}
+	if len(iface.Operations) != 4 {
+		t.Errorf("operations = %d, want 4", len(iface.Operations))
+	}
+	for _, name := range []string{"getBalance", "transfer", "log", "ping"} {
+		b := iface.Bindings[name+"."+DefaultSourceName]
+		if b.Operation != name || b.Ref != name {
+			t.Errorf("binding for %s = %+v", name, b)
+		}
+	}
+
+	balance := iface.Operations["getBalance"]
+	if balance.Kind != openbindings.OperationKindMethod || balance.Description != "Get an account balance." {
+		t.Errorf("getBalance = %+v", balance)
+	}
+	if !reflect.DeepEqual(balance.Tags, []string{"accounts", "read"}) {
+		t.Errorf("tags = %v", balance.Tags)
+	}
+	if got := balance.Input["required"]; !reflect.DeepEqual(got, []any{"account"}) {
+		t.Errorf("required = %v", got)
+	}
+	block := balance.Input["properties"].(map[string]any)["block"].(map[string]any)
+	if block["type"] != "integer" || block["description"] != "Block height." {
+		t.Errorf("block = %v, want the referenced content descriptor", block)
+	}
+	if balance.Output["$ref"] != "#/schemas/Amount" {
+		t.Errorf("output = %v", balance.Output)
+	}
+
+	unit := iface.Schemas["Amount"]["properties"].(map[string]any)["unit"].(map[string]any)
+	if unit["$ref"] != "#/schemas/Unit" {
+		t.Errorf("Amount.unit = %v, want a rewritten $ref", unit)
+	}
+	if _, ok := iface.Schemas["Unit"]; !ok {
+		t.Error("missing Unit schema")
+	}
+
+	log := iface.Operations["log"]
+	if !log.Deprecated || log.Output != nil {
+		t.Errorf("log = %+v, want a deprecated operation without output", log)
+	}
+	if iface.Operations["ping"].Input != nil {
+		t.Error("a method without params has no input")
+	}
+}
+
+func TestParseDocument(t *testing.T) {
+	yamlDoc := `
+openrpc: 1.3.2
+info: {title: Ledger, version: "1"}
+methods:
+  - name: ping
+    params: []
+    result: {name: pong, schema: {type: string}}
+`
+	doc, err := parseDocument([]byte(yamlDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Methods) != 1 || doc.Methods[0].Result.Schema["type"] != "string" {
+		t.Errorf("methods = %+v", doc.Methods)
+	}
+
+	if _, err := parseDocument([]byte(`{"openrpc": "2.0.0", "methods": []}`)); err == nil {
+		t.Error("expected an error for an unsupported version")
+	}
+}
+
+func TestBuildParams(t *testing.T) {
+	doc := loadTestDocument(t)
+	balance, err := findMethod(doc, "getBalance")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := buildParams(balance, map[string]any{"account": "a1"})
+	if err != nil || !reflect.DeepEqual(got, []any{"a1"}) {
+		t.Errorf("by position = %v, %v", got, err)
+	}
+	got, err = buildParams(balance, map[string]any{"block": 7})
+	if err != nil || !reflect.DeepEqual(got, []any{nil, 7}) {
+		t.Errorf("by position with a gap = %v, %v", got, err)
+	}
+	if _, err := buildParams(balance, map[string]any{"nope": 1}); err == nil {
+		t.Error("expected an error for an unknown param")
+	}
+
+	transfer, _ := findMethod(doc, "transfer")
+	input := map[string]any{"from": "a1", "to": "a2"}
+	if got, err := buildParams(transfer, input); err != nil || !reflect.DeepEqual(got, input) {
+		t.Errorf("by name = %v, %v", got, err)
+	}
+}
+
+func TestIsEndpoint(t *testing.T) {
+	for loc, want := range map[string]bool{
+		"https://node.example.com/rpc":         true,
+		"wss://node.example.com":               true,
+		"https://example.com/openrpc.json":     false,
+		"https://example.com/openrpc.YAML?v=1": false,
+		"./openrpc.json":                       false,
+	} {
+		if got := IsEndpoint(loc); got != want {
+			t.Errorf("IsEndpoint(%q) = %v, want %v", loc, got, want)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package openrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// rpcRequest is a JSON-RPC 2.0 request. Notifications have no ID.
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      *int   `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response.
+type rpcResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// rpcError is the error object of a JSON-RPC 2.0 response.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// details returns the error's code and data for delegates.Error.Details.
+func (e *rpcError) details() map[string]any {
+	d := map[string]any{"code": e.Code}
+	if e.Data != nil {
+		d["data"] = e.Data
+	}
+	return d
+}
+
+// call is one request of a round trip.
+type call struct {
+	method string
+	params any
+	notify bool // a notification gets no response
+}
+
+// httpStatusError reports an HTTP error response without a JSON-RPC body.
+type httpStatusError struct {
+	resp *http.Response
+}
+
+func (e *httpStatusError) Error() string {
+	return "HTTP " + e.resp.Status
+}
+
+// Execute calls the method named by input.Ref. The input is an object of
+// the method's params by name, sent by name or by position according to the
+// method's paramStructure ("either" is sent by position). An array of such
+// objects is sent as a batch, and the output is the array of results in the
+// same order. A method without a result is sent as a notification.
+func Execute(ctx context.Context, input delegates.ExecuteInput) delegates.ExecuteOutput {
+	start := time.Now()
+
+	doc, err := executionDocument(ctx, input.Source, input.Context)
+	if err != nil {
+		return delegates.FailedOutput(start, "doc_load_failed", err.Error())
+	}
+	method, err := findMethod(doc, input.Ref)
+	if err != nil {
+		return delegates.FailedOutput(start, "method_not_found", err.Error())
+	}
+	endpoint, err := endpointFor(doc, method, input)
+	if err != nil {
+		return delegates.FailedOutput(start, "no_endpoint", err.Error())
+	}
+
+	items, batch := input.Input.([]any)
+	if !batch {
+		items = []any{input.Input}
+	} else if len(items) == 0 {
+		return delegates.FailedOutput(start, "invalid_input", "a batch must hold at least one call")
+	}
+	calls := make([]call, len(items))
+	for i, item := range items {
+		params, err := buildParams(method, item)
+		if err != nil {
+			if batch {
+				err = fmt.Errorf("call %d: %w", i, err)
+			}
+			return delegates.FailedOutput(start, "invalid_input", err.Error())
+		}
+		calls[i] = call{method: method.Name, params: params, notify: method.Result == nil}
+	}
+
+	responses, err := roundTrip(ctx, endpoint, input.Context, calls)
+	if err != nil {
+		var statusErr *httpStatusError
+		if errors.As(err, &statusErr) {
+			return delegates.HTTPResponseErrorOutput(start, statusErr.resp)
+		}
+		return delegates.FailedOutput(start, "request_failed", err.Error())
+	}
+
+	if !batch {
+		r := responses[0]
+		if r != nil && r.Error != nil {
+			out := delegates.FailedOutput(start, "rpc_error", r.Error.Message)
+			out.Error.Details = r.Error.details()
+			return out
+		}
+		return delegates.ExecuteOutput{
+			Output:     r.output(),
+			Status:     0,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+	}
+
+	results := make([]any, len(responses))
+	var failed []any
+	var firstMessage string
+	for i, r := range responses {
+		if r != nil && r.Error != nil {
+			d := r.Error.details()
+			d["index"] = i
+			d["message"] = r.Error.Message
+			failed = append(failed, d)
+			if firstMessage == "" {
+				firstMessage = r.Error.Message
+			}
+			continue
+		}
+		results[i] = r.output()
+	}
+	if len(failed) > 0 {
+		out := delegates.FailedOutput(start, "rpc_error", fmt.Sprintf("%d of %d calls failed: %s", len(failed), len(calls), firstMessage))
+		out.Output = results
+		out.Error.Details = map[string]any{"errors": failed}
+		return out
+	}
+	return delegates.ExecuteOutput{
+		Output:     results,
+		Status:     0,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+}
+
+// output returns the decoded result, or nil for a notification.
+func (r *rpcResponse) output() any {
+	if r == nil || len(r.Result) == 0 {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(r.Result, &v); err != nil {
+		return nil
+	}
+	return v
+}
+
+// buildParams converts an input object to the params of a call: the object
+// itself for by-name methods, otherwise an array in the order the method
+// declares its params, without trailing unset ones. A nil input sends no
+// params.
+func buildParams(method *Method, input any) (any, error) {
+	if input == nil {
+		return nil, nil
+	}
+	values, ok := delegates.ToStringAnyMap(input)
+	if !ok {
+		return nil, fmt.Errorf("input must be an object of params by name")
+	}
+	if method.ParamStructure == ParamsByName {
+		return values, nil
+	}
+
+	for name := range values {
+		if !hasParam(method, name) {
+			return nil, fmt.Errorf("method %q has no param %q", method.Name, name)
+		}
+	}
+	params := make([]any, len(method.Params))
+	n := 0
+	for i, p := range method.Params {
+		if v, ok := values[p.Name]; ok {
+			params[i] = v
+			n = i + 1
+		}
+	}
+	return params[:n], nil
+}
+
+func hasParam(method *Method, name string) bool {
+	for _, p := range method.Params {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// endpointFor returns the URL a method is called at: the context's
+// "endpoint" metadata, the source location when it is an endpoint, or else
+// the first of the method's servers (or the document's) with its variables
+// set to their defaults. Relative server URLs are resolved against the
+// document URL.
+func endpointFor(doc *Document, method *Method, input delegates.ExecuteInput) (string, error) {
+	if input.Context != nil {
+		if ep, ok := input.Context.Metadata["endpoint"].(string); ok && ep != "" {
+			return ep, nil
+		}
+	}
+	if IsEndpoint(input.Source.Location) {
+		return input.Source.Location, nil
+	}
+
+	servers := method.Servers
+	if len(servers) == 0 {
+		servers = doc.Servers
+	}
+	if len(servers) == 0 || servers[0].URL == "" {
+		return "", fmt.Errorf("no server URL: set servers in the OpenRPC document or provide endpoint in context metadata")
+	}
+	endpoint := servers[0].URL
+	for name, v := range servers[0].Variables {
+		endpoint = strings.ReplaceAll(endpoint, "${"+name+"}", v.Default)
+	}
+	if delegates.IsHTTPURL(input.Source.Location) && !delegates.IsHTTPURL(endpoint) && !isWSURL(endpoint) {
+		base, err := url.Parse(input.Source.Location)
+		if err != nil {
+			return "", fmt.Errorf("invalid document URL: %w", err)
+		}
+		ref, err := url.Parse(endpoint)
+		if err != nil {
+			return "", fmt.Errorf("invalid server URL %q: %w", endpoint, err)
+		}
+		endpoint = base.ResolveReference(ref).String()
+	}
+	return endpoint, nil
+}
+
+// roundTrip sends calls in one request, as a batch when there is more than
+// one, over HTTP or a WebSocket depending on the endpoint's scheme. It
+// returns the response of each call in order; notifications have none.
+func roundTrip(ctx context.Context, endpoint string, bindCtx *delegates.BindingContext, calls []call) ([]*rpcResponse, error) {
+	requests := make([]rpcRequest, len(calls))
+	pending := map[int]int{} // request ID -> call index
+	for i, c := range calls {
+		requests[i] = rpcRequest{JSONRPC: "2.0", Method: c.method, Params: c.params}
+		if !c.notify {
+			id := i + 1
+			requests[i].ID = &id
+			pending[id] = i
+		}
+	}
+	var body any = requests
+	if len(requests) == 1 {
+		body = requests[0]
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var received []rpcResponse
+	if isWSURL(endpoint) {
+		received, err = wsRoundTrip(ctx, endpoint, bindCtx, data, pending)
+	} else {
+		received, err = httpRoundTrip(ctx, endpoint, bindCtx, data, len(pending) > 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*rpcResponse, len(calls))
+	for i := range received {
+		r := &received[i]
+		id, ok := responseID(r)
+		if idx, known := pending[id]; ok && known {
+			responses[idx] = r
+			continue
+		}
+		if r.Error != nil {
+			// An error without a known ID concerns the whole request
+			// (e.g., a parse error).
+			return nil, fmt.Errorf("JSON-RPC error %d: %s", r.Error.Code, r.Error.Message)
+		}
+	}
+	for id, idx := range pending {
+		if responses[idx] == nil {
+			return nil, fmt.Errorf("no response to request %d", id)
+		}
+	}
+	return responses, nil
+}
+
+// responseID returns the numeric ID of a response.
+func responseID(r *rpcResponse) (int, bool) {
+	var id int
+	if err := json.Unmarshal(r.ID, &id); err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// decodeResponses decodes a single response or a batch of them.
+func decodeResponses(data []byte) ([]rpcResponse, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var batch []rpcResponse
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return nil, fmt.Errorf("invalid JSON-RPC response: %w", err)
+		}
+		return batch, nil
+	}
+	var single rpcResponse
+	if err := json.Unmarshal(data, &single); err != nil {
+		return nil, fmt.Errorf("invalid JSON-RPC response: %w", err)
+	}
+	return []rpcResponse{single}, nil
+}
+
+// httpRoundTrip POSTs a request with the context's credentials and headers.
+// A request of notifications only may get an empty response.
+func httpRoundTrip(ctx context.Context, endpoint string, bindCtx *delegates.BindingContext, data []byte, expectResponse bool) ([]rpcResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	delegates.ApplyHTTPContext(req, bindCtx)
+
+	client, err := delegates.HTTPClient(bindCtx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if !expectResponse && resp.StatusCode < 400 && len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+	responses, err := decodeResponses(body)
+	if err != nil {
+		if resp.StatusCode >= 400 {
+			return nil, &httpStatusError{resp: resp}
+		}
+		return nil, err
+	}
+	return responses, nil
+}
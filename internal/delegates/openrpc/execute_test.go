@@ -0,0 +1,299 @@
+package openrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/coder/websocket"
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// testServer is a JSON-RPC 2.0 server for the test document over HTTP and
+// WebSocket. Calls other than rpc.discover require the bearer token
+// "secret"; notifications are recorded in logged.
+type testServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	logged []any
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	s := &testServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") == "websocket" {
+			s.serveWebSocket(t, w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path == "/openrpc.json" {
+			w.Write([]byte(testDocument))
+			return
+		}
+		authorized := r.Header.Get("Authorization") == "Bearer secret"
+		if !authorized && !bytes.Contains(body, []byte(`"rpc.discover"`)) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if resp := s.handle(body); resp != nil {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testServer) serveWebSocket(t *testing.T, w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		t.Errorf("accept: %v", err)
+		return
+	}
+	defer conn.CloseNow()
+	ctx := r.Context()
+
+	// A server notification before the response is skipped by clients.
+	conn.Write(ctx, websocket.MessageText, []byte(`{"jsonrpc":"2.0","method":"newBlock","params":[1]}`))
+	for {
+		_, msg, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+		if resp := s.handle(msg); resp != nil {
+			data, _ := json.Marshal(resp)
+			conn.Write(ctx, websocket.MessageText, data)
+		}
+	}
+}
+
+// handle answers a request or a batch; it returns nil when there is nothing
+// to answer.
+func (s *testServer) handle(body []byte) any {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var batch []json.RawMessage
+		json.Unmarshal(body, &batch)
+		var responses []any
+		for _, req := range batch {
+			if resp := s.call(req); resp != nil {
+				responses = append(responses, resp)
+			}
+		}
+		if len(responses) == 0 {
+			return nil
+		}
+		return responses
+	}
+	if resp := s.call(body); resp != nil {
+		return resp
+	}
+	return nil
+}
+
+func (s *testServer) call(data []byte) map[string]any {
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return map[string]any{"jsonrpc": "2.0", "id": nil, "error": map[string]any{"code": -32700, "message": "parse error"}}
+	}
+	var byPosition []any
+	var byName map[string]any
+	json.Unmarshal(req.Params, &byPosition)
+	json.Unmarshal(req.Params, &byName)
+
+	var result any
+	var rpcErr map[string]any
+	switch req.Method {
+	case "rpc.discover":
+		result = json.RawMessage(testDocument)
+	case "getBalance":
+		if byPosition == nil {
+			rpcErr = map[string]any{"code": -32602, "message": "params must be an array"}
+		} else if byPosition[0] == "missing" {
+			rpcErr = map[string]any{"code": -32000, "message": "unknown account", "data": "missing"}
+		} else {
+			result = map[string]any{"value": "100", "account": byPosition[0], "params": len(byPosition)}
+		}
+	case "transfer":
+		if byName == nil {
+			rpcErr = map[string]any{"code": -32602, "message": "params must be an object"}
+		} else {
+			result = map[string]any{"from": byName["from"], "amount": byName["amount"]}
+		}
+	case "log":
+		s.mu.Lock()
+		s.logged = append(s.logged, byPosition...)
+		s.mu.Unlock()
+	default:
+		rpcErr = map[string]any{"code": -32601, "message": "method not found"}
+	}
+	if req.ID == nil {
+		return nil
+	}
+	resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+	if rpcErr != nil {
+		resp["error"] = rpcErr
+	} else {
+		resp["result"] = result
+	}
+	return resp
+}
+
+func bearer(token string) *delegates.BindingContext {
+	return &delegates.BindingContext{Credentials: &delegates.Credentials{BearerToken: token}}
+}
+
+func TestExecute(t *testing.T) {
+	server := newTestServer(t)
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	for _, location := range []string{server.URL, wsURL} {
+		source := delegates.Source{Format: FormatToken, Location: location}
+		execute := func(ref string, input any) delegates.ExecuteOutput {
+			return Execute(context.Background(), delegates.ExecuteInput{Source: source, Ref: ref, Input: input, Context: bearer("secret")})
+		}
+
+		out := execute("getBalance", map[string]any{"account": "a1"})
+		if out.Error != nil {
+			t.Fatalf("%s: getBalance: %+v", location, out.Error)
+		}
+		if want := map[string]any{"value": "100", "account": "a1", "params": float64(1)}; !reflect.DeepEqual(out.Output, want) {
+			t.Errorf("%s: getBalance = %v, want %v", location, out.Output, want)
+		}
+
+		out = execute("transfer", map[string]any{"from": "a1", "to": "a2", "amount": "5"})
+		if out.Error != nil || !reflect.DeepEqual(out.Output, map[string]any{"from": "a1", "amount": "5"}) {
+			t.Errorf("%s: transfer = %v, %+v", location, out.Output, out.Error)
+		}
+
+		out = execute("getBalance", map[string]any{"account": "missing"})
+		if out.Status != 1 || out.Error == nil || out.Error.Code != "rpc_error" || out.Error.Message != "unknown account" {
+			t.Errorf("%s: unknown account = status %d, error %+v", location, out.Status, out.Error)
+		} else if d := out.Error.Details.(map[string]any); d["code"] != -32000 || d["data"] != "missing" {
+			t.Errorf("%s: error details = %v", location, d)
+		}
+
+		out = execute("getBalance", []any{
+			map[string]any{"account": "a1"},
+			map[string]any{"account": "missing"},
+			map[string]any{"account": "a3", "block": 9},
+		})
+		results, _ := out.Output.([]any)
+		if out.Error == nil || out.Error.Code != "rpc_error" || len(results) != 3 {
+			t.Fatalf("%s: batch = %v, %+v", location, out.Output, out.Error)
+		}
+		if results[0].(map[string]any)["account"] != "a1" || results[1] != nil || results[2].(map[string]any)["params"] != float64(2) {
+			t.Errorf("%s: batch results = %v", location, results)
+		}
+		failed := out.Error.Details.(map[string]any)["errors"].([]any)
+		if len(failed) != 1 || failed[0].(map[string]any)["index"] != 1 {
+			t.Errorf("%s: batch errors = %v", location, failed)
+		}
+
+		out = execute("ping", nil)
+		if out.Error == nil || out.Error.Message != "method not found" {
+			t.Errorf("%s: ping = %+v, want the server's error", location, out.Error)
+		}
+	}
+
+	out := Execute(context.Background(), delegates.ExecuteInput{
+		Source: delegates.Source{Location: server.URL}, Ref: "nope", Context: bearer("secret"),
+	})
+	if out.Error == nil || out.Error.Code != "method_not_found" {
+		t.Errorf("unknown method = %+v, want method_not_found", out.Error)
+	}
+}
+
+func TestExecute_Notification(t *testing.T) {
+	server := newTestServer(t)
+	out := Execute(context.Background(), delegates.ExecuteInput{
+		Source:  delegates.Source{Location: server.URL},
+		Ref:     "log",
+		Input:   []any{map[string]any{"message": "one"}, map[string]any{"message": "two"}},
+		Context: bearer("secret"),
+	})
+	if out.Error != nil || out.Status != 0 {
+		t.Fatalf("log = %+v", out.Error)
+	}
+	if want := []any{"one", "two"}; !reflect.DeepEqual(server.logged, want) {
+		t.Errorf("logged = %v, want %v", server.logged, want)
+	}
+}
+
+func TestExecute_DocumentSource(t *testing.T) {
+	server := newTestServer(t)
+	source := delegates.Source{Format: FormatToken, Location: server.URL + "/openrpc.json"}
+	input := map[string]any{"account": "a1"}
+
+	// The document's server is not the test server.
+	metadata := map[string]any{"endpoint": server.URL}
+	bindCtx := &delegates.BindingContext{Credentials: &delegates.Credentials{BearerToken: "secret"}, Metadata: metadata}
+	out := Execute(context.Background(), delegates.ExecuteInput{Source: source, Ref: "getBalance", Input: input, Context: bindCtx})
+	if out.Error != nil {
+		t.Fatalf("getBalance: %+v", out.Error)
+	}
+
+	out = Execute(context.Background(), delegates.ExecuteInput{Source: source, Ref: "getBalance", Input: input, Context: &delegates.BindingContext{Metadata: metadata}})
+	if out.Status != http.StatusUnauthorized {
+		t.Errorf("without credentials: status = %d, want 401", out.Status)
+	}
+}
+
+func TestHTTPStatusError(t *testing.T) {
+	err := &httpStatusError{resp: &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found"}}
+	if got := err.Error(); got != "HTTP 404 Not Found" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestEndpointFor(t *testing.T) {
+	doc := loadTestDocument(t)
+	method, _ := findMethod(doc, "getBalance")
+
+	got, err := endpointFor(doc, method, delegates.ExecuteInput{Source: delegates.Source{Location: "./ledger.json"}})
+	if err != nil || got != "http://localhost/rpc" {
+		t.Errorf("endpoint = %q, %v", got, err)
+	}
+
+	doc.Servers = []Server{{URL: "/rpc"}}
+	got, err = endpointFor(doc, method, delegates.ExecuteInput{Source: delegates.Source{Location: "https://example.com/api/openrpc.json"}})
+	if err != nil || got != "https://example.com/rpc" {
+		t.Errorf("relative endpoint = %q, %v", got, err)
+	}
+
+	doc.Servers = nil
+	if _, err := endpointFor(doc, method, delegates.ExecuteInput{Source: delegates.Source{Location: "./ledger.json"}}); err == nil {
+		t.Error("expected an error without servers")
+	}
+}
+
+func TestDiscoverSource(t *testing.T) {
+	server := newTestServer(t)
+	content, iface, err := New().DiscoverSource(context.Background(), delegates.Source{Format: FormatToken, Location: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(content, []byte(`{"components":`)) {
+		t.Errorf("content should be the discovered document as canonical JSON, got %.80s", content)
+	}
+	if len(iface.Operations) != 4 {
+		t.Errorf("operations = %d, want 4", len(iface.Operations))
+	}
+
+	content, _, err = New().DiscoverSource(context.Background(), delegates.Source{Format: FormatToken, Location: server.URL + "/openrpc.json"})
+	if err != nil || string(content) != testDocument {
+		t.Errorf("a document URL should be returned as read, got %.80s, %v", content, err)
+	}
+}
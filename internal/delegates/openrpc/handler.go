@@ -0,0 +1,115 @@
+// Package openrpc implements the OpenRPC binding format handler delegate.
+//
+// The OpenRPC handler reads an OpenRPC 1.x document from a file or URL, or
+// by calling rpc.discover on a JSON-RPC 2.0 endpoint, and converts each of
+// its methods to an OpenBindings method operation. Bindings refer to a
+// method by name:
+//
+//	"sources": {"node": {"format": "openrpc@1.2.6", "location": "https://node.example.com/rpc"}}
+//	"bindings": {"getBalance.node": {"operation": "getBalance", "source": "node", "ref": "getBalance"}}
+//
+// Methods are called over HTTP or, for ws(s) endpoints, a WebSocket. The
+// endpoint is the context's "endpoint" metadata, the source location when it
+// is an endpoint, or the document's first server. The operation's input is
+// an object of the method's params by name; it is sent as is to by-name
+// methods and as an array in declared order otherwise. An array input is
+// sent as a batch of calls to the method. The input schema describes a
+// single call, so a batch input does not validate against it.
+package openrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/openbindings/cli/internal/delegates"
+	"github.com/openbindings/openbindings-go"
+	"github.com/openbindings/openbindings-go/canonicaljson"
+)
+
+// FormatToken is the format identifier for OpenRPC sources.
+const FormatToken = "openrpc@^1.0.0"
+
+// DefaultTimeout is the maximum time to wait for JSON-RPC requests.
+const DefaultTimeout = 30 * time.Second
+
+// Handler implements the OpenRPC binding format handler delegate.
+type Handler struct{}
+
+// New creates a new OpenRPC handler.
+func New() *Handler {
+	return &Handler{}
+}
+
+// GetInfo returns identity and metadata about this delegate.
+func (h *Handler) GetInfo() delegates.SoftwareInfo {
+	return delegates.SoftwareInfo{
+		Name:        "OpenRPC",
+		Description: "JSON-RPC 2.0 APIs described by OpenRPC documents",
+	}
+}
+
+// ListFormats returns the binding formats this delegate supports.
+func (h *Handler) ListFormats() []delegates.FormatInfo {
+	return []delegates.FormatInfo{
+		{
+			Token:       FormatToken,
+			Description: "OpenRPC 1.x documents or rpc.discover (JSON-RPC 2.0 over HTTP and WebSocket)",
+		},
+	}
+}
+
+// CreateInterface loads an OpenRPC document and converts it to an
+// OpenBindings interface.
+func (h *Handler) CreateInterface(source delegates.Source) (openbindings.Interface, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+
+	_, iface, err := h.discoverAndConvert(ctx, source)
+	return iface, err
+}
+
+// ExecuteOperation calls a JSON-RPC method.
+func (h *Handler) ExecuteOperation(ctx context.Context, input delegates.ExecuteInput) delegates.ExecuteOutput {
+	ctx, cancel := delegates.WithDefaultTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	return Execute(ctx, input)
+}
+
+// DiscoverSource implements the delegates.SourceDiscoverer interface. The
+// content is the document as read, or the rpc.discover result as canonical
+// JSON.
+func (h *Handler) DiscoverSource(ctx context.Context, source delegates.Source) ([]byte, openbindings.Interface, error) {
+	return h.discoverAndConvert(ctx, source)
+}
+
+func (h *Handler) discoverAndConvert(ctx context.Context, source delegates.Source) ([]byte, openbindings.Interface, error) {
+	doc, content, err := loadDocument(ctx, source, nil)
+	if err != nil {
+		return nil, openbindings.Interface{}, fmt.Errorf("OpenRPC discovery: %w", err)
+	}
+
+	iface, err := ConvertToInterface(doc, source.Location)
+	if err != nil {
+		return nil, openbindings.Interface{}, fmt.Errorf("OpenRPC convert: %w", err)
+	}
+
+	if source.Content == nil && IsEndpoint(source.Location) {
+		var v any
+		if err := json.Unmarshal(content, &v); err != nil {
+			return nil, openbindings.Interface{}, fmt.Errorf("OpenRPC serialize discovery: %w", err)
+		}
+		if content, err = canonicaljson.Marshal(v); err != nil {
+			return nil, openbindings.Interface{}, fmt.Errorf("OpenRPC serialize discovery: %w", err)
+		}
+	}
+
+	return content, iface, nil
+}
+
+// Register registers the OpenRPC handler with a registry.
+func Register(r *delegates.Registry) {
+	r.Register(New())
+}
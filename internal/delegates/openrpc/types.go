@@ -0,0 +1,81 @@
+package openrpc
+
+// Document represents an OpenRPC 1.x document.
+// Only the fields needed for OpenBindings conversion are modeled.
+type Document struct {
+	OpenRPC    string      `json:"openrpc" yaml:"openrpc"`
+	Info       Info        `json:"info" yaml:"info"`
+	Servers    []Server    `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Methods    []Method    `json:"methods" yaml:"methods"`
+	Components *Components `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+// Info contains metadata about the API.
+type Info struct {
+	Title       string `json:"title" yaml:"title"`
+	Version     string `json:"version" yaml:"version"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Server describes an endpoint the methods are served at. Its URL may hold
+// ${name} variables.
+type Server struct {
+	Name        string                    `json:"name,omitempty" yaml:"name,omitempty"`
+	URL         string                    `json:"url" yaml:"url"`
+	Summary     string                    `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string                    `json:"description,omitempty" yaml:"description,omitempty"`
+	Variables   map[string]ServerVariable `json:"variables,omitempty" yaml:"variables,omitempty"`
+}
+
+// ServerVariable is a substitutable part of a server URL.
+type ServerVariable struct {
+	Default     string   `json:"default" yaml:"default"`
+	Enum        []string `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Method describes a JSON-RPC method. A method without a result is called
+// as a notification.
+type Method struct {
+	Name           string              `json:"name" yaml:"name"`
+	Summary        string              `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description    string              `json:"description,omitempty" yaml:"description,omitempty"`
+	Tags           []Tag               `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Params         []ContentDescriptor `json:"params" yaml:"params"`
+	Result         *ContentDescriptor  `json:"result,omitempty" yaml:"result,omitempty"`
+	Deprecated     bool                `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Servers        []Server            `json:"servers,omitempty" yaml:"servers,omitempty"`
+	ParamStructure string              `json:"paramStructure,omitempty" yaml:"paramStructure,omitempty"`
+}
+
+// Values of Method.ParamStructure. The default is ParamsEither.
+const (
+	ParamsByName     = "by-name"
+	ParamsByPosition = "by-position"
+	ParamsEither     = "either"
+)
+
+// ContentDescriptor describes a param or result, or references one in
+// components.contentDescriptors.
+type ContentDescriptor struct {
+	Name        string         `json:"name,omitempty" yaml:"name,omitempty"`
+	Summary     string         `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string         `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool           `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema      map[string]any `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Deprecated  bool           `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Ref         string         `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+}
+
+// Tag groups methods, or references a tag in components.tags.
+type Tag struct {
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	Ref  string `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+}
+
+// Components holds reusable objects referenced from methods.
+type Components struct {
+	ContentDescriptors map[string]ContentDescriptor `json:"contentDescriptors,omitempty" yaml:"contentDescriptors,omitempty"`
+	Schemas            map[string]map[string]any    `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+	Tags               map[string]Tag               `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
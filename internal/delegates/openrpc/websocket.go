@@ -0,0 +1,62 @@
+package openrpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/coder/websocket"
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// maxWSMessageSize bounds the size of a received WebSocket message.
+const maxWSMessageSize = 16 << 20
+
+// wsRoundTrip sends a request over a new WebSocket connection and reads
+// messages until every pending request ID is answered. Other messages, such
+// as server notifications, are skipped.
+func wsRoundTrip(ctx context.Context, endpoint string, bindCtx *delegates.BindingContext, data []byte, pending map[int]int) ([]rpcResponse, error) {
+	header := http.Header{}
+	delegates.ApplyHTTPContext(&http.Request{Header: header}, bindCtx)
+	client, err := delegates.HTTPClient(bindCtx)
+	if err != nil {
+		return nil, err
+	}
+	conn, _, err := websocket.Dial(ctx, endpoint, &websocket.DialOptions{
+		HTTPClient: client,
+		HTTPHeader: header,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("WebSocket connect: %w", err)
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(maxWSMessageSize)
+
+	if err := conn.Write(ctx, websocket.MessageText, data); err != nil {
+		return nil, fmt.Errorf("WebSocket write: %w", err)
+	}
+
+	var responses []rpcResponse
+	answered := map[int]bool{}
+	for len(answered) < len(pending) {
+		_, msg, err := conn.Read(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("WebSocket read: %w", err)
+		}
+		received, err := decodeResponses(msg)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range received {
+			id, ok := responseID(&r)
+			if _, known := pending[id]; ok && known {
+				answered[id] = true
+				responses = append(responses, r)
+			} else if r.Error != nil {
+				return append(responses, r), nil
+			}
+		}
+	}
+	conn.Close(websocket.StatusNormalClosure, "")
+	return responses, nil
+}
@@ -8,7 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"os/exec"
 	"sort"
 	"strings"
@@ -23,27 +22,47 @@ var ErrCommandTimeout = errors.New("command timeout")
 
 // ProbeFormats fetches the supported formats from a delegate
 // by running its listFormats operation.
-func ProbeFormats(path string, timeout time.Duration) ([]string, error) {
+//
+// HTTP delegates are discovered via their well-known OpenBindings document
+// and their listFormats binding is executed through invoke. CLI delegates
+// ignore invoke.
+func ProbeFormats(path string, timeout time.Duration, invoke BindingInvoker) ([]string, error) {
+	entry, err := probeDelegate(path, timeout, invoke)
+	if err != nil {
+		return nil, err
+	}
+	return entry.Formats, nil
+}
+
+// probeDelegate fetches a delegate's interface and formats. The returned entry
+// carries a validator (binary mtime or HTTP ETag) for the probe cache.
+func probeDelegate(path string, timeout time.Duration, invoke BindingInvoker) (*ProbeCacheEntry, error) {
 	if IsHTTPURL(path) {
-		return nil, fmt.Errorf("formats require executor")
+		return probeHTTPDelegate(path, timeout, invoke)
 	}
+	cmd := path
 	if IsExecURL(path) {
-		cmd, err := execref.RootCommand(path)
+		root, err := execref.RootCommand(path)
 		if err != nil {
 			return nil, err
 		}
-		iface, err := RunCLIOpenBindings(cmd, timeout)
-		if err != nil {
-			return nil, err
-		}
-		return probeFormatsFromInterface(cmd, timeout, iface)
+		cmd = root
 	}
 
-	iface, err := RunCLIOpenBindings(path, timeout)
+	iface, err := RunCLIOpenBindings(cmd, timeout)
+	if err != nil {
+		return nil, err
+	}
+	formats, err := probeFormatsFromInterface(cmd, timeout, iface)
 	if err != nil {
 		return nil, err
 	}
-	return probeFormatsFromInterface(path, timeout, iface)
+	return &ProbeCacheEntry{
+		Location:  path,
+		Validator: binaryValidator(cmd),
+		Formats:   formats,
+		Interface: &iface,
+	}, nil
 }
 
 // RunCLIOpenBindings runs "<path> --openbindings" and parses the result.
@@ -120,34 +139,49 @@ func probeFormatsFromInterface(path string, timeout time.Duration, iface openbin
 	return out, nil
 }
 
-// FetchOpenBindings fetches an OpenBindings interface from an HTTP URL.
-func FetchOpenBindings(raw string, timeout time.Duration) (openbindings.Interface, error) {
-	u, err := url.Parse(raw)
+// FetchOpenBindings fetches an OpenBindings interface from an HTTP URL,
+// using the transport of bindCtx, which may be nil.
+func FetchOpenBindings(raw string, timeout time.Duration, bindCtx *BindingContext) (openbindings.Interface, error) {
+	iface, _, _, err := fetchOpenBindings(raw, timeout, "", bindCtx)
+	return iface, err
+}
+
+// fetchOpenBindings fetches a delegate's OpenBindings interface and returns it
+// with the response ETag. When etag is non-empty the request is conditional;
+// notModified reports a 304 response, in which case iface is empty.
+func fetchOpenBindings(raw string, timeout time.Duration, etag string, bindCtx *BindingContext) (iface openbindings.Interface, newETag string, notModified bool, err error) {
+	wellKnown, err := WellKnownURL(raw)
 	if err != nil {
-		return openbindings.Interface{}, fmt.Errorf("invalid delegate URL %q: %w", raw, err)
-	}
-	if !strings.Contains(u.Path, WellKnownPath) {
-		u.Path = strings.TrimRight(u.Path, "/") + WellKnownPath
+		return iface, "", false, err
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return iface, "", false, fmt.Errorf("create request for %q: %w", wellKnown, err)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	client, err := HTTPClient(bindCtx)
 	if err != nil {
-		return openbindings.Interface{}, fmt.Errorf("create request for %q: %w", u.String(), err)
+		return iface, "", false, err
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
-		return openbindings.Interface{}, fmt.Errorf("fetch openbindings from %q: %w", u.String(), err)
+		return iface, "", false, fmt.Errorf("fetch openbindings from %q: %w", wellKnown, err)
 	}
 	defer resp.Body.Close()
+	if etag != "" && resp.StatusCode == http.StatusNotModified {
+		return iface, etag, true, nil
+	}
 	if resp.StatusCode >= 400 {
-		return openbindings.Interface{}, fmt.Errorf("openbindings request to %q failed: %s", u.String(), resp.Status)
+		return iface, "", false, fmt.Errorf("openbindings request to %q failed: %s", wellKnown, resp.Status)
 	}
-	var iface openbindings.Interface
 	if err := json.NewDecoder(resp.Body).Decode(&iface); err != nil {
-		return openbindings.Interface{}, fmt.Errorf("invalid openbindings JSON from %q: %w", u.String(), err)
+		return iface, "", false, fmt.Errorf("invalid openbindings JSON from %q: %w", wellKnown, err)
 	}
-	return iface, nil
+	return iface, resp.Header.Get("ETag"), false, nil
 }
 
 // RunCLI executes a CLI command with timeout and returns stdout, stderr, and error.
@@ -0,0 +1,111 @@
+// Package delegates - remote.go contains support for HTTP-based delegates.
+package delegates
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openbindings/openbindings-go"
+)
+
+// BindingInvoker invokes an operation on a delegate's OpenBindings interface
+// by executing one of its bindings. ifaceURL is the URL the interface was
+// fetched from; relative source locations are resolved against it.
+//
+// The invoker is injected by the caller (like BuiltinFormatChecker) because
+// executing a binding requires the builtin format handlers, which themselves
+// depend on this package.
+type BindingInvoker func(ctx context.Context, iface *openbindings.Interface, ifaceURL string, opKey string, input any) (any, error)
+
+// WellKnownURL returns the OpenBindings discovery URL for an HTTP delegate.
+// URLs that already point at the well-known path are returned unchanged.
+func WellKnownURL(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid delegate URL %q: %w", raw, err)
+	}
+	if !strings.Contains(u.Path, WellKnownPath) {
+		u.Path = strings.TrimRight(u.Path, "/") + WellKnownPath
+	}
+	return u.String(), nil
+}
+
+// ResolveSourceURL resolves a source location from a remote interface against
+// the URL the interface was fetched from. Absolute URLs and exec: references
+// pass through unchanged.
+func ResolveSourceURL(ifaceURL, loc string) string {
+	if loc == "" || ifaceURL == "" || IsExecURL(loc) || strings.Contains(loc, "://") {
+		return loc
+	}
+	base, err := url.Parse(ifaceURL)
+	if err != nil {
+		return loc
+	}
+	ref, err := url.Parse(loc)
+	if err != nil {
+		return loc
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// probeHTTPDelegate discovers an HTTP delegate's interface and invokes its
+// listFormats binding.
+func probeHTTPDelegate(raw string, timeout time.Duration, invoke BindingInvoker) (*ProbeCacheEntry, error) {
+	if invoke == nil {
+		return nil, fmt.Errorf("formats require executor")
+	}
+	ifaceURL, err := WellKnownURL(raw)
+	if err != nil {
+		return nil, err
+	}
+	iface, etag, _, err := fetchOpenBindings(ifaceURL, timeout, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	out, err := invoke(ctx, &iface, ifaceURL, OpListFormats, nil)
+	if err != nil {
+		return nil, fmt.Errorf("formats request failed: %w", err)
+	}
+	formats, err := ParseFormatList(out)
+	if err != nil {
+		return nil, err
+	}
+	return &ProbeCacheEntry{
+		Location:  raw,
+		Validator: etag,
+		Formats:   formats,
+		Interface: &iface,
+	}, nil
+}
+
+// ParseFormatList extracts format tokens from a listFormats result.
+// Accepts an array of FormatInfo objects or an array of token strings.
+func ParseFormatList(v any) ([]string, error) {
+	items, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s output: expected an array, got %T", OpListFormats, v)
+	}
+	var out []string
+	for _, item := range items {
+		switch it := item.(type) {
+		case string:
+			if tok := strings.TrimSpace(it); tok != "" {
+				out = append(out, tok)
+			}
+		case map[string]any:
+			if tok, _ := it["token"].(string); strings.TrimSpace(tok) != "" {
+				out = append(out, strings.TrimSpace(tok))
+			}
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
@@ -22,6 +22,12 @@ type ResolveParams struct {
 	DelegatePreferences map[string]string
 	// WorkspaceDelegates is the list of delegate locations from the active workspace.
 	WorkspaceDelegates []string
+	// Invoker executes bindings of HTTP delegates while probing their formats.
+	// HTTP delegates are skipped when nil.
+	Invoker BindingInvoker
+	// Cache serves probe results from disk. Delegates are probed on every
+	// resolution when nil.
+	Cache *ProbeCache
 }
 
 // BuiltinFormatChecker is a function that checks if the builtin delegate supports a format.
@@ -31,7 +37,7 @@ type BuiltinFormatChecker func(format string) bool
 // Resolution order:
 //  1. Workspace delegatePreferences (explicit mapping)
 //  2. Builtin delegate if it supports the format
-//  3. Workspace delegates that support the format (probed dynamically)
+//  3. Workspace delegates that support the format (probed, or read from params.Cache)
 //
 // The builtinChecker function is used to check if the builtin delegate supports a format.
 // This allows the caller to inject the format checking logic without creating a circular dependency.
@@ -78,7 +84,15 @@ func Resolve(params ResolveParams, builtinChecker BuiltinFormatChecker) (Resolve
 		if loc == "" {
 			continue
 		}
-		formats, err := ProbeFormats(loc, DefaultProbeTimeout)
+		var (
+			formats []string
+			err     error
+		)
+		if params.Cache != nil {
+			formats, err = params.Cache.ProbeFormats(loc, DefaultProbeTimeout, params.Invoker)
+		} else {
+			formats, err = ProbeFormats(loc, DefaultProbeTimeout, params.Invoker)
+		}
 		if err != nil {
 			continue
 		}
@@ -2,14 +2,24 @@
 package delegates
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// Error.Details keys that callers may use when deciding whether to retry.
+const (
+	// RetryAfterDetailsKey carries a server's Retry-After hint in milliseconds.
+	RetryAfterDetailsKey = "retryAfterMs"
+	// GRPCCodeDetailsKey carries the gRPC status code name (e.g., "Unavailable").
+	GRPCCodeDetailsKey = "grpcCode"
+)
+
 // nonKeyChars matches characters that aren't valid in OBI operation keys.
 var nonKeyChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
 
@@ -83,6 +93,58 @@ func HTTPErrorOutput(start time.Time, statusCode int, status string) ExecuteOutp
 	}
 }
 
+// HTTPResponseErrorOutput builds an ExecuteOutput from an HTTP error response,
+// recording the response's Retry-After hint in the error details if present.
+func HTTPResponseErrorOutput(start time.Time, resp *http.Response) ExecuteOutput {
+	out := HTTPErrorOutput(start, resp.StatusCode, resp.Status)
+	if d, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		out.Error.Details = map[string]any{RetryAfterDetailsKey: d.Milliseconds()}
+	}
+	return out
+}
+
+// ParseRetryAfter parses a Retry-After header value, which is either a
+// number of seconds or an HTTP date. Dates in the past yield zero.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// WithDefaultTimeout applies timeout to ctx unless the caller already set a
+// deadline or disabled timeouts with WithoutDefaultTimeout, so that an
+// execution policy's per-attempt timeout takes precedence over a handler's
+// built-in default.
+func WithDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || ctx.Value(noDefaultTimeoutKey{}) != nil {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+type noDefaultTimeoutKey struct{}
+
+// WithoutDefaultTimeout marks ctx so that WithDefaultTimeout leaves it
+// unbounded, for callers that explicitly disabled the timeout.
+func WithoutDefaultTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noDefaultTimeoutKey{}, true)
+}
+
 // ToStringAnyMap converts any to map[string]any if possible.
 // Returns (nil, false) for nil input.
 func ToStringAnyMap(v any) (map[string]any, bool) {
@@ -0,0 +1,220 @@
+// Package delegates - transport.go builds HTTP clients and TLS settings for
+// network delegates from a BindingContext's transport configuration.
+package delegates
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// TransportConfig controls how network delegates connect to servers.
+// File paths are read when a client is built; relative paths are resolved
+// against the working directory.
+type TransportConfig struct {
+	CAFile             string `json:"caFile,omitempty"`             // PEM bundle trusted in addition to the system roots
+	ClientCertFile     string `json:"clientCertFile,omitempty"`     // PEM client certificate for mTLS
+	ClientKeyFile      string `json:"clientKeyFile,omitempty"`      // PEM private key for ClientCertFile
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"` // Skip server certificate verification
+	ProxyURL           string `json:"proxyUrl,omitempty"`           // HTTP(S) proxy; overrides HTTP_PROXY/HTTPS_PROXY
+	ServerName         string `json:"serverName,omitempty"`         // SNI and verification name override
+}
+
+// HasTLS reports whether the config customizes TLS.
+func (c *TransportConfig) HasTLS() bool {
+	return c != nil && (c.CAFile != "" || c.ClientCertFile != "" || c.ClientKeyFile != "" || c.InsecureSkipVerify || c.ServerName != "")
+}
+
+// transportOf returns the transport config of bindCtx, or nil.
+func transportOf(bindCtx *BindingContext) *TransportConfig {
+	if bindCtx == nil {
+		return nil
+	}
+	return bindCtx.Transport
+}
+
+// TLSConfig builds a tls.Config from the context's transport settings.
+// It returns an empty config when no TLS settings are present.
+func TLSConfig(bindCtx *BindingContext) (*tls.Config, error) {
+	cfg := &tls.Config{}
+	tc := transportOf(bindCtx)
+	if !tc.HasTLS() {
+		return cfg, nil
+	}
+
+	cfg.ServerName = tc.ServerName
+	cfg.InsecureSkipVerify = tc.InsecureSkipVerify //nolint:gosec // explicitly requested by the user's context
+
+	if tc.CAFile != "" {
+		pem, err := os.ReadFile(tc.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA file %q contains no PEM certificates", tc.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if tc.ClientCertFile != "" || tc.ClientKeyFile != "" {
+		if tc.ClientCertFile == "" || tc.ClientKeyFile == "" {
+			return nil, fmt.Errorf("client certificate and key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(tc.ClientCertFile, tc.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// httpClients caches clients by transport config so repeated executions
+// with the same context reuse connections.
+var httpClients sync.Map // TransportConfig -> *http.Client
+
+// HTTPClient returns the HTTP client to use for the context: http.DefaultClient
+// when no transport settings are present, otherwise a client with the
+// configured proxy and TLS settings.
+func HTTPClient(bindCtx *BindingContext) (*http.Client, error) {
+	tc := transportOf(bindCtx)
+	if tc == nil || *tc == (TransportConfig{}) {
+		return http.DefaultClient, nil
+	}
+	if c, ok := httpClients.Load(*tc); ok {
+		return c.(*http.Client), nil
+	}
+
+	tlsCfg, err := TLSConfig(bindCtx)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsCfg
+	if tc.ProxyURL != "" {
+		proxy, err := parseProxyURL(tc.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	client := &http.Client{Transport: transport}
+	actual, _ := httpClients.LoadOrStore(*tc, client)
+	return actual.(*http.Client), nil
+}
+
+// ProxyDialer returns a dial function that tunnels connections through the
+// context's proxy with HTTP CONNECT, for protocols such as gRPC that do not
+// use an http.Client. It returns nil when no proxy is configured.
+func ProxyDialer(bindCtx *BindingContext) (func(ctx context.Context, addr string) (net.Conn, error), error) {
+	tc := transportOf(bindCtx)
+	if tc == nil || tc.ProxyURL == "" {
+		return nil, nil
+	}
+	proxy, err := parseProxyURL(tc.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		return dialConnect(ctx, proxy, addr)
+	}, nil
+}
+
+func parseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q", raw)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported proxy scheme %q (want http or https)", u.Scheme)
+	}
+	return u, nil
+}
+
+// dialConnect opens a tunnel to addr through an HTTP proxy.
+func dialConnect(ctx context.Context, proxy *url.URL, addr string) (net.Conn, error) {
+	proxyAddr := proxy.Host
+	if proxy.Port() == "" {
+		port := "80"
+		if proxy.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddr = net.JoinHostPort(proxy.Hostname(), port)
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("dial proxy: %w", err)
+	}
+	if proxy.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxy.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("proxy TLS handshake: %w", err)
+		}
+		conn = tlsConn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+	if proxy.User != nil {
+		pw, _ := proxy.User.Password()
+		creds := base64.StdEncoding.EncodeToString([]byte(proxy.User.Username() + ":" + pw))
+		req.Header.Set("Proxy-Authorization", "Basic "+creds)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("write CONNECT: %w", err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("read CONNECT response: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT to %s failed: %s", addr, resp.Status)
+	}
+	if br.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, r: br}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn is a net.Conn whose first reads drain bytes the proxy sent
+// along with its CONNECT response.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
@@ -0,0 +1,192 @@
+package delegates
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHTTPClientDefault(t *testing.T) {
+	for _, bindCtx := range []*BindingContext{nil, {}, {Transport: &TransportConfig{}}} {
+		client, err := HTTPClient(bindCtx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if client != http.DefaultClient {
+			t.Errorf("HTTPClient(%+v) should return http.DefaultClient", bindCtx)
+		}
+	}
+}
+
+func TestHTTPClientCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := http.Get(server.URL); err == nil {
+		t.Fatal("expected the default client to reject the test certificate")
+	}
+
+	client, err := HTTPClient(&BindingContext{Transport: &TransportConfig{CAFile: caFile}})
+	if err != nil {
+		t.Fatalf("HTTPClient: %v", err)
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET with CA file: %v", err)
+	}
+	resp.Body.Close()
+
+	again, _ := HTTPClient(&BindingContext{Transport: &TransportConfig{CAFile: caFile}})
+	if again != client {
+		t.Error("expected the client to be reused for the same transport config")
+	}
+}
+
+func TestTLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "not.pem")
+	if err := os.WriteFile(notPEM, []byte("hello"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		tc   TransportConfig
+		want string
+	}{
+		{"missing CA file", TransportConfig{CAFile: filepath.Join(dir, "missing.pem")}, "read CA file"},
+		{"CA file without certificates", TransportConfig{CAFile: notPEM}, "no PEM certificates"},
+		{"cert without key", TransportConfig{ClientCertFile: notPEM}, "must be set together"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := tt.tc
+			_, err := TLSConfig(&BindingContext{Transport: &tc})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPClientProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		fmt.Fprint(w, "via proxy")
+	}))
+	defer proxy.Close()
+
+	client, err := HTTPClient(&BindingContext{Transport: &TransportConfig{ProxyURL: proxy.URL}})
+	if err != nil {
+		t.Fatalf("HTTPClient: %v", err)
+	}
+	resp, err := client.Get("http://api.example.invalid/items")
+	if err != nil {
+		t.Fatalf("GET via proxy: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != "via proxy" || proxied != "http://api.example.invalid/items" {
+		t.Errorf("body = %q, proxied = %q", body, proxied)
+	}
+
+	if _, err := HTTPClient(&BindingContext{Transport: &TransportConfig{ProxyURL: "socks5://localhost:1080"}}); err == nil {
+		t.Error("expected error for unsupported proxy scheme")
+	}
+}
+
+func TestProxyDialer(t *testing.T) {
+	// Target echoes one line back.
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Close()
+	go func() {
+		conn, err := target.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.Copy(conn, conn)
+	}()
+
+	var connectHost, proxyAuth string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "CONNECT only", http.StatusMethodNotAllowed)
+			return
+		}
+		connectHost = r.Host
+		proxyAuth = r.Header.Get("Proxy-Authorization")
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			upstream.Close()
+			return
+		}
+		fmt.Fprint(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+		go func() {
+			defer upstream.Close()
+			defer conn.Close()
+			go io.Copy(upstream, conn)
+			io.Copy(conn, upstream)
+		}()
+	}))
+	defer proxy.Close()
+
+	proxyURL := strings.Replace(proxy.URL, "http://", "http://user:secret@", 1)
+	dial, err := ProxyDialer(&BindingContext{Transport: &TransportConfig{ProxyURL: proxyURL}})
+	if err != nil || dial == nil {
+		t.Fatalf("ProxyDialer = (%v, %v)", dial != nil, err)
+	}
+
+	conn, err := dial(context.Background(), target.Addr().String())
+	if err != nil {
+		t.Fatalf("dial through proxy: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("ping\n")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "ping\n" {
+		t.Errorf("echo = %q", buf)
+	}
+	if connectHost != target.Addr().String() {
+		t.Errorf("CONNECT host = %q, want %q", connectHost, target.Addr().String())
+	}
+	if proxyAuth != "Basic dXNlcjpzZWNyZXQ=" {
+		t.Errorf("Proxy-Authorization = %q", proxyAuth)
+	}
+
+	if dial, err := ProxyDialer(&BindingContext{}); dial != nil || err != nil {
+		t.Errorf("no proxy: got (%v, %v), want (nil, nil)", dial != nil, err)
+	}
+}
@@ -0,0 +1,222 @@
+// Package usage - complete.go lists candidate values for flags and args from
+// the completers declared in a usage spec.
+package usage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openbindings/cli/internal/delegates"
+	"github.com/openbindings/usage-go/usage"
+)
+
+// completeTimeout bounds how long a completer command may run.
+const completeTimeout = 5 * time.Second
+
+// completersFor returns the completers in scope for a command: the spec's
+// followed by the command's own, which take precedence.
+func completersFor(spec *usage.Spec, cmd usage.Command) []usage.Complete {
+	var completes []usage.Complete
+	completes = append(completes, spec.Completes()...)
+	return append(completes, cmd.Completes...)
+}
+
+// findCompleter returns the last completer named name that runs a command,
+// or nil.
+func findCompleter(completes []usage.Complete, name string) *usage.Complete {
+	if name == "" {
+		return nil
+	}
+	for i := len(completes) - 1; i >= 0; i-- {
+		if completes[i].Name == name && completes[i].Run != "" {
+			return &completes[i]
+		}
+	}
+	return nil
+}
+
+// completeAnnotation is the CompleteKey annotation recorded for a completer.
+func completeAnnotation(c usage.Complete) map[string]any {
+	annotation := map[string]any{"run": c.Run}
+	if c.Descriptions {
+		annotation["descriptions"] = true
+	}
+	return annotation
+}
+
+// flagValueName returns the name of the value a flag takes ("user" for
+// "--user <user>"), which completers are matched against.
+func flagValueName(flag usage.Flag) string {
+	if len(flag.Args) > 0 {
+		return flag.Args[0].CleanName()
+	}
+	name := strings.Trim(flag.ParseUsage().ArgName, "<>[]")
+	return strings.TrimSuffix(name, "...")
+}
+
+// Complete lists the candidate values of an input field of the command at
+// input.Ref: the output of the field's completer, run through the shell with
+// the context's environment and working directory, or else the field's
+// choices. Values entered so far are exported to the completer as
+// usage_<name> variables. A field with neither has no candidates.
+func Complete(ctx context.Context, input ExecuteInput, field string) ([]delegates.Completion, error) {
+	spec, err := loadSpec(input.Source)
+	if err != nil {
+		return nil, err
+	}
+	found, err := findCommand(spec, input.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	valueName, choices, ok := lookupField(found, field)
+	if !ok {
+		return nil, fmt.Errorf("command %q has no flag or arg %q", input.Ref, field)
+	}
+	c := findCompleter(completersFor(spec, *found.cmd), valueName)
+	if c == nil {
+		completions := make([]delegates.Completion, len(choices))
+		for i, choice := range choices {
+			completions[i] = delegates.Completion{Value: choice}
+		}
+		return completions, nil
+	}
+
+	opts, err := cliOptionsFor(input.Context, nil)
+	if err != nil {
+		return nil, err
+	}
+	opts.stdin = nil
+	if opts.env == nil {
+		opts.env = os.Environ()
+	}
+	opts.env = append(opts.env, usageEnv(input.Input)...)
+
+	ctx, cancel := context.WithTimeout(ctx, completeTimeout)
+	defer cancel()
+	inv := &cliInvocation{opts: opts}
+	if runtime.GOOS == "windows" {
+		inv.bin, inv.args = "cmd", []string{"/C", c.Run}
+	} else {
+		inv.bin, inv.args = "sh", []string{"-c", c.Run}
+	}
+	cmd := newCLICommand(ctx, inv)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("completer for %q failed: %s", field, msg)
+		}
+		return nil, fmt.Errorf("completer for %q failed: %w", field, err)
+	}
+	return parseCompletions(stdout.String(), c.Descriptions), nil
+}
+
+// lookupField finds a flag or arg of the command by its input field name,
+// returning the value name completers are matched against and its choices.
+func lookupField(found *findCommandResult, field string) (valueName string, choices []string, ok bool) {
+	for _, f := range found.cmd.AllFlags(found.inheritedFlags) {
+		if f.PrimaryName() == field {
+			return flagValueName(f), f.Choices, true
+		}
+	}
+	for _, a := range found.cmd.Args {
+		if a.CleanName() == field {
+			return a.CleanName(), a.Choices, true
+		}
+	}
+	return "", nil, false
+}
+
+// parseCompletions reads one candidate per non-empty line of a completer's
+// output. With descriptions, a line is "value:description", where a colon
+// in the value is escaped as "\:".
+func parseCompletions(output string, descriptions bool) []delegates.Completion {
+	var completions []delegates.Completion
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		completion := delegates.Completion{Value: line}
+		if descriptions {
+			completion.Value, completion.Description = splitDescription(line)
+		}
+		completions = append(completions, completion)
+	}
+	return completions
+}
+
+// splitDescription splits a "value:description" line at its first unescaped
+// colon.
+func splitDescription(line string) (value, description string) {
+	var sb strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == ':':
+			sb.WriteByte(':')
+			i++
+		case line[i] == ':':
+			return sb.String(), strings.TrimSpace(line[i+1:])
+		default:
+			sb.WriteByte(line[i])
+		}
+	}
+	return sb.String(), ""
+}
+
+// usageEnv returns the usage_<name> variables for the values of an object
+// input, in name order. Dashes in names become underscores.
+func usageEnv(input any) []string {
+	m, ok := toStringMap(input)
+	if !ok {
+		return nil
+	}
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	env := make([]string, 0, len(names))
+	for _, name := range names {
+		env = append(env, "usage_"+strings.ReplaceAll(name, "-", "_")+"="+envValue(m[name]))
+	}
+	return env
+}
+
+// envValue formats an input value for the environment: strings as is, lists
+// of strings separated by spaces, and other values as JSON.
+func envValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return jsonString(v)
+			}
+			parts[i] = s
+		}
+		return strings.Join(parts, " ")
+	default:
+		return jsonString(v)
+	}
+}
+
+func jsonString(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,80 @@
+// Package usage - complete_test.go contains tests for field completion.
+package usage
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+func TestComplete(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	content := `
+name "mise"
+bin "mise"
+
+complete "plugin" run="printf 'node:Node.js\\npython\\\\:3:Python\\n'" descriptions=#true
+
+cmd "install" help="Install a tool version" {
+  arg "<plugin>" help="Plugin to install"
+  arg "[version]" help="Version to install"
+  flag "--shell <shell>" help="Shell type" {
+    choices "bash" "zsh"
+  }
+  complete "version" run="echo \"$usage_plugin\"-1.0"
+}
+`
+	source := Source{Content: content}
+	complete := func(field string, input any) []delegates.Completion {
+		t.Helper()
+		got, err := Complete(context.Background(), ExecuteInput{Source: source, Ref: "install", Input: input}, field)
+		if err != nil {
+			t.Fatalf("complete %s: %v", field, err)
+		}
+		return got
+	}
+
+	if got, want := complete("plugin", nil), []delegates.Completion{
+		{Value: "node", Description: "Node.js"},
+		{Value: "python:3", Description: "Python"},
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("plugin = %v, want %v", got, want)
+	}
+	if got, want := complete("version", map[string]any{"plugin": "node"}), []delegates.Completion{{Value: "node-1.0"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("version = %v, want %v", got, want)
+	}
+	if got, want := complete("shell", nil), []delegates.Completion{{Value: "bash"}, {Value: "zsh"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("shell = %v, want the choices %v", got, want)
+	}
+
+	if _, err := Complete(context.Background(), ExecuteInput{Source: source, Ref: "install"}, "missing"); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+func TestParseCompletions(t *testing.T) {
+	got := parseCompletions("a\r\n\nb:with colon\n", false)
+	want := []delegates.Completion{{Value: "a"}, {Value: "b:with colon"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("without descriptions = %v, want %v", got, want)
+	}
+
+	got = parseCompletions("a\\:b: the a \nc\n", true)
+	want = []delegates.Completion{{Value: "a:b", Description: "the a"}, {Value: "c"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("with descriptions = %v, want %v", got, want)
+	}
+}
+
+func TestUsageEnv(t *testing.T) {
+	got := usageEnv(map[string]any{"dry-run": true, "tags": []any{"a", "b"}, "name": "x"})
+	want := []string{"usage_dry_run=true", "usage_name=x", "usage_tags=a b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("usageEnv = %v, want %v", got, want)
+	}
+}
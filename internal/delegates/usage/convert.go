@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/openbindings/cli/internal/delegates"
 	"github.com/openbindings/openbindings-go"
 	"github.com/openbindings/openbindings-go/canonicaljson"
 	"github.com/openbindings/openbindings-go/formattoken"
@@ -133,7 +134,9 @@ func ConvertToInterface(params ConvertParams) (openbindings.Interface, error) {
 			}
 		}
 
-		// Generate input schema from flags and args
+		// Generate input schema from flags and args, with the spec's
+		// completers in scope
+		cmd.Completes = completersFor(spec, cmd)
 		inputSchema, err := generateInputSchema(cmd, inheritedGlobals)
 		if err != nil {
 			schemaErr = err
@@ -232,6 +235,7 @@ func generateInputSchema(cmd usage.Command, inheritedGlobals []usage.Flag) (map[
 
 		prop := generateFlagSchema(flag)
 		if prop != nil {
+			annotateField(prop, flag.Env, findCompleter(cmd.Completes, flagValueName(flag)))
 			properties[name] = prop
 		}
 	}
@@ -252,16 +256,25 @@ func generateInputSchema(cmd usage.Command, inheritedGlobals []usage.Flag) (map[
 
 		prop := generateArgSchema(arg)
 		if prop != nil {
+			annotateField(prop, arg.Env, findCompleter(cmd.Completes, name))
 			properties[name] = prop
 		}
 
 		// Required if using <name> syntax (not [name]) AND no default value
-		// Args with defaults are effectively optional at runtime
-		if arg.IsRequired() && arg.Default == nil {
+		// Args with defaults (or read from the environment) are effectively
+		// optional at runtime
+		if arg.IsRequired() && arg.Default == nil && arg.Env == "" {
 			required = append(required, name)
 		}
 	}
 
+	// Unless the command defines it, the stdin field feeds standard input
+	if _, ok := seen[stdinInputKey]; !ok {
+		properties[stdinInputKey] = map[string]any{
+			"description": "Written to the command's standard input: strings as is, other values as JSON.",
+		}
+	}
+
 	// Build the schema object
 	if len(properties) == 0 {
 		return nil, nil
@@ -279,6 +292,18 @@ func generateInputSchema(cmd usage.Command, inheritedGlobals []usage.Flag) (map[
 	return schema, nil
 }
 
+// annotateField records where a flag or arg value can also come from: the
+// environment variable the command reads it from, and the completer that
+// lists its candidate values.
+func annotateField(prop map[string]any, env string, c *usage.Complete) {
+	if env != "" {
+		prop[delegates.EnvKey] = env
+	}
+	if c != nil {
+		prop[delegates.CompleteKey] = completeAnnotation(*c)
+	}
+}
+
 // generateFlagSchema generates a JSON Schema property for a flag.
 func generateFlagSchema(flag usage.Flag) map[string]any {
 	prop := make(map[string]any)
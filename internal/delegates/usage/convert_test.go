@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/openbindings/cli/internal/delegates"
 	"github.com/openbindings/usage-go/usage"
 )
 
@@ -128,6 +129,39 @@ func TestGenerateInputSchema_FlagWithChoices(t *testing.T) {
 	}
 }
 
+func TestGenerateInputSchema_CompleterAndEnv(t *testing.T) {
+	cmd := usage.Command{
+		Name: "install",
+		Args: []usage.Arg{
+			{Name: "<plugin>", Help: "Plugin to install", Env: "PLUGIN"},
+		},
+		Completes: []usage.Complete{
+			{Name: "plugin", Run: "echo other"},
+			{Name: "plugin", Run: "mise plugins ls", Descriptions: true},
+		},
+	}
+
+	schema, err := generateInputSchema(cmd, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	plugin := schema["properties"].(map[string]any)["plugin"].(map[string]any)
+	if plugin[delegates.EnvKey] != "PLUGIN" {
+		t.Errorf("expected %s PLUGIN, got %v", delegates.EnvKey, plugin[delegates.EnvKey])
+	}
+	complete, ok := plugin[delegates.CompleteKey].(map[string]any)
+	if !ok {
+		t.Fatalf("expected %s annotation, got %v", delegates.CompleteKey, plugin)
+	}
+	if complete["run"] != "mise plugins ls" || complete["descriptions"] != true {
+		t.Errorf("expected the last completer, got %v", complete)
+	}
+	if _, ok := schema["required"]; ok {
+		t.Error("an arg read from the environment should not be required")
+	}
+}
+
 func TestGenerateInputSchema_RequiredArg(t *testing.T) {
 	cmd := usage.Command{
 		Name: "test",
@@ -257,8 +291,30 @@ func TestGenerateInputSchema_EmptyCommand(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if schema != nil {
-		t.Errorf("expected nil schema for command with no flags/args, got %v", schema)
+	// Only the stdin field is left
+	props, _ := schema["properties"].(map[string]any)
+	if _, ok := props[stdinInputKey]; !ok || len(props) != 1 {
+		t.Errorf("expected only the stdin property for command with no flags/args, got %v", schema)
+	}
+}
+
+func TestGenerateInputSchema_StdinDefinedByCommand(t *testing.T) {
+	cmd := usage.Command{
+		Name: "test",
+		Flags: []usage.Flag{
+			{Usage: "--stdin <mode>", Help: "Stdin mode"},
+		},
+	}
+
+	schema, err := generateInputSchema(cmd, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	props := schema["properties"].(map[string]any)
+	stdin, _ := props[stdinInputKey].(map[string]any)
+	if stdin["type"] != schemaTypeString {
+		t.Errorf("expected the command's stdin flag, got %v", props[stdinInputKey])
 	}
 }
 
@@ -5,7 +5,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -21,6 +23,10 @@ const (
 	// This timeout applies when running a command to retrieve a usage spec dynamically.
 	// 5 seconds is generous for most CLI tools but may need adjustment for slow tools.
 	ResolveArtifactTimeout = 5 * time.Second
+
+	// cliInterruptGrace is how long a cancelled command has to exit after
+	// being interrupted before it is killed.
+	cliInterruptGrace = 10 * time.Second
 )
 
 // Execute executes an operation defined in a usage spec.
@@ -36,47 +42,96 @@ func Execute(input ExecuteInput) ExecuteOutput {
 func ExecuteWithContext(ctx context.Context, input ExecuteInput) ExecuteOutput {
 	start := time.Now()
 
+	inv, prepErr := prepareCLI(input)
+	if prepErr != nil {
+		return ExecuteOutput{
+			Error:      prepErr,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+	}
+
+	// Execute the CLI command
+	output, status, err := runCLI(ctx, inv)
+	duration := time.Since(start).Milliseconds()
+
+	// Check for context cancellation
+	if ctx.Err() != nil {
+		return ExecuteOutput{
+			DurationMs: duration,
+			Error: &Error{
+				Code:    "cancelled",
+				Message: "operation cancelled",
+			},
+		}
+	}
+
+	if err != nil {
+		return ExecuteOutput{
+			Output:     output,
+			Status:     status,
+			DurationMs: duration,
+			Error: &Error{
+				Code:    "execution_failed",
+				Message: err.Error(),
+			},
+		}
+	}
+
+	return ExecuteOutput{
+		Output:     output,
+		Status:     status,
+		DurationMs: duration,
+	}
+}
+
+// cliInvocation is a CLI command ready to run.
+type cliInvocation struct {
+	bin  string
+	args []string
+	opts cliOptions
+}
+
+// prepareCLI resolves the binary, arguments and process options of an
+// operation. The returned error carries the code of the step that failed.
+func prepareCLI(input ExecuteInput) (*cliInvocation, *Error) {
 	var binName string
 	var args []string
+	var stdin any
 
 	// If we have a binary hint, use direct execution (primary path for CLI targets)
 	if input.Source.Binary != "" {
 		binName = input.Source.Binary
+		// The stdin field feeds standard input unless the command defines it
+		cliInput := input.Input
+		if !sourceDefinesField(input.Source, input.Ref, stdinInputKey) {
+			cliInput, stdin = takeStdin(cliInput)
+		}
 		var err error
-		args, err = buildDirectArgsFromRef(input.Ref, input.Input)
+		args, err = buildDirectArgsFromRef(input.Ref, cliInput)
 		if err != nil {
-			return ExecuteOutput{
-				Error: &Error{
-					Code:    "args_build_failed",
-					Message: err.Error(),
-					Details: map[string]any{"ref": input.Ref},
-				},
-				DurationMs: time.Since(start).Milliseconds(),
+			return nil, &Error{
+				Code:    "args_build_failed",
+				Message: err.Error(),
+				Details: map[string]any{"ref": input.Ref},
 			}
 		}
 	} else {
 		// No binary hint - load the usage spec to get binary name and validate
 		spec, err := loadSpec(input.Source)
 		if err != nil {
-			return ExecuteOutput{
-				Error: &Error{
-					Code:    "spec_load_failed",
-					Message: err.Error(),
-				},
-				DurationMs: time.Since(start).Milliseconds(),
+			return nil, &Error{
+				Code:    "spec_load_failed",
+				Message: err.Error(),
 			}
 		}
 
 		// Find the command matching the ref
 		found, err := findCommand(spec, input.Ref)
 		if err != nil {
-			return ExecuteOutput{
-				Error: &Error{
-					Code:    "command_not_found",
-					Message: err.Error(),
-					Details: map[string]any{"ref": input.Ref},
-				},
-				DurationMs: time.Since(start).Milliseconds(),
+			return nil, &Error{
+				Code:    "command_not_found",
+				Message: err.Error(),
+				Details: map[string]any{"ref": input.Ref},
 			}
 		}
 
@@ -87,60 +142,37 @@ func ExecuteWithContext(ctx context.Context, input ExecuteInput) ExecuteOutput {
 			binName = meta.Name
 		}
 		if binName == "" {
-			return ExecuteOutput{
-				Error: &Error{
-					Code:    "no_binary",
-					Message: "usage spec does not define a binary name (bin or name)",
-				},
-				DurationMs: time.Since(start).Milliseconds(),
+			return nil, &Error{
+				Code:    "no_binary",
+				Message: "usage spec does not define a binary name (bin or name)",
 			}
 		}
 
+		// The stdin field feeds standard input unless the command defines it
+		cliInput := input.Input
+		if !definesField(found.cmd, found.inheritedFlags, stdinInputKey) {
+			cliInput, stdin = takeStdin(cliInput)
+		}
+
 		// Build CLI arguments from spec (pass inherited globals for global flag support)
-		args, err = buildCLIArgs(found.path, found.cmd, found.inheritedFlags, input.Input)
+		args, err = buildCLIArgs(found.path, found.cmd, found.inheritedFlags, cliInput)
 		if err != nil {
-			return ExecuteOutput{
-				Error: &Error{
-					Code:    "args_build_failed",
-					Message: err.Error(),
-				},
-				DurationMs: time.Since(start).Milliseconds(),
+			return nil, &Error{
+				Code:    "args_build_failed",
+				Message: err.Error(),
 			}
 		}
 	}
 
-	// Execute the CLI command
-	output, status, err := runCLI(ctx, binName, args)
-	duration := time.Since(start).Milliseconds()
-
-	// Check for context cancellation
-	if ctx.Err() != nil {
-		return ExecuteOutput{
-			DurationMs: duration,
-			Error: &Error{
-				Code:    "cancelled",
-				Message: "operation cancelled",
-			},
-		}
-	}
-
+	opts, err := cliOptionsFor(input.Context, stdin)
 	if err != nil {
-		return ExecuteOutput{
-			Output:     output,
-			Status:     status,
-			DurationMs: duration,
-			Error: &Error{
-				Code:    "execution_failed",
-				Message: err.Error(),
-			},
+		return nil, &Error{
+			Code:    "invalid_context",
+			Message: err.Error(),
 		}
 	}
 
-	return ExecuteOutput{
-		Output:     output,
-		Status:     status,
-		DurationMs: duration,
-	}
+	return &cliInvocation{bin: binName, args: args, opts: opts}, nil
 }
 
 // buildDirectArgsFromRef builds CLI arguments directly from the ref.
@@ -490,22 +522,16 @@ func formatFlagWithDef(name string, value any, flagDef usage.Flag) ([]string, er
 // If stdout contains valid JSON, it is parsed and returned directly.
 // Otherwise, returns {stdout: string, stderr?: string}.
 // The context allows cancellation of long-running commands.
-func runCLI(ctx context.Context, binName string, args []string) (any, int, error) {
-	cmd := exec.CommandContext(ctx, binName, args...)
+func runCLI(ctx context.Context, inv *cliInvocation) (any, int, error) {
+	cmd := newCLICommand(ctx, inv)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
-
-	exitCode := 0
+	exitCode, err := exitStatus(cmd.Run())
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			exitCode = exitErr.ExitCode()
-		} else {
-			return nil, 1, err
-		}
+		return nil, 1, err
 	}
 
 	stdoutStr := stdout.String()
@@ -542,6 +568,36 @@ func runCLI(ctx context.Context, binName string, args []string) (any, int, error
 	return output, exitCode, nil
 }
 
+// newCLICommand returns the command for inv. Cancelling ctx interrupts the
+// command (SIGINT where supported) so it can clean up, and kills it if it
+// has not exited cliInterruptGrace later.
+func newCLICommand(ctx context.Context, inv *cliInvocation) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, inv.bin, inv.args...)
+	inv.opts.apply(cmd)
+	cmd.Cancel = func() error {
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = cliInterruptGrace
+	return cmd
+}
+
+// exitStatus returns the exit code of a finished command from the error
+// returned by its Run or Wait. Errors other than a non-zero exit, such as a
+// missing binary, are returned as is.
+func exitStatus(err error) (int, error) {
+	if err == nil {
+		return 0, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	return 1, err
+}
+
 // resolveCommandArtifact resolves an exec: artifact by running the command.
 func resolveCommandArtifact(location string) (string, error) {
 	cmdStr := strings.TrimPrefix(location, "exec:")
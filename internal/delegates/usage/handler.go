@@ -56,18 +56,7 @@ func (h *Handler) CreateInterface(source delegates.Source) (openbindings.Interfa
 
 // ExecuteOperation executes a CLI operation from a usage spec.
 func (h *Handler) ExecuteOperation(ctx context.Context, input delegates.ExecuteInput) delegates.ExecuteOutput {
-	localInput := ExecuteInput{
-		Source: Source{
-			Format:   input.Source.Format,
-			Location: input.Source.Location,
-			Content:  input.Source.Content,
-			Binary:   input.Source.Binary,
-		},
-		Ref:   input.Ref,
-		Input: input.Input,
-	}
-
-	result := ExecuteWithContext(ctx, localInput)
+	result := ExecuteWithContext(ctx, localInput(input))
 
 	return delegates.ExecuteOutput{
 		Output:     result.Output,
@@ -77,6 +66,39 @@ func (h *Handler) ExecuteOperation(ctx context.Context, input delegates.ExecuteI
 	}
 }
 
+// SubscribeOperation runs a CLI operation and streams its output as the
+// command prints it (see Subscribe).
+func (h *Handler) SubscribeOperation(ctx context.Context, input delegates.ExecuteInput) (<-chan delegates.StreamEvent, error) {
+	return Subscribe(ctx, localInput(input))
+}
+
+// StreamsMethodOutput reports that SubscribeOperation streams the output of
+// CLI operations, which are methods.
+func (h *Handler) StreamsMethodOutput() bool {
+	return true
+}
+
+// CompleteField lists the candidate values of a CLI operation's flag or arg
+// (see Complete).
+func (h *Handler) CompleteField(ctx context.Context, input delegates.ExecuteInput, field string) ([]delegates.Completion, error) {
+	return Complete(ctx, localInput(input), field)
+}
+
+// localInput adapts a delegate execution input to this package's types.
+func localInput(input delegates.ExecuteInput) ExecuteInput {
+	return ExecuteInput{
+		Source: Source{
+			Format:   input.Source.Format,
+			Location: input.Source.Location,
+			Content:  input.Source.Content,
+			Binary:   input.Source.Binary,
+		},
+		Ref:     input.Ref,
+		Input:   input.Input,
+		Context: input.Context,
+	}
+}
+
 // Register registers the usage handler with a registry.
 func Register(r *delegates.Registry) {
 	r.Register(New())
@@ -0,0 +1,194 @@
+// Package usage - process.go configures the process a CLI command runs in.
+package usage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/openbindings/cli/internal/delegates"
+	"github.com/openbindings/usage-go/usage"
+)
+
+// BindingContext.Metadata keys that control the command's process.
+const (
+	// metadataCleanEnv starts the command with only BindingContext.Environment
+	// instead of the parent environment plus BindingContext.Environment.
+	metadataCleanEnv = "cleanEnv"
+	// metadataWorkingDir is the directory the command runs in.
+	metadataWorkingDir = "workingDir"
+	// metadataStdinFile names a file written to the command's standard input
+	// when the input has no stdin field.
+	metadataStdinFile = "stdinFile"
+)
+
+// stdinInputKey is the input field written to the command's standard input,
+// unless the command defines a flag or arg of that name. Strings are written
+// as is and other values as JSON.
+const stdinInputKey = "stdin"
+
+// cliOptions configures the process a command runs in.
+type cliOptions struct {
+	env   []string // nil inherits the parent environment
+	dir   string   // empty runs in the current directory
+	stdin []byte   // nil leaves standard input empty
+}
+
+// apply sets the options on cmd.
+func (o cliOptions) apply(cmd *exec.Cmd) {
+	cmd.Env = o.env
+	cmd.Dir = o.dir
+	if o.stdin != nil {
+		cmd.Stdin = bytes.NewReader(o.stdin)
+	}
+}
+
+// cliOptionsFor returns the process options for a binding context: its
+// environment layered over the parent's (or alone with cleanEnv), the
+// workingDir metadata, and stdin from the stdin input field or else the
+// stdinFile metadata.
+func cliOptionsFor(bindCtx *delegates.BindingContext, stdin any) (cliOptions, error) {
+	var opts cliOptions
+	var meta map[string]any
+	var env map[string]string
+	if bindCtx != nil {
+		meta, env = bindCtx.Metadata, bindCtx.Environment
+	}
+
+	clean, err := metadataBool(meta, metadataCleanEnv)
+	if err != nil {
+		return opts, err
+	}
+	if clean || len(env) > 0 {
+		if !clean {
+			opts.env = os.Environ()
+		}
+		keys := make([]string, 0, len(env))
+		for k := range env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			opts.env = append(opts.env, k+"="+env[k])
+		}
+		if opts.env == nil {
+			opts.env = []string{}
+		}
+	}
+
+	opts.dir, _ = meta[metadataWorkingDir].(string)
+	if opts.dir != "" && opts.env != nil {
+		// exec only sets PWD for Dir when it builds the environment itself.
+		if abs, err := filepath.Abs(opts.dir); err == nil {
+			opts.env = append(opts.env, "PWD="+abs)
+		}
+	}
+
+	switch v := stdin.(type) {
+	case nil:
+		if path, _ := meta[metadataStdinFile].(string); path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return opts, fmt.Errorf("read stdin file: %w", err)
+			}
+			opts.stdin = data
+		}
+	case string:
+		opts.stdin = []byte(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return opts, fmt.Errorf("encode stdin: %w", err)
+		}
+		opts.stdin = data
+	}
+	return opts, nil
+}
+
+// metadataBool reads a boolean metadata value, which may be a bool or a
+// string such as "true" (as set by ob context set --meta).
+func metadataBool(meta map[string]any, key string) (bool, error) {
+	switch v := meta[key].(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("metadata %s: %q is not a boolean", key, v)
+		}
+		return b, nil
+	default:
+		return false, fmt.Errorf("metadata %s: %v is not a boolean", key, v)
+	}
+}
+
+// takeStdin removes the stdin field from an object input, returning the
+// remaining input and the field's value.
+func takeStdin(input any) (any, any) {
+	m, ok := toStringMap(input)
+	if !ok {
+		return input, nil
+	}
+	stdin, ok := m[stdinInputKey]
+	if !ok {
+		return input, nil
+	}
+	rest := make(map[string]any, len(m)-1)
+	for k, v := range m {
+		if k != stdinInputKey {
+			rest[k] = v
+		}
+	}
+	return rest, stdin
+}
+
+// sourceDefinesField reports whether the command ref names in the source's
+// usage spec defines a flag or arg called name. A bare binary hint has no
+// spec, so nothing is known to be defined.
+func sourceDefinesField(source Source, ref, name string) bool {
+	if source.Location == "" && source.Content == nil {
+		return false
+	}
+	spec, err := loadSpec(source)
+	if err != nil {
+		return false
+	}
+	found, err := findCommand(spec, ref)
+	if err != nil {
+		return false
+	}
+	return definesField(found.cmd, found.inheritedFlags, name)
+}
+
+// definesField reports whether name is a flag or arg of cmd.
+func definesField(cmd *usage.Command, inheritedGlobals []usage.Flag, name string) bool {
+	for _, f := range cmd.AllFlags(inheritedGlobals) {
+		if f.PrimaryName() == name {
+			return true
+		}
+		parsed := f.ParseUsage()
+		for _, n := range parsed.Short {
+			if n == name {
+				return true
+			}
+		}
+		for _, n := range parsed.Long {
+			if n == name {
+				return true
+			}
+		}
+	}
+	for _, a := range cmd.Args {
+		if a.CleanName() == name {
+			return true
+		}
+	}
+	return false
+}
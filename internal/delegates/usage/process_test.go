@@ -0,0 +1,131 @@
+// Package usage - process_test.go contains tests for the command process options.
+package usage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+func TestExecute_ContextEnvDirAndStdin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OB_PARENT_VAR", "parent")
+
+	// Only shell builtins are used, since a clean environment has no PATH.
+	script := `sh -c 'read -r line; printf "%s|%s|%s|%s" "$TOKEN" "${OB_PARENT_VAR-unset}" "$PWD" "$line"'`
+	run := func(bindCtx *delegates.BindingContext, input any) string {
+		t.Helper()
+		result := ExecuteWithContext(context.Background(), ExecuteInput{
+			Source:  Source{Binary: "sh"},
+			Ref:     strings.TrimPrefix(script, "sh "),
+			Input:   input,
+			Context: bindCtx,
+		})
+		if result.Error != nil {
+			t.Fatalf("execute: %s", result.Error.Message)
+		}
+		out, _ := result.Output.(map[string]any)
+		stdout, _ := out["stdout"].(string)
+		return stdout
+	}
+
+	bindCtx := &delegates.BindingContext{
+		Environment: map[string]string{"TOKEN": "secret"},
+		Metadata:    map[string]any{"workingDir": dir},
+	}
+	if got, want := run(bindCtx, map[string]any{"stdin": "hello\n"}), "secret|parent|"+dir+"|hello"; got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+
+	bindCtx.Metadata["cleanEnv"] = "true"
+	if got, want := run(bindCtx, map[string]any{"stdin": map[string]any{"a": 1}}), `secret|unset|`+dir+`|{"a":1}`; got != want {
+		t.Errorf("stdout with cleanEnv = %q, want %q", got, want)
+	}
+}
+
+func TestExecute_BinaryHintKeepsStdinFlag(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses echo")
+	}
+	content := `
+name "echo"
+bin "echo"
+
+cmd "hello" {
+  flag "--stdin <mode>"
+}
+`
+	result := ExecuteWithContext(context.Background(), ExecuteInput{
+		Source: Source{Binary: "echo", Content: content},
+		Ref:    "hello",
+		Input:  map[string]any{"stdin": "lines"},
+	})
+	if result.Error != nil {
+		t.Fatalf("execute: %s", result.Error.Message)
+	}
+	out, _ := result.Output.(map[string]any)
+	if stdout, _ := out["stdout"].(string); strings.TrimSpace(stdout) != "hello --stdin lines" {
+		t.Errorf("stdout = %q, want the stdin flag passed", stdout)
+	}
+}
+
+func TestCLIOptionsFor(t *testing.T) {
+	stdinFile := filepath.Join(t.TempDir(), "in.txt")
+	if err := os.WriteFile(stdinFile, []byte("from file"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	bindCtx := &delegates.BindingContext{Metadata: map[string]any{"stdinFile": stdinFile}}
+
+	opts, err := cliOptionsFor(bindCtx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(opts.stdin) != "from file" {
+		t.Errorf("stdin = %q, want the file contents", opts.stdin)
+	}
+	if opts.env != nil {
+		t.Errorf("env = %v, want nil (inherit)", opts.env)
+	}
+
+	// The input field wins over the file.
+	opts, _ = cliOptionsFor(bindCtx, "from input")
+	if string(opts.stdin) != "from input" {
+		t.Errorf("stdin = %q, want the input field", opts.stdin)
+	}
+
+	bindCtx.Metadata = map[string]any{"cleanEnv": true}
+	opts, _ = cliOptionsFor(bindCtx, nil)
+	if opts.env == nil || len(opts.env) != 0 {
+		t.Errorf("env = %v, want empty", opts.env)
+	}
+
+	bindCtx.Metadata = map[string]any{"cleanEnv": "sometimes"}
+	if _, err := cliOptionsFor(bindCtx, nil); err == nil {
+		t.Error("expected an error for a non-boolean cleanEnv")
+	}
+}
+
+func TestTakeStdin(t *testing.T) {
+	input := map[string]any{"stdin": "data", "verbose": true}
+	rest, stdin := takeStdin(input)
+	if stdin != "data" {
+		t.Errorf("stdin = %v, want data", stdin)
+	}
+	if m, _ := rest.(map[string]any); len(m) != 1 || m["verbose"] != true {
+		t.Errorf("rest = %v, want only verbose", rest)
+	}
+	if len(input) != 2 {
+		t.Error("takeStdin modified its input")
+	}
+}
@@ -0,0 +1,114 @@
+// Package usage - stream.go streams the output of CLI commands as it is printed.
+package usage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// Subscribe runs an operation's command and streams its output line by line
+// while it runs. Each stdout line that holds JSON (as with NDJSON output) is
+// sent as its parsed value; other stdout lines are sent as {"stdout": line}
+// and stderr lines as {"stderr": line}. A non-zero exit is reported as a
+// final error event. The channel is closed when the command exits; cancelling
+// ctx interrupts the command.
+func Subscribe(ctx context.Context, input ExecuteInput) (<-chan delegates.StreamEvent, error) {
+	inv, prepErr := prepareCLI(input)
+	if prepErr != nil {
+		return nil, errors.New(prepErr.Message)
+	}
+
+	ch := make(chan delegates.StreamEvent)
+	send := func(ev delegates.StreamEvent) {
+		select {
+		case ch <- ev:
+		case <-ctx.Done():
+		}
+	}
+	stdout := &lineWriter{emit: func(line string) { send(delegates.StreamEvent{Data: parseOutputLine(line)}) }}
+	stderr := &lineWriter{emit: func(line string) { send(delegates.StreamEvent{Data: map[string]any{"stderr": line}}) }}
+
+	cmd := newCLICommand(ctx, inv)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start %s: %w", inv.bin, err)
+	}
+
+	go func() {
+		defer close(ch)
+		waitErr := cmd.Wait()
+		stdout.flush()
+		stderr.flush()
+
+		if ctx.Err() != nil {
+			return
+		}
+		code, err := exitStatus(waitErr)
+		switch {
+		case err != nil:
+			send(delegates.StreamEvent{Error: &delegates.Error{Code: "execution_failed", Message: err.Error()}})
+		case code != 0:
+			send(delegates.StreamEvent{Error: &delegates.Error{
+				Code:    "execution_failed",
+				Message: fmt.Sprintf("%s exited with status %d", inv.bin, code),
+				Details: map[string]any{"status": code},
+			}})
+		}
+	}()
+
+	return ch, nil
+}
+
+// parseOutputLine returns the parsed value of a stdout line holding JSON,
+// or {"stdout": line}.
+func parseOutputLine(line string) any {
+	trimmed := strings.TrimSpace(line)
+	if delegates.MaybeJSON(trimmed) {
+		var parsed any
+		if json.Unmarshal([]byte(trimmed), &parsed) == nil {
+			return parsed
+		}
+	}
+	return map[string]any{"stdout": line}
+}
+
+// lineWriter passes each complete line written to it, without its line
+// ending, to emit.
+type lineWriter struct {
+	mu      sync.Mutex
+	partial []byte
+	emit    func(line string)
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(strings.TrimSuffix(string(w.partial[:i]), "\r"))
+		w.partial = append(w.partial[:0], w.partial[i+1:]...)
+	}
+	return len(p), nil
+}
+
+// flush emits a final line that has no line ending.
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.partial) > 0 {
+		w.emit(string(w.partial))
+		w.partial = nil
+	}
+}
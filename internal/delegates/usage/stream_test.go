@@ -0,0 +1,100 @@
+// Package usage - stream_test.go contains tests for streaming command output.
+package usage
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+func TestSubscribe_StreamsLinesAndExitStatus(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	ch, err := Subscribe(context.Background(), ExecuteInput{
+		Source: Source{Binary: "sh"},
+		Ref:    `-c 'echo "{\"a\":1}"; echo text; echo err >&2; exit 3'`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var data []any
+	var last *delegates.Error
+	for ev := range ch {
+		if ev.Error != nil {
+			last = ev.Error
+			continue
+		}
+		data = append(data, ev.Data)
+	}
+
+	// stdout and stderr are read concurrently, so only stdout order is fixed.
+	var stdout []any
+	sawStderr := false
+	for _, d := range data {
+		if m, ok := d.(map[string]any); ok && m["stderr"] == "err" {
+			sawStderr = true
+			continue
+		}
+		stdout = append(stdout, d)
+	}
+	want := []any{map[string]any{"a": float64(1)}, map[string]any{"stdout": "text"}}
+	if !reflect.DeepEqual(stdout, want) {
+		t.Errorf("stdout events = %v, want %v", stdout, want)
+	}
+	if !sawStderr {
+		t.Error("missing stderr event")
+	}
+	if last == nil {
+		t.Fatal("expected a final error event")
+	}
+	if details, _ := last.Details.(map[string]any); details["status"] != 3 {
+		t.Errorf("error details = %v, want status 3", last.Details)
+	}
+}
+
+func TestSubscribe_CancelInterruptsCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := Subscribe(ctx, ExecuteInput{
+		Source: Source{Binary: "sh"},
+		Ref:    `-c 'echo ready; exec sleep 30'`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev := <-ch; !reflect.DeepEqual(ev.Data, map[string]any{"stdout": "ready"}) {
+		t.Fatalf("first event = %v, want ready", ev.Data)
+	}
+	cancel()
+
+	select {
+	case ev, ok := <-ch:
+		if ok {
+			t.Errorf("unexpected event after cancel: %+v", ev)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("command was not interrupted")
+	}
+}
+
+func TestLineWriter(t *testing.T) {
+	var lines []string
+	w := &lineWriter{emit: func(line string) { lines = append(lines, line) }}
+	w.Write([]byte("one\r\ntw"))
+	w.Write([]byte("o\nthree"))
+	if want := []string{"one", "two"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+	w.flush()
+	if want := []string{"one", "two", "three"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines after flush = %q, want %q", lines, want)
+	}
+}
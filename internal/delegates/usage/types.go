@@ -20,6 +20,10 @@ type ExecuteInput struct {
 	Source Source // Binding source
 	Ref    string // Operation reference (command path, e.g., "config set")
 	Input  any    // Operation input data
+
+	// Context supplies the command's environment, working directory and
+	// stdin (see cliOptionsFor). Optional.
+	Context *delegates.BindingContext
 }
 
 // ExecuteOutput is the output from operation execution.
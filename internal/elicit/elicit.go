@@ -0,0 +1,215 @@
+// Package elicit builds interactive forms for elicitation requests, in which
+// a server asks the user for structured input while an operation runs (see
+// delegates.Interaction). The same form runs standalone in the CLI and
+// embedded in the browse TUI.
+package elicit
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// Request is an elicitation request being answered through a form.
+type Request struct {
+	Form *huh.Form
+
+	fields []*field
+	send   bool
+}
+
+// field is one schema property and the raw value entered for it.
+type field struct {
+	name     string
+	kind     string // "string", "number", "integer", "boolean" or "enum"
+	required bool
+	text     string // entered value for string, number, integer and enum fields
+	checked  bool   // entered value for boolean fields
+}
+
+// New builds a form asking for the properties of req.Schema, followed by a
+// choice between sending the answer and declining.
+func New(req delegates.ElicitRequest) *Request {
+	r := &Request{send: true}
+
+	message := req.Message
+	if message == "" {
+		message = "The server is asking for input."
+	}
+	groupFields := []huh.Field{
+		huh.NewNote().Title("Input requested").Description(message),
+	}
+
+	props, _ := req.Schema["properties"].(map[string]any)
+	required := map[string]bool{}
+	if list, ok := req.Schema["required"].([]any); ok {
+		for _, v := range list {
+			if name, ok := v.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, _ := props[name].(map[string]any)
+		f := newField(name, prop, required[name])
+		r.fields = append(r.fields, f)
+		groupFields = append(groupFields, f.input(prop))
+	}
+
+	groupFields = append(groupFields, huh.NewConfirm().
+		Title("Send this response?").
+		Affirmative("Send").
+		Negative("Decline").
+		Value(&r.send))
+
+	r.Form = huh.NewForm(huh.NewGroup(groupFields...))
+	return r
+}
+
+// Result returns the answer once the form has finished: the entered
+// values when the user chose to send them, a decline, or a cancel when
+// the form was aborted.
+func (r *Request) Result() delegates.ElicitResult {
+	if r.Form.State != huh.StateCompleted {
+		return delegates.ElicitResult{Action: delegates.ElicitCancel}
+	}
+	if !r.send {
+		return delegates.ElicitResult{Action: delegates.ElicitDecline}
+	}
+	content := map[string]any{}
+	for _, f := range r.fields {
+		if v, ok := f.value(); ok {
+			content[f.name] = v
+		}
+	}
+	return delegates.ElicitResult{Action: delegates.ElicitAccept, Content: content}
+}
+
+// Run shows the form on the terminal and returns the answer. The form is
+// drawn on stderr so that it does not mix with operation output.
+func Run(req delegates.ElicitRequest) (delegates.ElicitResult, error) {
+	r := New(req)
+	if err := r.Form.WithOutput(os.Stderr).Run(); err != nil && err != huh.ErrUserAborted {
+		return delegates.ElicitResult{}, err
+	}
+	return r.Result(), nil
+}
+
+func newField(name string, prop map[string]any, required bool) *field {
+	f := &field{name: name, required: required}
+	f.kind, _ = prop["type"].(string)
+	if len(enumValues(prop)) > 0 {
+		f.kind = "enum"
+	}
+	switch def := prop["default"].(type) {
+	case bool:
+		f.checked = def
+	case nil:
+	default:
+		f.text = fmt.Sprint(def)
+	}
+	return f
+}
+
+// input returns the form control for the field.
+func (f *field) input(prop map[string]any) huh.Field {
+	title, _ := prop["title"].(string)
+	if title == "" {
+		title = f.name
+	}
+	if f.required {
+		title += " *"
+	}
+	description, _ := prop["description"].(string)
+
+	switch f.kind {
+	case "boolean":
+		return huh.NewConfirm().Title(title).Description(description).Value(&f.checked)
+	case "enum":
+		values := enumValues(prop)
+		if f.text == "" {
+			f.text = values[0]
+		}
+		return huh.NewSelect[string]().Title(title).Description(description).
+			Options(huh.NewOptions(values...)...).Value(&f.text)
+	default:
+		return huh.NewInput().Title(title).Description(description).Value(&f.text).
+			Validate(func(s string) error {
+				_, err := f.parseText(s)
+				return err
+			})
+	}
+}
+
+// value returns the typed value of the field, or false when it was left
+// empty.
+func (f *field) value() (any, bool) {
+	if f.kind == "boolean" {
+		return f.checked, true
+	}
+	if strings.TrimSpace(f.text) == "" {
+		return nil, false
+	}
+	v, err := f.parseText(f.text)
+	return v, err == nil
+}
+
+// parseText converts entered text to the field's type.
+func (f *field) parseText(text string) (any, error) {
+	s := strings.TrimSpace(text)
+	if s == "" {
+		if f.required {
+			return nil, fmt.Errorf("required")
+		}
+		return nil, nil
+	}
+	switch f.kind {
+	case "integer":
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		return n, nil
+	case "number":
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return n, nil
+	default:
+		return text, nil
+	}
+}
+
+// enumValues returns the allowed values of an enum property, given either
+// as "enum" or as "oneOf" entries with "const".
+func enumValues(prop map[string]any) []string {
+	var values []string
+	if list, ok := prop["enum"].([]any); ok {
+		for _, v := range list {
+			values = append(values, fmt.Sprint(v))
+		}
+	}
+	if list, ok := prop["oneOf"].([]any); ok {
+		for _, v := range list {
+			if m, ok := v.(map[string]any); ok {
+				if c, ok := m["const"]; ok {
+					values = append(values, fmt.Sprint(c))
+				}
+			}
+		}
+	}
+	return values
+}
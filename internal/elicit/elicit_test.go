@@ -0,0 +1,71 @@
+package elicit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/huh"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+func TestResult(t *testing.T) {
+	req := delegates.ElicitRequest{
+		Message: "Who are you?",
+		Schema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name":  map[string]any{"type": "string"},
+				"age":   map[string]any{"type": "integer"},
+				"admin": map[string]any{"type": "boolean", "default": true},
+				"color": map[string]any{"type": "string", "enum": []any{"red", "blue"}},
+				"note":  map[string]any{"type": "string"},
+			},
+			"required": []any{"name"},
+		},
+	}
+
+	r := New(req)
+	values := map[string]string{"name": "Ada", "age": "36", "color": "blue"}
+	for _, f := range r.fields {
+		if v, ok := values[f.name]; ok {
+			f.text = v
+		}
+	}
+
+	r.Form.State = huh.StateCompleted
+	want := delegates.ElicitResult{Action: delegates.ElicitAccept, Content: map[string]any{
+		"name": "Ada", "age": int64(36), "admin": true, "color": "blue",
+	}}
+	if got := r.Result(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Result = %+v, want %+v", got, want)
+	}
+
+	r.send = false
+	if got := r.Result(); got.Action != delegates.ElicitDecline || got.Content != nil {
+		t.Errorf("declined Result = %+v", got)
+	}
+
+	r.Form.State = huh.StateAborted
+	if got := r.Result(); got.Action != delegates.ElicitCancel {
+		t.Errorf("aborted Result = %+v", got)
+	}
+}
+
+func TestParseTextValidates(t *testing.T) {
+	tests := []struct {
+		f       field
+		text    string
+		wantErr bool
+	}{
+		{field{kind: "integer"}, "4.5", true},
+		{field{kind: "number"}, "4.5", false},
+		{field{kind: "string", required: true}, " ", true},
+		{field{kind: "string"}, "", false},
+	}
+	for _, tt := range tests {
+		if _, err := tt.f.parseText(tt.text); (err != nil) != tt.wantErr {
+			t.Errorf("parseText(%q) for %+v: error = %v, wantErr %v", tt.text, tt.f, err, tt.wantErr)
+		}
+	}
+}
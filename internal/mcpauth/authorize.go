@@ -0,0 +1,328 @@
+package mcpauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// resourceMetadata is OAuth 2.0 protected resource metadata (RFC 9728).
+type resourceMetadata struct {
+	Resource             string   `json:"resource"`
+	AuthorizationServers []string `json:"authorization_servers"`
+	ScopesSupported      []string `json:"scopes_supported"`
+}
+
+// serverMetadata is OAuth 2.0 authorization server metadata (RFC 8414).
+type serverMetadata struct {
+	Issuer                        string   `json:"issuer"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	RegistrationEndpoint          string   `json:"registration_endpoint"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported"`
+}
+
+// Authorize runs the authorization code flow for the server: it discovers
+// the authorization server, registers a client if needed, calls open with
+// the authorization URL for the user to visit, and waits for the redirect
+// to a loopback listener. The obtained tokens are stored in the credentials
+// and saved.
+func (c *Client) Authorize(ctx context.Context, open func(ctx context.Context, authURL string) error) error {
+	if open == nil {
+		return fmt.Errorf("MCP server %s requires authorization, which needs an interactive session", c.resource)
+	}
+
+	c.mu.Lock()
+	challenge := c.challenge
+	creds := c.creds
+	c.mu.Unlock()
+	if challenge == nil {
+		challenge = &Challenge{}
+	}
+
+	prm, err := c.discoverResource(ctx, challenge)
+	if err != nil {
+		return err
+	}
+	if len(prm.AuthorizationServers) == 0 {
+		return fmt.Errorf("protected resource metadata for %s lists no authorization servers", c.resource)
+	}
+	asm, err := c.discoverServer(ctx, prm.AuthorizationServers[0])
+	if err != nil {
+		return err
+	}
+	if asm.AuthorizationEndpoint == "" || asm.TokenEndpoint == "" {
+		return fmt.Errorf("authorization server %s has no authorization or token endpoint", asm.Issuer)
+	}
+	if !supportsS256(asm.CodeChallengeMethodsSupported) {
+		return fmt.Errorf("authorization server %s does not support PKCE with S256", asm.Issuer)
+	}
+
+	scope := challenge.Scope
+	if scope == "" {
+		scope = strings.Join(prm.ScopesSupported, " ")
+	}
+
+	// Reuse the previous client registration, and its redirect port, with
+	// the same authorization server.
+	prev, _ := stateOf(creds, c.resource)
+	if prev.Issuer != asm.Issuer {
+		prev = State{}
+	}
+	lis, err := listenLoopback(prev.RedirectURI)
+	if err != nil {
+		return fmt.Errorf("start redirect listener: %w", err)
+	}
+	defer lis.Close()
+	redirectURI := fmt.Sprintf("http://%s/callback", lis.Addr().String())
+
+	st := State{
+		Resource:      c.resource,
+		Issuer:        asm.Issuer,
+		TokenEndpoint: asm.TokenEndpoint,
+		RedirectURI:   redirectURI,
+	}
+	switch {
+	case prev.ClientID != "" && prev.RedirectURI == redirectURI:
+		st.ClientID, st.ClientSecret = prev.ClientID, prev.ClientSecret
+	case customString(creds, customClientID) != "":
+		st.ClientID = customString(creds, customClientID)
+		st.ClientSecret = customString(creds, customClientSecret)
+	case asm.RegistrationEndpoint != "":
+		st.ClientID, st.ClientSecret, err = c.register(ctx, asm.RegistrationEndpoint, redirectURI)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("authorization server %s does not support dynamic client registration; set credentials.custom.%s to a registered client ID", asm.Issuer, customClientID)
+	}
+
+	verifier := randomString(32)
+	state := randomString(16)
+	authURL, err := url.Parse(asm.AuthorizationEndpoint)
+	if err != nil {
+		return fmt.Errorf("invalid authorization endpoint %q: %w", asm.AuthorizationEndpoint, err)
+	}
+	q := authURL.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", st.ClientID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("code_challenge", codeChallenge(verifier))
+	q.Set("code_challenge_method", "S256")
+	q.Set("state", state)
+	q.Set("resource", c.resource)
+	if scope != "" {
+		q.Set("scope", scope)
+	}
+	authURL.RawQuery = q.Encode()
+
+	codes := make(chan callbackResult, 1)
+	srv := &http.Server{Handler: callbackHandler(state, codes)}
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Close()
+
+	if err := open(ctx, authURL.String()); err != nil {
+		return err
+	}
+
+	var code string
+	select {
+	case res := <-codes:
+		if res.err != nil {
+			return res.err
+		}
+		code = res.code
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for authorization: %w", ctx.Err())
+	}
+
+	tok, err := c.requestToken(ctx, st, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+		"resource":      {c.resource},
+	})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.challenge = nil
+	return c.storeTokenLocked(st, tok)
+}
+
+// discoverResource fetches the protected resource metadata, from the URL in
+// the challenge or else from the well-known locations for the server.
+func (c *Client) discoverResource(ctx context.Context, challenge *Challenge) (resourceMetadata, error) {
+	var candidates []string
+	if challenge.ResourceMetadata != "" {
+		candidates = []string{challenge.ResourceMetadata}
+	} else {
+		u, _ := url.Parse(c.resource)
+		origin := u.Scheme + "://" + u.Host
+		if u.Path != "" {
+			candidates = append(candidates, origin+"/.well-known/oauth-protected-resource"+u.Path)
+		}
+		candidates = append(candidates, origin+"/.well-known/oauth-protected-resource")
+	}
+
+	var prm resourceMetadata
+	if err := c.getFirst(ctx, candidates, &prm); err != nil {
+		return resourceMetadata{}, fmt.Errorf("discover protected resource metadata for %s: %w", c.resource, err)
+	}
+	if prm.Resource != "" && strings.TrimSuffix(prm.Resource, "/") != c.resource {
+		return resourceMetadata{}, fmt.Errorf("protected resource metadata is for %s, not %s", prm.Resource, c.resource)
+	}
+	return prm, nil
+}
+
+// discoverServer fetches the metadata of the authorization server issuer,
+// trying OAuth and then OpenID Connect discovery.
+func (c *Client) discoverServer(ctx context.Context, issuer string) (serverMetadata, error) {
+	u, err := url.Parse(issuer)
+	if err != nil || u.Host == "" {
+		return serverMetadata{}, fmt.Errorf("invalid authorization server %q", issuer)
+	}
+	origin := u.Scheme + "://" + u.Host
+	path := strings.TrimSuffix(u.Path, "/")
+	candidates := []string{
+		origin + "/.well-known/oauth-authorization-server" + path,
+		origin + "/.well-known/openid-configuration" + path,
+	}
+	if path != "" {
+		candidates = append(candidates, origin+path+"/.well-known/openid-configuration")
+	}
+
+	var asm serverMetadata
+	if err := c.getFirst(ctx, candidates, &asm); err != nil {
+		return serverMetadata{}, fmt.Errorf("discover authorization server metadata for %s: %w", issuer, err)
+	}
+	if asm.Issuer == "" {
+		asm.Issuer = issuer
+	}
+	return asm, nil
+}
+
+// register registers a public client with the authorization server.
+func (c *Client) register(ctx context.Context, endpoint, redirectURI string) (clientID, clientSecret string, err error) {
+	body, err := json.Marshal(map[string]any{
+		"client_name":                clientName,
+		"redirect_uris":              []string{redirectURI},
+		"grant_types":                []string{"authorization_code", "refresh_token"},
+		"response_types":             []string{"code"},
+		"token_endpoint_auth_method": "none",
+	})
+	if err != nil {
+		return "", "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return "", "", fmt.Errorf("build registration request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.base.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("client registration failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", "", fmt.Errorf("client registration at %q failed: %s", endpoint, resp.Status)
+	}
+	var reg struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reg); err != nil {
+		return "", "", fmt.Errorf("invalid client registration response: %w", err)
+	}
+	if reg.ClientID == "" {
+		return "", "", fmt.Errorf("client registration response has no client_id")
+	}
+	return reg.ClientID, reg.ClientSecret, nil
+}
+
+// getFirst decodes the JSON document at the first of urls that serves one.
+func (c *Client) getFirst(ctx context.Context, urls []string, out any) error {
+	var errs []error
+	for _, u := range urls {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		req.Header.Set("Accept", "application/json")
+		resp, err := c.base.Do(req)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			drain(resp)
+			errs = append(errs, fmt.Errorf("GET %s: %s", u, resp.Status))
+			continue
+		}
+		err = json.NewDecoder(resp.Body).Decode(out)
+		resp.Body.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("GET %s: %w", u, err))
+			continue
+		}
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// listenLoopback listens on the loopback address of a previous redirect URI,
+// so a registered client can be reused, or else on any free port.
+func listenLoopback(prevRedirectURI string) (net.Listener, error) {
+	if u, err := url.Parse(prevRedirectURI); err == nil && u.Host != "" {
+		if lis, err := net.Listen("tcp", u.Host); err == nil {
+			return lis, nil
+		}
+	}
+	return net.Listen("tcp", "127.0.0.1:0")
+}
+
+// callbackResult is the outcome of the authorization redirect.
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// callbackHandler receives the authorization redirect and sends its code,
+// or the error it reports, on results.
+func callbackHandler(state string, results chan<- callbackResult) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var res callbackResult
+		switch {
+		case q.Get("state") != state:
+			http.Error(w, "Authorization failed: state mismatch.", http.StatusBadRequest)
+			return
+		case q.Get("error") != "":
+			res.err = fmt.Errorf("authorization denied: %s %s", q.Get("error"), q.Get("error_description"))
+			fmt.Fprintln(w, "Authorization failed. You can close this window.")
+		case q.Get("code") == "":
+			res.err = fmt.Errorf("authorization redirect has no code")
+			fmt.Fprintln(w, "Authorization failed. You can close this window.")
+		default:
+			res.code = q.Get("code")
+			fmt.Fprintln(w, "Authorization complete. You can close this window.")
+		}
+		select {
+		case results <- res:
+		default: // a result was already delivered
+		}
+	})
+	return mux
+}
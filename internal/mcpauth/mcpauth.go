@@ -0,0 +1,472 @@
+// Package mcpauth implements the client side of the MCP authorization spec
+// for servers reached over HTTP: protected resource metadata discovery
+// (RFC 9728), authorization server metadata discovery (RFC 8414 and OpenID
+// Connect discovery), dynamic client registration (RFC 7591), and the OAuth
+// 2.1 authorization code flow with PKCE and a loopback redirect.
+//
+// Grants live in delegates.Credentials under Custom[StateKey], one per MCP
+// server keyed by its canonical URL, so a context can hold tokens for several
+// servers. The context's BearerToken is left alone and is sent only to
+// servers without a grant. Persisting the credentials lets later runs reuse
+// the tokens, which are refreshed transparently when they expire.
+package mcpauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// StateKey is the Credentials.Custom key holding the State of each OAuth
+// grant, keyed by the canonical URL of the MCP server it is for.
+const StateKey = "mcpOAuth"
+
+// Keys in Credentials.Custom for a client registered ahead of time, used
+// when the authorization server does not support dynamic registration.
+const (
+	customClientID     = "clientId"
+	customClientSecret = "clientSecret"
+)
+
+// AuthorizationTimeout bounds how long Authorize waits for the user to
+// complete the authorization in the browser.
+const AuthorizationTimeout = 5 * time.Minute
+
+// expiryLeeway is subtracted from token lifetimes so a token is refreshed
+// shortly before it expires rather than just after.
+const expiryLeeway = 30 * time.Second
+
+// clientName is the client name sent with dynamic client registration.
+const clientName = "ob"
+
+// State is an OAuth grant for one MCP server.
+type State struct {
+	Resource      string    `json:"resource"`               // MCP server the tokens are for
+	AccessToken   string    `json:"accessToken,omitempty"`  // Current access token
+	Issuer        string    `json:"issuer,omitempty"`       // Authorization server issuer
+	TokenEndpoint string    `json:"tokenEndpoint"`          // Token endpoint, for refreshing
+	ClientID      string    `json:"clientId"`               // Client ID, registered or configured
+	ClientSecret  string    `json:"clientSecret,omitempty"` // Client secret, for confidential clients
+	RedirectURI   string    `json:"redirectUri,omitempty"`  // Redirect URI the client was registered with
+	RefreshToken  string    `json:"refreshToken,omitempty"`
+	ExpiresAt     time.Time `json:"expiresAt,omitzero"` // Access token expiry; zero when unknown
+}
+
+// Challenge holds the parameters of a Bearer WWW-Authenticate challenge.
+type Challenge struct {
+	ResourceMetadata string // URL of the protected resource metadata
+	Scope            string // Scopes required for the request
+	Error            string // OAuth error code, e.g. "invalid_token"
+}
+
+// Client authorizes the requests made to one MCP server.
+type Client struct {
+	resource string // canonical server URL
+	base     *http.Client
+	headers  map[string]string
+	save     delegates.CredentialSaver
+
+	mu        sync.Mutex
+	creds     *delegates.Credentials
+	challenge *Challenge // from the last rejected request, until authorized
+}
+
+// NewClient returns a Client for the MCP server at serverURL. Requests go
+// through base with the headers and credentials of bindCtx, which may be
+// nil. save, when not nil, persists credentials after an authorization or
+// a refresh.
+func NewClient(serverURL string, base *http.Client, bindCtx *delegates.BindingContext, save delegates.CredentialSaver) (*Client, error) {
+	resource, err := canonicalResource(serverURL)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{resource: resource, base: base, save: save}
+	if bindCtx != nil {
+		c.headers = bindCtx.Headers
+		c.creds = bindCtx.Credentials
+	}
+	return c, nil
+}
+
+// HTTPClient returns an HTTP client that sends the access token with each
+// request, refreshing it when it has expired. When the server rejects a
+// request with 401 and the token cannot be refreshed, the response is
+// returned as is and NeedsAuthorization reports true.
+func (c *Client) HTTPClient() *http.Client {
+	hc := *c.base
+	base := c.base.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	hc.Transport = &transport{client: c, base: base}
+	return &hc
+}
+
+// NeedsAuthorization reports whether the server has rejected a request for
+// lack of a valid token since the last authorization.
+func (c *Client) NeedsAuthorization() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.challenge != nil
+}
+
+// Credentials returns the current credentials.
+func (c *Client) Credentials() *delegates.Credentials {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.creds
+}
+
+type transport struct {
+	client *Client
+	base   http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c := t.client
+	token := c.accessToken(req.Context())
+	resp, err := t.send(req, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The access token may have been revoked or expired early; a refresh
+	// token can still be good.
+	if fresh, ok := c.refresh(req.Context(), token); ok {
+		if retry, err := t.send(req, fresh); err == nil {
+			if retry.StatusCode != http.StatusUnauthorized {
+				drain(resp)
+				return retry, nil
+			}
+			drain(retry)
+		}
+	}
+
+	c.mu.Lock()
+	c.challenge = parseChallenge(resp.Header.Values("WWW-Authenticate"))
+	c.mu.Unlock()
+	return resp, nil
+}
+
+// send sends a copy of req with the configured headers and token.
+func (t *transport) send(req *http.Request, token string) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		out.Body = body
+	}
+	for k, v := range t.client.headers {
+		out.Header.Set(k, v)
+	}
+	if token != "" {
+		out.Header.Set("Authorization", "Bearer "+token)
+	}
+	return t.base.RoundTrip(out)
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// accessToken returns the token to send: the access token of the grant for
+// this server, refreshed first when it has expired, or else the context's
+// BearerToken. Tokens from grants for other servers are never sent.
+func (c *Client) accessToken(ctx context.Context) string {
+	c.mu.Lock()
+	st, hasState := stateOf(c.creds, c.resource)
+	expired := hasState && st.RefreshToken != "" &&
+		!st.ExpiresAt.IsZero() && time.Now().After(st.ExpiresAt)
+	token := st.AccessToken
+	if !hasState && c.creds != nil {
+		token = c.creds.BearerToken
+	}
+	c.mu.Unlock()
+
+	if expired {
+		if fresh, ok := c.refresh(ctx, token); ok {
+			return fresh
+		}
+	}
+	return token
+}
+
+// refresh exchanges the refresh token for a new access token, unless
+// another request already replaced stale. It reports false when there is
+// no refresh token or the exchange fails.
+func (c *Client) refresh(ctx context.Context, stale string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	st, ok := stateOf(c.creds, c.resource)
+	if !ok || st.RefreshToken == "" {
+		return "", false
+	}
+	if st.AccessToken != stale && st.AccessToken != "" {
+		return st.AccessToken, true
+	}
+
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {st.RefreshToken},
+		"resource":      {c.resource},
+	}
+	tok, err := c.requestToken(ctx, st, form)
+	if err != nil {
+		return "", false
+	}
+	// Saving is best effort; the new token is used for this session either way.
+	_ = c.storeTokenLocked(st, tok)
+	return tok.AccessToken, true
+}
+
+// tokenResponse is a successful token endpoint response.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// requestToken posts form to the token endpoint of st, authenticating as
+// the client of st.
+func (c *Client) requestToken(ctx context.Context, st State, form url.Values) (tokenResponse, error) {
+	form.Set("client_id", st.ClientID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, st.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return tokenResponse{}, fmt.Errorf("build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if st.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(st.ClientID), url.QueryEscape(st.ClientSecret))
+	}
+
+	resp, err := c.base.Do(req)
+	if err != nil {
+		return tokenResponse{}, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return tokenResponse{}, fmt.Errorf("read token response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		var oerr struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		if json.Unmarshal(body, &oerr) == nil && oerr.Error != "" {
+			return tokenResponse{}, fmt.Errorf("token request to %q failed: %s %s", st.TokenEndpoint, oerr.Error, oerr.Description)
+		}
+		return tokenResponse{}, fmt.Errorf("token request to %q failed: %s", st.TokenEndpoint, resp.Status)
+	}
+
+	var tok tokenResponse
+	if err := json.Unmarshal(body, &tok); err != nil {
+		return tokenResponse{}, fmt.Errorf("invalid token response: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return tokenResponse{}, fmt.Errorf("token response has no access_token")
+	}
+	return tok, nil
+}
+
+// storeTokenLocked records tok as the grant for this server in a copy of
+// the credentials and in the stored credentials. c.mu must be held.
+func (c *Client) storeTokenLocked(st State, tok tokenResponse) error {
+	st.Resource = c.resource
+	st.AccessToken = tok.AccessToken
+	if tok.RefreshToken != "" {
+		st.RefreshToken = tok.RefreshToken
+	}
+	st.ExpiresAt = time.Time{}
+	if tok.ExpiresIn > 0 {
+		st.ExpiresAt = time.Now().Add(time.Duration(tok.ExpiresIn)*time.Second - expiryLeeway)
+	}
+
+	raw, err := toMap(st)
+	if err != nil {
+		return err
+	}
+	c.creds = withGrant(c.creds, c.resource, raw)
+
+	if c.save == nil {
+		return nil
+	}
+	// Other clients may have stored grants for other servers since these
+	// credentials were loaded; only this server's grant is replaced.
+	err = c.save(func(stored *delegates.Credentials) *delegates.Credentials {
+		return withGrant(stored, c.resource, raw)
+	})
+	if err != nil {
+		return fmt.Errorf("save credentials: %w", err)
+	}
+	return nil
+}
+
+// withGrant returns a copy of creds, which may be nil, with grant stored
+// for resource.
+func withGrant(creds *delegates.Credentials, resource string, grant map[string]any) *delegates.Credentials {
+	out := &delegates.Credentials{}
+	if creds != nil {
+		*out = *creds
+	}
+	out.Custom = maps.Clone(out.Custom)
+	if out.Custom == nil {
+		out.Custom = map[string]any{}
+	}
+	grants := map[string]any{}
+	if prev, ok := out.Custom[StateKey].(map[string]any); ok {
+		maps.Copy(grants, prev)
+	}
+	grants[resource] = grant
+	out.Custom[StateKey] = grants
+	return out
+}
+
+// stateOf returns the OAuth grant stored in creds for resource.
+func stateOf(creds *delegates.Credentials, resource string) (State, bool) {
+	if creds == nil {
+		return State{}, false
+	}
+	grants, _ := creds.Custom[StateKey].(map[string]any)
+	if grants[resource] == nil {
+		return State{}, false
+	}
+	b, err := json.Marshal(grants[resource])
+	if err != nil {
+		return State{}, false
+	}
+	var st State
+	if err := json.Unmarshal(b, &st); err != nil || st.TokenEndpoint == "" || st.Resource != resource {
+		return State{}, false
+	}
+	return st, true
+}
+
+func toMap(v any) (map[string]any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// customString returns the string value of creds.Custom[key], or "".
+func customString(creds *delegates.Credentials, key string) string {
+	if creds == nil {
+		return ""
+	}
+	s, _ := creds.Custom[key].(string)
+	return s
+}
+
+// canonicalResource returns the canonical URI of an MCP server (RFC 8707):
+// lowercase scheme and host, no fragment, query or trailing slash.
+func canonicalResource(serverURL string) (string, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("invalid MCP server URL %q", serverURL)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment, u.RawFragment, u.RawQuery = "", "", ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+// parseChallenge returns the parameters of the Bearer challenge among the
+// WWW-Authenticate header values. A response without one still needs
+// authorization, so the result is never nil.
+func parseChallenge(values []string) *Challenge {
+	ch := &Challenge{}
+	for _, v := range values {
+		scheme, params, _ := strings.Cut(strings.TrimSpace(v), " ")
+		if !strings.EqualFold(scheme, "Bearer") {
+			continue
+		}
+		for name, value := range authParams(params) {
+			switch name {
+			case "resource_metadata":
+				ch.ResourceMetadata = value
+			case "scope":
+				ch.Scope = value
+			case "error":
+				ch.Error = value
+			}
+		}
+		break
+	}
+	return ch
+}
+
+// authParams parses comma-separated auth-params (name=token or
+// name="quoted string").
+func authParams(s string) map[string]string {
+	params := map[string]string{}
+	for {
+		s = strings.TrimLeft(s, " ,")
+		name, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			return params
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		rest = strings.TrimLeft(rest, " ")
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			var sb strings.Builder
+			i := 1
+			for ; i < len(rest) && rest[i] != '"'; i++ {
+				if rest[i] == '\\' && i+1 < len(rest) {
+					i++
+				}
+				sb.WriteByte(rest[i])
+			}
+			value, s = sb.String(), rest[min(i+1, len(rest)):]
+		} else {
+			value, s, _ = strings.Cut(rest, ",")
+			value = strings.TrimSpace(value)
+		}
+		params[name] = value
+	}
+}
+
+// randomString returns n random bytes encoded as unpadded base64url.
+func randomString(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// codeChallenge returns the S256 PKCE challenge for verifier.
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// supportsS256 reports whether the authorization server advertises PKCE
+// with S256, which the MCP authorization spec requires.
+func supportsS256(methods []string) bool {
+	return slices.Contains(methods, "S256")
+}
@@ -0,0 +1,344 @@
+package mcpauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openbindings/cli/internal/delegates"
+)
+
+// fakeServer is an MCP server protected by its own authorization server.
+type fakeServer struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	codes      map[string]string // code -> PKCE challenge
+	access     map[string]bool   // valid access tokens
+	refresh    map[string]bool   // valid refresh tokens
+	registered int
+	issued     int
+	expiresIn  int64
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+	s := &fakeServer{codes: map[string]string{}, access: map[string]bool{}, refresh: map[string]bool{}, expiresIn: 3600}
+	mux := http.NewServeMux()
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	writeJSON := func(w http.ResponseWriter, status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	mux.HandleFunc("/.well-known/oauth-protected-resource/mcp", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, 200, map[string]any{
+			"resource":              s.URL + "/mcp",
+			"authorization_servers": []string{s.URL},
+			"scopes_supported":      []string{"tools"},
+		})
+	})
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, 200, map[string]any{
+			"issuer":                           s.URL,
+			"authorization_endpoint":           s.URL + "/authorize",
+			"token_endpoint":                   s.URL + "/token",
+			"registration_endpoint":            s.URL + "/register",
+			"code_challenge_methods_supported": []string{"S256"},
+		})
+	})
+	mux.HandleFunc("/register", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.registered++
+		s.mu.Unlock()
+		writeJSON(w, 201, map[string]any{"client_id": "client-1"})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("client_id") != "client-1" || q.Get("code_challenge_method") != "S256" || q.Get("resource") != s.URL+"/mcp" {
+			http.Error(w, "bad request", 400)
+			return
+		}
+		s.mu.Lock()
+		code := fmt.Sprintf("code-%d", len(s.codes))
+		s.codes[code] = q.Get("code_challenge")
+		s.mu.Unlock()
+		redirect, _ := url.Parse(q.Get("redirect_uri"))
+		redirect.RawQuery = url.Values{"code": {code}, "state": {q.Get("state")}}.Encode()
+		http.Redirect(w, r, redirect.String(), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			challenge, ok := s.codes[r.PostForm.Get("code")]
+			if !ok || codeChallenge(r.PostForm.Get("code_verifier")) != challenge {
+				writeJSON(w, 400, map[string]any{"error": "invalid_grant"})
+				return
+			}
+			delete(s.codes, r.PostForm.Get("code"))
+		case "refresh_token":
+			if !s.refresh[r.PostForm.Get("refresh_token")] {
+				writeJSON(w, 400, map[string]any{"error": "invalid_grant"})
+				return
+			}
+		default:
+			writeJSON(w, 400, map[string]any{"error": "unsupported_grant_type"})
+			return
+		}
+		s.issued++
+		access, refresh := fmt.Sprintf("access-%d", s.issued), fmt.Sprintf("refresh-%d", s.issued)
+		s.access[access], s.refresh[refresh] = true, true
+		writeJSON(w, 200, map[string]any{"access_token": access, "refresh_token": refresh, "expires_in": s.expiresIn, "token_type": "Bearer"})
+	})
+	mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
+		token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		s.mu.Lock()
+		ok := s.access[token]
+		s.mu.Unlock()
+		if !ok {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer resource_metadata="%s/.well-known/oauth-protected-resource/mcp", scope="tools"`, s.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	})
+	return s
+}
+
+// followRedirects plays the browser: it visits the authorization URL and
+// follows the redirect to the loopback callback.
+func followRedirects(ctx context.Context, authURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, authURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("authorization: %s", resp.Status)
+	}
+	return nil
+}
+
+func get(t *testing.T, hc *http.Client, u string) int {
+	t.Helper()
+	resp, err := hc.Get(u)
+	if err != nil {
+		t.Fatalf("GET %s: %v", u, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestAuthorizeAndRefresh(t *testing.T) {
+	srv := newFakeServer(t)
+	var saved []*delegates.Credentials
+	save := func(update func(*delegates.Credentials) *delegates.Credentials) error {
+		var stored *delegates.Credentials
+		if len(saved) > 0 {
+			stored = saved[len(saved)-1]
+		}
+		saved = append(saved, update(stored))
+		return nil
+	}
+
+	c, err := NewClient(srv.URL+"/mcp/", http.DefaultClient, nil, save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hc := c.HTTPClient()
+
+	if got := get(t, hc, srv.URL+"/mcp"); got != http.StatusUnauthorized {
+		t.Fatalf("status before authorization = %d, want 401", got)
+	}
+	if !c.NeedsAuthorization() {
+		t.Fatal("NeedsAuthorization() = false after 401")
+	}
+
+	if err := c.Authorize(context.Background(), followRedirects); err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	if c.NeedsAuthorization() {
+		t.Fatal("NeedsAuthorization() = true after Authorize")
+	}
+	if got := get(t, hc, srv.URL+"/mcp"); got != http.StatusOK {
+		t.Fatalf("status after authorization = %d, want 200", got)
+	}
+	if len(saved) != 1 || saved[0].BearerToken != "" {
+		t.Fatalf("saved credentials = %+v, want one save leaving BearerToken unset", saved)
+	}
+	st, ok := stateOf(saved[0], srv.URL+"/mcp")
+	if !ok || st.AccessToken != "access-1" || st.RefreshToken != "refresh-1" || st.ClientID != "client-1" {
+		t.Fatalf("saved state = %+v", st)
+	}
+
+	// A revoked access token is replaced using the refresh token.
+	srv.mu.Lock()
+	delete(srv.access, "access-1")
+	srv.mu.Unlock()
+	if got := get(t, hc, srv.URL+"/mcp"); got != http.StatusOK {
+		t.Fatalf("status after revocation = %d, want 200", got)
+	}
+	if st, _ := stateOf(saved[len(saved)-1], srv.URL+"/mcp"); len(saved) != 2 || st.AccessToken != "access-2" {
+		t.Fatalf("credentials after refresh = %+v, want access-2", saved)
+	}
+
+	// A later run reuses the saved registration.
+	c2, err := NewClient(srv.URL+"/mcp", http.DefaultClient, &delegates.BindingContext{Credentials: saved[1]}, save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.mu.Lock()
+	srv.access = map[string]bool{}
+	srv.refresh = map[string]bool{}
+	srv.mu.Unlock()
+	if got := get(t, c2.HTTPClient(), srv.URL+"/mcp"); got != http.StatusUnauthorized {
+		t.Fatalf("status with revoked grant = %d, want 401", got)
+	}
+	if err := c2.Authorize(context.Background(), followRedirects); err != nil {
+		t.Fatalf("Authorize again: %v", err)
+	}
+	if srv.registered != 1 {
+		t.Fatalf("client registered %d times, want 1", srv.registered)
+	}
+}
+
+func TestGrantsForTwoServersAreKept(t *testing.T) {
+	srv1, srv2 := newFakeServer(t), newFakeServer(t)
+	var mu sync.Mutex
+	stored := &delegates.Credentials{BearerToken: "static"}
+	save := func(update func(*delegates.Credentials) *delegates.Credentials) error {
+		mu.Lock()
+		defer mu.Unlock()
+		stored = update(stored)
+		return nil
+	}
+
+	// Both clients start from the credentials loaded before either
+	// authorized.
+	bindCtx := &delegates.BindingContext{Credentials: stored}
+	for _, srv := range []*fakeServer{srv1, srv2} {
+		c, err := NewClient(srv.URL+"/mcp", http.DefaultClient, bindCtx, save)
+		if err != nil {
+			t.Fatal(err)
+		}
+		get(t, c.HTTPClient(), srv.URL+"/mcp")
+		if err := c.Authorize(context.Background(), followRedirects); err != nil {
+			t.Fatalf("Authorize %s: %v", srv.URL, err)
+		}
+	}
+
+	for _, srv := range []*fakeServer{srv1, srv2} {
+		if st, ok := stateOf(stored, srv.URL+"/mcp"); !ok || st.AccessToken != "access-1" {
+			t.Errorf("grant for %s = %+v, %v", srv.URL, st, ok)
+		}
+	}
+	if stored.BearerToken != "static" {
+		t.Errorf("BearerToken = %q, want static", stored.BearerToken)
+	}
+}
+
+func TestExpiredTokenIsRefreshedBeforeUse(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.refresh["refresh-0"] = true
+	state, _ := toMap(State{
+		Resource:      srv.URL + "/mcp",
+		AccessToken:   "expired",
+		TokenEndpoint: srv.URL + "/token",
+		ClientID:      "client-1",
+		RefreshToken:  "refresh-0",
+		ExpiresAt:     time.Now().Add(-time.Minute),
+	})
+	grants := map[string]any{srv.URL + "/mcp": state}
+	creds := &delegates.Credentials{BearerToken: "static", Custom: map[string]any{StateKey: grants}}
+
+	c, err := NewClient(srv.URL+"/mcp", http.DefaultClient, &delegates.BindingContext{Credentials: creds}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, c.HTTPClient(), srv.URL+"/mcp"); got != http.StatusOK {
+		t.Fatalf("status = %d, want 200", got)
+	}
+	if st, _ := stateOf(c.Credentials(), srv.URL+"/mcp"); st.AccessToken != "access-1" {
+		t.Fatalf("AccessToken = %q, want access-1", st.AccessToken)
+	}
+	if got := c.Credentials().BearerToken; got != "static" {
+		t.Fatalf("BearerToken = %q, want static", got)
+	}
+	if st, _ := stateOf(creds, srv.URL+"/mcp"); st.AccessToken != "expired" {
+		t.Fatal("the credentials passed in were modified")
+	}
+}
+
+func TestTokenForAnotherServerIsNotSent(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	state, _ := toMap(State{Resource: "https://other.example/mcp", AccessToken: "secret", TokenEndpoint: "https://other.example/token", ClientID: "x"})
+	grants := map[string]any{"https://other.example/mcp": state}
+	creds := &delegates.Credentials{Custom: map[string]any{StateKey: grants}}
+	c, err := NewClient(srv.URL, http.DefaultClient, &delegates.BindingContext{Credentials: creds}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	get(t, c.HTTPClient(), srv.URL)
+	if gotAuth != "" {
+		t.Fatalf("Authorization = %q, want none", gotAuth)
+	}
+
+	// A plain bearer token is sent as configured to servers without a grant.
+	creds = &delegates.Credentials{BearerToken: "static", Custom: map[string]any{StateKey: grants}}
+	c, _ = NewClient(srv.URL, http.DefaultClient, &delegates.BindingContext{Credentials: creds}, nil)
+	get(t, c.HTTPClient(), srv.URL)
+	if gotAuth != "Bearer static" {
+		t.Fatalf("Authorization = %q, want Bearer static", gotAuth)
+	}
+}
+
+func TestAuthorizeWithoutInteraction(t *testing.T) {
+	c, err := NewClient("https://example.com/mcp", http.DefaultClient, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Authorize(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "requires authorization") {
+		t.Fatalf("err = %v, want requires authorization", err)
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	ch := parseChallenge([]string{
+		`Basic realm="x"`,
+		`Bearer error="invalid_token", resource_metadata="https://a.example/.well-known/oauth-protected-resource", scope="read write"`,
+	})
+	want := Challenge{
+		ResourceMetadata: "https://a.example/.well-known/oauth-protected-resource",
+		Scope:            "read write",
+		Error:            "invalid_token",
+	}
+	if *ch != want {
+		t.Fatalf("parseChallenge = %+v, want %+v", *ch, want)
+	}
+
+	if ch := parseChallenge(nil); *ch != (Challenge{}) {
+		t.Fatalf("parseChallenge(nil) = %+v, want empty", *ch)
+	}
+}
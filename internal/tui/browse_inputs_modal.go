@@ -1,7 +1,9 @@
 package tui
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -10,6 +12,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/openbindings/cli/internal/app"
 	openbindings "github.com/openbindings/openbindings-go"
 )
 
@@ -106,9 +109,46 @@ func (m *model) showNewInputModal(opKey string) tea.Cmd {
 		m.newInputModal.selectedTemplate = 0
 	}
 
+	// Fields with completers list their candidate values in the background
+	if len(app.CompletableFields(t.obi, opKey)) > 0 {
+		return tea.Batch(textinput.Blink, fieldCandidatesCmd(t.id, opKey, t.obi, t.obiDir))
+	}
 	return textinput.Blink
 }
 
+// fieldCandidatesMsg carries the candidate values listed for an operation's
+// completable input fields.
+type fieldCandidatesMsg struct {
+	tabID      int
+	opKey      string
+	candidates map[string][]app.Completion
+}
+
+// fieldCandidatesCmd runs the completers of an operation's input fields.
+func fieldCandidatesCmd(tabID int, opKey string, iface *openbindings.Interface, obiDir string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return fieldCandidatesMsg{
+			tabID:      tabID,
+			opKey:      opKey,
+			candidates: app.OperationFieldCandidates(ctx, iface, opKey, obiDir, ""),
+		}
+	}
+}
+
+// handleFieldCandidates keeps the candidates listed for the open new input
+// modal, shown below the schema preview. The template itself is left empty.
+func (m *model) handleFieldCandidates(msg fieldCandidatesMsg) (tea.Model, tea.Cmd) {
+	modal := m.newInputModal
+	t := m.findTab(msg.tabID)
+	if modal == nil || t == nil || t.id != m.tabs[m.active].id || modal.opKey != msg.opKey || len(msg.candidates) == 0 {
+		return m, nil
+	}
+	modal.candidates = msg.candidates
+	return m, nil
+}
+
 // isExistingTemplate returns true if the selected template is "existing file"
 func (m *model) isExistingTemplate() bool {
 	modal := m.newInputModal
@@ -276,6 +316,20 @@ func (m *model) getExistingInputNames(targetID, opKey string) map[string]bool {
 	return existingNames
 }
 
+// candidateList joins up to max candidate values, noting how many more
+// there are.
+func candidateList(completions []app.Completion, max int) string {
+	values := make([]string, 0, max)
+	for i, c := range completions {
+		if i == max {
+			values = append(values, fmt.Sprintf("… (%d more)", len(completions)-max))
+			break
+		}
+		values = append(values, c.Value)
+	}
+	return strings.Join(values, ", ")
+}
+
 // generateSchemaPreview creates a JSON preview from an operation's input schema.
 func generateSchemaPreview(op openbindings.Operation, iface *openbindings.Interface) string {
 	if op.Input == nil {
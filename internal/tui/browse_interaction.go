@@ -0,0 +1,231 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/openbindings/cli/internal/app"
+	"github.com/openbindings/cli/internal/elicit"
+)
+
+// opActivityMsg carries a progress notification or log message that a
+// server reported while an operation runs.
+type opActivityMsg struct {
+	tabID int
+	opKey string
+	text  string
+}
+
+// elicitRequestMsg is sent when a server asks for user input while an
+// operation runs. The answer is sent on reply.
+type elicitRequestMsg struct {
+	tabID int
+	opKey string
+	req   app.ElicitRequest
+	reply chan<- app.ElicitResult
+}
+
+// elicitPromptState holds an elicitation form waiting for the user.
+type elicitPromptState struct {
+	tabID   int
+	opKey   string
+	request *elicit.Request
+	reply   chan<- app.ElicitResult
+}
+
+// listenInteractionsCmd waits for the next message from the interaction
+// callbacks of running operations. It is re-issued after each message.
+func listenInteractionsCmd(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+// opInteraction returns the Interaction for a run of opKey in a tab. Its
+// callbacks run on delegate goroutines and hand their work to Update
+// through m.interactions.
+func (m *model) opInteraction(tabID int, opKey string) app.Interaction {
+	ch := m.interactions
+	activity := func(text string) {
+		select {
+		case ch <- opActivityMsg{tabID: tabID, opKey: opKey, text: text}:
+		default: // drop updates the UI has not caught up with
+		}
+	}
+	return app.Interaction{
+		Progress: func(p app.Progress) {
+			activity(app.FormatProgress(p))
+		},
+		Log: func(l app.LogMessage) {
+			activity(l.Level + ": " + app.FormatLogMessage(l))
+		},
+		Authorize: func(ctx context.Context, authURL string) error {
+			// Unlike progress, the URL must not be dropped.
+			select {
+			case ch <- opActivityMsg{tabID: tabID, opKey: opKey, text: "authorize in your browser: " + authURL}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			_ = app.OpenBrowser(authURL)
+			return nil
+		},
+		Elicit: func(ctx context.Context, req app.ElicitRequest) (app.ElicitResult, error) {
+			reply := make(chan app.ElicitResult, 1)
+			select {
+			case ch <- elicitRequestMsg{tabID: tabID, opKey: opKey, req: req, reply: reply}:
+			case <-ctx.Done():
+				return app.ElicitResult{}, ctx.Err()
+			}
+			select {
+			case res := <-reply:
+				return res, nil
+			case <-ctx.Done():
+				return app.ElicitResult{}, ctx.Err()
+			}
+		},
+	}
+}
+
+func (m *model) handleOpActivity(msg opActivityMsg) (tea.Model, tea.Cmd) {
+	if t := m.findTab(msg.tabID); t != nil {
+		if rs := t.runState[msg.opKey]; rs != nil && (rs.status == app.RunStatusRunning || rs.status == app.RunStatusStreaming) {
+			rs.activity = msg.text
+			m.syncViewport()
+		}
+	}
+	return m, listenInteractionsCmd(m.interactions)
+}
+
+func (m *model) handleElicitRequest(msg elicitRequestMsg) (tea.Model, tea.Cmd) {
+	cmds := []tea.Cmd{listenInteractionsCmd(m.interactions)}
+	m.elicitPrompts = append(m.elicitPrompts, &elicitPromptState{
+		tabID:   msg.tabID,
+		opKey:   msg.opKey,
+		request: elicit.New(msg.req),
+		reply:   msg.reply,
+	})
+	if len(m.elicitPrompts) == 1 {
+		cmds = append(cmds, m.showElicitPrompt())
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// showElicitPrompt starts the form of the first queued elicitation.
+func (m *model) showElicitPrompt() tea.Cmd {
+	if len(m.elicitPrompts) == 0 {
+		return nil
+	}
+	form := m.elicitPrompts[0].request.Form
+	form.WithWidth(clampMin(m.width-2-4, 20)).WithShowHelp(true)
+	return form.Init()
+}
+
+// answerElicitPrompt sends the result of the current form to the server and
+// moves on to the next queued elicitation, if any.
+func (m *model) answerElicitPrompt(res app.ElicitResult) tea.Cmd {
+	p := m.elicitPrompts[0]
+	p.reply <- res // buffered; the operation may already have given up waiting
+	m.elicitPrompts = m.elicitPrompts[1:]
+	return m.showElicitPrompt()
+}
+
+// dropElicitPrompts cancels the elicitations of a run that has ended.
+func (m *model) dropElicitPrompts(tabID int, opKey string) tea.Cmd {
+	var kept []*elicitPromptState
+	headDropped := false
+	for i, p := range m.elicitPrompts {
+		if p.tabID == tabID && p.opKey == opKey {
+			p.reply <- app.ElicitResult{Action: app.ElicitCancel}
+			headDropped = headDropped || i == 0
+			continue
+		}
+		kept = append(kept, p)
+	}
+	m.elicitPrompts = kept
+	if headDropped {
+		return m.showElicitPrompt()
+	}
+	return nil
+}
+
+// handleElicitKeys sends keys to the current elicitation form. Esc cancels
+// the elicitation; the operation itself keeps running.
+func (m *model) handleElicitKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "esc" {
+		return m, m.answerElicitPrompt(app.ElicitResult{Action: app.ElicitCancel})
+	}
+	return m.updateElicitForm(msg)
+}
+
+// updateElicitForm forwards msg to the current elicitation form and answers
+// the server once the form is done.
+func (m *model) updateElicitForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	p := m.elicitPrompts[0]
+	updated, cmd := p.request.Form.Update(msg)
+	if form, ok := updated.(*huh.Form); ok {
+		p.request.Form = form
+	}
+	if p.request.Form.State != huh.StateNormal {
+		return m, tea.Batch(cmd, m.answerElicitPrompt(p.request.Result()))
+	}
+	return m, cmd
+}
+
+// viewElicitPrompt renders the full-screen modal for the current
+// elicitation form.
+func (m *model) viewElicitPrompt() string {
+	p := m.elicitPrompts[0]
+
+	subtitleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("7"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	dividerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	contentW := clampMin(m.width-2-4, 0)
+	innerW := clampMin(m.width-2, 0)
+	innerH := clampMin(m.height-2, 0)
+
+	var sb strings.Builder
+
+	// Context header: interface name › operation
+	interfaceName := ""
+	if t := m.findTab(p.tabID); t != nil {
+		interfaceName = t.url
+		if t.obi != nil && t.obi.Name != "" {
+			interfaceName = t.obi.Name
+		}
+	}
+	sb.WriteString(dimStyle.Render(interfaceName))
+	sb.WriteString(dimStyle.Render(" › "))
+	sb.WriteString(subtitleStyle.Render(p.opKey))
+	sb.WriteString("\n")
+	sb.WriteString(dividerStyle.Render(strings.Repeat("─", contentW)))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(p.request.Form.View())
+
+	sb.WriteString("\n")
+	sb.WriteString(dividerStyle.Render(strings.Repeat("─", contentW)))
+	sb.WriteString("\n")
+	footerHelp := "esc: cancel request"
+	if n := len(m.elicitPrompts) - 1; n > 0 {
+		footerHelp += fmt.Sprintf("    %d more waiting", n)
+	}
+	sb.WriteString(dimStyle.Render(footerHelp))
+
+	content := sb.String()
+	padded := lipgloss.NewStyle().Padding(1, 2).Render(content)
+	inner := lipgloss.Place(innerW, innerH, lipgloss.Left, lipgloss.Top, padded)
+
+	frame := lipgloss.NewStyle().
+		Width(innerW).
+		Height(innerH).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("14")) // Highlight border for modal
+
+	return frame.Render(inner)
+}
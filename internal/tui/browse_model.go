@@ -47,6 +47,13 @@ type model struct {
 	// Remove input confirmation modal
 	removeInputConfirm *removeInputConfirmState
 
+	// Messages from the interaction callbacks of running operations
+	// (progress, logs, elicitation requests); see opInteraction.
+	interactions chan tea.Msg
+
+	// Elicitation forms waiting for the user, current first
+	elicitPrompts []*elicitPromptState
+
 	// Spinner state - prevents multiple tick chains
 	spinnerActive bool
 
@@ -108,15 +115,14 @@ type newInputModalState struct {
 	focusField       int              // 0 = name, 1 = template list, 2 = path input (for existing file)
 	pathInput        textinput.Model  // Path input (for "existing" template)
 	pathError        string           // Error message for invalid path
+
+	candidates map[string][]app.Completion // Candidate values of completable fields, once listed
 }
 
 func (m *model) Init() tea.Cmd {
-	if len(m.tabs) == 0 {
-		return nil
-	}
+	cmds := []tea.Cmd{listenInteractionsCmd(m.interactions)}
 
 	// Probe all tabs on startup
-	var cmds []tea.Cmd
 	for i := range m.tabs {
 		t := &m.tabs[i]
 		if t.url != "" {
@@ -124,10 +130,6 @@ func (m *model) Init() tea.Cmd {
 			cmds = append(cmds, probeCmd(t.id, t.url))
 		}
 	}
-
-	if len(cmds) == 0 {
-		return nil
-	}
 	return tea.Batch(cmds...)
 }
 
@@ -173,6 +175,7 @@ type opRunState struct {
 	streaming  bool   // true when this is a streaming/event subscription
 	eventCount int    // number of events received so far
 	streamCh   <-chan app.StreamEvent // active stream channel (for re-issuing listenCmd)
+	activity   string // latest progress or log line reported by the server
 }
 
 // findTab returns a pointer to the tab with the given ID, or nil if not found.
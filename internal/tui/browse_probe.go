@@ -29,6 +29,9 @@ type probeResult struct {
 	// OBI base directory for resolving relative artifact paths.
 	// Set for file-path targets. Empty for exec: targets.
 	obiDir string
+
+	// Resolved server URL per binding key, for formats that have one.
+	servers map[string]string
 }
 
 type probeResultMsg struct {
@@ -54,6 +57,7 @@ func probeCmd(tabID int, rawURL string) tea.Cmd {
 			if err := json.Unmarshal([]byte(result.OBI), &iface); err == nil {
 				pr.parsed = &iface
 				pr.opKeys = sortedOpKeys(iface.Operations)
+				pr.servers = bindingServers(&iface, pr.obiDir)
 			}
 		}
 
@@ -64,6 +68,17 @@ func probeCmd(tabID int, rawURL string) tea.Cmd {
 	}
 }
 
+// bindingServers resolves the server URL for each binding, loading each
+// source document once. Bindings whose server cannot be resolved are
+// omitted; execution reports the error.
+func bindingServers(iface *openbindings.Interface, obiDir string) map[string]string {
+	servers, err := app.ResolveBindingServers(iface, obiDir, "")
+	if err != nil {
+		return map[string]string{}
+	}
+	return servers
+}
+
 func sortedOpKeys(ops map[string]openbindings.Operation) []string {
 	keys := make([]string, 0, len(ops))
 	for k := range ops {
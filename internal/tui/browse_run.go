@@ -56,6 +56,7 @@ func RunBrowse(workspaceName string) error {
 		workspace:      ws,
 		workspacePath:  wsPath,
 		workspaceMtime: wsMtime,
+		interactions:   make(chan tea.Msg, 64),
 	}
 
 	// Initialize tabs from workspace (or default)
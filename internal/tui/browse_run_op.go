@@ -119,7 +119,8 @@ func executeOpWithContext(ctx context.Context, opKey string, targetURL string, o
 	// Build ExecuteOperationInput
 	execInput := app.ExecuteOperationInput{
 		Source: app.ExecuteSource{
-			Format: source.Format,
+			Format:     source.Format,
+			Extensions: source.Extensions,
 		},
 		Ref:   binding.Ref,
 		Input: execInputData,
@@ -334,9 +335,10 @@ func (m *model) runWithExample(t *tab, opKey, exampleKey string) tea.Cmd {
 	return m.launchOp(t, opKey, inputData, "example:"+exampleKey)
 }
 
-// subscribeOpCmd starts a streaming subscription for an event operation.
-// It opens the channel and returns a streamReadyMsg with the event channel.
-func subscribeOpCmd(ctx context.Context, tabID int, opKey string, targetURL string, obiDir string, iface *openbindings.Interface) tea.Cmd {
+// subscribeOpCmd starts a streaming subscription for an event operation, or
+// an operation whose output streams. It opens the channel and returns a
+// streamReadyMsg with the event channel.
+func subscribeOpCmd(ctx context.Context, tabID int, opKey string, targetURL string, obiDir string, iface *openbindings.Interface, inputData map[string]any) tea.Cmd {
 	return func() tea.Msg {
 		if iface == nil {
 			return opRunResultMsg{tabID: tabID, opKey: opKey, status: app.RunStatusError, err: fmt.Errorf("no interface")}
@@ -352,7 +354,11 @@ func subscribeOpCmd(ctx context.Context, tabID int, opKey string, targetURL stri
 			return opRunResultMsg{tabID: tabID, opKey: opKey, status: app.RunStatusError, err: fmt.Errorf("source %q not found", binding.Source)}
 		}
 
-		ch, err := app.SubscribeOBIOperationDirect(ctx, iface, opKey, binding, source, obiDir, "")
+		var input any
+		if inputData != nil {
+			input = inputData
+		}
+		ch, err := app.SubscribeOBIOperationDirect(ctx, iface, opKey, binding, source, obiDir, input, "")
 		if err != nil {
 			return opRunResultMsg{tabID: tabID, opKey: opKey, status: app.RunStatusError, err: err}
 		}
@@ -379,6 +385,9 @@ func listenCmd(ch <-chan app.StreamEvent, tabID int, opKey string) tea.Cmd {
 		if ev.Error != nil {
 			return streamEventMsg{tabID: tabID, opKey: opKey, data: "Error: " + ev.Error.Message}
 		}
+		if line, _, ok := app.OutputLine(ev.Data); ok {
+			return streamEventMsg{tabID: tabID, opKey: opKey, data: line}
+		}
 		return streamEventMsg{tabID: tabID, opKey: opKey, data: formatOutput(ev.Data)}
 	}
 }
@@ -398,8 +407,10 @@ func (m *model) launchOp(t *tab, opKey string, inputData map[string]any, inputNa
 		}
 	}
 
-	// Create cancellable context
+	// Create cancellable context; server progress, logs and elicitation
+	// requests for this run are routed back to the UI.
 	ctx, cancel := context.WithCancel(context.Background())
+	ctx = app.WithInteraction(ctx, m.opInteraction(t.id, opKey))
 
 	// Expand the operation in tree
 	if t.tree != nil {
@@ -408,9 +419,10 @@ func (m *model) launchOp(t *tab, opKey string, inputData map[string]any, inputNa
 		}
 	}
 
-	// Check if this is an event operation — use streaming path.
+	// Event operations, and operations whose output streams (CLI commands),
+	// use the streaming path.
 	if t.obi != nil {
-		if op, ok := t.obi.Operations[opKey]; ok && op.Kind == "event" {
+		if op, ok := t.obi.Operations[opKey]; ok && (op.Kind == "event" || app.OperationStreamsOutput(t.obi, opKey)) {
 			t.runState[opKey] = &opRunState{
 				status:    app.RunStatusRunning,
 				streaming: true,
@@ -418,7 +430,7 @@ func (m *model) launchOp(t *tab, opKey string, inputData map[string]any, inputNa
 				cancel:    cancel,
 			}
 			m.syncViewport()
-			cmds := []tea.Cmd{subscribeOpCmd(ctx, t.id, opKey, t.url, t.obiDir, t.obi)}
+			cmds := []tea.Cmd{subscribeOpCmd(ctx, t.id, opKey, t.url, t.obiDir, t.obi, inputData)}
 			if !m.spinnerActive {
 				m.spinnerActive = true
 				cmds = append(cmds, spinnerTick())
@@ -41,6 +41,12 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case streamEndedMsg:
 		return m.handleStreamEnded(msg)
 
+	case opActivityMsg:
+		return m.handleOpActivity(msg)
+
+	case elicitRequestMsg:
+		return m.handleElicitRequest(msg)
+
 	case spinnerTickMsg:
 		return m.handleSpinnerTick()
 
@@ -55,6 +61,8 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleInputError(msg)
 	case workspaceInputCreatedMsg:
 		return m.handleWorkspaceInputCreated(msg)
+	case fieldCandidatesMsg:
+		return m.handleFieldCandidates(msg)
 
 	case workspaceSavedMsg:
 		return m.handleWorkspaceSaved(msg)
@@ -77,6 +85,10 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleKeyMsg(msg)
 	}
 
+	// Internal messages of the elicitation form (focus changes, cursor blink).
+	if len(m.elicitPrompts) > 0 {
+		return m.updateElicitForm(msg)
+	}
 	return m, nil
 }
 
@@ -159,6 +171,7 @@ func (m *model) handleProbeResult(msg probeResultMsg) (tea.Model, tea.Cmd) {
 
 	if msg.result.parsed != nil {
 		tree := BuildOBITree(msg.result.parsed, msg.result.opKeys)
+		annotateBindingServers(tree, msg.result.servers)
 		t.tree = NewTreeState(tree)
 		m.restoreUIState(t)
 		for _, opKey := range msg.result.opKeys {
@@ -199,10 +212,11 @@ func (m *model) handleProbeResult(msg probeResultMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *model) handleRunResult(msg opRunResultMsg) (tea.Model, tea.Cmd) {
+	promptCmd := m.dropElicitPrompts(msg.tabID, msg.opKey)
 	t := m.findTab(msg.tabID)
 	if t == nil {
 		m.syncViewport()
-		return m, nil
+		return m, promptCmd
 	}
 	if t.runState == nil {
 		t.runState = make(map[string]*opRunState)
@@ -222,11 +236,12 @@ func (m *model) handleRunResult(msg opRunResultMsg) (tea.Model, tea.Cmd) {
 	}
 	existing.inputName = msg.inputName
 	existing.durationMs = msg.durationMs
+	existing.activity = ""
 	if !existing.expanded && (msg.status == app.RunStatusSuccess || msg.status == app.RunStatusError) {
 		existing.expanded = true
 	}
 	m.syncViewport()
-	return m, nil
+	return m, promptCmd
 }
 
 func (m *model) handleOpCancelled(msg opCancelledMsg) (tea.Model, tea.Cmd) {
@@ -246,10 +261,11 @@ func (m *model) handleOpCancelled(msg opCancelledMsg) (tea.Model, tea.Cmd) {
 		existing.status = app.RunStatusError
 		existing.error = "cancelled"
 		existing.cancel = nil
+		existing.activity = ""
 	}
 	m.syncViewport()
 	m.statusMsg = "Operation cancelled"
-	return m, clearStatusAfter(2 * time.Second)
+	return m, tea.Batch(clearStatusAfter(2*time.Second), m.dropElicitPrompts(msg.tabID, msg.opKey))
 }
 
 func (m *model) handleStreamReady(msg streamReadyMsg) (tea.Model, tea.Cmd) {
@@ -304,8 +320,9 @@ func (m *model) handleStreamEnded(msg streamEndedMsg) (tea.Model, tea.Cmd) {
 	rs.status = app.RunStatusSuccess
 	rs.streamCh = nil
 	rs.cancel = nil
+	rs.activity = ""
 	m.syncViewport()
-	return m, nil
+	return m, m.dropElicitPrompts(msg.tabID, msg.opKey)
 }
 
 func (m *model) handleSpinnerTick() (tea.Model, tea.Cmd) {
@@ -466,6 +483,9 @@ func (m *model) handleMouseMsg(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 
 func (m *model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Handle modal if active
+	if len(m.elicitPrompts) > 0 {
+		return m.handleElicitKeys(msg)
+	}
 	if m.newInputModal != nil {
 		return m.handleNewInputModalKeys(msg)
 	}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -22,6 +23,9 @@ func (m *model) View() string {
 	}
 
 	// If modal is active, show modal view instead
+	if len(m.elicitPrompts) > 0 {
+		return m.viewElicitPrompt()
+	}
 	if m.newInputModal != nil {
 		return m.viewNewInputModal()
 	}
@@ -252,6 +256,19 @@ func (m *model) viewNewInputModal() string {
 		previewW := clampMin(contentW-4, 20)
 		sb.WriteString(previewBoxStyle.Width(previewW).Render(previewContent))
 		sb.WriteString("\n")
+
+		// Candidate values listed by the fields' completers
+		if len(modal.candidates) > 0 {
+			fields := make([]string, 0, len(modal.candidates))
+			for name := range modal.candidates {
+				fields = append(fields, name)
+			}
+			sort.Strings(fields)
+			for _, name := range fields {
+				sb.WriteString(dimStyle.MaxWidth(previewW).Render(name + ": " + candidateList(modal.candidates[name], 5)))
+				sb.WriteString("\n")
+			}
+		}
 	}
 
 	// Footer with key help
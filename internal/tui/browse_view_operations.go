@@ -181,6 +181,8 @@ func (m *model) getNodeLabelStyle(node *TreeNode, selected bool) lipgloss.Style
 		return style.Bold(true).Foreground(lipgloss.Color("7"))
 	case NodeTypeBinding:
 		return style.Foreground(lipgloss.Color("12"))
+	case NodeTypeBindingServer:
+		return style.Foreground(lipgloss.Color("8"))
 	case NodeTypeAliases, NodeTypeSatisfies:
 		return style.Bold(true).Foreground(lipgloss.Color("7"))
 	case NodeTypeAlias:
@@ -295,6 +297,10 @@ func (m *model) renderRunState(indent string, runState *opRunState) string {
 			sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(fmt.Sprintf(" (using %s)", runState.inputName)))
 		}
 		sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(" [c to cancel]"))
+		if runState.activity != "" {
+			sb.WriteString("\n" + indent)
+			sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(runState.activity))
+		}
 	case app.RunStatusStreaming:
 		spinner := spinnerFrames[runState.frame%len(spinnerFrames)]
 		evLabel := fmt.Sprintf("%d events", runState.eventCount)
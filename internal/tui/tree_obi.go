@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 
 	"github.com/openbindings/cli/internal/app"
 	openbindings "github.com/openbindings/openbindings-go"
@@ -241,3 +242,31 @@ func buildBindingsNode(opKey string, bindings map[string]openbindings.BindingEnt
 
 	return node
 }
+
+// annotateBindingServers adds a server child under each binding node whose
+// key has a resolved server URL, so the user can see where requests will go.
+func annotateBindingServers(root *TreeNode, servers map[string]string) {
+	if root == nil || len(servers) == 0 {
+		return
+	}
+	for _, opNode := range root.Children {
+		for _, section := range opNode.Children {
+			if section.Type != NodeTypeBindings {
+				continue
+			}
+			prefix := section.ID + "."
+			for _, bindingNode := range section.Children {
+				server, ok := servers[strings.TrimPrefix(bindingNode.ID, prefix)]
+				if !ok || server == "" {
+					continue
+				}
+				bindingNode.Children = []*TreeNode{{
+					ID:    bindingNode.ID + ".server",
+					Label: "server: " + server,
+					Type:  NodeTypeBindingServer,
+					Icon:  "→",
+				}}
+			}
+		}
+	}
+}
@@ -12,6 +12,9 @@ const (
 	// NodeTypeBinding is a single binding entry.
 	NodeTypeBinding = "binding"
 
+	// NodeTypeBindingServer is the server a binding's requests are sent to.
+	NodeTypeBindingServer = "binding-server"
+
 	// NodeTypeInputs is a container for operation inputs.
 	NodeTypeInputs = "inputs"
 